@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/x509"
 	"log"
 	"net/http"
 	"os"
@@ -10,10 +12,17 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/rotsu1/jimu-backend/internal/appstore"
 	"github.com/rotsu1/jimu-backend/internal/db"
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
 	"github.com/rotsu1/jimu-backend/internal/handlers"
+	"github.com/rotsu1/jimu-backend/internal/jobs"
+	"github.com/rotsu1/jimu-backend/internal/push"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 	router "github.com/rotsu1/jimu-backend/internal/routers"
+	"github.com/rotsu1/jimu-backend/internal/storage"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
 )
 
 func main() {
@@ -45,31 +54,120 @@ func main() {
 	routineExerciseRepo := repository.NewRoutineExerciseRepository(pool)
 
 	routineSetRepo := repository.NewRoutineSetRepository(pool)
+	personalRecordRepo := repository.NewPersonalRecordRepository(pool)
+	statsRepo := repository.NewStatsRepository(pool)
+	mentionRepo := repository.NewMentionRepository(pool)
+	hashtagRepo := repository.NewHashtagRepository(pool)
+	forYouRepo := repository.NewForYouRepository(pool)
+	notificationRepo := repository.NewNotificationRepository(pool)
+	pushOutboxRepo := repository.NewPushOutboxRepository(pool)
+	uploadRepo := repository.NewUploadRepository(pool)
+	blobDeletionRepo := repository.NewBlobDeletionRepository(pool)
+	adminRepo := repository.NewAdminRepository(pool)
+	reportRepo := repository.NewReportRepository(pool)
 	healthRepo := repository.NewHealthRepository(pool)
 
+	_ = godotenv.Load()
+
+	// Purchases are verified against Apple's root certificate. Without one,
+	// every transaction is rejected.
+	appStoreRoots := x509.NewCertPool()
+	if path := os.Getenv("APPLE_ROOT_CA_PATH"); path != "" {
+		appStoreRoots, err = appstore.LoadRoots(path)
+		if err != nil {
+			log.Fatalf("Failed to load Apple root certificates: %v", err)
+		}
+	} else {
+		log.Println("APPLE_ROOT_CA_PATH is not set; App Store purchases cannot be verified")
+	}
+	appStoreVerifier, err := appstore.NewVerifier(appStoreRoots, os.Getenv("APPSTORE_BUNDLE_ID"))
+	if err != nil {
+		log.Fatalf("Failed to initialize App Store verification: %v", err)
+	}
+	entitlementService := entitlements.NewService(subscriptionRepo)
+
+	// Google Play purchases are fetched from the Play Developer API. Without
+	// a package, every purchase is rejected.
+	playClient := &googleplay.Client{}
+	if packageName := os.Getenv("GOOGLE_PLAY_PACKAGE_NAME"); packageName != "" {
+		playClient, err = googleplay.NewClient(context.Background(), packageName)
+		if err != nil {
+			log.Fatalf("Failed to initialize Google Play: %v", err)
+		}
+	} else {
+		log.Println("GOOGLE_PLAY_PACKAGE_NAME is not set; Google Play purchases cannot be verified")
+	}
+
+	// The API's own address, which avatar URLs and local blob URLs point at
+	publicBaseURL := os.Getenv("PUBLIC_BASE_URL")
+	if publicBaseURL == "" {
+		publicBaseURL = "http://localhost:8080"
+	}
+
+	// Uploaded files go to an S3 or Cloud Storage bucket. The local store
+	// keeps them on disk and serves its own presigned URLs, for development.
+	var blobStore storage.BlobStore
+	var blobHandler http.Handler
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "s3":
+		blobStore = storage.NewS3Store(os.Getenv("STORAGE_REGION"), os.Getenv("STORAGE_BUCKET"),
+			os.Getenv("STORAGE_ACCESS_KEY_ID"), os.Getenv("STORAGE_SECRET_ACCESS_KEY"))
+	case "gcs":
+		blobStore = storage.NewGCSStore(os.Getenv("STORAGE_BUCKET"),
+			os.Getenv("STORAGE_ACCESS_KEY_ID"), os.Getenv("STORAGE_SECRET_ACCESS_KEY"))
+	case "", "local":
+		root := os.Getenv("LOCAL_STORAGE_DIR")
+		if root == "" {
+			root = "./data/blobs"
+		}
+		secret := []byte(os.Getenv("LOCAL_STORAGE_SECRET"))
+		if len(secret) == 0 {
+			log.Println("LOCAL_STORAGE_SECRET is not set; signed blob URLs will not survive a restart")
+			secret = make([]byte, 32)
+			rand.Read(secret)
+		}
+		local := storage.NewLocalStore(root, publicBaseURL, secret)
+		blobStore = local
+		blobHandler = local.Handler()
+	default:
+		log.Fatalf("Unknown STORAGE_BACKEND %q", backend)
+	}
+	uploadService := uploads.NewService(blobStore, publicBaseURL)
+
 	// 3. Initialize the Handler (Injecting the Repo)
 	authHandler := handlers.NewAuthHandler(userRepo, userSessionRepo, &handlers.GoogleValidator{})
 	userSettingsHandler := handlers.NewUserSettingsHandler(userRepo)
 	userDeviceHandler := handlers.NewUserDeviceHandler(userDeviceRepo)
-	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionRepo)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionRepo, appStoreVerifier, playClient, entitlementService)
 	followHandler := handlers.NewFollowHandler(followRepo)
 	blockedUserHandler := handlers.NewBlockedUserHandler(blockedUserRepo)
-	workoutHandler := handlers.NewWorkoutHandler(workoutRepo)
+	workoutHandler := handlers.NewWorkoutHandler(workoutRepo, uploadService)
 	workoutExerciseHandler := handlers.NewWorkoutExerciseHandler(workoutExerciseRepo)
 	workoutSetHandler := handlers.NewWorkoutSetHandler(workoutSetRepo)
-	workoutImageHandler := handlers.NewWorkoutImageHandler(workoutImageRepo)
+	workoutImageHandler := handlers.NewWorkoutImageHandler(workoutImageRepo, uploadRepo, uploadService)
 	workoutLikeHandler := handlers.NewWorkoutLikeHandler(workoutLikeRepo)
 	exerciseHandler := handlers.NewExerciseHandler(exerciseRepo)
 	muscleHandler := handlers.NewMuscleHandler(muscleRepo)
 	exerciseTargetMuscleHandler := handlers.NewExerciseTargetMuscleHandler(exerciseTargetMuscleRepo)
 	commentHandler := handlers.NewCommentHandler(commentRepo)
 	commentLikeHandler := handlers.NewCommentLikeHandler(commentLikeRepo)
-	routineHandler := handlers.NewRoutineHandler(routineRepo)
+	routineHandler := handlers.NewRoutineHandler(routineRepo, entitlementService)
 	routineExerciseHandler := handlers.NewRoutineExerciseHandler(routineExerciseRepo)
 	routineSetHandler := handlers.NewRoutineSetHandler(routineSetRepo)
+	personalRecordHandler := handlers.NewPersonalRecordHandler(personalRecordRepo)
+	statsHandler := handlers.NewStatsHandler(statsRepo)
+	mentionHandler := handlers.NewMentionHandler(mentionRepo)
+	hashtagHandler := handlers.NewHashtagHandler(hashtagRepo)
+	forYouHandler := handlers.NewForYouHandler(forYouRepo, uploadService)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+	appStoreWebhookHandler := handlers.NewAppStoreWebhookHandler(subscriptionRepo, appStoreVerifier, entitlementService)
+	googlePlayWebhookHandler := handlers.NewGooglePlayWebhookHandler(subscriptionRepo, playClient, entitlementService, os.Getenv("GOOGLE_PLAY_RTDN_TOKEN"))
 	healthHandler := handlers.NewHealthHandler(healthRepo)
+	uploadHandler := handlers.NewUploadHandler(uploadRepo, uploadService)
+	avatarHandler := handlers.NewAvatarHandler(userRepo, uploadRepo, uploadService)
+	adminHandler := handlers.NewAdminHandler(adminRepo)
+	reportHandler := handlers.NewReportHandler(reportRepo)
 
-	_ = godotenv.Load()
 	JWTSecret := os.Getenv("JWTSecret")
 	if JWTSecret == "" {
 		log.Fatal("JWTSecret is not set")
@@ -96,11 +194,47 @@ func main() {
 		RoutineHandler:              routineHandler,
 		RoutineExerciseHandler:      routineExerciseHandler,
 		RoutineSetHandler:           routineSetHandler,
+		PersonalRecordHandler:       personalRecordHandler,
+		StatsHandler:                statsHandler,
+		MentionHandler:              mentionHandler,
+		HashtagHandler:              hashtagHandler,
+		ForYouHandler:               forYouHandler,
+		NotificationHandler:         notificationHandler,
+		AppStoreWebhookHandler:      appStoreWebhookHandler,
+		GooglePlayWebhookHandler:    googlePlayWebhookHandler,
 		HealthHandler:               healthHandler,
+		UploadHandler:               uploadHandler,
+		AvatarHandler:               avatarHandler,
+		AdminHandler:                adminHandler,
+		ReportHandler:               reportHandler,
+		Blobs:                       blobHandler,
+		Entitlements:                entitlementService,
+		Admins:                      adminRepo,
+		Accounts:                    userRepo,
 		JWTSecret:                   JWTSecret,
 	}
 
-	// 6. Define the Server
+	// 6. Start background jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go jobs.Every(jobCtx, "for-you candidates", 10*time.Minute, forYouRepo.Refresh)
+	go jobs.Every(jobCtx, "blob sweeper", 10*time.Minute, uploads.NewSweeper(blobDeletionRepo, blobStore).Run)
+	go jobs.Every(jobCtx, "expired uploads", time.Hour, uploadRepo.DeleteExpiredUploads)
+	go jobs.Every(jobCtx, "expired suspensions", 10*time.Minute, adminRepo.RestoreExpiredSuspensions)
+
+	if fcmProjectID := os.Getenv("FCM_PROJECT_ID"); fcmProjectID != "" {
+		sender, err := push.NewFCMSender(jobCtx, fcmProjectID)
+		if err != nil {
+			log.Fatalf("Failed to initialize FCM: %v", err)
+		}
+		pushWorker := push.NewWorker(pushOutboxRepo, sender)
+		go jobs.Every(jobCtx, "push delivery", 5*time.Second, pushWorker.Run)
+		go jobs.Every(jobCtx, "push outbox cleanup", time.Hour, pushWorker.Prune)
+	} else {
+		log.Println("FCM_PROJECT_ID is not set; push delivery is disabled")
+	}
+
+	// 7. Define the Server
 	server := &http.Server{
 		Addr:    ":8080",
 		Handler: jimuRouter,
@@ -132,6 +266,9 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop background jobs before the pool they use goes away
+	stopJobs()
+
 	// Close the Database pool
 	log.Println("Closing database connections...")
 	pool.Close()
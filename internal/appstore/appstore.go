@@ -0,0 +1,341 @@
+package appstore
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+var (
+	// ErrInvalidSignature means the JWS is malformed, its certificate chain
+	// does not lead to a trusted root, or the signature does not match.
+	ErrInvalidSignature = errors.New("invalid App Store signature")
+	// ErrNoBundleID means a Verifier was created without the app's bundle
+	// ID, which would let it accept any app's transactions.
+	ErrNoBundleID = errors.New("App Store bundle ID is required")
+	// ErrWrongBundle means the transaction was signed for another app.
+	ErrWrongBundle = errors.New("transaction belongs to another app")
+	// ErrWrongEnvironment means the transaction comes from an App Store
+	// environment the verifier does not accept, such as the free Sandbox.
+	ErrWrongEnvironment = errors.New("transaction is from a disallowed environment")
+)
+
+// EnvironmentProduction is the App Store environment of real purchases.
+// Sandbox and Xcode purchases cost nothing.
+const EnvironmentProduction = "Production"
+
+// Apple marks its App Store signing certificates with these extensions.
+var (
+	oidLeafMarker         = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 11, 1}
+	oidIntermediateMarker = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 2, 1}
+)
+
+// Transaction is the decoded payload of a signed App Store transaction
+// (JWSTransactionDecodedPayload). Dates are milliseconds since the epoch.
+type Transaction struct {
+	TransactionID         string `json:"transactionId"`
+	OriginalTransactionID string `json:"originalTransactionId"`
+	BundleID              string `json:"bundleId"`
+	ProductID             string `json:"productId"`
+	PurchaseDate          int64  `json:"purchaseDate"`
+	ExpiresDate           int64  `json:"expiresDate"`
+	RevocationDate        int64  `json:"revocationDate,omitempty"`
+	SignedDate            int64  `json:"signedDate"`
+	Environment           string `json:"environment"`
+	Type                  string `json:"type"`
+	AppAccountToken       string `json:"appAccountToken,omitempty"`
+
+	// Raw is the verified payload exactly as Apple signed it
+	Raw json.RawMessage `json:"-"`
+}
+
+func millis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+func (t *Transaction) ExpiresAt() time.Time { return millis(t.ExpiresDate) }
+func (t *Transaction) SignedAt() time.Time  { return millis(t.SignedDate) }
+
+// Status derives the subscription status as of now: a refunded or revoked
+// transaction is revoked, otherwise it is active until it expires.
+func (t *Transaction) Status(now time.Time) string {
+	switch {
+	case t.RevocationDate != 0:
+		return models.SubscriptionRevoked
+	case !t.ExpiresAt().After(now):
+		return models.SubscriptionExpired
+	default:
+		return models.SubscriptionActive
+	}
+}
+
+// Verifier checks signed App Store payloads against a set of trusted roots,
+// normally Apple Root CA - G3. Only transactions from Environments are
+// accepted.
+type Verifier struct {
+	Roots        *x509.CertPool
+	BundleID     string
+	Environments []string
+	Now          func() time.Time
+}
+
+// NewVerifier accepts Production purchases of bundleID only.
+func NewVerifier(roots *x509.CertPool, bundleID string) (*Verifier, error) {
+	if bundleID == "" {
+		return nil, ErrNoBundleID
+	}
+	return &Verifier{
+		Roots:        roots,
+		BundleID:     bundleID,
+		Environments: []string{EnvironmentProduction},
+		Now:          time.Now,
+	}, nil
+}
+
+// LoadRoots reads trusted root certificates from a PEM or DER file, such as
+// AppleRootCA-G3.cer from https://www.apple.com/certificateauthority/.
+func LoadRoots(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read root certificates: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if pool.AppendCertsFromPEM(data) {
+		return pool, nil
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse root certificate: %w", err)
+	}
+	pool.AddCert(cert)
+	return pool, nil
+}
+
+// VerifyTransaction verifies a signedTransactionInfo JWS and decodes it.
+func (v *Verifier) VerifyTransaction(signed string) (*Transaction, error) {
+	payload, err := v.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+
+	var t Transaction
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return nil, fmt.Errorf("%w: malformed transaction: %v", ErrInvalidSignature, err)
+	}
+	if v.BundleID == "" || t.BundleID != v.BundleID {
+		return nil, ErrWrongBundle
+	}
+	if !slices.Contains(v.Environments, t.Environment) {
+		return nil, fmt.Errorf("%w: %q", ErrWrongEnvironment, t.Environment)
+	}
+	if t.OriginalTransactionID == "" || t.ProductID == "" {
+		return nil, fmt.Errorf("%w: transaction is missing identifiers", ErrInvalidSignature)
+	}
+	t.Raw = payload
+	return &t, nil
+}
+
+// Verify checks a JWS signed by the App Store and returns its payload. The
+// x5c header must carry a chain from an Apple-marked leaf certificate to one
+// of the trusted roots, and the leaf's key must have produced the signature.
+func (v *Verifier) Verify(signed string) ([]byte, error) {
+	parts := strings.Split(signed, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a compact JWS", ErrInvalidSignature)
+	}
+
+	var header struct {
+		Alg string   `json:"alg"`
+		X5C []string `json:"x5c"`
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(headerJSON, &header) != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if header.Alg != jwt.SigningMethodES256.Alg() {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidSignature, header.Alg)
+	}
+
+	leaf, err := v.verifyChain(header.X5C)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	if err := jwt.SigningMethodES256.Verify(parts[0]+"."+parts[1], sig, leaf.PublicKey); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidSignature)
+	}
+	return payload, nil
+}
+
+func (v *Verifier) verifyChain(x5c []string) (*x509.Certificate, error) {
+	if len(x5c) < 2 {
+		return nil, fmt.Errorf("%w: certificate chain is too short", ErrInvalidSignature)
+	}
+
+	certs := make([]*x509.Certificate, len(x5c))
+	for i, encoded := range x5c {
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed certificate", ErrInvalidSignature)
+		}
+		if certs[i], err = x509.ParseCertificate(der); err != nil {
+			return nil, fmt.Errorf("%w: malformed certificate: %v", ErrInvalidSignature, err)
+		}
+	}
+	leaf, intermediate := certs[0], certs[1]
+
+	if !hasExtension(leaf, oidLeafMarker) || !hasExtension(intermediate, oidIntermediateMarker) {
+		return nil, fmt.Errorf("%w: not an App Store certificate", ErrInvalidSignature)
+	}
+	if _, ok := leaf.PublicKey.(*ecdsa.PublicKey); !ok {
+		return nil, fmt.Errorf("%w: leaf key is not ECDSA", ErrInvalidSignature)
+	}
+
+	// The root in x5c is ignored; only the configured roots are trusted, and
+	// a nil pool must not fall back to the system roots
+	if v.Roots == nil {
+		return nil, fmt.Errorf("%w: no trusted roots configured", ErrInvalidSignature)
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate)
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         v.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   v.Now(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return leaf, nil
+}
+
+func hasExtension(cert *x509.Certificate, oid asn1.ObjectIdentifier) bool {
+	return slices.ContainsFunc(cert.Extensions, func(ext pkix.Extension) bool {
+		return ext.Id.Equal(oid)
+	})
+}
+
+// Notification is the decoded payload of an App Store Server Notification
+// (responseBodyV2DecodedPayload). Transaction is the verified content of
+// data.signedTransactionInfo, nil for notifications without one.
+type Notification struct {
+	NotificationType string `json:"notificationType"`
+	Subtype          string `json:"subtype,omitempty"`
+	NotificationUUID string `json:"notificationUUID"`
+	Version          string `json:"version"`
+	SignedDate       int64  `json:"signedDate"`
+	Data             struct {
+		BundleID              string `json:"bundleId"`
+		Environment           string `json:"environment"`
+		SignedTransactionInfo string `json:"signedTransactionInfo,omitempty"`
+		SignedRenewalInfo     string `json:"signedRenewalInfo,omitempty"`
+		// Status is the subscription's status after the event, 1 to 5
+		Status int `json:"status,omitempty"`
+	} `json:"data"`
+
+	Transaction *Transaction    `json:"-"`
+	Raw         json.RawMessage `json:"-"`
+}
+
+// Subscription statuses as reported in a notification's data.status.
+const (
+	statusActive       = 1
+	statusExpired      = 2
+	statusBillingRetry = 3
+	statusGracePeriod  = 4
+	statusRevoked      = 5
+)
+
+func (n *Notification) SignedAt() time.Time { return millis(n.SignedDate) }
+
+// SubscriptionStatus is the subscription's status after the notification.
+// Apple's own status is preferred; older notifications without one fall back
+// to the notification type and the transaction. ok is false for
+// notifications that do not change the subscription, such as TEST.
+func (n *Notification) SubscriptionStatus(now time.Time) (status string, ok bool) {
+	switch n.Data.Status {
+	case statusActive:
+		return models.SubscriptionActive, true
+	case statusExpired:
+		return models.SubscriptionExpired, true
+	case statusBillingRetry:
+		return models.SubscriptionBillingRetry, true
+	case statusGracePeriod:
+		return models.SubscriptionGracePeriod, true
+	case statusRevoked:
+		return models.SubscriptionRevoked, true
+	}
+
+	if n.Transaction == nil {
+		return "", false
+	}
+	switch n.NotificationType {
+	case "REFUND", "REVOKE":
+		return models.SubscriptionRevoked, true
+	case "EXPIRED", "GRACE_PERIOD_EXPIRED":
+		return models.SubscriptionExpired, true
+	case "DID_FAIL_TO_RENEW":
+		if n.Subtype == "GRACE_PERIOD" {
+			return models.SubscriptionGracePeriod, true
+		}
+		return models.SubscriptionBillingRetry, true
+	case "SUBSCRIBED", "DID_RENEW", "OFFER_REDEEMED", "RENEWAL_EXTENDED",
+		"DID_CHANGE_RENEWAL_PREF", "DID_CHANGE_RENEWAL_STATUS", "REFUND_REVERSED":
+		return n.Transaction.Status(now), true
+	default:
+		return "", false
+	}
+}
+
+// VerifyNotification verifies a signedPayload from an App Store Server
+// Notification and the transaction it carries.
+func (v *Verifier) VerifyNotification(signed string) (*Notification, error) {
+	payload, err := v.Verify(signed)
+	if err != nil {
+		return nil, err
+	}
+
+	var n Notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return nil, fmt.Errorf("%w: malformed notification: %v", ErrInvalidSignature, err)
+	}
+	if n.NotificationUUID == "" {
+		return nil, fmt.Errorf("%w: notification is missing its UUID", ErrInvalidSignature)
+	}
+	if v.BundleID == "" || n.Data.BundleID != v.BundleID {
+		return nil, ErrWrongBundle
+	}
+	if !slices.Contains(v.Environments, n.Data.Environment) {
+		return nil, fmt.Errorf("%w: %q", ErrWrongEnvironment, n.Data.Environment)
+	}
+	if n.Data.SignedTransactionInfo != "" {
+		if n.Transaction, err = v.VerifyTransaction(n.Data.SignedTransactionInfo); err != nil {
+			return nil, err
+		}
+	}
+	n.Raw = payload
+	return &n, nil
+}
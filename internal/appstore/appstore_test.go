@@ -0,0 +1,234 @@
+package appstore
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/appstore/appstoretest"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+func newTestSigner(t *testing.T) *appstoretest.Signer {
+	t.Helper()
+	signer, err := appstoretest.NewSigner()
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	return signer
+}
+
+func newTestVerifier(t *testing.T, signer *appstoretest.Signer) *Verifier {
+	t.Helper()
+	v, err := NewVerifier(signer.Roots(), "com.jimu.app")
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+	return v
+}
+
+func testTransaction(expires time.Time) map[string]any {
+	return map[string]any{
+		"transactionId":         "2000000000000002",
+		"originalTransactionId": "2000000000000001",
+		"bundleId":              "com.jimu.app",
+		"productId":             "premium_monthly",
+		"purchaseDate":          expires.Add(-30 * 24 * time.Hour).UnixMilli(),
+		"expiresDate":           expires.UnixMilli(),
+		"signedDate":            time.Now().UnixMilli(),
+		"environment":           "Production",
+		"type":                  "Auto-Renewable Subscription",
+	}
+}
+
+func TestVerifyTransaction(t *testing.T) {
+	signer := newTestSigner(t)
+	v := newTestVerifier(t, signer)
+
+	expires := time.Now().Add(30 * 24 * time.Hour).Truncate(time.Millisecond)
+	signed, err := signer.Sign(testTransaction(expires))
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	tx, err := v.VerifyTransaction(signed)
+	if err != nil {
+		t.Fatalf("Expected a valid transaction, got %v", err)
+	}
+	if tx.OriginalTransactionID != "2000000000000001" || tx.ProductID != "premium_monthly" {
+		t.Errorf("Unexpected transaction %+v", tx)
+	}
+	if !tx.ExpiresAt().Equal(expires) {
+		t.Errorf("Expected expiry %v, got %v", expires, tx.ExpiresAt())
+	}
+	if tx.Status(time.Now()) != models.SubscriptionActive {
+		t.Errorf("Expected active, got %q", tx.Status(time.Now()))
+	}
+	if !strings.Contains(string(tx.Raw), `"bundleId":"com.jimu.app"`) {
+		t.Errorf("Expected the raw payload to be kept, got %s", tx.Raw)
+	}
+}
+
+func TestVerifyTransaction_Rejects(t *testing.T) {
+	signer := newTestSigner(t)
+	other := newTestSigner(t)
+	expires := time.Now().Add(time.Hour)
+
+	signed, _ := signer.Sign(testTransaction(expires))
+	parts := strings.Split(signed, ".")
+	forged, _ := signer.Sign(map[string]any{"productId": "lifetime"})
+	forgedParts := strings.Split(forged, ".")
+
+	otherBundle := testTransaction(expires)
+	otherBundle["bundleId"] = "com.example.other"
+	signedOtherBundle, _ := signer.Sign(otherBundle)
+
+	sandbox := testTransaction(expires)
+	sandbox["environment"] = "Sandbox"
+	signedSandbox, _ := signer.Sign(sandbox)
+
+	tests := []struct {
+		name    string
+		v       *Verifier
+		signed  string
+		wantErr error
+	}{
+		{"untrusted root", newTestVerifier(t, other), signed, ErrInvalidSignature},
+		{"no roots", &Verifier{BundleID: "com.jimu.app", Environments: []string{EnvironmentProduction}, Now: time.Now}, signed, ErrInvalidSignature},
+		{"tampered payload", newTestVerifier(t, signer), parts[0] + "." + forgedParts[1] + "." + parts[2], ErrInvalidSignature},
+		{"not a JWS", newTestVerifier(t, signer), "garbage", ErrInvalidSignature},
+		{"other app", newTestVerifier(t, signer), signedOtherBundle, ErrWrongBundle},
+		{"sandbox", newTestVerifier(t, signer), signedSandbox, ErrWrongEnvironment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.v.VerifyTransaction(tt.signed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewVerifier_RequiresBundleID(t *testing.T) {
+	if _, err := NewVerifier(newTestSigner(t).Roots(), ""); !errors.Is(err, ErrNoBundleID) {
+		t.Errorf("Expected ErrNoBundleID, got %v", err)
+	}
+}
+
+func TestVerify_ExpiredChain(t *testing.T) {
+	signer := newTestSigner(t)
+	v := newTestVerifier(t, signer)
+	v.Now = func() time.Time { return time.Now().Add(72 * time.Hour) }
+
+	signed, _ := signer.Sign(testTransaction(time.Now().Add(time.Hour)))
+	if _, err := v.Verify(signed); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected an expired chain to be rejected, got %v", err)
+	}
+}
+
+func TestTransactionStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		tx   Transaction
+		want string
+	}{
+		{"active", Transaction{ExpiresDate: now.Add(time.Hour).UnixMilli()}, models.SubscriptionActive},
+		{"expired", Transaction{ExpiresDate: now.Add(-time.Hour).UnixMilli()}, models.SubscriptionExpired},
+		{"revoked", Transaction{ExpiresDate: now.Add(time.Hour).UnixMilli(), RevocationDate: now.UnixMilli()}, models.SubscriptionRevoked},
+	}
+	for _, tt := range tests {
+		if got := tt.tx.Status(now); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestVerifyNotification(t *testing.T) {
+	signer := newTestSigner(t)
+	v := newTestVerifier(t, signer)
+
+	signedTx, _ := signer.Sign(testTransaction(time.Now().Add(-time.Hour)))
+	signed, err := signer.Sign(map[string]any{
+		"notificationType": "EXPIRED",
+		"subtype":          "VOLUNTARY",
+		"notificationUUID": "6f7a1c2e-0000-4000-8000-000000000001",
+		"version":          "2.0",
+		"signedDate":       time.Now().UnixMilli(),
+		"data": map[string]any{
+			"bundleId":              "com.jimu.app",
+			"environment":           "Production",
+			"signedTransactionInfo": signedTx,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+
+	n, err := v.VerifyNotification(signed)
+	if err != nil {
+		t.Fatalf("Expected a valid notification, got %v", err)
+	}
+	if n.Transaction == nil || n.Transaction.OriginalTransactionID != "2000000000000001" {
+		t.Fatalf("Expected the nested transaction to be verified, got %+v", n.Transaction)
+	}
+	if status, ok := n.SubscriptionStatus(time.Now()); !ok || status != models.SubscriptionExpired {
+		t.Errorf("Expected expired, got %q (%v)", status, ok)
+	}
+
+	// A nested transaction from an untrusted chain fails the whole notification
+	forgedTx, _ := newTestSigner(t).Sign(testTransaction(time.Now().Add(time.Hour)))
+	signed, _ = signer.Sign(map[string]any{
+		"notificationType": "DID_RENEW",
+		"notificationUUID": "6f7a1c2e-0000-4000-8000-000000000002",
+		"data":             map[string]any{"bundleId": "com.jimu.app", "environment": "Production", "signedTransactionInfo": forgedTx},
+	})
+	if _, err := v.VerifyNotification(signed); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+
+	// Sandbox notifications are rejected along with sandbox purchases
+	signed, _ = signer.Sign(map[string]any{
+		"notificationType": "TEST",
+		"notificationUUID": "6f7a1c2e-0000-4000-8000-000000000003",
+		"data":             map[string]any{"bundleId": "com.jimu.app", "environment": "Sandbox"},
+	})
+	if _, err := v.VerifyNotification(signed); !errors.Is(err, ErrWrongEnvironment) {
+		t.Errorf("Expected ErrWrongEnvironment, got %v", err)
+	}
+}
+
+func TestNotificationSubscriptionStatus(t *testing.T) {
+	now := time.Now()
+	active := &Transaction{ExpiresDate: now.Add(time.Hour).UnixMilli()}
+
+	tests := []struct {
+		name    string
+		kind    string
+		subtype string
+		status  int
+		tx      *Transaction
+		want    string
+		wantOK  bool
+	}{
+		{"apple status wins", "DID_RENEW", "", statusBillingRetry, active, models.SubscriptionBillingRetry, true},
+		{"renewal", "DID_RENEW", "", 0, active, models.SubscriptionActive, true},
+		{"refund", "REFUND", "", 0, active, models.SubscriptionRevoked, true},
+		{"grace period", "DID_FAIL_TO_RENEW", "GRACE_PERIOD", 0, active, models.SubscriptionGracePeriod, true},
+		{"billing retry", "DID_FAIL_TO_RENEW", "", 0, active, models.SubscriptionBillingRetry, true},
+		{"test notification", "TEST", "", 0, nil, "", false},
+		{"informational", "PRICE_INCREASE", "", 0, active, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Notification{NotificationType: tt.kind, Subtype: tt.subtype, Transaction: tt.tx}
+			n.Data.Status = tt.status
+			got, ok := n.SubscriptionStatus(now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Expected %q (%v), got %q (%v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package appstoretest signs App Store payloads with a throwaway certificate
+// chain shaped like Apple's, for tests that exercise verification.
+package appstoretest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	oidLeafMarker         = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 11, 1}
+	oidIntermediateMarker = asn1.ObjectIdentifier{1, 2, 840, 113635, 100, 6, 2, 1}
+)
+
+// Signer holds a root, an intermediate and a leaf certificate, and signs
+// payloads with the leaf key the way the App Store does.
+type Signer struct {
+	Root         *x509.Certificate
+	Intermediate *x509.Certificate
+	Leaf         *x509.Certificate
+	leafKey      *ecdsa.PrivateKey
+}
+
+// NewSigner creates a fresh chain valid for a day either side of now.
+func NewSigner() (*Signer, error) {
+	rootKey, root, err := newCert("Test Root CA", nil, nil, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	intKey, intermediate, err := newCert("Test WWDR CA", root, rootKey, true, oidIntermediateMarker)
+	if err != nil {
+		return nil, err
+	}
+	leafKey, leaf, err := newCert("Test App Store Signing", intermediate, intKey, false, oidLeafMarker)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{Root: root, Intermediate: intermediate, Leaf: leaf, leafKey: leafKey}, nil
+}
+
+// Roots returns a pool trusting only this signer's root.
+func (s *Signer) Roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Root)
+	return pool
+}
+
+// Sign encodes payload as JSON and returns it as a compact JWS whose x5c
+// header carries the signer's chain.
+func (s *Signer) Sign(payload any) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(map[string]any{
+		"alg": "ES256",
+		"x5c": []string{
+			base64.StdEncoding.EncodeToString(s.Leaf.Raw),
+			base64.StdEncoding.EncodeToString(s.Intermediate.Raw),
+			base64.StdEncoding.EncodeToString(s.Root.Raw),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingString := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	sig, err := jwt.SigningMethodES256.Sign(signingString, s.leafKey)
+	if err != nil {
+		return "", err
+	}
+	return signingString + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func newCert(
+	name string,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+	isCA bool,
+	marker asn1.ObjectIdentifier,
+) (*ecdsa.PrivateKey, *x509.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-24 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+	}
+	if isCA {
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	}
+	if marker != nil {
+		tmpl.ExtraExtensions = []pkix.Extension{{Id: marker, Value: []byte{0x05, 0x00}}}
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, cert, nil
+}
@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxTagLength caps the length of a single mention or hashtag in runes.
+// Longer tokens are ignored rather than truncated.
+const MaxTagLength = 64
+
+// Entities are the @mentions and #hashtags found in a piece of text. Both are
+// lowercased, without their sigil, deduplicated and in order of first appearance.
+type Entities struct {
+	Mentions []string
+	Hashtags []string
+}
+
+// Extract parses mentions and hashtags out of free text such as comment content
+// or a workout caption. A sigil only starts a token at the beginning of the text
+// or after a non-word character, so "a@b.com" and "c#" are not entities.
+// Mentions may contain letters, digits, '_' and '.', but not end in '.';
+// hashtags may contain letters, digits and '_' and must not be all digits.
+func Extract(text string) Entities {
+	var e Entities
+	seenMentions := map[string]bool{}
+	seenHashtags := map[string]bool{}
+
+	prev := ' '
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if (r == '@' || r == '#') && !isWordRune(prev) {
+			token, n := scanToken(text[i+size:], r == '@')
+			if token != "" {
+				token = strings.ToLower(token)
+				if r == '@' && !seenMentions[token] {
+					seenMentions[token] = true
+					e.Mentions = append(e.Mentions, token)
+				}
+				if r == '#' && !isAllDigits(token) && !seenHashtags[token] {
+					seenHashtags[token] = true
+					e.Hashtags = append(e.Hashtags, token)
+				}
+			}
+			i += size + n
+			prev, _ = utf8.DecodeLastRuneInString(text[:i])
+			continue
+		}
+		prev = r
+		i += size
+	}
+
+	return e
+}
+
+// scanToken reads the token following a sigil and returns it along with the
+// number of bytes consumed. It returns an empty token if it is too long.
+func scanToken(s string, mention bool) (string, int) {
+	n := 0
+	runes := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !isWordRune(r) && !(mention && r == '.') {
+			break
+		}
+		n += size
+		runes++
+	}
+
+	// A trailing '.' ends the sentence rather than the username
+	token := s[:n]
+	if mention {
+		token = strings.TrimRight(token, ".")
+	}
+	if runes > MaxTagLength {
+		return "", n
+	}
+	return token, n
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isAllDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
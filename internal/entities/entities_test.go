@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		mentions []string
+		hashtags []string
+	}{
+		{"empty", "", nil, nil},
+		{"plain text", "Great session today", nil, nil},
+		{"mention and hashtag", "Leg day with @Alex #LegDay", []string{"alex"}, []string{"legday"}},
+		{"deduplicated in order", "@bob @alice @Bob #a #b #A", []string{"bob", "alice"}, []string{"a", "b"}},
+		{"trailing punctuation", "Thanks @jo.doe. #pr!", []string{"jo.doe"}, []string{"pr"}},
+		{"email is not a mention", "mail me at a@b.com", nil, nil},
+		{"sigil after word char", "C# and x#y", nil, nil},
+		{"numeric hashtag ignored", "#1 #5x5", nil, []string{"5x5"}},
+		{"unicode hashtag", "#筋トレ done", nil, []string{"筋トレ"}},
+		{"bare sigils", "@ # @. #!", nil, nil},
+		{"too long", "#" + strings.Repeat("a", MaxTagLength+1), nil, nil},
+	}
+	for _, tt := range tests {
+		got := Extract(tt.text)
+		if !reflect.DeepEqual(got.Mentions, tt.mentions) {
+			t.Errorf("%s: mentions = %v, want %v", tt.name, got.Mentions, tt.mentions)
+		}
+		if !reflect.DeepEqual(got.Hashtags, tt.hashtags) {
+			t.Errorf("%s: hashtags = %v, want %v", tt.name, got.Hashtags, tt.hashtags)
+		}
+	}
+}
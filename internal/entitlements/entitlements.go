@@ -0,0 +1,181 @@
+package entitlements
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+// Feature is a capability that depends on the user's plan.
+type Feature string
+
+const (
+	FeatureAdvancedStats     Feature = "advanced_stats"
+	FeatureUnlimitedRoutines Feature = "unlimited_routines"
+)
+
+const (
+	PlanFree    = "free"
+	PlanPremium = "premium"
+)
+
+// FreeRoutineLimit is how many routines a user without
+// FeatureUnlimitedRoutines may keep.
+const FreeRoutineLimit = 3
+
+// ProductPlans maps the store product IDs on sale to the plan each grants.
+// Other products grant nothing. public.subscription_plan lists the same
+// products.
+var ProductPlans = map[string]string{
+	"premium_monthly": PlanPremium,
+	"premium_yearly":  PlanPremium,
+}
+
+var planFeatures = map[string][]Feature{
+	PlanFree:    {},
+	PlanPremium: {FeatureAdvancedStats, FeatureUnlimitedRoutines},
+}
+
+const (
+	// renewalLeeway keeps an active subscription entitled briefly past its
+	// expiry, since the renewal can reach us after the old period ends.
+	renewalLeeway = time.Hour
+	// maxGracePeriod is the longest billing grace period the stores offer.
+	maxGracePeriod = 16 * 24 * time.Hour
+
+	defaultTTL = time.Minute
+	// maxCacheEntries bounds the cache; expired entries are swept past it.
+	maxCacheEntries = 10000
+)
+
+// PlanFor derives the plan a subscription entitles its owner to at now, and
+// until when that holds. A nil subscription, a purchase outside production or
+// an unknown product is the free plan. The rules are mirrored by the
+// public.subscription_plan SQL function.
+func PlanFor(sub *models.Subscription, now time.Time) (plan string, until time.Time) {
+	if sub == nil || sub.Environment != models.EnvironmentProduction {
+		return PlanFree, time.Time{}
+	}
+	plan, ok := ProductPlans[sub.ProductID]
+	if !ok {
+		return PlanFree, time.Time{}
+	}
+
+	var end time.Time
+	switch sub.Status {
+	case models.SubscriptionActive:
+		end = sub.ExpiresAt.Add(renewalLeeway)
+	case models.SubscriptionGracePeriod:
+		end = sub.ExpiresAt.Add(maxGracePeriod)
+	default:
+		return PlanFree, time.Time{}
+	}
+	if !end.After(now) {
+		return PlanFree, time.Time{}
+	}
+	return plan, end
+}
+
+// PlanIncludes reports whether a plan grants a feature.
+func PlanIncludes(plan string, feature Feature) bool {
+	return slices.Contains(planFeatures[plan], feature)
+}
+
+// SubscriptionStore looks up a user's subscription.
+type SubscriptionStore interface {
+	GetSubscriptionByUserID(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) (*models.Subscription, error)
+}
+
+type cachedPlan struct {
+	plan    string
+	expires time.Time
+}
+
+// Service answers entitlement checks, caching each user's plan for TTL. A
+// cached premium plan never outlives the subscription that granted it, and
+// Invalidate drops a user's entry when their subscription changes.
+type Service struct {
+	Store SubscriptionStore
+	TTL   time.Duration
+	Now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedPlan
+}
+
+func NewService(store SubscriptionStore) *Service {
+	return &Service{
+		Store: store,
+		TTL:   defaultTTL,
+		Now:   time.Now,
+		cache: make(map[uuid.UUID]cachedPlan),
+	}
+}
+
+// Plan returns the user's current plan.
+func (s *Service) Plan(ctx context.Context, userID uuid.UUID) (string, error) {
+	now := s.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.plan, nil
+	}
+
+	sub, err := s.Store.GetSubscriptionByUserID(ctx, userID, userID)
+	if err != nil && !errors.Is(err, repository.ErrSubscriptionNotFound) {
+		return "", fmt.Errorf("failed to look up plan: %w", err)
+	}
+
+	plan, until := PlanFor(sub, now)
+	expires := now.Add(s.TTL)
+	if plan != PlanFree && until.Before(expires) {
+		expires = until
+	}
+	s.store(userID, cachedPlan{plan: plan, expires: expires}, now)
+	return plan, nil
+}
+
+// Has reports whether the user's current plan grants feature.
+func (s *Service) Has(ctx context.Context, userID uuid.UUID, feature Feature) (bool, error) {
+	plan, err := s.Plan(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return PlanIncludes(plan, feature), nil
+}
+
+// Invalidate forgets the user's cached plan.
+func (s *Service) Invalidate(userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, userID)
+}
+
+func (s *Service) store(userID uuid.UUID, entry cachedPlan, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cache == nil {
+		s.cache = make(map[uuid.UUID]cachedPlan)
+	}
+	if len(s.cache) >= maxCacheEntries {
+		for id, e := range s.cache {
+			if !now.Before(e.expires) {
+				delete(s.cache, id)
+			}
+		}
+		if len(s.cache) >= maxCacheEntries {
+			clear(s.cache)
+		}
+	}
+	s.cache[userID] = entry
+}
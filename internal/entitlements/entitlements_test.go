@@ -0,0 +1,132 @@
+package entitlements
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+type mockSubscriptionStore struct {
+	Sub   *models.Subscription
+	Err   error
+	Calls int
+}
+
+func (m *mockSubscriptionStore) GetSubscriptionByUserID(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) (*models.Subscription, error) {
+	m.Calls++
+	return m.Sub, m.Err
+}
+
+func TestPlanFor(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		sub       *models.Subscription
+		wantPlan  string
+		wantUntil time.Time
+	}{
+		{"No Subscription", nil, PlanFree, time.Time{}},
+		{"Active", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionActive, ExpiresAt: now.Add(24 * time.Hour)}, PlanPremium, now.Add(25 * time.Hour)},
+		{"Active Within Renewal Leeway", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionActive, ExpiresAt: now.Add(-30 * time.Minute)}, PlanPremium, now.Add(30 * time.Minute)},
+		{"Active Past Leeway", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionActive, ExpiresAt: now.Add(-2 * time.Hour)}, PlanFree, time.Time{}},
+		{"Grace Period", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionGracePeriod, ExpiresAt: now.Add(-3 * 24 * time.Hour)}, PlanPremium, now.Add(13 * 24 * time.Hour)},
+		{"Grace Period Over", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionGracePeriod, ExpiresAt: now.Add(-17 * 24 * time.Hour)}, PlanFree, time.Time{}},
+		{"Billing Retry", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionBillingRetry, ExpiresAt: now.Add(time.Hour)}, PlanFree, time.Time{}},
+		{"Expired", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionExpired, ExpiresAt: now.Add(time.Hour)}, PlanFree, time.Time{}},
+		{"Revoked", &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionRevoked, ExpiresAt: now.Add(time.Hour)}, PlanFree, time.Time{}},
+		{"Sandbox", &models.Subscription{ProductID: "premium_monthly", Environment: "sandbox", Status: models.SubscriptionActive, ExpiresAt: now.Add(24 * time.Hour)}, PlanFree, time.Time{}},
+		{"Unknown Product", &models.Subscription{ProductID: "lifetime", Environment: models.EnvironmentProduction, Status: models.SubscriptionActive, ExpiresAt: now.Add(24 * time.Hour)}, PlanFree, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, until := PlanFor(tt.sub, now)
+			if plan != tt.wantPlan || !until.Equal(tt.wantUntil) {
+				t.Errorf("got (%s, %v), want (%s, %v)", plan, until, tt.wantPlan, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestPlanIncludes(t *testing.T) {
+	if PlanIncludes(PlanFree, FeatureAdvancedStats) {
+		t.Error("free plan should not include advanced stats")
+	}
+	if !PlanIncludes(PlanPremium, FeatureAdvancedStats) || !PlanIncludes(PlanPremium, FeatureUnlimitedRoutines) {
+		t.Error("premium plan should include every feature")
+	}
+	if PlanIncludes("enterprise", FeatureAdvancedStats) {
+		t.Error("unknown plans should include nothing")
+	}
+}
+
+func TestService_Has(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		store   *mockSubscriptionStore
+		want    bool
+		wantErr bool
+	}{
+		{"Premium", &mockSubscriptionStore{Sub: &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionActive, ExpiresAt: now.Add(time.Hour)}}, true, false},
+		{"Not Found", &mockSubscriptionStore{Err: repository.ErrSubscriptionNotFound}, false, false},
+		{"Store Error", &mockSubscriptionStore{Err: errors.New("db down")}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(tt.store)
+			got, err := s.Has(context.Background(), userID, FeatureAdvancedStats)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_Cache(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	store := &mockSubscriptionStore{Err: repository.ErrSubscriptionNotFound}
+	s := NewService(store)
+	s.Now = func() time.Time { return now }
+
+	// 1. Lookups within the TTL hit the cache
+	for range 3 {
+		if _, err := s.Plan(context.Background(), userID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if store.Calls != 1 {
+		t.Fatalf("expected 1 store call, got %d", store.Calls)
+	}
+
+	// 2. Invalidate picks up a new subscription immediately
+	store.Sub, store.Err = &models.Subscription{ProductID: "premium_monthly", Environment: models.EnvironmentProduction, Status: models.SubscriptionActive, ExpiresAt: now.Add(10*time.Second - renewalLeeway)}, nil
+	s.Invalidate(userID)
+	if plan, _ := s.Plan(context.Background(), userID); plan != PlanPremium {
+		t.Fatalf("expected premium after invalidation, got %s", plan)
+	}
+
+	// 3. A cached premium plan expires with the subscription, before the TTL
+	store.Sub = nil
+	store.Err = repository.ErrSubscriptionNotFound
+	now = now.Add(11 * time.Second)
+	if plan, _ := s.Plan(context.Background(), userID); plan != PlanFree {
+		t.Errorf("expected free once the subscription lapsed, got %s", plan)
+	}
+	if store.Calls != 3 {
+		t.Errorf("expected 3 store calls, got %d", store.Calls)
+	}
+}
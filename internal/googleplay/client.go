@@ -0,0 +1,131 @@
+package googleplay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	apiEndpoint  = "https://androidpublisher.googleapis.com"
+	androidScope = "https://www.googleapis.com/auth/androidpublisher"
+)
+
+// Client reads and acknowledges subscription purchases through the Google
+// Play Developer API. A Client without a PackageName rejects every call with
+// ErrNotConfigured. License testers' purchases are rejected with
+// ErrTestPurchase unless AllowTestPurchases is set.
+type Client struct {
+	PackageName string
+	// HTTP must attach OAuth2 credentials to each request
+	HTTP               *http.Client
+	Endpoint           string
+	AllowTestPurchases bool
+}
+
+// NewClient authenticates with Application Default Credentials. The service
+// account must be granted access to the app in the Play Console.
+func NewClient(ctx context.Context, packageName string) (*Client, error) {
+	creds, err := google.FindDefaultCredentials(ctx, androidScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Google Play credentials: %w", err)
+	}
+	return &Client{
+		PackageName: packageName,
+		HTTP:        oauth2.NewClient(ctx, creds.TokenSource),
+		Endpoint:    apiEndpoint,
+	}, nil
+}
+
+// GetSubscription fetches the current state of a subscription purchase.
+func (c *Client) GetSubscription(ctx context.Context, purchaseToken string) (*SubscriptionPurchase, error) {
+	if c.PackageName == "" {
+		return nil, ErrNotConfigured
+	}
+	if purchaseToken == "" {
+		return nil, ErrInvalidPurchase
+	}
+
+	u := fmt.Sprintf("%s/androidpublisher/v3/applications/%s/purchases/subscriptionsv2/tokens/%s",
+		c.Endpoint, url.PathEscape(c.PackageName), url.PathEscape(purchaseToken))
+	body, err := c.do(ctx, http.MethodGet, u)
+	if err != nil {
+		return nil, err
+	}
+
+	var p SubscriptionPurchase
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode Google Play purchase: %w", err)
+	}
+	if _, ok := p.LineItem(); !ok {
+		return nil, fmt.Errorf("%w: purchase has no line items", ErrInvalidPurchase)
+	}
+	if p.TestPurchase != nil && !c.AllowTestPurchases {
+		return nil, ErrTestPurchase
+	}
+	p.PurchaseToken = purchaseToken
+	p.Raw = body
+	return &p, nil
+}
+
+// Acknowledge confirms a subscription purchase so Google does not refund it.
+func (c *Client) Acknowledge(ctx context.Context, productID string, purchaseToken string) error {
+	if c.PackageName == "" {
+		return ErrNotConfigured
+	}
+
+	u := fmt.Sprintf("%s/androidpublisher/v3/applications/%s/purchases/subscriptions/%s/tokens/%s:acknowledge",
+		c.Endpoint, url.PathEscape(c.PackageName), url.PathEscape(productID), url.PathEscape(purchaseToken))
+	_, err := c.do(ctx, http.MethodPost, u)
+	return err
+}
+
+func (c *Client) do(ctx context.Context, method string, u string) ([]byte, error) {
+	var body io.Reader
+	if method == http.MethodPost {
+		body = strings.NewReader("{}")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Google Play request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Google Play: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google Play response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent:
+		return data, nil
+	// Unknown, malformed and expired-long-ago tokens
+	case resp.StatusCode == http.StatusBadRequest,
+		resp.StatusCode == http.StatusNotFound,
+		resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%w: Google Play returned %d", ErrInvalidPurchase, resp.StatusCode)
+	default:
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(data, &apiErr)
+		return nil, fmt.Errorf("Google Play returned %d: %s", resp.StatusCode, apiErr.Error.Message)
+	}
+}
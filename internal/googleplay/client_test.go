@@ -0,0 +1,101 @@
+package googleplay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(handler http.HandlerFunc) (*Client, func()) {
+	srv := httptest.NewServer(handler)
+	return &Client{PackageName: "app.jimu", HTTP: srv.Client(), Endpoint: srv.URL}, srv.Close
+}
+
+func TestClient_GetSubscription(t *testing.T) {
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/androidpublisher/v3/applications/app.jimu/purchases/subscriptionsv2/tokens/play-token" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"subscriptionState": "SUBSCRIPTION_STATE_ACTIVE",
+			"latestOrderId": "GPA.1234",
+			"acknowledgementState": "ACKNOWLEDGEMENT_STATE_PENDING",
+			"lineItems": [{"productId": "premium_monthly", "expiryTime": "2026-11-18T12:00:00.000Z"}],
+			"externalAccountIdentifiers": {"obfuscatedExternalAccountId": "user-1"}
+		}`))
+	})
+	defer done()
+
+	p, err := c.GetSubscription(context.Background(), "play-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.PurchaseToken != "play-token" || p.ProductID() != "premium_monthly" || !p.NeedsAcknowledgement() {
+		t.Errorf("unexpected purchase: %+v", p)
+	}
+	if p.ExternalAccountIdentifiers.ObfuscatedExternalAccountID != "user-1" || len(p.Raw) == 0 {
+		t.Errorf("unexpected purchase: %+v", p)
+	}
+
+	if _, err := c.GetSubscription(context.Background(), "unknown"); !errors.Is(err, ErrInvalidPurchase) {
+		t.Errorf("expected ErrInvalidPurchase for an unknown token, got %v", err)
+	}
+}
+
+func TestClient_GetSubscription_TestPurchase(t *testing.T) {
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"subscriptionState": "SUBSCRIPTION_STATE_ACTIVE",
+			"lineItems": [{"productId": "premium_monthly", "expiryTime": "2026-11-18T12:00:00.000Z"}],
+			"testPurchase": {}
+		}`))
+	})
+	defer done()
+
+	_, err := c.GetSubscription(context.Background(), "tester-token")
+	if !errors.Is(err, ErrTestPurchase) || !errors.Is(err, ErrInvalidPurchase) {
+		t.Errorf("expected a license tester's purchase to be invalid, got %v", err)
+	}
+
+	c.AllowTestPurchases = true
+	p, err := c.GetSubscription(context.Background(), "tester-token")
+	if err != nil || p.Environment() != "sandbox" {
+		t.Errorf("expected an allowed test purchase, got %+v, %v", p, err)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"message": "backend error"}}`, http.StatusServiceUnavailable)
+	})
+	defer done()
+
+	_, err := c.GetSubscription(context.Background(), "play-token")
+	if err == nil || errors.Is(err, ErrInvalidPurchase) {
+		t.Errorf("expected a retryable error, got %v", err)
+	}
+
+	unconfigured := &Client{}
+	if _, err := unconfigured.GetSubscription(context.Background(), "play-token"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("expected ErrNotConfigured, got %v", err)
+	}
+}
+
+func TestClient_Acknowledge(t *testing.T) {
+	var gotPath string
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.Method + " " + r.URL.Path
+	})
+	defer done()
+
+	if err := c.Acknowledge(context.Background(), "premium_monthly", "play-token"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "POST /androidpublisher/v3/applications/app.jimu/purchases/subscriptions/premium_monthly/tokens/play-token:acknowledge"
+	if gotPath != want {
+		t.Errorf("got %s, want %s", gotPath, want)
+	}
+}
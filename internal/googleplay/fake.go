@@ -0,0 +1,65 @@
+package googleplay
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// FakeClient serves purchases from memory instead of Google Play, for tests
+// only. Unknown tokens are invalid, and test purchases are rejected as by
+// Client.
+type FakeClient struct {
+	mu           sync.Mutex
+	Purchases    map[string]*SubscriptionPurchase
+	Acknowledged []string
+
+	AllowTestPurchases bool
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		Purchases: make(map[string]*SubscriptionPurchase),
+	}
+}
+
+// SetPurchase makes GetSubscription return p for purchaseToken.
+func (f *FakeClient) SetPurchase(purchaseToken string, p *SubscriptionPurchase) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Purchases == nil {
+		f.Purchases = make(map[string]*SubscriptionPurchase)
+	}
+	f.Purchases[purchaseToken] = p
+}
+
+func (f *FakeClient) GetSubscription(ctx context.Context, purchaseToken string) (*SubscriptionPurchase, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.Purchases[purchaseToken]
+	if !ok {
+		return nil, ErrInvalidPurchase
+	}
+	if stored.TestPurchase != nil && !f.AllowTestPurchases {
+		return nil, ErrTestPurchase
+	}
+
+	p := *stored
+	p.PurchaseToken = purchaseToken
+	p.Raw, _ = json.Marshal(stored)
+	return &p, nil
+}
+
+func (f *FakeClient) Acknowledge(ctx context.Context, productID string, purchaseToken string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.Purchases[purchaseToken]
+	if !ok {
+		return ErrInvalidPurchase
+	}
+	p.AcknowledgementState = AcknowledgementAcknowledged
+	f.Acknowledged = append(f.Acknowledged, purchaseToken)
+	return nil
+}
@@ -0,0 +1,292 @@
+package googleplay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+var (
+	// ErrInvalidPurchase means Google Play does not know the purchase token,
+	// or no longer considers it valid.
+	ErrInvalidPurchase = errors.New("invalid Google Play purchase")
+	// ErrNotConfigured means no Google Play package is configured.
+	ErrNotConfigured = errors.New("Google Play billing is not configured")
+	// ErrTestPurchase means the purchase was made by a license tester, who
+	// is not charged for it.
+	ErrTestPurchase = fmt.Errorf("%w: license tester purchase", ErrInvalidPurchase)
+)
+
+// Subscription states reported by the Play Developer API.
+const (
+	StatePending                 = "SUBSCRIPTION_STATE_PENDING"
+	StateActive                  = "SUBSCRIPTION_STATE_ACTIVE"
+	StatePaused                  = "SUBSCRIPTION_STATE_PAUSED"
+	StateInGracePeriod           = "SUBSCRIPTION_STATE_IN_GRACE_PERIOD"
+	StateOnHold                  = "SUBSCRIPTION_STATE_ON_HOLD"
+	StateCanceled                = "SUBSCRIPTION_STATE_CANCELED"
+	StateExpired                 = "SUBSCRIPTION_STATE_EXPIRED"
+	StatePendingPurchaseCanceled = "SUBSCRIPTION_STATE_PENDING_PURCHASE_CANCELED"
+
+	AcknowledgementPending      = "ACKNOWLEDGEMENT_STATE_PENDING"
+	AcknowledgementAcknowledged = "ACKNOWLEDGEMENT_STATE_ACKNOWLEDGED"
+)
+
+// LineItem is one product within a subscription purchase.
+type LineItem struct {
+	ProductID    string    `json:"productId"`
+	ExpiryTime   time.Time `json:"expiryTime"`
+	OfferDetails struct {
+		BasePlanID string `json:"basePlanId"`
+		OfferID    string `json:"offerId,omitempty"`
+	} `json:"offerDetails"`
+}
+
+// SubscriptionPurchase is a subscription as returned by the Play Developer
+// API (purchases.subscriptionsv2). Google Play does not sign it; it is
+// trusted because it was fetched from Google with the app's credentials.
+type SubscriptionPurchase struct {
+	SubscriptionState          string     `json:"subscriptionState"`
+	LatestOrderID              string     `json:"latestOrderId"`
+	LinkedPurchaseToken        string     `json:"linkedPurchaseToken,omitempty"`
+	AcknowledgementState       string     `json:"acknowledgementState"`
+	StartTime                  time.Time  `json:"startTime"`
+	LineItems                  []LineItem `json:"lineItems"`
+	ExternalAccountIdentifiers struct {
+		ObfuscatedExternalAccountID string `json:"obfuscatedExternalAccountId,omitempty"`
+	} `json:"externalAccountIdentifiers"`
+	// TestPurchase is set for license testers' purchases
+	TestPurchase *struct{} `json:"testPurchase,omitempty"`
+
+	// PurchaseToken is the token the purchase was fetched with
+	PurchaseToken string          `json:"-"`
+	Raw           json.RawMessage `json:"-"`
+}
+
+// LineItem returns the line item that expires last, which is the one
+// granting access. ok is false for a purchase without line items.
+func (p *SubscriptionPurchase) LineItem() (LineItem, bool) {
+	if len(p.LineItems) == 0 {
+		return LineItem{}, false
+	}
+	latest := p.LineItems[0]
+	for _, item := range p.LineItems[1:] {
+		if item.ExpiryTime.After(latest.ExpiryTime) {
+			latest = item
+		}
+	}
+	return latest, true
+}
+
+func (p *SubscriptionPurchase) ProductID() string {
+	item, _ := p.LineItem()
+	return item.ProductID
+}
+
+func (p *SubscriptionPurchase) ExpiresAt() time.Time {
+	item, _ := p.LineItem()
+	return item.ExpiryTime.UTC()
+}
+
+// Environment mirrors the App Store's naming: license testers buy in the
+// sandbox.
+func (p *SubscriptionPurchase) Environment() string {
+	if p.TestPurchase != nil {
+		return "sandbox"
+	}
+	return models.EnvironmentProduction
+}
+
+// NeedsAcknowledgement reports whether the purchase is paid for but not yet
+// acknowledged. Google refunds purchases left unacknowledged for three days.
+func (p *SubscriptionPurchase) NeedsAcknowledgement() bool {
+	if p.AcknowledgementState != AcknowledgementPending {
+		return false
+	}
+	switch p.SubscriptionState {
+	case StateActive, StateInGracePeriod:
+		return true
+	}
+	return false
+}
+
+// Status derives the subscription status as of now. A canceled subscription
+// only stops renewing, so it stays active until it expires; a pending one
+// has not been paid for yet.
+func (p *SubscriptionPurchase) Status(now time.Time) string {
+	switch p.SubscriptionState {
+	case StateActive:
+		return models.SubscriptionActive
+	case StateInGracePeriod:
+		return models.SubscriptionGracePeriod
+	case StateOnHold:
+		return models.SubscriptionBillingRetry
+	case StateCanceled:
+		if p.ExpiresAt().After(now) {
+			return models.SubscriptionActive
+		}
+		return models.SubscriptionExpired
+	default:
+		return models.SubscriptionExpired
+	}
+}
+
+// VerifiedSubscription converts the purchase, fetched at now, into the
+// store-agnostic subscription state. Fetches are ordered by when they were
+// made, since each returns the purchase's current state.
+func (p *SubscriptionPurchase) VerifiedSubscription(now time.Time) models.VerifiedSubscription {
+	return models.VerifiedSubscription{
+		Store:                 models.StoreGooglePlay,
+		OriginalTransactionID: p.PurchaseToken,
+		LinkedTransactionID:   p.LinkedPurchaseToken,
+		TransactionID:         p.LatestOrderID,
+		ProductID:             p.ProductID(),
+		Status:                p.Status(now),
+		ExpiresAt:             p.ExpiresAt(),
+		Environment:           p.Environment(),
+		SignedAt:              now,
+		Payload:               p.Raw,
+	}
+}
+
+// Subscription notification types sent through Real-time Developer
+// Notifications.
+const (
+	NotificationRecovered               = 1
+	NotificationRenewed                 = 2
+	NotificationCanceled                = 3
+	NotificationPurchased               = 4
+	NotificationOnHold                  = 5
+	NotificationInGracePeriod           = 6
+	NotificationRestarted               = 7
+	NotificationPriceChangeConfirmed    = 8
+	NotificationDeferred                = 9
+	NotificationPaused                  = 10
+	NotificationPauseScheduleChanged    = 11
+	NotificationRevoked                 = 12
+	NotificationExpired                 = 13
+	NotificationPendingPurchaseCanceled = 20
+)
+
+var notificationNames = map[int]string{
+	NotificationRecovered:               "SUBSCRIPTION_RECOVERED",
+	NotificationRenewed:                 "SUBSCRIPTION_RENEWED",
+	NotificationCanceled:                "SUBSCRIPTION_CANCELED",
+	NotificationPurchased:               "SUBSCRIPTION_PURCHASED",
+	NotificationOnHold:                  "SUBSCRIPTION_ON_HOLD",
+	NotificationInGracePeriod:           "SUBSCRIPTION_IN_GRACE_PERIOD",
+	NotificationRestarted:               "SUBSCRIPTION_RESTARTED",
+	NotificationPriceChangeConfirmed:    "SUBSCRIPTION_PRICE_CHANGE_CONFIRMED",
+	NotificationDeferred:                "SUBSCRIPTION_DEFERRED",
+	NotificationPaused:                  "SUBSCRIPTION_PAUSED",
+	NotificationPauseScheduleChanged:    "SUBSCRIPTION_PAUSE_SCHEDULE_CHANGED",
+	NotificationRevoked:                 "SUBSCRIPTION_REVOKED",
+	NotificationExpired:                 "SUBSCRIPTION_EXPIRED",
+	NotificationPendingPurchaseCanceled: "SUBSCRIPTION_PENDING_PURCHASE_CANCELED",
+}
+
+// voidedSubscription is the productType of a voided subscription purchase.
+const voidedSubscription = 1
+
+// DeveloperNotification is a Real-time Developer Notification. Exactly one
+// of its notification fields is set.
+type DeveloperNotification struct {
+	Version                  string `json:"version"`
+	PackageName              string `json:"packageName"`
+	EventTimeMillis          string `json:"eventTimeMillis"`
+	SubscriptionNotification *struct {
+		NotificationType int    `json:"notificationType"`
+		PurchaseToken    string `json:"purchaseToken"`
+		SubscriptionID   string `json:"subscriptionId"`
+	} `json:"subscriptionNotification,omitempty"`
+	VoidedPurchaseNotification *struct {
+		PurchaseToken string `json:"purchaseToken"`
+		OrderID       string `json:"orderId"`
+		ProductType   int    `json:"productType"`
+	} `json:"voidedPurchaseNotification,omitempty"`
+	TestNotification *struct{} `json:"testNotification,omitempty"`
+
+	// MessageID is the Pub/Sub message ID, unique per notification
+	MessageID string          `json:"-"`
+	Raw       json.RawMessage `json:"-"`
+}
+
+// EventTime is when Google generated the notification.
+func (n *DeveloperNotification) EventTime() time.Time {
+	var ms int64
+	fmt.Sscan(n.EventTimeMillis, &ms)
+	return time.UnixMilli(ms).UTC()
+}
+
+// Type names the notification for the event history.
+func (n *DeveloperNotification) Type() string {
+	switch {
+	case n.SubscriptionNotification != nil:
+		if name, ok := notificationNames[n.SubscriptionNotification.NotificationType]; ok {
+			return name
+		}
+		return fmt.Sprintf("SUBSCRIPTION_%d", n.SubscriptionNotification.NotificationType)
+	case n.VoidedPurchaseNotification != nil:
+		return "VOIDED_PURCHASE"
+	case n.TestNotification != nil:
+		return "TEST"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// PurchaseToken is the subscription purchase the notification is about,
+// empty for notifications about anything else.
+func (n *DeveloperNotification) PurchaseToken() string {
+	switch {
+	case n.SubscriptionNotification != nil:
+		return n.SubscriptionNotification.PurchaseToken
+	case n.VoidedPurchaseNotification != nil && n.VoidedPurchaseNotification.ProductType == voidedSubscription:
+		return n.VoidedPurchaseNotification.PurchaseToken
+	default:
+		return ""
+	}
+}
+
+// Revokes reports whether the notification takes access away immediately,
+// which the purchase's own state does not distinguish from expiry.
+func (n *DeveloperNotification) Revokes() bool {
+	if n.VoidedPurchaseNotification != nil {
+		return n.VoidedPurchaseNotification.ProductType == voidedSubscription
+	}
+	return n.SubscriptionNotification != nil &&
+		n.SubscriptionNotification.NotificationType == NotificationRevoked
+}
+
+// ParsePushMessage decodes the body of a Pub/Sub push request carrying a
+// developer notification.
+func ParsePushMessage(body []byte) (*DeveloperNotification, error) {
+	var envelope struct {
+		Message struct {
+			Data      string `json:"data"`
+			MessageID string `json:"messageId"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("malformed push message: %w", err)
+	}
+	if envelope.Message.MessageID == "" {
+		return nil, errors.New("push message is missing its ID")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(envelope.Message.Data)
+	if err != nil {
+		return nil, fmt.Errorf("malformed push message data: %w", err)
+	}
+	var n DeveloperNotification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("malformed developer notification: %w", err)
+	}
+	n.MessageID = envelope.Message.MessageID
+	n.Raw = data
+	return &n, nil
+}
@@ -0,0 +1,99 @@
+package googleplay
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+func TestSubscriptionPurchase_Status(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		state   string
+		expires time.Time
+		want    string
+	}{
+		{StateActive, now.Add(time.Hour), models.SubscriptionActive},
+		{StateInGracePeriod, now.Add(-time.Hour), models.SubscriptionGracePeriod},
+		{StateOnHold, now.Add(-time.Hour), models.SubscriptionBillingRetry},
+		{StateCanceled, now.Add(time.Hour), models.SubscriptionActive},
+		{StateCanceled, now.Add(-time.Hour), models.SubscriptionExpired},
+		{StatePaused, now.Add(time.Hour), models.SubscriptionExpired},
+		{StatePending, now.Add(time.Hour), models.SubscriptionExpired},
+		{StateExpired, now.Add(-time.Hour), models.SubscriptionExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			p := &SubscriptionPurchase{
+				SubscriptionState: tt.state,
+				LineItems:         []LineItem{{ProductID: "premium_monthly", ExpiryTime: tt.expires}},
+			}
+			if got := p.Status(now); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionPurchase_VerifiedSubscription(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	p := &SubscriptionPurchase{
+		SubscriptionState:   StateActive,
+		LatestOrderID:       "GPA.1234..2",
+		LinkedPurchaseToken: "old-token",
+		LineItems: []LineItem{
+			{ProductID: "premium_monthly", ExpiryTime: now.Add(time.Hour)},
+			{ProductID: "premium_yearly", ExpiryTime: now.Add(365 * 24 * time.Hour)},
+		},
+		TestPurchase:  &struct{}{},
+		PurchaseToken: "new-token",
+	}
+
+	v := p.VerifiedSubscription(now)
+
+	if v.Store != models.StoreGooglePlay || v.OriginalTransactionID != "new-token" || v.LinkedTransactionID != "old-token" {
+		t.Errorf("unexpected identifiers: %+v", v)
+	}
+	if v.ProductID != "premium_yearly" || !v.ExpiresAt.Equal(now.Add(365*24*time.Hour)) {
+		t.Errorf("expected the latest line item, got %s expiring %v", v.ProductID, v.ExpiresAt)
+	}
+	if v.Environment != "sandbox" || !v.SignedAt.Equal(now) {
+		t.Errorf("unexpected environment or signed time: %+v", v)
+	}
+}
+
+func TestParsePushMessage(t *testing.T) {
+	data := `{"version":"1.0","packageName":"app.jimu","eventTimeMillis":"1760000000000",` +
+		`"voidedPurchaseNotification":{"purchaseToken":"play-token","orderId":"GPA.1","productType":1}}`
+	body, _ := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"data":      base64.StdEncoding.EncodeToString([]byte(data)),
+			"messageId": "msg-1",
+		},
+	})
+
+	n, err := ParsePushMessage(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n.MessageID != "msg-1" || n.Type() != "VOIDED_PURCHASE" || n.PurchaseToken() != "play-token" || !n.Revokes() {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+	if !n.EventTime().Equal(time.UnixMilli(1760000000000)) {
+		t.Errorf("unexpected event time: %v", n.EventTime())
+	}
+	if string(n.Raw) != data {
+		t.Errorf("expected the decoded data to be kept, got %s", n.Raw)
+	}
+
+	for _, bad := range []string{`not json`, `{"message": {"data": "e30="}}`, `{"message": {"data": "%%%", "messageId": "1"}}`} {
+		if _, err := ParsePushMessage([]byte(bad)); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
@@ -0,0 +1,801 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+type AdminScanner interface {
+	SearchUsers(ctx context.Context, query string, cursor *pagination.Cursor, limit int) ([]*models.AdminUser, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*models.AdminUser, error)
+	SuspendUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, until time.Time, reason *string) error
+	UnsuspendUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error
+	BanUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, reason *string) error
+	UnbanUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error
+	RevokeUserSessions(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) (int64, error)
+	RemoveComment(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error
+	RemoveWorkout(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error
+	RemoveWorkoutImage(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error
+	GetSystemExercises(ctx context.Context) ([]*models.Exercise, error)
+	CreateSystemExercise(ctx context.Context, adminID uuid.UUID, req models.CreateSystemExerciseRequest) (*models.Exercise, error)
+	UpdateSystemExercise(ctx context.Context, adminID uuid.UUID, id uuid.UUID, updates models.UpdateExerciseRequest) (*models.Exercise, error)
+	DeleteSystemExercise(ctx context.Context, adminID uuid.UUID, id uuid.UUID) error
+	GetAuditLog(ctx context.Context, filter models.AuditLogFilter, cursor *pagination.Cursor, limit int) ([]*models.AuditLogEntry, error)
+	GetReports(ctx context.Context, filter models.ReportFilter, cursor *pagination.Cursor, limit int) ([]*models.QueuedReport, error)
+	ResolveReport(ctx context.Context, adminID uuid.UUID, reportID uuid.UUID, req models.ResolveReportRequest) (int64, error)
+	UnhideContent(ctx context.Context, adminID uuid.UUID, targetType string, id uuid.UUID) error
+}
+
+// AdminHandler serves the /admin routes. The router only reaches it through
+// middleware.RequireAdmin, and the repository re-checks the caller as it
+// writes each action to the audit log.
+type AdminHandler struct {
+	Repo AdminScanner
+}
+
+func NewAdminHandler(r AdminScanner) *AdminHandler {
+	return &AdminHandler{Repo: r}
+}
+
+// SearchUsers lists accounts matching ?q= (username, display name or
+// email), newest first.
+func (h *AdminHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	if _, err := uuid.Parse(ctxID); err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Query Params
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	users, err := h.Repo.SearchUsers(r.Context(), r.URL.Query().Get("q"), cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Search users error: %v", err)
+		http.Error(w, "Failed to search users", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(users, limit, adminUserCursor))
+}
+
+func adminUserCursor(u *models.AdminUser) pagination.Cursor {
+	return pagination.At(u.CreatedAt, u.ID)
+}
+
+func (h *AdminHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	if _, err := uuid.Parse(ctxID); err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction
+	// Path: /admin/users/{id}
+	userID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	user, err := h.Repo.GetUser(r.Context(), userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrProfileNotFound) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Get user error: %v", err)
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// SuspendUser blocks a user from signing in until the given time and signs
+// them out of every device.
+func (h *AdminHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /admin/users/{id}/suspension
+	userID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+	if userID == adminID {
+		http.Error(w, "Cannot suspend yourself", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SuspendUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !req.Until.After(time.Now()) {
+		http.Error(w, "until must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.SuspendUser(r.Context(), adminID, userID, req.Until, req.Reason)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "User not found", "Suspend user", "Failed to suspend user")
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction
+	// Path: /admin/users/{id}/suspension
+	userID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.UnsuspendUser(r.Context(), adminID, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "User not found", "Unsuspend user", "Failed to unsuspend user")
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BanUser disables a user's account until it is unbanned, hiding their
+// content and signing them out of every device.
+func (h *AdminHandler) BanUser(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /admin/users/{id}/ban
+	userID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+	if userID == adminID {
+		http.Error(w, "Cannot ban yourself", http.StatusBadRequest)
+		return
+	}
+
+	var req models.BanUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.BanUser(r.Context(), adminID, userID, req.Reason)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "User not found", "Ban user", "Failed to ban user")
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnbanUser restores a banned account and brings its content back.
+func (h *AdminHandler) UnbanUser(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction
+	// Path: /admin/users/{id}/ban
+	userID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.UnbanUser(r.Context(), adminID, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "User not found", "Unban user", "Failed to unban user")
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeUserSessions signs a user out of every device.
+func (h *AdminHandler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction
+	// Path: /admin/users/{id}/sessions
+	userID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	revoked, err := h.Repo.RevokeUserSessions(r.Context(), adminID, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "User not found", "Revoke sessions", "Failed to revoke sessions")
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.RevokeSessionsResponse{SessionsRevoked: revoked})
+}
+
+// RemoveComment takes down a comment. An optional ?reason= is kept in the
+// audit log.
+func (h *AdminHandler) RemoveComment(w http.ResponseWriter, r *http.Request) {
+	h.removeContent(w, r, h.Repo.RemoveComment, "comment", "Comment not found")
+}
+
+// RemoveWorkout takes down a workout. An optional ?reason= is kept in the
+// audit log.
+func (h *AdminHandler) RemoveWorkout(w http.ResponseWriter, r *http.Request) {
+	h.removeContent(w, r, h.Repo.RemoveWorkout, "workout", "Workout not found")
+}
+
+// RemoveWorkoutImage takes down a workout image. An optional ?reason= is
+// kept in the audit log.
+func (h *AdminHandler) RemoveWorkoutImage(w http.ResponseWriter, r *http.Request) {
+	h.removeContent(w, r, h.Repo.RemoveWorkoutImage, "workout image", "Workout image not found")
+}
+
+// removeContent runs a takedown of the item named by the last path segment.
+func (h *AdminHandler) removeContent(
+	w http.ResponseWriter,
+	r *http.Request,
+	remove func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error,
+	kind string,
+	notFound string,
+) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	id, err := GetIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid or missing "+kind+" ID", http.StatusBadRequest)
+		return
+	}
+	var reason *string
+	if s := strings.TrimSpace(r.URL.Query().Get("reason")); s != "" {
+		reason = &s
+	}
+
+	// 3. Repo Call
+	err = remove(r.Context(), adminID, id, reason)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, notFound, "Remove "+kind, "Failed to remove "+kind)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSystemExercises lists the global exercise library.
+func (h *AdminHandler) ListSystemExercises(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	if _, err := uuid.Parse(ctxID); err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Repo Call
+	exercises, err := h.Repo.GetSystemExercises(r.Context())
+
+	// 3. Error Mapping
+	if err != nil {
+		log.Printf("List system exercises error: %v", err)
+		http.Error(w, "Failed to list exercises", http.StatusInternalServerError)
+		return
+	}
+
+	// 4. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	if exercises == nil {
+		exercises = []*models.Exercise{}
+	}
+	json.NewEncoder(w).Encode(exercises)
+}
+
+func (h *AdminHandler) CreateSystemExercise(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	var req models.CreateSystemExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	exercise, err := h.Repo.CreateSystemExercise(r.Context(), adminID, req)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "Exercise not found", "Create system exercise", "Failed to create exercise")
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(exercise)
+}
+
+func (h *AdminHandler) UpdateSystemExercise(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	exerciseID, err := GetIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid or missing exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateExerciseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		http.Error(w, "Name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	exercise, err := h.Repo.UpdateSystemExercise(r.Context(), adminID, exerciseID, req)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, "Exercise not found", "Update system exercise", "Failed to update exercise")
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(exercise)
+}
+
+func (h *AdminHandler) DeleteSystemExercise(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction
+	exerciseID, err := GetIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid or missing exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.DeleteSystemExercise(r.Context(), adminID, exerciseID)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrReferenceViolation) {
+			http.Error(w, "Exercise is in use", http.StatusConflict)
+			return
+		}
+		writeAdminError(w, err, "Exercise not found", "Delete system exercise", "Failed to delete exercise")
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAuditLog lists admin actions, newest first. admin_id, target_type and
+// target_id narrow the list.
+func (h *AdminHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	if _, err := uuid.Parse(ctxID); err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Query Params
+	var filter models.AuditLogFilter
+	q := r.URL.Query()
+	if s := q.Get("admin_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			http.Error(w, "Invalid admin ID", http.StatusBadRequest)
+			return
+		}
+		filter.AdminID = &id
+	}
+	if s := q.Get("target_type"); s != "" {
+		filter.TargetType = &s
+	}
+	if s := q.Get("target_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			http.Error(w, "Invalid target ID", http.StatusBadRequest)
+			return
+		}
+		filter.TargetID = &id
+	}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	entries, err := h.Repo.GetAuditLog(r.Context(), filter, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get audit log error: %v", err)
+		http.Error(w, "Failed to get audit log", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(entries, limit, auditLogCursor))
+}
+
+func auditLogCursor(e *models.AuditLogEntry) pagination.Cursor {
+	return pagination.At(e.CreatedAt, e.ID)
+}
+
+// ListReports is the moderation queue, newest first. ?status= and
+// ?target_type= narrow it; open reports are shown by default.
+func (h *AdminHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	if _, err := uuid.Parse(ctxID); err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Query Params
+	var filter models.ReportFilter
+	q := r.URL.Query()
+	switch s := q.Get("status"); s {
+	case "":
+		open := models.ReportStatusOpen
+		filter.Status = &open
+	case "all":
+	case models.ReportStatusOpen, models.ReportStatusActioned, models.ReportStatusDismissed:
+		filter.Status = &s
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	if s := q.Get("target_type"); s != "" {
+		filter.TargetType = &s
+	}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	reports, err := h.Repo.GetReports(r.Context(), filter, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get reports error: %v", err)
+		http.Error(w, "Failed to get reports", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(reports, limit, queuedReportCursor))
+}
+
+func queuedReportCursor(q *models.QueuedReport) pagination.Cursor {
+	return pagination.At(q.CreatedAt, q.ID)
+}
+
+// ResolveReport hides the reported content, suspends its author or dismisses
+// the report. Every open report on the same target is closed with it.
+func (h *AdminHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /admin/reports/{id}/resolve
+	reportID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing report ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ResolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	switch req.Action {
+	case models.ReportActionHideContent, models.ReportActionDismiss:
+	case models.ReportActionSuspendUser:
+		if req.SuspendUntil == nil || !req.SuspendUntil.After(time.Now()) {
+			http.Error(w, "suspend_until must be in the future", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	resolved, err := h.Repo.ResolveReport(r.Context(), adminID, reportID, req)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrReportNotFound) {
+			http.Error(w, "Report not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrReportResolved) {
+			http.Error(w, "Report already resolved", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidReportAction) {
+			http.Error(w, "Action does not apply to the reported target", http.StatusBadRequest)
+			return
+		}
+		writeAdminError(w, err, "Report target not found", "Resolve report", "Failed to resolve report")
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.ResolveReportResponse{ReportsResolved: resolved})
+}
+
+// UnhideComment restores a comment a moderator hid.
+func (h *AdminHandler) UnhideComment(w http.ResponseWriter, r *http.Request) {
+	h.unhideContent(w, r, models.ReportTargetComment, "comment", "Hidden comment not found")
+}
+
+// UnhideWorkout restores a workout a moderator hid.
+func (h *AdminHandler) UnhideWorkout(w http.ResponseWriter, r *http.Request) {
+	h.unhideContent(w, r, models.ReportTargetWorkout, "workout", "Hidden workout not found")
+}
+
+// UnhideWorkoutImage restores a workout image a moderator hid.
+func (h *AdminHandler) UnhideWorkoutImage(w http.ResponseWriter, r *http.Request) {
+	h.unhideContent(w, r, models.ReportTargetWorkoutImage, "workout image", "Hidden workout image not found")
+}
+
+// unhideContent restores the hidden item at /admin/{resource}/{id}/hidden.
+func (h *AdminHandler) unhideContent(
+	w http.ResponseWriter,
+	r *http.Request,
+	targetType string,
+	kind string,
+	notFound string,
+) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	adminID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction
+	id, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing "+kind+" ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.UnhideContent(r.Context(), adminID, targetType, id)
+
+	// 4. Error Mapping
+	if err != nil {
+		writeAdminError(w, err, notFound, "Unhide "+kind, "Failed to unhide "+kind)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAdminError maps the errors every admin write can return.
+func writeAdminError(w http.ResponseWriter, err error, notFound string, action string, failure string) {
+	switch {
+	case errors.Is(err, repository.ErrUnauthorizedAction):
+		http.Error(w, "Admin access required", http.StatusForbidden)
+	case errors.Is(err, repository.ErrProfileNotFound),
+		errors.Is(err, repository.ErrCommentNotFound),
+		errors.Is(err, repository.ErrWorkoutNotFound),
+		errors.Is(err, repository.ErrWorkoutImageNotFound),
+		errors.Is(err, repository.ErrExerciseNotFound):
+		http.Error(w, notFound, http.StatusNotFound)
+	case errors.Is(err, repository.ErrAccountBanned):
+		http.Error(w, "User is banned", http.StatusConflict)
+	case errors.Is(err, repository.ErrAccountNotBanned):
+		http.Error(w, "User is not banned", http.StatusConflict)
+	default:
+		log.Printf("%s error: %v", action, err)
+		http.Error(w, failure, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,615 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+// --- Mocks ---
+
+type mockAdminRepo struct {
+	SearchUsersFunc          func(ctx context.Context, query string, cursor *pagination.Cursor, limit int) ([]*models.AdminUser, error)
+	GetUserFunc              func(ctx context.Context, id uuid.UUID) (*models.AdminUser, error)
+	SuspendUserFunc          func(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, until time.Time, reason *string) error
+	UnsuspendUserFunc        func(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error
+	BanUserFunc              func(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, reason *string) error
+	UnbanUserFunc            func(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error
+	RevokeUserSessionsFunc   func(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) (int64, error)
+	RemoveCommentFunc        func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error
+	RemoveWorkoutFunc        func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error
+	RemoveWorkoutImageFunc   func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error
+	DeleteSystemExerciseFunc func(ctx context.Context, adminID uuid.UUID, id uuid.UUID) error
+	GetAuditLogFunc          func(ctx context.Context, filter models.AuditLogFilter, cursor *pagination.Cursor, limit int) ([]*models.AuditLogEntry, error)
+	GetReportsFunc           func(ctx context.Context, filter models.ReportFilter, cursor *pagination.Cursor, limit int) ([]*models.QueuedReport, error)
+	ResolveReportFunc        func(ctx context.Context, adminID uuid.UUID, reportID uuid.UUID, req models.ResolveReportRequest) (int64, error)
+	UnhideContentFunc        func(ctx context.Context, adminID uuid.UUID, targetType string, id uuid.UUID) error
+}
+
+func (m *mockAdminRepo) SearchUsers(ctx context.Context, query string, cursor *pagination.Cursor, limit int) ([]*models.AdminUser, error) {
+	if m.SearchUsersFunc != nil {
+		return m.SearchUsersFunc(ctx, query, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminRepo) GetUser(ctx context.Context, id uuid.UUID) (*models.AdminUser, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(ctx, id)
+	}
+	return &models.AdminUser{ID: id}, nil
+}
+
+func (m *mockAdminRepo) SuspendUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, until time.Time, reason *string) error {
+	if m.SuspendUserFunc != nil {
+		return m.SuspendUserFunc(ctx, adminID, userID, until, reason)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) UnsuspendUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error {
+	if m.UnsuspendUserFunc != nil {
+		return m.UnsuspendUserFunc(ctx, adminID, userID)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) BanUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, reason *string) error {
+	if m.BanUserFunc != nil {
+		return m.BanUserFunc(ctx, adminID, userID, reason)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) UnbanUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error {
+	if m.UnbanUserFunc != nil {
+		return m.UnbanUserFunc(ctx, adminID, userID)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) RevokeUserSessions(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) (int64, error) {
+	if m.RevokeUserSessionsFunc != nil {
+		return m.RevokeUserSessionsFunc(ctx, adminID, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockAdminRepo) RemoveComment(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error {
+	if m.RemoveCommentFunc != nil {
+		return m.RemoveCommentFunc(ctx, adminID, id, reason)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) RemoveWorkout(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error {
+	if m.RemoveWorkoutFunc != nil {
+		return m.RemoveWorkoutFunc(ctx, adminID, id, reason)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) RemoveWorkoutImage(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error {
+	if m.RemoveWorkoutImageFunc != nil {
+		return m.RemoveWorkoutImageFunc(ctx, adminID, id, reason)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) GetSystemExercises(ctx context.Context) ([]*models.Exercise, error) {
+	return nil, nil
+}
+
+func (m *mockAdminRepo) CreateSystemExercise(ctx context.Context, adminID uuid.UUID, req models.CreateSystemExerciseRequest) (*models.Exercise, error) {
+	return &models.Exercise{ID: uuid.New(), Name: req.Name}, nil
+}
+
+func (m *mockAdminRepo) UpdateSystemExercise(ctx context.Context, adminID uuid.UUID, id uuid.UUID, updates models.UpdateExerciseRequest) (*models.Exercise, error) {
+	return &models.Exercise{ID: id}, nil
+}
+
+func (m *mockAdminRepo) DeleteSystemExercise(ctx context.Context, adminID uuid.UUID, id uuid.UUID) error {
+	if m.DeleteSystemExerciseFunc != nil {
+		return m.DeleteSystemExerciseFunc(ctx, adminID, id)
+	}
+	return nil
+}
+
+func (m *mockAdminRepo) GetAuditLog(ctx context.Context, filter models.AuditLogFilter, cursor *pagination.Cursor, limit int) ([]*models.AuditLogEntry, error) {
+	if m.GetAuditLogFunc != nil {
+		return m.GetAuditLogFunc(ctx, filter, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminRepo) GetReports(ctx context.Context, filter models.ReportFilter, cursor *pagination.Cursor, limit int) ([]*models.QueuedReport, error) {
+	if m.GetReportsFunc != nil {
+		return m.GetReportsFunc(ctx, filter, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockAdminRepo) ResolveReport(ctx context.Context, adminID uuid.UUID, reportID uuid.UUID, req models.ResolveReportRequest) (int64, error) {
+	if m.ResolveReportFunc != nil {
+		return m.ResolveReportFunc(ctx, adminID, reportID, req)
+	}
+	return 1, nil
+}
+
+func (m *mockAdminRepo) UnhideContent(ctx context.Context, adminID uuid.UUID, targetType string, id uuid.UUID) error {
+	if m.UnhideContentFunc != nil {
+		return m.UnhideContentFunc(ctx, adminID, targetType, id)
+	}
+	return nil
+}
+
+// --- Tests ---
+
+func TestAdminSearchUsers(t *testing.T) {
+	var gotQuery string
+	var gotLimit int
+	repo := &mockAdminRepo{
+		SearchUsersFunc: func(ctx context.Context, query string, cursor *pagination.Cursor, limit int) ([]*models.AdminUser, error) {
+			gotQuery, gotLimit = query, limit
+			return []*models.AdminUser{
+				{ID: uuid.New(), CreatedAt: time.Now()},
+				{ID: uuid.New(), CreatedAt: time.Now()},
+				{ID: uuid.New(), CreatedAt: time.Now()},
+			}, nil
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	req := httptest.NewRequest("GET", "/admin/users?q=lifter&limit=2", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.SearchUsers(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotQuery != "lifter" || gotLimit != 3 {
+		t.Errorf("expected query %q with limit+1, got %q, %d", "lifter", gotQuery, gotLimit)
+	}
+	var page pagination.Page[models.AdminUser]
+	json.NewDecoder(rr.Body).Decode(&page)
+	if len(page.Items) != 2 || page.NextCursor == nil {
+		t.Errorf("expected a full page with a next cursor, got %d items", len(page.Items))
+	}
+}
+
+func TestAdminGetUser_NotFound(t *testing.T) {
+	repo := &mockAdminRepo{
+		GetUserFunc: func(ctx context.Context, id uuid.UUID) (*models.AdminUser, error) {
+			return nil, repository.ErrProfileNotFound
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	req := httptest.NewRequest("GET", "/admin/users/"+uuid.New().String(), nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetUser(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
+
+func TestAdminSuspendUser(t *testing.T) {
+	adminID := uuid.New()
+	targetID := uuid.New()
+	future := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		target   uuid.UUID
+		body     string
+		repoErr  error
+		expected int
+	}{
+		{"Success", targetID, `{"until": "` + future + `", "reason": "spam"}`, nil, http.StatusNoContent},
+		{"Until In Past", targetID, `{"until": "` + past + `"}`, nil, http.StatusBadRequest},
+		{"Missing Until", targetID, `{"reason": "spam"}`, nil, http.StatusBadRequest},
+		{"Self", adminID, `{"until": "` + future + `"}`, nil, http.StatusBadRequest},
+		{"User Not Found", targetID, `{"until": "` + future + `"}`, repository.ErrProfileNotFound, http.StatusNotFound},
+		{"Banned", targetID, `{"until": "` + future + `"}`, repository.ErrAccountBanned, http.StatusConflict},
+		{"Not Admin", targetID, `{"until": "` + future + `"}`, repository.ErrUnauthorizedAction, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReason *string
+			repo := &mockAdminRepo{
+				SuspendUserFunc: func(ctx context.Context, a uuid.UUID, u uuid.UUID, until time.Time, reason *string) error {
+					gotReason = reason
+					return tt.repoErr
+				},
+			}
+			h := NewAdminHandler(repo)
+
+			req := httptest.NewRequest("PUT", "/admin/users/"+tt.target.String()+"/suspension", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, adminID.String())
+			rr := httptest.NewRecorder()
+
+			h.SuspendUser(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if tt.name == "Success" && (gotReason == nil || *gotReason != "spam") {
+				t.Errorf("expected the reason to reach the repo, got %v", gotReason)
+			}
+		})
+	}
+}
+
+func TestAdminBanUser(t *testing.T) {
+	adminID := uuid.New()
+	targetID := uuid.New()
+
+	tests := []struct {
+		name     string
+		target   uuid.UUID
+		body     string
+		repoErr  error
+		expected int
+	}{
+		{"Success", targetID, `{"reason": "spam"}`, nil, http.StatusNoContent},
+		{"No Reason", targetID, `{}`, nil, http.StatusNoContent},
+		{"Invalid Body", targetID, `{`, nil, http.StatusBadRequest},
+		{"Self", adminID, `{}`, nil, http.StatusBadRequest},
+		{"User Not Found", targetID, `{}`, repository.ErrProfileNotFound, http.StatusNotFound},
+		{"Not Admin", targetID, `{}`, repository.ErrUnauthorizedAction, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReason *string
+			repo := &mockAdminRepo{
+				BanUserFunc: func(ctx context.Context, a uuid.UUID, u uuid.UUID, reason *string) error {
+					gotReason = reason
+					return tt.repoErr
+				},
+			}
+			h := NewAdminHandler(repo)
+
+			req := httptest.NewRequest("PUT", "/admin/users/"+tt.target.String()+"/ban", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, adminID.String())
+			rr := httptest.NewRecorder()
+
+			h.BanUser(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if tt.name == "Success" && (gotReason == nil || *gotReason != "spam") {
+				t.Errorf("expected the reason to reach the repo, got %v", gotReason)
+			}
+		})
+	}
+}
+
+func TestAdminUnbanUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		expected int
+	}{
+		{"Success", nil, http.StatusNoContent},
+		{"User Not Found", repository.ErrProfileNotFound, http.StatusNotFound},
+		{"Not Banned", repository.ErrAccountNotBanned, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAdminRepo{
+				UnbanUserFunc: func(ctx context.Context, a uuid.UUID, u uuid.UUID) error {
+					return tt.repoErr
+				},
+			}
+			h := NewAdminHandler(repo)
+
+			req := httptest.NewRequest("DELETE", "/admin/users/"+uuid.New().String()+"/ban", nil)
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.UnbanUser(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
+
+func TestAdminRevokeUserSessions(t *testing.T) {
+	repo := &mockAdminRepo{
+		RevokeUserSessionsFunc: func(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) (int64, error) {
+			return 3, nil
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	req := httptest.NewRequest("DELETE", "/admin/users/"+uuid.New().String()+"/sessions", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.RevokeUserSessions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	var resp models.RevokeSessionsResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if resp.SessionsRevoked != 3 {
+		t.Errorf("expected 3 sessions revoked, got %d", resp.SessionsRevoked)
+	}
+}
+
+func TestAdminRemoveContent(t *testing.T) {
+	commentID := uuid.New()
+	var gotID uuid.UUID
+	var gotReason *string
+	repo := &mockAdminRepo{
+		RemoveCommentFunc: func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error {
+			gotID, gotReason = id, reason
+			return nil
+		},
+		RemoveWorkoutFunc: func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, reason *string) error {
+			return repository.ErrWorkoutNotFound
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	// Comment removed with a reason
+	req := httptest.NewRequest("DELETE", "/admin/comments/"+commentID.String()+"?reason=harassment", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+	h.RemoveComment(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 No Content, got %d", rr.Code)
+	}
+	if gotID != commentID || gotReason == nil || *gotReason != "harassment" {
+		t.Errorf("expected comment %s with reason, got %s, %v", commentID, gotID, gotReason)
+	}
+
+	// Missing workout
+	req = httptest.NewRequest("DELETE", "/admin/workouts/"+uuid.New().String(), nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr = httptest.NewRecorder()
+	h.RemoveWorkout(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+
+	// Invalid ID
+	req = httptest.NewRequest("DELETE", "/admin/workout-images/not-a-uuid", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr = httptest.NewRecorder()
+	h.RemoveWorkoutImage(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestAdminCreateSystemExercise_MissingName(t *testing.T) {
+	h := NewAdminHandler(&mockAdminRepo{})
+
+	req := httptest.NewRequest("POST", "/admin/exercises", strings.NewReader(`{"name": "  "}`))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.CreateSystemExercise(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestAdminDeleteSystemExercise_InUse(t *testing.T) {
+	repo := &mockAdminRepo{
+		DeleteSystemExerciseFunc: func(ctx context.Context, adminID uuid.UUID, id uuid.UUID) error {
+			return repository.ErrReferenceViolation
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	req := httptest.NewRequest("DELETE", "/admin/exercises/"+uuid.New().String(), nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.DeleteSystemExercise(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected 409 Conflict, got %d", rr.Code)
+	}
+}
+
+func TestAdminGetAuditLog(t *testing.T) {
+	targetID := uuid.New()
+	var gotFilter models.AuditLogFilter
+	repo := &mockAdminRepo{
+		GetAuditLogFunc: func(ctx context.Context, filter models.AuditLogFilter, cursor *pagination.Cursor, limit int) ([]*models.AuditLogEntry, error) {
+			gotFilter = filter
+			return nil, nil
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	req := httptest.NewRequest("GET", "/admin/audit-log?target_type=user&target_id="+targetID.String(), nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+	h.GetAuditLog(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotFilter.AdminID != nil || gotFilter.TargetType == nil || *gotFilter.TargetType != "user" ||
+		gotFilter.TargetID == nil || *gotFilter.TargetID != targetID {
+		t.Errorf("unexpected filter %+v", gotFilter)
+	}
+
+	req = httptest.NewRequest("GET", "/admin/audit-log?admin_id=nope", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr = httptest.NewRecorder()
+	h.GetAuditLog(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestAdminListReports(t *testing.T) {
+	var gotFilter models.ReportFilter
+	repo := &mockAdminRepo{
+		GetReportsFunc: func(ctx context.Context, filter models.ReportFilter, cursor *pagination.Cursor, limit int) ([]*models.QueuedReport, error) {
+			gotFilter = filter
+			return nil, nil
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	tests := []struct {
+		name       string
+		query      string
+		expected   int
+		wantStatus string // empty for no status filter
+	}{
+		{"Defaults To Open", "", http.StatusOK, models.ReportStatusOpen},
+		{"Dismissed", "?status=dismissed", http.StatusOK, models.ReportStatusDismissed},
+		{"All", "?status=all", http.StatusOK, ""},
+		{"Invalid Status", "?status=pending", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFilter = models.ReportFilter{}
+			req := httptest.NewRequest("GET", "/admin/reports"+tt.query, nil)
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.ListReports(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if tt.expected != http.StatusOK {
+				return
+			}
+			gotStatus := ""
+			if gotFilter.Status != nil {
+				gotStatus = *gotFilter.Status
+			}
+			if gotStatus != tt.wantStatus {
+				t.Errorf("expected status filter %q, got %q", tt.wantStatus, gotStatus)
+			}
+		})
+	}
+}
+
+func TestAdminResolveReport(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		body     string
+		repoErr  error
+		expected int
+	}{
+		{"Hide Content", `{"action": "hide_content"}`, nil, http.StatusOK},
+		{"Dismiss", `{"action": "dismiss", "note": "not abusive"}`, nil, http.StatusOK},
+		{"Suspend User", `{"action": "suspend_user", "suspend_until": "` + future + `"}`, nil, http.StatusOK},
+		{"Suspend Without Until", `{"action": "suspend_user"}`, nil, http.StatusBadRequest},
+		{"Unknown Action", `{"action": "delete_everything"}`, nil, http.StatusBadRequest},
+		{"Action Does Not Apply", `{"action": "hide_content"}`, repository.ErrInvalidReportAction, http.StatusBadRequest},
+		{"Already Resolved", `{"action": "dismiss"}`, repository.ErrReportResolved, http.StatusConflict},
+		{"Report Not Found", `{"action": "dismiss"}`, repository.ErrReportNotFound, http.StatusNotFound},
+		{"Target Gone", `{"action": "hide_content"}`, repository.ErrCommentNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reportID := uuid.New()
+			var gotID uuid.UUID
+			repo := &mockAdminRepo{
+				ResolveReportFunc: func(ctx context.Context, adminID uuid.UUID, id uuid.UUID, req models.ResolveReportRequest) (int64, error) {
+					gotID = id
+					return 2, tt.repoErr
+				},
+			}
+			h := NewAdminHandler(repo)
+
+			req := httptest.NewRequest("POST", "/admin/reports/"+reportID.String()+"/resolve", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.ResolveReport(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if rr.Code == http.StatusOK {
+				var resp models.ResolveReportResponse
+				json.NewDecoder(rr.Body).Decode(&resp)
+				if gotID != reportID || resp.ReportsResolved != 2 {
+					t.Errorf("expected report %s with 2 resolved, got %s, %d", reportID, gotID, resp.ReportsResolved)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminUnhideContent(t *testing.T) {
+	workoutID := uuid.New()
+	var gotType string
+	var gotID uuid.UUID
+	repo := &mockAdminRepo{
+		UnhideContentFunc: func(ctx context.Context, adminID uuid.UUID, targetType string, id uuid.UUID) error {
+			gotType, gotID = targetType, id
+			if targetType == models.ReportTargetComment {
+				return repository.ErrCommentNotFound
+			}
+			return nil
+		},
+	}
+	h := NewAdminHandler(repo)
+
+	req := httptest.NewRequest("DELETE", "/admin/workouts/"+workoutID.String()+"/hidden", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+	h.UnhideWorkout(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 No Content, got %d", rr.Code)
+	}
+	if gotType != models.ReportTargetWorkout || gotID != workoutID {
+		t.Errorf("expected workout %s, got %s %s", workoutID, gotType, gotID)
+	}
+
+	// A comment that is not hidden
+	req = httptest.NewRequest("DELETE", "/admin/comments/"+uuid.New().String()+"/hidden", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr = httptest.NewRecorder()
+	h.UnhideComment(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/appstore"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+// maxWebhookBody caps the size of a store notification request.
+const maxWebhookBody = 1 << 20
+
+type SubscriptionEventScanner interface {
+	ApplySubscriptionEvent(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error)
+}
+
+// NotificationVerifier checks a signed App Store Server Notification.
+type NotificationVerifier interface {
+	VerifyNotification(signed string) (*appstore.Notification, error)
+}
+
+type AppStoreWebhookHandler struct {
+	Repo         SubscriptionEventScanner
+	Verifier     NotificationVerifier
+	Entitlements PlanInvalidator
+}
+
+func NewAppStoreWebhookHandler(r SubscriptionEventScanner, v NotificationVerifier, e PlanInvalidator) *AppStoreWebhookHandler {
+	return &AppStoreWebhookHandler{Repo: r, Verifier: v, Entitlements: e}
+}
+
+// HandleNotification receives App Store Server Notifications v2. It is public;
+// the signature is what authenticates the request. Apple retries anything but
+// a 2xx, so redelivered notifications are acknowledged without reapplying.
+func (h *AppStoreWebhookHandler) HandleNotification(w http.ResponseWriter, r *http.Request) {
+	// 1. Request Decoding
+	var req struct {
+		SignedPayload string `json:"signedPayload"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBody)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SignedPayload == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	n, err := h.Verifier.VerifyNotification(req.SignedPayload)
+	if err != nil {
+		log.Printf("Verify App Store notification error: %v", err)
+		http.Error(w, "Invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	event := models.SubscriptionEvent{
+		Store:            models.StoreAppStore,
+		NotificationID:   n.NotificationUUID,
+		NotificationType: n.NotificationType,
+		Subtype:          n.Subtype,
+		SignedAt:         n.SignedAt(),
+		Payload:          n.Raw,
+	}
+	var update *models.VerifiedSubscription
+	if tx := n.Transaction; tx != nil {
+		event.OriginalTransactionID = tx.OriginalTransactionID
+		if status, ok := n.SubscriptionStatus(time.Now()); ok {
+			event.Status = status
+			update = &models.VerifiedSubscription{
+				Store:                 models.StoreAppStore,
+				OriginalTransactionID: tx.OriginalTransactionID,
+				TransactionID:         tx.TransactionID,
+				ProductID:             tx.ProductID,
+				Status:                status,
+				ExpiresAt:             tx.ExpiresAt(),
+				Environment:           strings.ToLower(tx.Environment),
+				SignedAt:              tx.SignedAt(),
+				Payload:               tx.Raw,
+			}
+		}
+	}
+
+	// 2. Repo Call
+	userID, applied, err := h.Repo.ApplySubscriptionEvent(r.Context(), event, update)
+
+	// 3. Error Mapping
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		// The app hasn't reported the purchase yet; Apple retries, which
+		// applies it once it has
+		log.Printf("App Store notification %s matches no subscription", n.NotificationUUID)
+		http.Error(w, "Unknown subscription", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Apply App Store notification error: %v", err)
+		http.Error(w, "Failed to process notification", http.StatusInternalServerError)
+		return
+	}
+	if !applied {
+		log.Printf("App Store notification %s already processed", n.NotificationUUID)
+	} else if update != nil {
+		h.Entitlements.Invalidate(userID)
+	}
+
+	// 4. Response Construction
+	w.WriteHeader(http.StatusOK)
+}
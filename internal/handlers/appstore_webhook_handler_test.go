@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/appstore"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+// --- Mocks ---
+
+type mockSubscriptionEventRepo struct {
+	ApplySubscriptionEventFunc func(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error)
+}
+
+func (m *mockSubscriptionEventRepo) ApplySubscriptionEvent(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error) {
+	if m.ApplySubscriptionEventFunc != nil {
+		return m.ApplySubscriptionEventFunc(ctx, event, update)
+	}
+	return uuid.Nil, true, nil
+}
+
+type mockNotificationVerifier struct {
+	VerifyNotificationFunc func(signed string) (*appstore.Notification, error)
+}
+
+func (m *mockNotificationVerifier) VerifyNotification(signed string) (*appstore.Notification, error) {
+	if m.VerifyNotificationFunc != nil {
+		return m.VerifyNotificationFunc(signed)
+	}
+	return &appstore.Notification{NotificationType: "TEST", NotificationUUID: "uuid-1"}, nil
+}
+
+// --- Tests ---
+
+func TestHandleNotification_AppliesRefund(t *testing.T) {
+	var gotEvent models.SubscriptionEvent
+	var gotUpdate *models.VerifiedSubscription
+	userID := uuid.New()
+	repo := &mockSubscriptionEventRepo{
+		ApplySubscriptionEventFunc: func(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error) {
+			gotEvent, gotUpdate = event, update
+			return userID, true, nil
+		},
+	}
+	verifier := &mockNotificationVerifier{
+		VerifyNotificationFunc: func(signed string) (*appstore.Notification, error) {
+			return &appstore.Notification{
+				NotificationType: "REFUND",
+				NotificationUUID: "uuid-1",
+				Transaction: &appstore.Transaction{
+					OriginalTransactionID: "1",
+					TransactionID:         "2",
+					ProductID:             "premium",
+					ExpiresDate:           time.Now().Add(time.Hour).UnixMilli(),
+					Environment:           "Production",
+				},
+			}, nil
+		},
+	}
+	invalidator := &mockPlanInvalidator{}
+	h := NewAppStoreWebhookHandler(repo, verifier, invalidator)
+
+	req := httptest.NewRequest("POST", "/webhooks/app-store", strings.NewReader(`{"signedPayload": "a.b.c"}`))
+	rr := httptest.NewRecorder()
+
+	h.HandleNotification(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if gotEvent.NotificationID != "uuid-1" || gotEvent.OriginalTransactionID != "1" || gotEvent.Status != models.SubscriptionRevoked {
+		t.Errorf("unexpected event %+v", gotEvent)
+	}
+	if gotUpdate == nil || gotUpdate.Status != models.SubscriptionRevoked || gotUpdate.Environment != "production" {
+		t.Errorf("expected the subscription to be revoked, got %+v", gotUpdate)
+	}
+	if len(invalidator.Invalidated) != 1 || invalidator.Invalidated[0] != userID {
+		t.Errorf("expected the user's plan to be invalidated, got %v", invalidator.Invalidated)
+	}
+}
+
+func TestHandleNotification_Responses(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		verify   func(signed string) (*appstore.Notification, error)
+		applyErr error
+		applied  bool
+		expected int
+	}{
+		{
+			name:     "Missing payload",
+			body:     `{}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "Forged payload",
+			body: `{"signedPayload": "a.b.c"}`,
+			verify: func(signed string) (*appstore.Notification, error) {
+				return nil, appstore.ErrInvalidSignature
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Test notification is recorded only",
+			body:     `{"signedPayload": "a.b.c"}`,
+			applied:  true,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Redelivery is acknowledged",
+			body:     `{"signedPayload": "a.b.c"}`,
+			applied:  false,
+			expected: http.StatusOK,
+		},
+		{
+			name:     "Unknown subscription asks Apple to retry",
+			body:     `{"signedPayload": "a.b.c"}`,
+			applyErr: repository.ErrSubscriptionNotFound,
+			expected: http.StatusNotFound,
+		},
+		{
+			name:     "Repo failure asks Apple to retry",
+			body:     `{"signedPayload": "a.b.c"}`,
+			applyErr: errors.New("db down"),
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSubscriptionEventRepo{
+				ApplySubscriptionEventFunc: func(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error) {
+					if update != nil {
+						t.Errorf("expected no subscription update, got %+v", update)
+					}
+					return uuid.Nil, tt.applied, tt.applyErr
+				},
+			}
+			invalidator := &mockPlanInvalidator{}
+			h := NewAppStoreWebhookHandler(repo, &mockNotificationVerifier{VerifyNotificationFunc: tt.verify}, invalidator)
+
+			req := httptest.NewRequest("POST", "/webhooks/app-store", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+
+			h.HandleNotification(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if len(invalidator.Invalidated) != 0 {
+				t.Errorf("expected no plan invalidation without an update, got %v", invalidator.Invalidated)
+			}
+		})
+	}
+}
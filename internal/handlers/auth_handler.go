@@ -29,6 +29,7 @@ type AuthResponse struct {
 type UserScanner interface {
 	UpsertGoogleUser(ctx context.Context, googleID, email string) (*models.Profile, error)
 	GetProfileByID(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID) (*models.Profile, error)
+	IsAccountActive(ctx context.Context, userID uuid.UUID) (bool, error)
 	UpdateProfile(ctx context.Context, id uuid.UUID, updates models.UpdateProfileRequest) error
 	DeleteProfile(ctx context.Context, id uuid.UUID) error
 	GetIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error)
@@ -107,6 +108,14 @@ func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Database error", http.StatusInternalServerError)
 		return
 	}
+	if !models.AccountActive(user.AccountStatus, user.SuspendedUntil, time.Now()) {
+		if user.AccountStatus == models.AccountStatusBanned {
+			http.Error(w, "Account banned", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "Account suspended", http.StatusForbidden)
+		return
+	}
 
 	// 4. Generate the Dual-Token Pair
 	secret := os.Getenv("JIMU_SECRET")
@@ -173,6 +182,17 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid or expired session", http.StatusUnauthorized)
 		return
 	}
+	// A disabled account keeps no way back in, even with a live session
+	active, err := h.UserRepo.IsAccountActive(r.Context(), session.UserID)
+	if err != nil {
+		log.Printf("Account status check failed: %v", err)
+		http.Error(w, "Failed to refresh token", http.StatusInternalServerError)
+		return
+	}
+	if !active {
+		http.Error(w, "Account disabled", http.StatusForbidden)
+		return
+	}
 
 	// 3. Generate a NEW token pair
 	secret := os.Getenv("JIMU_SECRET")
@@ -30,6 +30,7 @@ type mockUserRepo struct {
 	DeleteProfileFunc         func(ctx context.Context, id uuid.UUID) error
 	GetIdentitiesByUserIDFunc func(ctx context.Context, userID uuid.UUID) ([]*models.UserIdentity, error)
 	DeleteIdentityFunc        func(ctx context.Context, userID uuid.UUID, provider string) error
+	IsAccountActiveFunc       func(ctx context.Context, userID uuid.UUID) (bool, error)
 }
 
 func (m *mockUserRepo) UpsertGoogleUser(ctx context.Context, googleID, email string) (*models.Profile, error) {
@@ -46,6 +47,13 @@ func (m *mockUserRepo) GetProfileByID(ctx context.Context, viewerID uuid.UUID, t
 	return &models.Profile{ID: uuid.New()}, nil
 }
 
+func (m *mockUserRepo) IsAccountActive(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if m.IsAccountActiveFunc != nil {
+		return m.IsAccountActiveFunc(ctx, userID)
+	}
+	return true, nil
+}
+
 func (m *mockUserRepo) UpdateProfile(ctx context.Context, id uuid.UUID, updates models.UpdateProfileRequest) error {
 	if m.UpdateProfileFunc != nil {
 		return m.UpdateProfileFunc(ctx, id, updates)
@@ -209,6 +217,53 @@ func TestGoogleLogin_DatabaseDown(t *testing.T) {
 	}
 }
 
+func TestGoogleLogin_Suspended(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		status   string
+		until    *time.Time
+		expected int
+	}{
+		{"Active", models.AccountStatusActive, nil, http.StatusOK},
+		{"Suspension Active", models.AccountStatusSuspended, &future, http.StatusForbidden},
+		{"Suspension Over", models.AccountStatusSuspended, &past, http.StatusOK},
+		{"Banned", models.AccountStatusBanned, nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockUserRepo{
+				UpsertGoogleUserFunc: func(ctx context.Context, googleID, email string) (*models.Profile, error) {
+					return &models.Profile{ID: uuid.New(), AccountStatus: tt.status, SuspendedUntil: tt.until}, nil
+				},
+			}
+			sessionCreated := false
+			mockSessionRepo := &mockSessionRepo{
+				CreateSessionFunc: func(ctx context.Context, userID uuid.UUID, token string, agent *string, ip *string, exp time.Time) (*models.UserSession, error) {
+					sessionCreated = true
+					return &models.UserSession{}, nil
+				},
+			}
+			h := NewAuthHandler(mockRepo, mockSessionRepo, &mockValidator{})
+
+			req := httptest.NewRequest("POST", "/auth/google", strings.NewReader(`{"id_token": "valid-token"}`))
+			rr := httptest.NewRecorder()
+
+			h.GoogleLogin(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if sessionCreated != (tt.expected == http.StatusOK) {
+				t.Errorf("expected a session only for an allowed sign-in, created=%v", sessionCreated)
+			}
+		})
+	}
+}
+
 func TestGoogleLogin_SessionSaveFail(t *testing.T) {
 	mockSessionRepo := &mockSessionRepo{
 		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID, token string, agent *string, ip *string, exp time.Time) (*models.UserSession, error) {
@@ -262,6 +317,41 @@ func TestRefreshToken_ExpiredInvalid(t *testing.T) {
 	}
 }
 
+func TestRefreshToken_AccountDisabled(t *testing.T) {
+	userID := uuid.New()
+	sessionCreated := false
+	mockRepo := &mockUserRepo{
+		IsAccountActiveFunc: func(ctx context.Context, id uuid.UUID) (bool, error) {
+			if id != userID {
+				t.Errorf("expected the session's user to be checked, got %s", id)
+			}
+			return false, nil
+		},
+	}
+	mockSessionRepo := &mockSessionRepo{
+		GetSessionByRefreshTokenFunc: func(ctx context.Context, token string) (*models.UserSession, error) {
+			return &models.UserSession{ID: uuid.New(), UserID: userID}, nil
+		},
+		CreateSessionFunc: func(ctx context.Context, userID uuid.UUID, token string, agent *string, ip *string, exp time.Time) (*models.UserSession, error) {
+			sessionCreated = true
+			return &models.UserSession{}, nil
+		},
+	}
+	h := NewAuthHandler(mockRepo, mockSessionRepo, &mockValidator{})
+
+	req := httptest.NewRequest("POST", "/auth/refresh", strings.NewReader(`{"refresh_token": "live-token"}`))
+	rr := httptest.NewRecorder()
+
+	h.RefreshToken(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 Forbidden, got %d", rr.Code)
+	}
+	if sessionCreated {
+		t.Error("expected no new session for a disabled account")
+	}
+}
+
 func TestRefreshToken_RotationSuccess(t *testing.T) {
 	revokeCalled := false
 	createCalled := false
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
+)
+
+type AvatarScanner interface {
+	SetAvatarFromUpload(ctx context.Context, userID uuid.UUID, uploadID uuid.UUID, avatarURL string, keys []string) error
+	ClearAvatar(ctx context.Context, userID uuid.UUID) error
+}
+
+// AvatarProcessor turns uploads into stored avatars.
+type AvatarProcessor interface {
+	ImageURLSigner
+	ProcessAvatar(ctx context.Context, upload *models.Upload) (*uploads.Avatar, error)
+	Delete(ctx context.Context, keys ...string)
+}
+
+type AvatarHandler struct {
+	Repo    AvatarScanner
+	Uploads PendingUploadScanner
+	Avatars AvatarProcessor
+}
+
+func NewAvatarHandler(r AvatarScanner, u PendingUploadScanner, a AvatarProcessor) *AvatarHandler {
+	return &AvatarHandler{Repo: r, Uploads: u, Avatars: a}
+}
+
+// UpdateMyAvatar sets an upload as the user's avatar. The previous avatar's
+// files are deleted in the background once it is replaced.
+func (h *AvatarHandler) UpdateMyAvatar(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	var req struct {
+		UploadID uuid.UUID `json:"upload_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.UploadID == uuid.Nil {
+		http.Error(w, "Upload ID required", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	upload, err := h.Uploads.GetPendingUpload(r.Context(), req.UploadID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUploadNotFound) {
+			http.Error(w, "Upload not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Get upload error: %v", err)
+		http.Error(w, "Failed to update avatar", http.StatusInternalServerError)
+		return
+	}
+
+	avatar, err := h.Avatars.ProcessAvatar(r.Context(), upload)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	err = h.Repo.SetAvatarFromUpload(r.Context(), userID, upload.ID, avatar.URL, avatar.Keys)
+
+	// 4. Error Mapping
+	if err != nil {
+		h.Avatars.Delete(r.Context(), avatar.Keys...)
+		if errors.Is(err, repository.ErrUploadNotFound) {
+			http.Error(w, "Upload not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrProfileNotFound) {
+			http.Error(w, "Profile not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Update avatar error: %v", err)
+		http.Error(w, "Failed to update avatar", http.StatusInternalServerError)
+		return
+	}
+
+	// The original may carry EXIF location data and is never served
+	h.Avatars.Delete(r.Context(), upload.ObjectKey)
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.AvatarResponse{AvatarURL: avatar.URL})
+}
+
+func (h *AvatarHandler) DeleteMyAvatar(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Repo Call
+	err = h.Repo.ClearAvatar(r.Context(), userID)
+
+	// 3. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrProfileNotFound) {
+			http.Error(w, "Profile not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Delete avatar error: %v", err)
+		http.Error(w, "Failed to delete avatar", http.StatusInternalServerError)
+		return
+	}
+
+	// 4. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAvatar redirects an avatar URL (/avatars/{userId}/{avatarId}) to a
+// signed URL for the stored image. ?size= picks one of the standard sizes;
+// the largest is the default.
+func (h *AvatarHandler) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	// 1. ID Extraction
+	userID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+	avatarID, err := GetUUIDPathParam(r, 2)
+	if err != nil {
+		http.Error(w, "Invalid or missing avatar ID", http.StatusBadRequest)
+		return
+	}
+
+	size := uploads.AvatarSizes[0]
+	if s := r.URL.Query().Get("size"); s != "" {
+		size, err = strconv.Atoi(s)
+		if err != nil || !slices.Contains(uploads.AvatarSizes, size) {
+			http.Error(w, fmt.Sprintf("size must be one of %v", uploads.AvatarSizes), http.StatusBadRequest)
+			return
+		}
+	}
+
+	// 2. Signing
+	url, err := h.Avatars.SignURL(r.Context(), uploads.AvatarKey(userID, avatarID, size))
+	if err != nil {
+		log.Printf("Sign avatar URL error: %v", err)
+		http.Error(w, "Failed to get avatar", http.StatusInternalServerError)
+		return
+	}
+
+	// 3. Response Construction
+	// Clients may reuse the redirect until well before the signature expires
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(uploads.ReadURLTTL.Seconds())/2))
+	http.Redirect(w, r, url, http.StatusFound)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
+)
+
+// --- Mocks ---
+
+type mockAvatarRepo struct {
+	SetAvatarFromUploadFunc func(ctx context.Context, userID uuid.UUID, uploadID uuid.UUID, avatarURL string, keys []string) error
+	ClearAvatarFunc         func(ctx context.Context, userID uuid.UUID) error
+}
+
+func (m *mockAvatarRepo) SetAvatarFromUpload(ctx context.Context, userID uuid.UUID, uploadID uuid.UUID, avatarURL string, keys []string) error {
+	if m.SetAvatarFromUploadFunc != nil {
+		return m.SetAvatarFromUploadFunc(ctx, userID, uploadID, avatarURL, keys)
+	}
+	return nil
+}
+
+func (m *mockAvatarRepo) ClearAvatar(ctx context.Context, userID uuid.UUID) error {
+	if m.ClearAvatarFunc != nil {
+		return m.ClearAvatarFunc(ctx, userID)
+	}
+	return nil
+}
+
+type mockAvatarProcessor struct {
+	mockImageURLSigner
+	ProcessAvatarFunc func(ctx context.Context, upload *models.Upload) (*uploads.Avatar, error)
+	Deleted           []string
+}
+
+func (m *mockAvatarProcessor) ProcessAvatar(ctx context.Context, upload *models.Upload) (*uploads.Avatar, error) {
+	if m.ProcessAvatarFunc != nil {
+		return m.ProcessAvatarFunc(ctx, upload)
+	}
+	return &uploads.Avatar{
+		URL:  "https://api.example/avatars/u/a",
+		Keys: []string{"avatars/u/a/512.jpg", "avatars/u/a/128.jpg"},
+	}, nil
+}
+
+func (m *mockAvatarProcessor) Delete(ctx context.Context, keys ...string) {
+	m.Deleted = append(m.Deleted, keys...)
+}
+
+// --- Tests ---
+
+func TestUpdateMyAvatar_Success(t *testing.T) {
+	var gotKeys []string
+	repo := &mockAvatarRepo{
+		SetAvatarFromUploadFunc: func(ctx context.Context, userID uuid.UUID, uploadID uuid.UUID, avatarURL string, keys []string) error {
+			gotKeys = keys
+			return nil
+		},
+	}
+	avatars := &mockAvatarProcessor{}
+	h := NewAvatarHandler(repo, &mockPendingUploadRepo{}, avatars)
+
+	uploadID := uuid.New()
+	req := httptest.NewRequest("PUT", "/auth/profile/avatar", strings.NewReader(`{"upload_id": "`+uploadID.String()+`"}`))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateMyAvatar(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	var resp models.AvatarResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if resp.AvatarURL != "https://api.example/avatars/u/a" {
+		t.Errorf("expected the managed avatar URL, got %q", resp.AvatarURL)
+	}
+	if len(gotKeys) != 2 {
+		t.Errorf("expected both sizes to be recorded, got %v", gotKeys)
+	}
+	if !slices.Equal(avatars.Deleted, []string{"uploads/" + uploadID.String()}) {
+		t.Errorf("expected only the raw upload to be deleted, got %v", avatars.Deleted)
+	}
+}
+
+func TestUpdateMyAvatar_MissingUploadID(t *testing.T) {
+	h := NewAvatarHandler(&mockAvatarRepo{}, &mockPendingUploadRepo{}, &mockAvatarProcessor{})
+
+	req := httptest.NewRequest("PUT", "/auth/profile/avatar", strings.NewReader(`{"avatar_url": "https://tracker.example/pixel.gif"}`))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateMyAvatar(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestUpdateMyAvatar_RejectedUpload(t *testing.T) {
+	avatars := &mockAvatarProcessor{
+		ProcessAvatarFunc: func(ctx context.Context, upload *models.Upload) (*uploads.Avatar, error) {
+			return nil, uploads.ErrUnsupportedType
+		},
+	}
+	h := NewAvatarHandler(&mockAvatarRepo{}, &mockPendingUploadRepo{}, avatars)
+
+	req := httptest.NewRequest("PUT", "/auth/profile/avatar", strings.NewReader(`{"upload_id": "`+uuid.New().String()+`"}`))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateMyAvatar(rr, req)
+
+	if rr.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415, got %d", rr.Code)
+	}
+}
+
+func TestUpdateMyAvatar_UploadAlreadyUsed(t *testing.T) {
+	repo := &mockAvatarRepo{
+		SetAvatarFromUploadFunc: func(ctx context.Context, userID uuid.UUID, uploadID uuid.UUID, avatarURL string, keys []string) error {
+			return repository.ErrUploadNotFound
+		},
+	}
+	avatars := &mockAvatarProcessor{}
+	h := NewAvatarHandler(repo, &mockPendingUploadRepo{}, avatars)
+
+	req := httptest.NewRequest("PUT", "/auth/profile/avatar", strings.NewReader(`{"upload_id": "`+uuid.New().String()+`"}`))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateMyAvatar(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+	if !slices.Equal(avatars.Deleted, []string{"avatars/u/a/512.jpg", "avatars/u/a/128.jpg"}) {
+		t.Errorf("expected the processed avatar to be discarded, got %v", avatars.Deleted)
+	}
+}
+
+func TestDeleteMyAvatar(t *testing.T) {
+	h := NewAvatarHandler(&mockAvatarRepo{}, &mockPendingUploadRepo{}, &mockAvatarProcessor{})
+
+	req := httptest.NewRequest("DELETE", "/auth/profile/avatar", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.DeleteMyAvatar(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected 204 No Content, got %d", rr.Code)
+	}
+}
+
+func TestGetAvatar(t *testing.T) {
+	h := NewAvatarHandler(&mockAvatarRepo{}, &mockPendingUploadRepo{}, &mockAvatarProcessor{})
+	userID, avatarID := uuid.New(), uuid.New()
+	path := "/avatars/" + userID.String() + "/" + avatarID.String()
+
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+		location string
+	}{
+		{"Default Size", "", http.StatusFound, "https://signed.example/" + uploads.AvatarKey(userID, avatarID, 512)},
+		{"Small Size", "?size=128", http.StatusFound, "https://signed.example/" + uploads.AvatarKey(userID, avatarID, 128)},
+		{"Unknown Size", "?size=64", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", path+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			h.GetAvatar(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if got := rr.Header().Get("Location"); got != tt.location {
+				t.Errorf("expected Location %q, got %q", tt.location, got)
+			}
+		})
+	}
+}
@@ -6,19 +6,20 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
 type CommentScanner interface {
 	CreateComment(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID, parentID *uuid.UUID, content string) (*models.Comment, error)
 	GetCommentByUserID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.Comment, error)
-	GetCommentsByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.Comment, error)
-	GetReplies(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID) ([]*models.Comment, error)
+	GetCommentsByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Comment, error)
+	GetReplies(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Comment, error)
+	UpdateComment(ctx context.Context, id uuid.UUID, userID uuid.UUID, content string) (*models.Comment, error)
 	DeleteComment(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 }
 
@@ -138,6 +139,60 @@ func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(comment)
 }
 
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding (path param only: /comments/{id})
+	commentID, err := GetIDFromRequest(r)
+	if err != nil {
+		http.Error(w, "Invalid or missing comment ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Content == "" {
+		http.Error(w, "Content cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repository Call
+	comment, err := h.Repo.UpdateComment(r.Context(), commentID, userID, req.Content)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrCommentNotFound) {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrCommentEditWindowExpired) {
+			http.Error(w, "Comment can no longer be edited", http.StatusForbidden)
+			return
+		}
+		log.Printf("Update comment error: %v", err)
+		http.Error(w, "Failed to update comment", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
 func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	// 1. Context Check
 	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
@@ -192,20 +247,10 @@ func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
 	// 2. Query Params
 	workoutIDStr := r.URL.Query().Get("workout_id")
 	parentIDStr := r.URL.Query().Get("parent_id")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 20
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
-	}
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	var comments []*models.Comment
@@ -219,7 +264,7 @@ func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		// Now this correctly assigns to the outer 'err' and 'comments'
-		comments, err = h.Repo.GetReplies(r.Context(), parentID, userID)
+		comments, err = h.Repo.GetReplies(r.Context(), parentID, userID, cursor, limit+1)
 
 	} else if workoutIDStr != "" {
 		// List Workout Comments
@@ -229,7 +274,7 @@ func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Invalid workout ID", http.StatusBadRequest)
 			return
 		}
-		comments, err = h.Repo.GetCommentsByWorkoutID(r.Context(), workoutID, userID, limit, offset)
+		comments, err = h.Repo.GetCommentsByWorkoutID(r.Context(), workoutID, userID, cursor, limit+1)
 
 	} else {
 		http.Error(w, "Missing workout_id or parent_id", http.StatusBadRequest)
@@ -246,8 +291,9 @@ func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
 
 	// 5. Response Construction
 	w.Header().Set("Content-Type", "application/json")
-	if comments == nil {
-		comments = []*models.Comment{}
-	}
-	json.NewEncoder(w).Encode(comments)
+	json.NewEncoder(w).Encode(pagination.NewPage(comments, limit, commentCursor))
+}
+
+func commentCursor(c *models.Comment) pagination.Cursor {
+	return pagination.At(c.CreatedAt, c.ID)
 }
@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
 // --- Mocks ---
@@ -17,8 +19,9 @@ import (
 type mockCommentRepo struct {
 	CreateCommentFunc          func(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID, parentID *uuid.UUID, content string) (*models.Comment, error)
 	GetCommentByUserIDFunc     func(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.Comment, error)
-	GetCommentsByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.Comment, error)
-	GetRepliesFunc             func(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID) ([]*models.Comment, error)
+	GetCommentsByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Comment, error)
+	GetRepliesFunc             func(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Comment, error)
+	UpdateCommentFunc          func(ctx context.Context, id uuid.UUID, userID uuid.UUID, content string) (*models.Comment, error)
 	DeleteCommentFunc          func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 }
 
@@ -36,20 +39,27 @@ func (m *mockCommentRepo) GetCommentByUserID(ctx context.Context, id uuid.UUID,
 	return &models.Comment{ID: id}, nil
 }
 
-func (m *mockCommentRepo) GetCommentsByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.Comment, error) {
+func (m *mockCommentRepo) GetCommentsByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Comment, error) {
 	if m.GetCommentsByWorkoutIDFunc != nil {
-		return m.GetCommentsByWorkoutIDFunc(ctx, workoutID, viewerID, limit, offset)
+		return m.GetCommentsByWorkoutIDFunc(ctx, workoutID, viewerID, cursor, limit)
 	}
 	return []*models.Comment{}, nil
 }
 
-func (m *mockCommentRepo) GetReplies(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID) ([]*models.Comment, error) {
+func (m *mockCommentRepo) GetReplies(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Comment, error) {
 	if m.GetRepliesFunc != nil {
-		return m.GetRepliesFunc(ctx, commentID, viewerID)
+		return m.GetRepliesFunc(ctx, commentID, viewerID, cursor, limit)
 	}
 	return []*models.Comment{}, nil
 }
 
+func (m *mockCommentRepo) UpdateComment(ctx context.Context, id uuid.UUID, userID uuid.UUID, content string) (*models.Comment, error) {
+	if m.UpdateCommentFunc != nil {
+		return m.UpdateCommentFunc(ctx, id, userID, content)
+	}
+	return &models.Comment{ID: id, UserID: userID, Content: content}, nil
+}
+
 func (m *mockCommentRepo) DeleteComment(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
 	if m.DeleteCommentFunc != nil {
 		return m.DeleteCommentFunc(ctx, id, userID)
@@ -129,3 +139,56 @@ func TestDeleteComment_MissingCommentID(t *testing.T) {
 		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
 	}
 }
+
+func TestUpdateComment_Success(t *testing.T) {
+	h := NewCommentHandler(&mockCommentRepo{})
+
+	body := `{"content": "edited"}`
+	req := httptest.NewRequest("PUT", "/comments/"+uuid.New().String(), strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateComment(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"content":"edited"`) {
+		t.Errorf("unexpected body: %s", rr.Body.String())
+	}
+}
+
+func TestUpdateComment_EmptyContent(t *testing.T) {
+	h := NewCommentHandler(&mockCommentRepo{})
+
+	body := `{"content": ""}`
+	req := httptest.NewRequest("PUT", "/comments/"+uuid.New().String(), strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateComment(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestUpdateComment_EditWindowExpired(t *testing.T) {
+	mockRepo := &mockCommentRepo{
+		UpdateCommentFunc: func(ctx context.Context, id uuid.UUID, userID uuid.UUID, content string) (*models.Comment, error) {
+			return nil, repository.ErrCommentEditWindowExpired
+		},
+	}
+	h := NewCommentHandler(mockRepo)
+
+	body := `{"content": "too late"}`
+	req := httptest.NewRequest("PUT", "/comments/"+uuid.New().String(), strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpdateComment(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("expected 403 Forbidden, got %d", rr.Code)
+	}
+}
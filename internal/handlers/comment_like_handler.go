@@ -6,18 +6,18 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
 type CommentLikeScanner interface {
 	LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*models.CommentLike, error)
 	UnlikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) error
-	GetCommentLikesByCommentID(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.CommentLikeDetail, error)
+	GetCommentLikesByCommentID(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.CommentLikeDetail, error)
 }
 
 type CommentLikeHandler struct {
@@ -127,23 +127,14 @@ func (h *CommentLikeHandler) ListLikes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-	limit := 20
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
-	}
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// 3. Repo Call
-	likes, err := h.Repo.GetCommentLikesByCommentID(r.Context(), commentID, userID, limit, offset)
+	likes, err := h.Repo.GetCommentLikesByCommentID(r.Context(), commentID, userID, cursor, limit+1)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -154,8 +145,9 @@ func (h *CommentLikeHandler) ListLikes(w http.ResponseWriter, r *http.Request) {
 
 	// 5. Response Construction
 	w.Header().Set("Content-Type", "application/json")
-	if likes == nil {
-		likes = []*models.CommentLikeDetail{}
-	}
-	json.NewEncoder(w).Encode(likes)
+	json.NewEncoder(w).Encode(pagination.NewPage(likes, limit, commentLikeCursor))
+}
+
+func commentLikeCursor(l *models.CommentLikeDetail) pagination.Cursor {
+	return pagination.At(l.CreatedAt, l.UserID)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -17,7 +18,7 @@ import (
 type mockCommentLikeRepo struct {
 	LikeCommentFunc                func(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*models.CommentLike, error)
 	UnlikeCommentFunc              func(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) error
-	GetCommentLikesByCommentIDFunc func(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.CommentLikeDetail, error)
+	GetCommentLikesByCommentIDFunc func(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.CommentLikeDetail, error)
 }
 
 func (m *mockCommentLikeRepo) LikeComment(ctx context.Context, userID uuid.UUID, commentID uuid.UUID) (*models.CommentLike, error) {
@@ -34,9 +35,9 @@ func (m *mockCommentLikeRepo) UnlikeComment(ctx context.Context, userID uuid.UUI
 	return nil
 }
 
-func (m *mockCommentLikeRepo) GetCommentLikesByCommentID(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.CommentLikeDetail, error) {
+func (m *mockCommentLikeRepo) GetCommentLikesByCommentID(ctx context.Context, commentID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.CommentLikeDetail, error) {
 	if m.GetCommentLikesByCommentIDFunc != nil {
-		return m.GetCommentLikesByCommentIDFunc(ctx, commentID, viewerID, limit, offset)
+		return m.GetCommentLikesByCommentIDFunc(ctx, commentID, viewerID, cursor, limit)
 	}
 	return []*models.CommentLikeDetail{}, nil
 }
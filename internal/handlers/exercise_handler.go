@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -19,6 +20,8 @@ type ExerciseScanner interface {
 	GetExercisesByUserID(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID) ([]*models.Exercise, error)
 	UpdateExercise(ctx context.Context, id uuid.UUID, updates models.UpdateExerciseRequest, userID uuid.UUID) error
 	DeleteExercise(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	GetExerciseHistory(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.ExerciseSession, error)
+	GetLastExerciseSession(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID) (*models.ExerciseSession, error)
 }
 
 type ExerciseHandler struct {
@@ -250,3 +253,89 @@ func (h *ExerciseHandler) DeleteExercise(w http.ResponseWriter, r *http.Request)
 	// 5. Response Construction
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *ExerciseHandler) GetExerciseHistory(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /exercises/{id}/history
+	exerciseID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repository Call
+	sessions, err := h.Repo.GetExerciseHistory(r.Context(), exerciseID, userID, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get exercise history error: %v", err)
+		http.Error(w, "Failed to get exercise history", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(sessions, limit, exerciseSessionCursor))
+}
+
+func (h *ExerciseHandler) GetLastExerciseSession(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /exercises/{id}/last
+	exerciseID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repository Call
+	session, err := h.Repo.GetLastExerciseSession(r.Context(), exerciseID, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrExerciseSessionNotFound) {
+			http.Error(w, "No previous session", http.StatusNotFound)
+			return
+		}
+		log.Printf("Get last exercise session error: %v", err)
+		http.Error(w, "Failed to get last exercise session", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+func exerciseSessionCursor(s *models.ExerciseSession) pagination.Cursor {
+	return pagination.At(s.StartedAt, s.WorkoutExerciseID)
+}
@@ -6,10 +6,12 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -21,6 +23,8 @@ type mockExerciseRepo struct {
 	GetExercisesByUserIDFunc func(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID) ([]*models.Exercise, error)
 	UpdateExerciseFunc       func(ctx context.Context, id uuid.UUID, updates models.UpdateExerciseRequest, userID uuid.UUID) error
 	DeleteExerciseFunc       func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	GetExerciseHistoryFunc   func(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.ExerciseSession, error)
+	GetLastSessionFunc       func(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID) (*models.ExerciseSession, error)
 }
 
 func (m *mockExerciseRepo) CreateExercise(ctx context.Context, userID *uuid.UUID, name string, suggestedRestSeconds *int, icon *string, requesterID uuid.UUID) (*models.Exercise, error) {
@@ -58,6 +62,20 @@ func (m *mockExerciseRepo) DeleteExercise(ctx context.Context, id uuid.UUID, use
 	return nil
 }
 
+func (m *mockExerciseRepo) GetExerciseHistory(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.ExerciseSession, error) {
+	if m.GetExerciseHistoryFunc != nil {
+		return m.GetExerciseHistoryFunc(ctx, exerciseID, userID, cursor, limit)
+	}
+	return []*models.ExerciseSession{}, nil
+}
+
+func (m *mockExerciseRepo) GetLastExerciseSession(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID) (*models.ExerciseSession, error) {
+	if m.GetLastSessionFunc != nil {
+		return m.GetLastSessionFunc(ctx, exerciseID, userID)
+	}
+	return &models.ExerciseSession{WorkoutID: uuid.New(), Sets: []*models.TimelineWorkoutSet{}}, nil
+}
+
 // --- Tests ---
 
 func TestCreateExercise_Success(t *testing.T) {
@@ -165,3 +183,66 @@ func TestDeleteExercise_Success(t *testing.T) {
 		t.Errorf("expected 204 No Content, got %d", rr.Code)
 	}
 }
+
+func TestGetExerciseHistory_Success(t *testing.T) {
+	after := pagination.At(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), uuid.New())
+	var gotCursor *pagination.Cursor
+	var gotLimit int
+	mockRepo := &mockExerciseRepo{
+		GetExerciseHistoryFunc: func(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.ExerciseSession, error) {
+			gotCursor, gotLimit = cursor, limit
+			return []*models.ExerciseSession{}, nil
+		},
+	}
+	h := NewExerciseHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/exercises/00000000-0000-0000-0000-000000000001/history?limit=5&cursor="+after.Encode(), nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetExerciseHistory(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	// One extra row is fetched to detect whether there is a next page
+	if gotLimit != 6 {
+		t.Errorf("expected repo limit 6, got %d", gotLimit)
+	}
+	if gotCursor == nil || gotCursor.ID != after.ID {
+		t.Errorf("expected cursor %v, got %+v", after.ID, gotCursor)
+	}
+}
+
+func TestGetLastExerciseSession_Success(t *testing.T) {
+	h := NewExerciseHandler(&mockExerciseRepo{})
+
+	req := httptest.NewRequest("GET", "/exercises/00000000-0000-0000-0000-000000000001/last", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetLastExerciseSession(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+}
+
+func TestGetLastExerciseSession_NotFound(t *testing.T) {
+	mockRepo := &mockExerciseRepo{
+		GetLastSessionFunc: func(ctx context.Context, exerciseID uuid.UUID, userID uuid.UUID) (*models.ExerciseSession, error) {
+			return nil, repository.ErrExerciseSessionNotFound
+		},
+	}
+	h := NewExerciseHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/exercises/00000000-0000-0000-0000-000000000001/last", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetLastExerciseSession(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -18,8 +18,13 @@ type FollowScanner interface {
 	Follow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error)
 	Unfollow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
 	GetFollowStatus(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error)
-	GetFollowers(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Follow, error)
-	GetFollowing(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Follow, error)
+	GetFollowers(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Follow, error)
+	GetFollowing(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Follow, error)
+	AcceptFollow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
+	RejectFollow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
+	AcceptAllFollows(ctx context.Context, followingID uuid.UUID) (int64, error)
+	GetIncomingFollowRequests(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error)
+	GetOutgoingFollowRequests(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error)
 }
 
 type FollowHandler struct {
@@ -125,7 +130,7 @@ func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
 		return
 	}
-	_, err := uuid.Parse(ctxID)
+	viewerID, err := uuid.Parse(ctxID)
 	if err != nil {
 		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
 		return
@@ -139,23 +144,14 @@ func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-	limit := 20
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
-	}
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// 3. Repo Call
-	followers, err := h.Repo.GetFollowers(r.Context(), targetID, limit, offset)
+	followers, err := h.Repo.GetFollowers(r.Context(), targetID, viewerID, cursor, limit+1)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -166,10 +162,7 @@ func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
 
 	// 5. Response Construction
 	w.Header().Set("Content-Type", "application/json")
-	if followers == nil {
-		followers = []*models.Follow{}
-	}
-	json.NewEncoder(w).Encode(followers)
+	json.NewEncoder(w).Encode(pagination.NewPage(followers, limit, followerCursor))
 }
 
 func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
@@ -179,7 +172,7 @@ func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
 		return
 	}
-	_, err := uuid.Parse(ctxID)
+	viewerID, err := uuid.Parse(ctxID)
 	if err != nil {
 		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
 		return
@@ -193,23 +186,14 @@ func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-	limit := 20
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
-	}
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
-		}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// 3. Repo Call
-	following, err := h.Repo.GetFollowing(r.Context(), targetID, limit, offset)
+	following, err := h.Repo.GetFollowing(r.Context(), targetID, viewerID, cursor, limit+1)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -220,8 +204,172 @@ func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
 
 	// 5. Response Construction
 	w.Header().Set("Content-Type", "application/json")
-	if following == nil {
-		following = []*models.Follow{}
+	json.NewEncoder(w).Encode(pagination.NewPage(following, limit, followingCursor))
+}
+
+func (h *FollowHandler) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Query: direction=incoming|outgoing (default incoming), cursor, limit
+	direction := r.URL.Query().Get("direction")
+	if direction == "" {
+		direction = "incoming"
+	}
+	if direction != "incoming" && direction != "outgoing" {
+		http.Error(w, "Invalid direction", http.StatusBadRequest)
+		return
+	}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	var requests []*models.FollowRequest
+	if direction == "incoming" {
+		requests, err = h.Repo.GetIncomingFollowRequests(r.Context(), userID, cursor, limit+1)
+	} else {
+		requests, err = h.Repo.GetOutgoingFollowRequests(r.Context(), userID, cursor, limit+1)
+	}
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get follow requests error: %v", err)
+		http.Error(w, "Failed to get follow requests", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(requests, limit, followRequestCursor))
+}
+
+func (h *FollowHandler) AcceptFollowRequest(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path param only: /follow-requests/{userId}/accept
+	requesterID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.AcceptFollow(r.Context(), requesterID, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrFollowNotFound) {
+			http.Error(w, "Follow request not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Accept follow request error: %v", err)
+		http.Error(w, "Failed to accept follow request", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FollowHandler) RejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path param only: /follow-requests/{userId}/reject
+	requesterID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.RejectFollow(r.Context(), requesterID, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrFollowNotFound) {
+			http.Error(w, "Follow request not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Reject follow request error: %v", err)
+		http.Error(w, "Failed to reject follow request", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FollowHandler) AcceptAllFollowRequests(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Repo Call
+	accepted, err := h.Repo.AcceptAllFollows(r.Context(), userID)
+
+	// 3. Error Mapping
+	if err != nil {
+		log.Printf("Accept all follow requests error: %v", err)
+		http.Error(w, "Failed to accept follow requests", http.StatusInternalServerError)
+		return
 	}
-	json.NewEncoder(w).Encode(following)
+
+	// 4. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.AcceptAllFollowRequestsResponse{Accepted: accepted})
+}
+
+func followerCursor(f *models.Follow) pagination.Cursor {
+	return pagination.At(f.CreatedAt, f.FollowerID)
+}
+
+func followingCursor(f *models.Follow) pagination.Cursor {
+	return pagination.At(f.CreatedAt, f.FollowingID)
+}
+
+func followRequestCursor(fr *models.FollowRequest) pagination.Cursor {
+	return pagination.At(fr.CreatedAt, fr.UserID)
 }
@@ -4,22 +4,29 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
 // --- Mocks ---
 
 type mockFollowRepo struct {
-	FollowFunc          func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error)
-	UnfollowFunc        func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
-	GetFollowStatusFunc func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error)
-	GetFollowersFunc    func(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Follow, error)
-	GetFollowingFunc    func(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Follow, error)
+	FollowFunc                    func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error)
+	UnfollowFunc                  func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
+	GetFollowStatusFunc           func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error)
+	GetFollowersFunc              func(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Follow, error)
+	GetFollowingFunc              func(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Follow, error)
+	AcceptFollowFunc              func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
+	RejectFollowFunc              func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error
+	AcceptAllFollowsFunc          func(ctx context.Context, followingID uuid.UUID) (int64, error)
+	GetIncomingFollowRequestsFunc func(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error)
+	GetOutgoingFollowRequestsFunc func(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error)
 }
 
 func (m *mockFollowRepo) Follow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) (*models.Follow, error) {
@@ -43,20 +50,55 @@ func (m *mockFollowRepo) GetFollowStatus(ctx context.Context, followerID uuid.UU
 	return &models.Follow{FollowerID: followerID, FollowingID: followingID}, nil
 }
 
-func (m *mockFollowRepo) GetFollowers(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Follow, error) {
+func (m *mockFollowRepo) GetFollowers(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Follow, error) {
 	if m.GetFollowersFunc != nil {
-		return m.GetFollowersFunc(ctx, userID, limit, offset)
+		return m.GetFollowersFunc(ctx, userID, viewerID, cursor, limit)
 	}
 	return []*models.Follow{}, nil
 }
 
-func (m *mockFollowRepo) GetFollowing(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Follow, error) {
+func (m *mockFollowRepo) GetFollowing(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Follow, error) {
 	if m.GetFollowingFunc != nil {
-		return m.GetFollowingFunc(ctx, userID, limit, offset)
+		return m.GetFollowingFunc(ctx, userID, viewerID, cursor, limit)
 	}
 	return []*models.Follow{}, nil
 }
 
+func (m *mockFollowRepo) AcceptFollow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error {
+	if m.AcceptFollowFunc != nil {
+		return m.AcceptFollowFunc(ctx, followerID, followingID)
+	}
+	return nil
+}
+
+func (m *mockFollowRepo) RejectFollow(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error {
+	if m.RejectFollowFunc != nil {
+		return m.RejectFollowFunc(ctx, followerID, followingID)
+	}
+	return nil
+}
+
+func (m *mockFollowRepo) AcceptAllFollows(ctx context.Context, followingID uuid.UUID) (int64, error) {
+	if m.AcceptAllFollowsFunc != nil {
+		return m.AcceptAllFollowsFunc(ctx, followingID)
+	}
+	return 0, nil
+}
+
+func (m *mockFollowRepo) GetIncomingFollowRequests(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error) {
+	if m.GetIncomingFollowRequestsFunc != nil {
+		return m.GetIncomingFollowRequestsFunc(ctx, userID, cursor, limit)
+	}
+	return []*models.FollowRequest{}, nil
+}
+
+func (m *mockFollowRepo) GetOutgoingFollowRequests(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error) {
+	if m.GetOutgoingFollowRequestsFunc != nil {
+		return m.GetOutgoingFollowRequestsFunc(ctx, userID, cursor, limit)
+	}
+	return []*models.FollowRequest{}, nil
+}
+
 // --- Tests ---
 
 func TestFollowUser_Success(t *testing.T) {
@@ -139,3 +181,112 @@ func TestGetFollowing_Success(t *testing.T) {
 		t.Errorf("expected 200 OK, got %d", rr.Code)
 	}
 }
+
+func TestGetFollowRequests_Direction(t *testing.T) {
+	var gotDirection string
+	mockRepo := &mockFollowRepo{
+		GetIncomingFollowRequestsFunc: func(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error) {
+			gotDirection = "incoming"
+			return nil, nil
+		},
+		GetOutgoingFollowRequestsFunc: func(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.FollowRequest, error) {
+			gotDirection = "outgoing"
+			return nil, nil
+		},
+	}
+	h := NewFollowHandler(mockRepo)
+
+	for _, direction := range []string{"", "outgoing"} {
+		req := httptest.NewRequest("GET", "/follow-requests?direction="+direction, nil)
+		req = testutils.InjectUserID(req, uuid.New().String())
+		rr := httptest.NewRecorder()
+
+		h.GetFollowRequests(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected 200 OK, got %d", rr.Code)
+		}
+		want := direction
+		if want == "" {
+			want = "incoming"
+		}
+		if gotDirection != want {
+			t.Errorf("expected %s requests, got %s", want, gotDirection)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/follow-requests?direction=sideways", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetFollowRequests(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestAcceptFollowRequest_Success(t *testing.T) {
+	userID := uuid.New()
+	requesterID := uuid.New()
+	mockRepo := &mockFollowRepo{
+		AcceptFollowFunc: func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error {
+			if followerID != requesterID || followingID != userID {
+				t.Errorf("unexpected follow pair: %s -> %s", followerID, followingID)
+			}
+			return nil
+		},
+	}
+	h := NewFollowHandler(mockRepo)
+
+	req := httptest.NewRequest("POST", "/follow-requests/"+requesterID.String()+"/accept", nil)
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.AcceptFollowRequest(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected 204 No Content, got %d", rr.Code)
+	}
+}
+
+func TestRejectFollowRequest_NotFound(t *testing.T) {
+	mockRepo := &mockFollowRepo{
+		RejectFollowFunc: func(ctx context.Context, followerID uuid.UUID, followingID uuid.UUID) error {
+			return repository.ErrFollowNotFound
+		},
+	}
+	h := NewFollowHandler(mockRepo)
+
+	req := httptest.NewRequest("POST", "/follow-requests/"+uuid.New().String()+"/reject", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.RejectFollowRequest(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
+
+func TestAcceptAllFollowRequests_Success(t *testing.T) {
+	mockRepo := &mockFollowRepo{
+		AcceptAllFollowsFunc: func(ctx context.Context, followingID uuid.UUID) (int64, error) {
+			return 3, nil
+		},
+	}
+	h := NewFollowHandler(mockRepo)
+
+	req := httptest.NewRequest("POST", "/follow-requests/accept-all", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.AcceptAllFollowRequests(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"accepted":3`) {
+		t.Errorf("unexpected body: %s", rr.Body.String())
+	}
+}
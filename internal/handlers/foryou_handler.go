@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// maxSeenWorkouts caps how many workouts one request may mark as seen.
+const maxSeenWorkouts = 100
+
+type ForYouScanner interface {
+	GetForYouTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error)
+	RecordImpressions(ctx context.Context, viewerID uuid.UUID, workoutIDs []uuid.UUID) error
+}
+
+type ForYouHandler struct {
+	Repo   ForYouScanner
+	Images ImageURLSigner
+}
+
+func NewForYouHandler(r ForYouScanner, i ImageURLSigner) *ForYouHandler {
+	return &ForYouHandler{Repo: r, Images: i}
+}
+
+func (h *ForYouHandler) GetForYouTimelineWorkouts(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Query: cursor, limit, include
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	include, err := parseTimelineInclude(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	workouts, err := h.Repo.GetForYouTimelineWorkouts(r.Context(), userID, include, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get for-you timeline workouts error: %v", err)
+		http.Error(w, "Failed to get for-you timeline workouts", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	signTimelineImages(r.Context(), h.Images, workouts)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(workouts, limit, forYouWorkoutCursor))
+}
+
+// MarkSeen records that the viewer has scrolled past workouts in their
+// For-You feed so they are not shown again.
+func (h *ForYouHandler) MarkSeen(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	var req struct {
+		WorkoutIDs []uuid.UUID `json:"workout_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.WorkoutIDs) == 0 {
+		http.Error(w, "workout_ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.WorkoutIDs) > maxSeenWorkouts {
+		http.Error(w, "Too many workout_ids", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.RecordImpressions(r.Context(), userID, req.WorkoutIDs)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Record for-you impressions error: %v", err)
+		http.Error(w, "Failed to mark workouts as seen", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// forYouWorkoutCursor mirrors the ranking order: score, then recency.
+func forYouWorkoutCursor(w *models.TimelineWorkout) pagination.Cursor {
+	return pagination.Scored(w.Score, w.StartedAt, w.ID)
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type mockForYouRepo struct {
+	GetForYouTimelineWorkoutsFunc func(ctx context.Context, viewerID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error)
+	RecordImpressionsFunc         func(ctx context.Context, viewerID uuid.UUID, workoutIDs []uuid.UUID) error
+}
+
+func (m *mockForYouRepo) GetForYouTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+	if m.GetForYouTimelineWorkoutsFunc != nil {
+		return m.GetForYouTimelineWorkoutsFunc(ctx, viewerID, include, cursor, limit)
+	}
+	return []*models.TimelineWorkout{}, nil
+}
+
+func (m *mockForYouRepo) RecordImpressions(ctx context.Context, viewerID uuid.UUID, workoutIDs []uuid.UUID) error {
+	if m.RecordImpressionsFunc != nil {
+		return m.RecordImpressionsFunc(ctx, viewerID, workoutIDs)
+	}
+	return nil
+}
+
+func TestGetForYouTimelineWorkouts(t *testing.T) {
+	viewerID := uuid.New()
+
+	tests := []struct {
+		name           string
+		url            string
+		injectUserID   bool
+		mockErr        error
+		mockWorkouts   []*models.TimelineWorkout
+		expectedStatus int
+	}{
+		{
+			name:           "Success - returns 200 and timeline",
+			url:            "/workouts/timeline/for-you",
+			injectUserID:   true,
+			mockWorkouts:   []*models.TimelineWorkout{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unauthorized - no user in context",
+			url:            "/workouts/timeline/for-you",
+			injectUserID:   false,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid limit - 400",
+			url:            "/workouts/timeline/for-you?limit=abc",
+			injectUserID:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid cursor - 400",
+			url:            "/workouts/timeline/for-you?cursor=xyz",
+			injectUserID:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "DB error - 500",
+			url:            "/workouts/timeline/for-you",
+			injectUserID:   true,
+			mockErr:        context.DeadlineExceeded,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockForYouRepo{
+				GetForYouTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+					if tt.mockErr != nil {
+						return nil, tt.mockErr
+					}
+					return tt.mockWorkouts, nil
+				},
+			}
+			h := NewForYouHandler(mockRepo, &mockImageURLSigner{})
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.injectUserID {
+				req = testutils.InjectUserID(req, viewerID.String())
+			}
+			rr := httptest.NewRecorder()
+
+			h.GetForYouTimelineWorkouts(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestGetForYouTimelineWorkouts_Success_WithCursor(t *testing.T) {
+	viewerID := uuid.New()
+
+	mockRepo := &mockForYouRepo{
+		GetForYouTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+			if vID != viewerID {
+				t.Errorf("repo called with viewerID=%v", vID)
+			}
+			if limit != 11 || cursor == nil {
+				t.Errorf("repo called with limit=%d cursor=%v", limit, cursor)
+			}
+			return []*models.TimelineWorkout{}, nil
+		},
+	}
+	h := NewForYouHandler(mockRepo, &mockImageURLSigner{})
+
+	req := httptest.NewRequest("GET", "/workouts/timeline/for-you?limit=10&cursor="+pagination.Scored(3.5, time.Now(), uuid.New()).Encode(), nil)
+	req = testutils.InjectUserID(req, viewerID.String())
+	rr := httptest.NewRecorder()
+
+	h.GetForYouTimelineWorkouts(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+}
+
+func TestGetForYouTimelineWorkouts_NextCursorCarriesScore(t *testing.T) {
+	now := time.Now().UTC()
+	workouts := []*models.TimelineWorkout{
+		{ID: uuid.New(), StartedAt: now, Score: 9.5},
+		{ID: uuid.New(), StartedAt: now.Add(-time.Hour), Score: 7.25},
+		{ID: uuid.New(), StartedAt: now.Add(-2 * time.Hour), Score: 3},
+	}
+	mockRepo := &mockForYouRepo{
+		GetForYouTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+			return workouts, nil
+		},
+	}
+	h := NewForYouHandler(mockRepo, &mockImageURLSigner{})
+
+	req := httptest.NewRequest("GET", "/workouts/timeline/for-you?limit=2", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetForYouTimelineWorkouts(rr, req)
+
+	var page pagination.Page[*models.TimelineWorkout]
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == nil {
+		t.Fatalf("expected 2 items and a next cursor, got %d items", len(page.Items))
+	}
+	cursor, err := pagination.Decode(*page.NextCursor)
+	if err != nil {
+		t.Fatalf("failed to decode cursor: %v", err)
+	}
+	if s := cursor.ScoreKey(); s == nil || *s != 7.25 || cursor.ID != workouts[1].ID {
+		t.Errorf("expected cursor at second workout with score 7.25, got %+v", cursor)
+	}
+}
+
+func TestMarkSeen(t *testing.T) {
+	viewerID := uuid.New()
+	workoutID := uuid.New()
+
+	tooMany := make([]uuid.UUID, maxSeenWorkouts+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+	tooManyBody, _ := json.Marshal(map[string]any{"workout_ids": tooMany})
+
+	tests := []struct {
+		name           string
+		body           string
+		injectUserID   bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "Success - 204",
+			body:           `{"workout_ids": ["` + workoutID.String() + `"]}`,
+			injectUserID:   true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Unauthorized - no user in context",
+			body:           `{"workout_ids": ["` + workoutID.String() + `"]}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid body - 400",
+			body:           `{"workout_ids": ["not-a-uuid"]}`,
+			injectUserID:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty list - 400",
+			body:           `{"workout_ids": []}`,
+			injectUserID:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Too many IDs - 400",
+			body:           string(tooManyBody),
+			injectUserID:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "DB error - 500",
+			body:           `{"workout_ids": ["` + workoutID.String() + `"]}`,
+			injectUserID:   true,
+			mockErr:        context.DeadlineExceeded,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockForYouRepo{
+				RecordImpressionsFunc: func(ctx context.Context, vID uuid.UUID, ids []uuid.UUID) error {
+					if vID != viewerID {
+						t.Errorf("repo called with viewerID=%v", vID)
+					}
+					return tt.mockErr
+				},
+			}
+			h := NewForYouHandler(mockRepo, &mockImageURLSigner{})
+
+			req := httptest.NewRequest("POST", "/workouts/timeline/for-you/seen", bytes.NewBufferString(tt.body))
+			if tt.injectUserID {
+				req = testutils.InjectUserID(req, viewerID.String())
+			}
+			rr := httptest.NewRecorder()
+
+			h.MarkSeen(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
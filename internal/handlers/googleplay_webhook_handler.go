@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+type GooglePlayWebhookHandler struct {
+	Repo         SubscriptionEventScanner
+	Play         PlayPurchaseVerifier
+	Entitlements PlanInvalidator
+	// Token is the shared secret Pub/Sub sends in the push endpoint's
+	// ?token= query parameter. An empty Token rejects every request.
+	Token string
+}
+
+func NewGooglePlayWebhookHandler(r SubscriptionEventScanner, p PlayPurchaseVerifier, e PlanInvalidator, token string) *GooglePlayWebhookHandler {
+	return &GooglePlayWebhookHandler{Repo: r, Play: p, Entitlements: e, Token: token}
+}
+
+// HandleNotification receives Google Play Real-time Developer Notifications
+// pushed by Pub/Sub. Notifications only name a purchase; its state is always
+// fetched from Google, so they update subscriptions exactly as purchases
+// submitted by the app do. Pub/Sub redelivers anything but a 2xx.
+func (h *GooglePlayWebhookHandler) HandleNotification(w http.ResponseWriter, r *http.Request) {
+	// 1. Authentication
+	token := r.URL.Query().Get("token")
+	if h.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.Token)) != 1 {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	n, err := googleplay.ParsePushMessage(body)
+	if err != nil {
+		log.Printf("Parse Google Play notification error: %v", err)
+		http.Error(w, "Invalid notification", http.StatusBadRequest)
+		return
+	}
+
+	event := models.SubscriptionEvent{
+		Store:            models.StoreGooglePlay,
+		NotificationID:   n.MessageID,
+		NotificationType: n.Type(),
+		SignedAt:         n.EventTime(),
+		Payload:          n.Raw,
+	}
+	var update *models.VerifiedSubscription
+	var purchase *googleplay.SubscriptionPurchase
+	if purchaseToken := n.PurchaseToken(); purchaseToken != "" {
+		event.OriginalTransactionID = purchaseToken
+
+		purchase, err = h.Play.GetSubscription(r.Context(), purchaseToken)
+		switch {
+		case errors.Is(err, googleplay.ErrInvalidPurchase):
+			// Nothing to apply; the event is still recorded
+			log.Printf("Google Play notification %s names an invalid purchase", n.MessageID)
+			purchase = nil
+		case err != nil:
+			log.Printf("Get Google Play purchase error: %v", err)
+			http.Error(w, "Failed to process notification", http.StatusInternalServerError)
+			return
+		default:
+			verified := purchase.VerifiedSubscription(time.Now())
+			if n.Revokes() {
+				verified.Status = models.SubscriptionRevoked
+			}
+			event.Status = verified.Status
+			update = &verified
+		}
+	}
+
+	// 3. Repo Call
+	userID, applied, err := h.Repo.ApplySubscriptionEvent(r.Context(), event, update)
+
+	// 4. Error Mapping
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		// The app hasn't reported the purchase yet; Pub/Sub redelivers, which
+		// applies it once it has
+		log.Printf("Google Play notification %s matches no subscription", n.MessageID)
+		http.Error(w, "Unknown subscription", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Apply Google Play notification error: %v", err)
+		http.Error(w, "Failed to process notification", http.StatusInternalServerError)
+		return
+	}
+	if !applied {
+		log.Printf("Google Play notification %s already processed", n.MessageID)
+	} else if update != nil {
+		h.Entitlements.Invalidate(userID)
+	}
+
+	// Purchases the app never reported are acknowledged here
+	if purchase != nil && purchase.NeedsAcknowledgement() {
+		if err := h.Play.Acknowledge(r.Context(), purchase.ProductID(), purchase.PurchaseToken); err != nil {
+			log.Printf("Acknowledge Google Play purchase error: %v", err)
+		}
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusOK)
+}
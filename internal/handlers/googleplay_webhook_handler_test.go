@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+const testRTDNToken = "rtdn-secret"
+
+// pushBody wraps a developer notification in a Pub/Sub push envelope.
+func pushBody(t *testing.T, messageID string, notification map[string]any) string {
+	t.Helper()
+	data, err := json.Marshal(notification)
+	if err != nil {
+		t.Fatalf("failed to encode notification: %v", err)
+	}
+	body, _ := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"data":      base64.StdEncoding.EncodeToString(data),
+			"messageId": messageID,
+		},
+		"subscription": "projects/jimu/subscriptions/play-rtdn",
+	})
+	return string(body)
+}
+
+func subscriptionNotification(notificationType int, purchaseToken string) map[string]any {
+	return map[string]any{
+		"version":         "1.0",
+		"packageName":     "app.jimu",
+		"eventTimeMillis": "1760000000000",
+		"subscriptionNotification": map[string]any{
+			"version":          "1.0",
+			"notificationType": notificationType,
+			"purchaseToken":    purchaseToken,
+			"subscriptionId":   "premium_monthly",
+		},
+	}
+}
+
+func newFakePlay() *googleplay.FakeClient {
+	play := googleplay.NewFakeClient()
+	play.SetPurchase("play-token", &googleplay.SubscriptionPurchase{
+		SubscriptionState:    googleplay.StateActive,
+		LatestOrderID:        "GPA.1234..1",
+		AcknowledgementState: googleplay.AcknowledgementPending,
+		LineItems: []googleplay.LineItem{
+			{ProductID: "premium_monthly", ExpiryTime: time.Now().Add(30 * 24 * time.Hour)},
+		},
+	})
+	return play
+}
+
+func TestGooglePlayNotification_AppliesFetchedState(t *testing.T) {
+	tests := []struct {
+		name             string
+		notificationType int
+		wantType         string
+		wantStatus       string
+	}{
+		{"Renewal", googleplay.NotificationRenewed, "SUBSCRIPTION_RENEWED", models.SubscriptionActive},
+		{"Revocation", googleplay.NotificationRevoked, "SUBSCRIPTION_REVOKED", models.SubscriptionRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEvent models.SubscriptionEvent
+			var gotUpdate *models.VerifiedSubscription
+			userID := uuid.New()
+			repo := &mockSubscriptionEventRepo{
+				ApplySubscriptionEventFunc: func(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error) {
+					gotEvent, gotUpdate = event, update
+					return userID, true, nil
+				},
+			}
+			play := newFakePlay()
+			invalidator := &mockPlanInvalidator{}
+			h := NewGooglePlayWebhookHandler(repo, play, invalidator, testRTDNToken)
+
+			body := pushBody(t, "msg-1", subscriptionNotification(tt.notificationType, "play-token"))
+			req := httptest.NewRequest("POST", "/webhooks/google-play?token="+testRTDNToken, strings.NewReader(body))
+			rr := httptest.NewRecorder()
+
+			h.HandleNotification(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200 OK, got %d", rr.Code)
+			}
+			if gotEvent.Store != models.StoreGooglePlay || gotEvent.NotificationID != "msg-1" ||
+				gotEvent.NotificationType != tt.wantType || gotEvent.OriginalTransactionID != "play-token" {
+				t.Errorf("unexpected event: %+v", gotEvent)
+			}
+			if gotUpdate == nil || gotUpdate.Status != tt.wantStatus || gotUpdate.TransactionID != "GPA.1234..1" {
+				t.Fatalf("unexpected update: %+v", gotUpdate)
+			}
+			if len(play.Acknowledged) != 1 {
+				t.Errorf("expected the purchase to be acknowledged, got %v", play.Acknowledged)
+			}
+			if len(invalidator.Invalidated) != 1 || invalidator.Invalidated[0] != userID {
+				t.Errorf("expected the user's plan to be invalidated, got %v", invalidator.Invalidated)
+			}
+		})
+	}
+}
+
+func TestGooglePlayNotification_InvalidPurchaseIsRecorded(t *testing.T) {
+	var gotUpdate *models.VerifiedSubscription
+	called := false
+	repo := &mockSubscriptionEventRepo{
+		ApplySubscriptionEventFunc: func(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error) {
+			called, gotUpdate = true, update
+			return uuid.Nil, true, nil
+		},
+	}
+	invalidator := &mockPlanInvalidator{}
+	h := NewGooglePlayWebhookHandler(repo, googleplay.NewFakeClient(), invalidator, testRTDNToken)
+
+	body := pushBody(t, "msg-1", subscriptionNotification(googleplay.NotificationExpired, "unknown-token"))
+	req := httptest.NewRequest("POST", "/webhooks/google-play?token="+testRTDNToken, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	h.HandleNotification(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if !called || gotUpdate != nil {
+		t.Errorf("expected the event to be recorded without an update, got called=%v update=%+v", called, gotUpdate)
+	}
+	if len(invalidator.Invalidated) != 0 {
+		t.Errorf("expected no plan invalidation, got %v", invalidator.Invalidated)
+	}
+}
+
+func TestGooglePlayNotification_Responses(t *testing.T) {
+	valid := func(t *testing.T) string {
+		return pushBody(t, "msg-1", subscriptionNotification(googleplay.NotificationRenewed, "play-token"))
+	}
+
+	tests := []struct {
+		name         string
+		handlerToken string
+		query        string
+		body         func(t *testing.T) string
+		repoErr      error
+		expected     int
+	}{
+		{"Success", testRTDNToken, "?token=" + testRTDNToken, valid, nil, http.StatusOK},
+		{"Wrong token", testRTDNToken, "?token=guess", valid, nil, http.StatusUnauthorized},
+		{"Missing token", testRTDNToken, "", valid, nil, http.StatusUnauthorized},
+		{"Unconfigured token", "", "?token=", valid, nil, http.StatusUnauthorized},
+		{"Malformed envelope", testRTDNToken, "?token=" + testRTDNToken, func(t *testing.T) string { return `{"message": {}}` }, nil, http.StatusBadRequest},
+		{"Unknown subscription", testRTDNToken, "?token=" + testRTDNToken, valid, repository.ErrSubscriptionNotFound, http.StatusNotFound},
+		{"Repo error", testRTDNToken, "?token=" + testRTDNToken, valid, errors.New("db down"), http.StatusInternalServerError},
+		{"Test notification", testRTDNToken, "?token=" + testRTDNToken, func(t *testing.T) string {
+			return pushBody(t, "msg-2", map[string]any{"version": "1.0", "packageName": "app.jimu", "testNotification": map[string]any{"version": "1.0"}})
+		}, nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockSubscriptionEventRepo{
+				ApplySubscriptionEventFunc: func(ctx context.Context, event models.SubscriptionEvent, update *models.VerifiedSubscription) (uuid.UUID, bool, error) {
+					return uuid.Nil, tt.repoErr == nil, tt.repoErr
+				},
+			}
+			h := NewGooglePlayWebhookHandler(repo, newFakePlay(), &mockPlanInvalidator{}, tt.handlerToken)
+
+			req := httptest.NewRequest("POST", "/webhooks/google-play"+tt.query, strings.NewReader(tt.body(t)))
+			rr := httptest.NewRecorder()
+
+			h.HandleNotification(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
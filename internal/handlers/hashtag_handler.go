@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/entities"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type HashtagScanner interface {
+	GetWorkoutsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error)
+}
+
+type HashtagHandler struct {
+	Repo HashtagScanner
+}
+
+func NewHashtagHandler(r HashtagScanner) *HashtagHandler {
+	return &HashtagHandler{Repo: r}
+}
+
+func (h *HashtagHandler) GetHashtagWorkouts(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	viewerID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /hashtags/{tag}/workouts (query: cursor, limit)
+	parts := pathParts(r)
+	if len(parts) < 2 {
+		http.Error(w, "Invalid or missing hashtag", http.StatusBadRequest)
+		return
+	}
+	// Only accept tags the parser would have extracted, so lookups match stored rows
+	tag := strings.TrimPrefix(parts[1], "#")
+	parsed := entities.Extract("#" + tag).Hashtags
+	if len(parsed) != 1 || parsed[0] != strings.ToLower(tag) {
+		http.Error(w, "Invalid or missing hashtag", http.StatusBadRequest)
+		return
+	}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	workouts, err := h.Repo.GetWorkoutsByHashtag(r.Context(), parsed[0], viewerID, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get hashtag workouts error: %v", err)
+		http.Error(w, "Failed to get workouts", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(workouts, limit, workoutCursor))
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// --- Mocks ---
+
+type mockHashtagRepo struct {
+	GetWorkoutsByHashtagFunc func(ctx context.Context, tag string, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error)
+}
+
+func (m *mockHashtagRepo) GetWorkoutsByHashtag(ctx context.Context, tag string, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error) {
+	if m.GetWorkoutsByHashtagFunc != nil {
+		return m.GetWorkoutsByHashtagFunc(ctx, tag, viewerID, cursor, limit)
+	}
+	return []*models.Workout{}, nil
+}
+
+// --- Tests ---
+
+func TestGetHashtagWorkouts_Success(t *testing.T) {
+	var gotTag string
+	mockRepo := &mockHashtagRepo{
+		GetWorkoutsByHashtagFunc: func(ctx context.Context, tag string, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error) {
+			gotTag = tag
+			return []*models.Workout{{ID: uuid.New()}}, nil
+		},
+	}
+	h := NewHashtagHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/hashtags/LegDay/workouts", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetHashtagWorkouts(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotTag != "legday" {
+		t.Errorf("expected tag to be normalized to legday, got %q", gotTag)
+	}
+}
+
+func TestGetHashtagWorkouts_InvalidTag(t *testing.T) {
+	h := NewHashtagHandler(&mockHashtagRepo{})
+
+	req := httptest.NewRequest("GET", "/hashtags/not-a-tag/workouts", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetHashtagWorkouts(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestGetHashtagWorkouts_Unauthenticated(t *testing.T) {
+	h := NewHashtagHandler(&mockHashtagRepo{})
+
+	req := httptest.NewRequest("GET", "/hashtags/legday/workouts", nil)
+	rr := httptest.NewRecorder()
+
+	h.GetHashtagWorkouts(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 Unauthorized, got %d", rr.Code)
+	}
+}
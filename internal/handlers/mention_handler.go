@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type MentionScanner interface {
+	GetMentions(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Mention, error)
+}
+
+type MentionHandler struct {
+	Repo MentionScanner
+}
+
+func NewMentionHandler(r MentionScanner) *MentionHandler {
+	return &MentionHandler{Repo: r}
+}
+
+func (h *MentionHandler) GetMentions(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Query: cursor, limit
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	mentions, err := h.Repo.GetMentions(r.Context(), userID, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get mentions error: %v", err)
+		http.Error(w, "Failed to get mentions", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(mentions, limit, mentionCursor))
+}
+
+// mentionCursor keys a mention by its comment, or by its workout for captions.
+func mentionCursor(m *models.Mention) pagination.Cursor {
+	if m.CommentID != nil {
+		return pagination.At(m.CreatedAt, *m.CommentID)
+	}
+	return pagination.At(m.CreatedAt, m.WorkoutID)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// --- Mocks ---
+
+type mockMentionRepo struct {
+	GetMentionsFunc func(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Mention, error)
+}
+
+func (m *mockMentionRepo) GetMentions(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Mention, error) {
+	if m.GetMentionsFunc != nil {
+		return m.GetMentionsFunc(ctx, userID, cursor, limit)
+	}
+	return nil, nil
+}
+
+// --- Tests ---
+
+func TestGetMentions_Success(t *testing.T) {
+	userID := uuid.New()
+	var gotUserID uuid.UUID
+	mockRepo := &mockMentionRepo{
+		GetMentionsFunc: func(ctx context.Context, uid uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Mention, error) {
+			gotUserID = uid
+			return []*models.Mention{{AuthorID: uuid.New(), WorkoutID: uuid.New()}}, nil
+		},
+	}
+	h := NewMentionHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/mentions", nil)
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.GetMentions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotUserID != userID {
+		t.Errorf("expected mentions for %v, got %v", userID, gotUserID)
+	}
+}
+
+func TestGetMentions_EmptyList(t *testing.T) {
+	h := NewMentionHandler(&mockMentionRepo{})
+
+	req := httptest.NewRequest("GET", "/mentions", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetMentions(rr, req)
+
+	var page pagination.Page[*models.Mention]
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil || page.Items == nil {
+		t.Errorf("expected page with empty items, got %q", rr.Body.String())
+	}
+	if page.NextCursor != nil {
+		t.Errorf("expected no next cursor, got %v", *page.NextCursor)
+	}
+}
+
+func TestGetMentions_RepoError(t *testing.T) {
+	mockRepo := &mockMentionRepo{
+		GetMentionsFunc: func(ctx context.Context, uid uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Mention, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	h := NewMentionHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/mentions", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetMentions(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 Internal Server Error, got %d", rr.Code)
+	}
+}
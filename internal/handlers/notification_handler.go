@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// maxMarkReadNotifications caps how many entries one request may mark as read.
+const maxMarkReadNotifications = 100
+
+type NotificationScanner interface {
+	GetNotifications(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+type NotificationHandler struct {
+	Repo NotificationScanner
+}
+
+func NewNotificationHandler(r NotificationScanner) *NotificationHandler {
+	return &NotificationHandler{Repo: r}
+}
+
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Query: cursor, limit
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	notifications, err := h.Repo.GetNotifications(r.Context(), userID, cursor, limit+1)
+	var unread int
+	if err == nil {
+		unread, err = h.Repo.CountUnread(r.Context(), userID)
+	}
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get notifications error: %v", err)
+		http.Error(w, "Failed to get notifications", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.NotificationInbox{
+		Page:        pagination.NewPage(notifications, limit, notificationCursor),
+		UnreadCount: unread,
+	})
+}
+
+func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 3. Repo Call
+	unread, err := h.Repo.CountUnread(r.Context(), userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Count unread notifications error: %v", err)
+		http.Error(w, "Failed to count unread notifications", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.UnreadNotificationsResponse{UnreadCount: unread})
+}
+
+// MarkRead marks the given inbox entries as read.
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	var req struct {
+		IDs []uuid.UUID `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) == 0 {
+		http.Error(w, "ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) > maxMarkReadNotifications {
+		http.Error(w, "Too many ids", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	marked, err := h.Repo.MarkRead(r.Context(), userID, req.IDs)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Mark notifications read error: %v", err)
+		http.Error(w, "Failed to mark notifications read", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MarkNotificationsReadResponse{Marked: marked})
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 3. Repo Call
+	marked, err := h.Repo.MarkAllRead(r.Context(), userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Mark all notifications read error: %v", err)
+		http.Error(w, "Failed to mark notifications read", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.MarkNotificationsReadResponse{Marked: marked})
+}
+
+func notificationCursor(n *models.Notification) pagination.Cursor {
+	return pagination.At(n.CreatedAt, n.ID)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// --- Mocks ---
+
+type mockNotificationRepo struct {
+	GetNotificationsFunc func(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Notification, error)
+	CountUnreadFunc      func(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkReadFunc         func(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error)
+	MarkAllReadFunc      func(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
+func (m *mockNotificationRepo) GetNotifications(ctx context.Context, userID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Notification, error) {
+	if m.GetNotificationsFunc != nil {
+		return m.GetNotificationsFunc(ctx, userID, cursor, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockNotificationRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	if m.CountUnreadFunc != nil {
+		return m.CountUnreadFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockNotificationRepo) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	if m.MarkReadFunc != nil {
+		return m.MarkReadFunc(ctx, userID, ids)
+	}
+	return 0, nil
+}
+
+func (m *mockNotificationRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	if m.MarkAllReadFunc != nil {
+		return m.MarkAllReadFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+// --- Tests ---
+
+func TestGetNotifications_Success(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now().UTC()
+	mockRepo := &mockNotificationRepo{
+		GetNotificationsFunc: func(ctx context.Context, uid uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Notification, error) {
+			if uid != userID || limit != 3 {
+				t.Errorf("repo called with userID=%v limit=%d", uid, limit)
+			}
+			return []*models.Notification{
+				{ID: uuid.New(), Type: models.NotificationWorkoutLike, ActorCount: 13, CreatedAt: now},
+				{ID: uuid.New(), Type: models.NotificationFollow, ActorCount: 1, CreatedAt: now.Add(-time.Hour)},
+				{ID: uuid.New(), Type: models.NotificationComment, ActorCount: 1, CreatedAt: now.Add(-2 * time.Hour)},
+			}, nil
+		},
+		CountUnreadFunc: func(ctx context.Context, uid uuid.UUID) (int, error) {
+			return 2, nil
+		},
+	}
+	h := NewNotificationHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/notifications?limit=2", nil)
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.GetNotifications(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	var inbox models.NotificationInbox
+	if err := json.NewDecoder(rr.Body).Decode(&inbox); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(inbox.Items) != 2 || inbox.NextCursor == nil {
+		t.Errorf("expected 2 items and a next cursor, got %d items", len(inbox.Items))
+	}
+	if inbox.UnreadCount != 2 {
+		t.Errorf("expected unread_count 2, got %d", inbox.UnreadCount)
+	}
+}
+
+func TestGetNotifications_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		injectUserID   bool
+		repo           *mockNotificationRepo
+		expectedStatus int
+	}{
+		{
+			name:           "Unauthorized - no user in context",
+			url:            "/notifications",
+			repo:           &mockNotificationRepo{},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Invalid cursor - 400",
+			url:            "/notifications?cursor=xyz",
+			injectUserID:   true,
+			repo:           &mockNotificationRepo{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:         "List error - 500",
+			url:          "/notifications",
+			injectUserID: true,
+			repo: &mockNotificationRepo{
+				GetNotificationsFunc: func(ctx context.Context, uid uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Notification, error) {
+					return nil, errors.New("db down")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:         "Count error - 500",
+			url:          "/notifications",
+			injectUserID: true,
+			repo: &mockNotificationRepo{
+				CountUnreadFunc: func(ctx context.Context, uid uuid.UUID) (int, error) {
+					return 0, errors.New("db down")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewNotificationHandler(tt.repo)
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.injectUserID {
+				req = testutils.InjectUserID(req, uuid.New().String())
+			}
+			rr := httptest.NewRecorder()
+
+			h.GetNotifications(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestGetUnreadCount(t *testing.T) {
+	mockRepo := &mockNotificationRepo{
+		CountUnreadFunc: func(ctx context.Context, uid uuid.UUID) (int, error) {
+			return 5, nil
+		},
+	}
+	h := NewNotificationHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/notifications/unread-count", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetUnreadCount(rr, req)
+
+	var resp models.UnreadNotificationsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if rr.Code != http.StatusOK || resp.UnreadCount != 5 {
+		t.Errorf("expected 200 with unread_count 5, got %d and %d", rr.Code, resp.UnreadCount)
+	}
+}
+
+func TestMarkNotificationsRead(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{"Success - 200", `{"ids": ["` + id.String() + `"]}`, nil, http.StatusOK},
+		{"Invalid body - 400", `{"ids": ["nope"]}`, nil, http.StatusBadRequest},
+		{"Empty ids - 400", `{"ids": []}`, nil, http.StatusBadRequest},
+		{"DB error - 500", `{"ids": ["` + id.String() + `"]}`, errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockNotificationRepo{
+				MarkReadFunc: func(ctx context.Context, uid uuid.UUID, ids []uuid.UUID) (int64, error) {
+					if len(ids) != 1 || ids[0] != id {
+						t.Errorf("repo called with ids=%v", ids)
+					}
+					return 3, tt.mockErr
+				},
+			}
+			h := NewNotificationHandler(mockRepo)
+
+			req := httptest.NewRequest("POST", "/notifications/read", bytes.NewBufferString(tt.body))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.MarkRead(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	userID := uuid.New()
+	mockRepo := &mockNotificationRepo{
+		MarkAllReadFunc: func(ctx context.Context, uid uuid.UUID) (int64, error) {
+			if uid != userID {
+				t.Errorf("repo called with userID=%v", uid)
+			}
+			return 7, nil
+		},
+	}
+	h := NewNotificationHandler(mockRepo)
+
+	req := httptest.NewRequest("POST", "/notifications/read-all", nil)
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.MarkAllRead(rr, req)
+
+	var resp models.MarkNotificationsReadResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if rr.Code != http.StatusOK || resp.Marked != 7 {
+		t.Errorf("expected 200 with marked 7, got %d and %d", rr.Code, resp.Marked)
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type PersonalRecordScanner interface {
+	GetPersonalRecords(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID) ([]*models.PersonalRecord, error)
+	GetPersonalRecordHistory(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.PersonalRecord, error)
+}
+
+type PersonalRecordHandler struct {
+	Repo PersonalRecordScanner
+}
+
+func NewPersonalRecordHandler(r PersonalRecordScanner) *PersonalRecordHandler {
+	return &PersonalRecordHandler{Repo: r}
+}
+
+// parseExerciseIDFilter reads the optional exercise_id query param.
+func parseExerciseIDFilter(r *http.Request) (*uuid.UUID, error) {
+	exerciseIDStr := r.URL.Query().Get("exercise_id")
+	if exerciseIDStr == "" {
+		return nil, nil
+	}
+	exerciseID, err := uuid.Parse(exerciseIDStr)
+	if err != nil {
+		return nil, err
+	}
+	return &exerciseID, nil
+}
+
+func (h *PersonalRecordHandler) GetPersonalRecords(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	viewerID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /users/{id}/records (query: exercise_id)
+	targetID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	exerciseID, err := parseExerciseIDFilter(r)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	records, err := h.Repo.GetPersonalRecords(r.Context(), targetID, viewerID, exerciseID)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get personal records error: %v", err)
+		http.Error(w, "Failed to get personal records", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	if records == nil {
+		records = []*models.PersonalRecord{}
+	}
+	json.NewEncoder(w).Encode(records)
+}
+
+func (h *PersonalRecordHandler) GetPersonalRecordHistory(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	viewerID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /users/{id}/records/history (query: exercise_id, cursor, limit)
+	targetID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing user ID", http.StatusBadRequest)
+		return
+	}
+
+	exerciseID, err := parseExerciseIDFilter(r)
+	if err != nil {
+		http.Error(w, "Invalid exercise ID", http.StatusBadRequest)
+		return
+	}
+
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	records, err := h.Repo.GetPersonalRecordHistory(r.Context(), targetID, viewerID, exerciseID, cursor, limit+1)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get personal record history error: %v", err)
+		http.Error(w, "Failed to get personal record history", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pagination.NewPage(records, limit, personalRecordCursor))
+}
+
+func personalRecordCursor(pr *models.PersonalRecord) pagination.Cursor {
+	return pagination.At(pr.AchievedAt, pr.ID)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// --- Mocks ---
+
+type mockPersonalRecordRepo struct {
+	GetPersonalRecordsFunc       func(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID) ([]*models.PersonalRecord, error)
+	GetPersonalRecordHistoryFunc func(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.PersonalRecord, error)
+}
+
+func (m *mockPersonalRecordRepo) GetPersonalRecords(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID) ([]*models.PersonalRecord, error) {
+	if m.GetPersonalRecordsFunc != nil {
+		return m.GetPersonalRecordsFunc(ctx, targetID, viewerID, exerciseID)
+	}
+	return []*models.PersonalRecord{}, nil
+}
+
+func (m *mockPersonalRecordRepo) GetPersonalRecordHistory(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.PersonalRecord, error) {
+	if m.GetPersonalRecordHistoryFunc != nil {
+		return m.GetPersonalRecordHistoryFunc(ctx, targetID, viewerID, exerciseID, cursor, limit)
+	}
+	return []*models.PersonalRecord{}, nil
+}
+
+// --- Tests ---
+
+func TestGetPersonalRecords_Success(t *testing.T) {
+	exerciseID := uuid.New()
+	var gotExerciseID *uuid.UUID
+	mockRepo := &mockPersonalRecordRepo{
+		GetPersonalRecordsFunc: func(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, exerciseID *uuid.UUID) ([]*models.PersonalRecord, error) {
+			gotExerciseID = exerciseID
+			return []*models.PersonalRecord{}, nil
+		},
+	}
+	h := NewPersonalRecordHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/users/"+uuid.New().String()+"/records?exercise_id="+exerciseID.String(), nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetPersonalRecords(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotExerciseID == nil || *gotExerciseID != exerciseID {
+		t.Errorf("expected exercise filter %v, got %v", exerciseID, gotExerciseID)
+	}
+}
+
+func TestGetPersonalRecords_InvalidExerciseID(t *testing.T) {
+	h := NewPersonalRecordHandler(&mockPersonalRecordRepo{})
+
+	req := httptest.NewRequest("GET", "/users/"+uuid.New().String()+"/records?exercise_id=bad", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetPersonalRecords(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestGetPersonalRecordHistory_Success(t *testing.T) {
+	h := NewPersonalRecordHandler(&mockPersonalRecordRepo{})
+
+	req := httptest.NewRequest("GET", "/users/"+uuid.New().String()+"/records/history?limit=10", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetPersonalRecordHistory(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+type ReportScanner interface {
+	CreateReport(ctx context.Context, reporterID uuid.UUID, req models.CreateReportRequest) (*models.Report, error)
+}
+
+type ReportHandler struct {
+	Repo ReportScanner
+}
+
+func NewReportHandler(r ReportScanner) *ReportHandler {
+	return &ReportHandler{Repo: r}
+}
+
+// CreateReport files a report against a comment, workout, workout image or
+// profile for moderators to review.
+func (h *ReportHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	var req models.CreateReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	switch req.TargetType {
+	case models.ReportTargetComment, models.ReportTargetWorkout, models.ReportTargetWorkoutImage, models.ReportTargetUser:
+	default:
+		http.Error(w, "Invalid target type", http.StatusBadRequest)
+		return
+	}
+	if req.TargetID == uuid.Nil {
+		http.Error(w, "Invalid or missing target ID", http.StatusBadRequest)
+		return
+	}
+	if !models.ReportReasons[req.Reason] {
+		http.Error(w, "Invalid reason", http.StatusBadRequest)
+		return
+	}
+	if req.Note != nil {
+		note := strings.TrimSpace(*req.Note)
+		if utf8.RuneCountInString(note) > models.MaxReportNoteLength {
+			http.Error(w, fmt.Sprintf("Note must be at most %d characters", models.MaxReportNoteLength), http.StatusBadRequest)
+			return
+		}
+		req.Note = &note
+		if note == "" {
+			req.Note = nil
+		}
+	}
+	if req.TargetType == models.ReportTargetUser && req.TargetID == userID {
+		http.Error(w, "Cannot report yourself", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repository Call
+	report, err := h.Repo.CreateReport(r.Context(), userID, req)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrReportTargetNotFound) {
+			http.Error(w, "Report target not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrCannotReportSelf) {
+			http.Error(w, "Cannot report your own content", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, repository.ErrAlreadyReported) {
+			http.Error(w, "You have already reported this", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, repository.ErrReportLimit) {
+			http.Error(w, "Too many reports, try again later", http.StatusTooManyRequests)
+			return
+		}
+		log.Printf("Create report error: %v", err)
+		http.Error(w, "Failed to create report", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+)
+
+// --- Mocks ---
+
+type mockReportRepo struct {
+	CreateReportFunc func(ctx context.Context, reporterID uuid.UUID, req models.CreateReportRequest) (*models.Report, error)
+}
+
+func (m *mockReportRepo) CreateReport(ctx context.Context, reporterID uuid.UUID, req models.CreateReportRequest) (*models.Report, error) {
+	if m.CreateReportFunc != nil {
+		return m.CreateReportFunc(ctx, reporterID, req)
+	}
+	return &models.Report{ID: uuid.New(), ReporterID: reporterID, Status: models.ReportStatusOpen}, nil
+}
+
+// --- Tests ---
+
+func TestCreateReport(t *testing.T) {
+	userID := uuid.New()
+	targetID := uuid.New()
+	longNote := strings.Repeat("a", models.MaxReportNoteLength+1)
+
+	tests := []struct {
+		name     string
+		body     string
+		repoErr  error
+		expected int
+	}{
+		{"Success", `{"target_type": "comment", "target_id": "` + targetID.String() + `", "reason": "spam", "note": " buy now "}`, nil, http.StatusCreated},
+		{"Invalid Body", `{`, nil, http.StatusBadRequest},
+		{"Invalid Target Type", `{"target_type": "routine", "target_id": "` + targetID.String() + `", "reason": "spam"}`, nil, http.StatusBadRequest},
+		{"Missing Target ID", `{"target_type": "workout", "reason": "spam"}`, nil, http.StatusBadRequest},
+		{"Invalid Reason", `{"target_type": "workout", "target_id": "` + targetID.String() + `", "reason": "boring"}`, nil, http.StatusBadRequest},
+		{"Note Too Long", `{"target_type": "workout", "target_id": "` + targetID.String() + `", "reason": "other", "note": "` + longNote + `"}`, nil, http.StatusBadRequest},
+		{"Self", `{"target_type": "user", "target_id": "` + userID.String() + `", "reason": "other"}`, nil, http.StatusBadRequest},
+		{"Own Content", `{"target_type": "workout", "target_id": "` + targetID.String() + `", "reason": "spam"}`, repository.ErrCannotReportSelf, http.StatusBadRequest},
+		{"Target Not Found", `{"target_type": "workout_image", "target_id": "` + targetID.String() + `", "reason": "nudity"}`, repository.ErrReportTargetNotFound, http.StatusNotFound},
+		{"Duplicate", `{"target_type": "workout", "target_id": "` + targetID.String() + `", "reason": "spam"}`, repository.ErrAlreadyReported, http.StatusConflict},
+		{"Rate Limited", `{"target_type": "user", "target_id": "` + targetID.String() + `", "reason": "harassment"}`, repository.ErrReportLimit, http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq models.CreateReportRequest
+			repo := &mockReportRepo{
+				CreateReportFunc: func(ctx context.Context, reporterID uuid.UUID, req models.CreateReportRequest) (*models.Report, error) {
+					gotReq = req
+					if tt.repoErr != nil {
+						return nil, tt.repoErr
+					}
+					return &models.Report{ID: uuid.New(), ReporterID: reporterID}, nil
+				},
+			}
+			h := NewReportHandler(repo)
+
+			req := httptest.NewRequest("POST", "/reports", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, userID.String())
+			rr := httptest.NewRecorder()
+
+			h.CreateReport(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Fatalf("expected %d, got %d: %s", tt.expected, rr.Code, rr.Body.String())
+			}
+			if tt.name == "Success" && (gotReq.Note == nil || *gotReq.Note != "buy now") {
+				t.Errorf("expected the trimmed note to reach the repo, got %v", gotReq.Note)
+			}
+		})
+	}
+}
+
+func TestCreateReport_Unauthenticated(t *testing.T) {
+	h := NewReportHandler(&mockReportRepo{})
+
+	req := httptest.NewRequest("POST", "/reports", strings.NewReader(`{}`))
+	rr := httptest.NewRecorder()
+
+	h.CreateReport(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 Unauthorized, got %d", rr.Code)
+	}
+}
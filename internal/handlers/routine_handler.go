@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository"
@@ -15,18 +18,21 @@ import (
 
 type RoutineScanner interface {
 	CreateRoutine(ctx context.Context, userID uuid.UUID, name string) (*models.Routine, error)
+	CountRoutinesByUserID(ctx context.Context, userID uuid.UUID) (int, error)
 	GetRoutineByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.Routine, error)
 	GetRoutinesByUserID(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) ([]*models.Routine, error)
 	UpdateRoutine(ctx context.Context, id uuid.UUID, updates models.UpdateRoutineRequest, userID uuid.UUID) error
 	DeleteRoutine(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	StartWorkoutFromRoutine(ctx context.Context, routineID uuid.UUID, userID uuid.UUID, startedAt time.Time) (*models.StartedWorkout, error)
 }
 
 type RoutineHandler struct {
-	Repo RoutineScanner
+	Repo         RoutineScanner
+	Entitlements middleware.FeatureChecker
 }
 
-func NewRoutineHandler(r RoutineScanner) *RoutineHandler {
-	return &RoutineHandler{Repo: r}
+func NewRoutineHandler(r RoutineScanner, e middleware.FeatureChecker) *RoutineHandler {
+	return &RoutineHandler{Repo: r, Entitlements: e}
 }
 
 func (h *RoutineHandler) CreateRoutine(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +57,26 @@ func (h *RoutineHandler) CreateRoutine(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Free plans keep a limited number of routines
+	unlimited, err := h.Entitlements.Has(r.Context(), userID, entitlements.FeatureUnlimitedRoutines)
+	if err != nil {
+		log.Printf("Entitlement check error: %v", err)
+		http.Error(w, "Failed to create routine", http.StatusInternalServerError)
+		return
+	}
+	if !unlimited {
+		count, err := h.Repo.CountRoutinesByUserID(r.Context(), userID)
+		if err != nil {
+			log.Printf("Count routines error: %v", err)
+			http.Error(w, "Failed to create routine", http.StatusInternalServerError)
+			return
+		}
+		if count >= entitlements.FreeRoutineLimit {
+			http.Error(w, "Premium subscription required for more routines", http.StatusPaymentRequired)
+			return
+		}
+	}
+
 	// 3. Repository Call
 	routine, err := h.Repo.CreateRoutine(r.Context(), userID, req.Name)
 
@@ -232,3 +258,54 @@ func (h *RoutineHandler) DeleteRoutine(w http.ResponseWriter, r *http.Request) {
 	// 5. Response Construction
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *RoutineHandler) StartRoutine(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Path: /routines/{id}/start
+	routineID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing routine ID", http.StatusBadRequest)
+		return
+	}
+
+	// Body is optional; started_at defaults to now
+	var req models.StartRoutineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	startedAt := time.Now()
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+
+	// 3. Repository Call
+	workout, err := h.Repo.StartWorkoutFromRoutine(r.Context(), routineID, userID, startedAt)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrRoutineNotFound) {
+			http.Error(w, "Routine not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Start routine error: %v", err)
+		http.Error(w, "Failed to start routine", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(workout)
+}
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository"
@@ -17,10 +20,12 @@ import (
 
 type mockRoutineRepo struct {
 	CreateRoutineFunc       func(ctx context.Context, userID uuid.UUID, name string) (*models.Routine, error)
+	CountRoutinesFunc       func(ctx context.Context, userID uuid.UUID) (int, error)
 	GetRoutineByIDFunc      func(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.Routine, error)
 	GetRoutinesByUserIDFunc func(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) ([]*models.Routine, error)
 	UpdateRoutineFunc       func(ctx context.Context, id uuid.UUID, updates models.UpdateRoutineRequest, userID uuid.UUID) error
 	DeleteRoutineFunc       func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	StartWorkoutFunc        func(ctx context.Context, routineID uuid.UUID, userID uuid.UUID, startedAt time.Time) (*models.StartedWorkout, error)
 }
 
 func (m *mockRoutineRepo) CreateRoutine(ctx context.Context, userID uuid.UUID, name string) (*models.Routine, error) {
@@ -30,6 +35,13 @@ func (m *mockRoutineRepo) CreateRoutine(ctx context.Context, userID uuid.UUID, n
 	return &models.Routine{ID: uuid.New(), UserID: userID, Name: name}, nil
 }
 
+func (m *mockRoutineRepo) CountRoutinesByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	if m.CountRoutinesFunc != nil {
+		return m.CountRoutinesFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
 func (m *mockRoutineRepo) GetRoutineByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.Routine, error) {
 	if m.GetRoutineByIDFunc != nil {
 		return m.GetRoutineByIDFunc(ctx, id, viewerID)
@@ -58,10 +70,31 @@ func (m *mockRoutineRepo) DeleteRoutine(ctx context.Context, id uuid.UUID, userI
 	return nil
 }
 
+func (m *mockRoutineRepo) StartWorkoutFromRoutine(ctx context.Context, routineID uuid.UUID, userID uuid.UUID, startedAt time.Time) (*models.StartedWorkout, error) {
+	if m.StartWorkoutFunc != nil {
+		return m.StartWorkoutFunc(ctx, routineID, userID, startedAt)
+	}
+	return &models.StartedWorkout{
+		Workout:   models.Workout{ID: uuid.New(), UserID: userID, RoutineID: &routineID, StartedAt: startedAt},
+		Exercises: []*models.StartedWorkoutExercise{},
+	}, nil
+}
+
+type mockFeatureChecker struct {
+	HasFunc func(ctx context.Context, userID uuid.UUID, feature entitlements.Feature) (bool, error)
+}
+
+func (m *mockFeatureChecker) Has(ctx context.Context, userID uuid.UUID, feature entitlements.Feature) (bool, error) {
+	if m.HasFunc != nil {
+		return m.HasFunc(ctx, userID, feature)
+	}
+	return false, nil
+}
+
 // --- Tests ---
 
 func TestCreateRoutine_Success(t *testing.T) {
-	h := NewRoutineHandler(&mockRoutineRepo{})
+	h := NewRoutineHandler(&mockRoutineRepo{}, &mockFeatureChecker{})
 
 	body := `{"name": "Morning Routine"}`
 	req := httptest.NewRequest("POST", "/routines", strings.NewReader(body))
@@ -75,8 +108,52 @@ func TestCreateRoutine_Success(t *testing.T) {
 	}
 }
 
+func TestCreateRoutine_PlanLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		unlimited bool
+		count     int
+		checkErr  error
+		wantCode  int
+	}{
+		{"Free Under Limit", false, entitlements.FreeRoutineLimit - 1, nil, http.StatusCreated},
+		{"Free At Limit", false, entitlements.FreeRoutineLimit, nil, http.StatusPaymentRequired},
+		{"Premium Over Limit", true, entitlements.FreeRoutineLimit + 10, nil, http.StatusCreated},
+		{"Check Error", false, 0, errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &mockFeatureChecker{
+				HasFunc: func(ctx context.Context, userID uuid.UUID, feature entitlements.Feature) (bool, error) {
+					if feature != entitlements.FeatureUnlimitedRoutines {
+						t.Errorf("checked feature %q, want %q", feature, entitlements.FeatureUnlimitedRoutines)
+					}
+					return tt.unlimited, tt.checkErr
+				},
+			}
+			mockRepo := &mockRoutineRepo{
+				CountRoutinesFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
+					return tt.count, nil
+				},
+			}
+			h := NewRoutineHandler(mockRepo, checker)
+
+			req := httptest.NewRequest("POST", "/routines", strings.NewReader(`{"name": "Leg Day"}`))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.CreateRoutine(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d", tt.wantCode, rr.Code)
+			}
+		})
+	}
+}
+
 func TestGetRoutine_Success(t *testing.T) {
-	h := NewRoutineHandler(&mockRoutineRepo{})
+	h := NewRoutineHandler(&mockRoutineRepo{}, &mockFeatureChecker{})
 
 	req := httptest.NewRequest("GET", "/routines/00000000-0000-0000-0000-000000000001", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -90,7 +167,7 @@ func TestGetRoutine_Success(t *testing.T) {
 }
 
 func TestListRoutines_Success(t *testing.T) {
-	h := NewRoutineHandler(&mockRoutineRepo{})
+	h := NewRoutineHandler(&mockRoutineRepo{}, &mockFeatureChecker{})
 
 	req := httptest.NewRequest("GET", "/routines", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -104,7 +181,7 @@ func TestListRoutines_Success(t *testing.T) {
 }
 
 func TestUpdateRoutine_Success(t *testing.T) {
-	h := NewRoutineHandler(&mockRoutineRepo{})
+	h := NewRoutineHandler(&mockRoutineRepo{}, &mockFeatureChecker{})
 
 	body := `{"name": "New Name"}`
 	req := httptest.NewRequest("PUT", "/routines/00000000-0000-0000-0000-000000000001", strings.NewReader(body))
@@ -119,7 +196,7 @@ func TestUpdateRoutine_Success(t *testing.T) {
 }
 
 func TestDeleteRoutine_Success(t *testing.T) {
-	h := NewRoutineHandler(&mockRoutineRepo{})
+	h := NewRoutineHandler(&mockRoutineRepo{}, &mockFeatureChecker{})
 
 	req := httptest.NewRequest("DELETE", "/routines/00000000-0000-0000-0000-000000000001", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -138,7 +215,7 @@ func TestDeleteRoutine_NotFound(t *testing.T) {
 			return repository.ErrRoutineNotFound
 		},
 	}
-	h := NewRoutineHandler(mockRepo)
+	h := NewRoutineHandler(mockRepo, &mockFeatureChecker{})
 
 	req := httptest.NewRequest("DELETE", "/routines/00000000-0000-0000-0000-000000000001", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -150,3 +227,62 @@ func TestDeleteRoutine_NotFound(t *testing.T) {
 		t.Errorf("expected 404 Not Found, got %d", rr.Code)
 	}
 }
+
+func TestStartRoutine_Success(t *testing.T) {
+	h := NewRoutineHandler(&mockRoutineRepo{}, &mockFeatureChecker{})
+
+	req := httptest.NewRequest("POST", "/routines/00000000-0000-0000-0000-000000000001/start", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.StartRoutine(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("expected 201 Created, got %d", rr.Code)
+	}
+}
+
+func TestStartRoutine_UsesStartedAt(t *testing.T) {
+	want := time.Date(2026, 1, 2, 7, 30, 0, 0, time.UTC)
+	var got time.Time
+	mockRepo := &mockRoutineRepo{
+		StartWorkoutFunc: func(ctx context.Context, routineID uuid.UUID, userID uuid.UUID, startedAt time.Time) (*models.StartedWorkout, error) {
+			got = startedAt
+			return &models.StartedWorkout{}, nil
+		},
+	}
+	h := NewRoutineHandler(mockRepo, &mockFeatureChecker{})
+
+	body := `{"started_at": "2026-01-02T07:30:00Z"}`
+	req := httptest.NewRequest("POST", "/routines/00000000-0000-0000-0000-000000000001/start", strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.StartRoutine(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("expected 201 Created, got %d", rr.Code)
+	}
+	if !got.Equal(want) {
+		t.Errorf("expected started_at %v, got %v", want, got)
+	}
+}
+
+func TestStartRoutine_NotFound(t *testing.T) {
+	mockRepo := &mockRoutineRepo{
+		StartWorkoutFunc: func(ctx context.Context, routineID uuid.UUID, userID uuid.UUID, startedAt time.Time) (*models.StartedWorkout, error) {
+			return nil, repository.ErrRoutineNotFound
+		},
+	}
+	h := NewRoutineHandler(mockRepo, &mockFeatureChecker{})
+
+	req := httptest.NewRequest("POST", "/routines/00000000-0000-0000-0000-000000000001/start", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.StartRoutine(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
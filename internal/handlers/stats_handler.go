@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+const (
+	// maxStatsBuckets bounds the work per request (a year of daily buckets fits).
+	maxStatsBuckets = 400
+	defaultTopN     = 5
+	maxTopN         = 20
+)
+
+type StatsScanner interface {
+	GetTrainingStats(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, bucket string, topN int) (*models.TrainingStats, error)
+}
+
+type StatsHandler struct {
+	Repo StatsScanner
+}
+
+func NewStatsHandler(r StatsScanner) *StatsHandler {
+	return &StatsHandler{Repo: r}
+}
+
+// parseStatsTime accepts either RFC3339 or a plain date (YYYY-MM-DD, UTC midnight).
+func parseStatsTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// bucketLength approximates the length of one bucket, for range validation and defaults.
+func bucketLength(bucket string) time.Duration {
+	switch bucket {
+	case models.StatsBucketDay:
+		return 24 * time.Hour
+	case models.StatsBucketMonth:
+		return 28 * 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+func parseStatsQuery(r *http.Request) (from time.Time, to time.Time, bucket string, topN int, err error) {
+	q := r.URL.Query()
+
+	bucket = q.Get("bucket")
+	if bucket == "" {
+		bucket = models.StatsBucketWeek
+	}
+	if bucket != models.StatsBucketDay && bucket != models.StatsBucketWeek && bucket != models.StatsBucketMonth {
+		return from, to, "", 0, errors.New("Invalid bucket")
+	}
+
+	to = time.Now().UTC()
+	if s := q.Get("to"); s != "" {
+		if to, err = parseStatsTime(s); err != nil {
+			return from, to, "", 0, errors.New("Invalid to")
+		}
+	}
+	from = to.Add(-12 * bucketLength(bucket))
+	if s := q.Get("from"); s != "" {
+		if from, err = parseStatsTime(s); err != nil {
+			return from, to, "", 0, errors.New("Invalid from")
+		}
+	}
+	if !from.Before(to) {
+		return from, to, "", 0, errors.New("from must be before to")
+	}
+	if to.Sub(from) > maxStatsBuckets*bucketLength(bucket) {
+		return from, to, "", 0, errors.New("Range too large for bucket")
+	}
+
+	topN = defaultTopN
+	if s := q.Get("top"); s != "" {
+		topN, err = strconv.Atoi(s)
+		if err != nil || topN < 1 {
+			return from, to, "", 0, errors.New("Invalid top")
+		}
+		if topN > maxTopN {
+			topN = maxTopN
+		}
+	}
+
+	return from, to, bucket, topN, nil
+}
+
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	// Query: from, to, bucket (day|week|month), top
+	from, to, bucket, topN, err := parseStatsQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	stats, err := h.Repo.GetTrainingStats(r.Context(), userID, from, to, bucket, topN)
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Get stats error: %v", err)
+		http.Error(w, "Failed to get stats", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
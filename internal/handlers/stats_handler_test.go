@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+// --- Mocks ---
+
+type mockStatsRepo struct {
+	GetTrainingStatsFunc func(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, bucket string, topN int) (*models.TrainingStats, error)
+}
+
+func (m *mockStatsRepo) GetTrainingStats(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, bucket string, topN int) (*models.TrainingStats, error) {
+	if m.GetTrainingStatsFunc != nil {
+		return m.GetTrainingStatsFunc(ctx, userID, from, to, bucket, topN)
+	}
+	return &models.TrainingStats{Unit: "kg", Bucket: bucket, From: from, To: to}, nil
+}
+
+// --- Tests ---
+
+func TestGetStats_Success(t *testing.T) {
+	var gotFrom, gotTo time.Time
+	var gotBucket string
+	mockRepo := &mockStatsRepo{
+		GetTrainingStatsFunc: func(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, bucket string, topN int) (*models.TrainingStats, error) {
+			gotFrom, gotTo, gotBucket = from, to, bucket
+			return &models.TrainingStats{}, nil
+		},
+	}
+	h := NewStatsHandler(mockRepo)
+
+	req := httptest.NewRequest("GET", "/stats?from=2026-01-01&to=2026-02-01&bucket=day", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetStats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotBucket != models.StatsBucketDay {
+		t.Errorf("expected bucket day, got %q", gotBucket)
+	}
+	if !gotFrom.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !gotTo.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected range %v - %v", gotFrom, gotTo)
+	}
+}
+
+func TestGetStats_Defaults(t *testing.T) {
+	h := NewStatsHandler(&mockStatsRepo{})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetStats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+}
+
+func TestGetStats_InvalidQuery(t *testing.T) {
+	h := NewStatsHandler(&mockStatsRepo{})
+
+	tests := []string{
+		"/stats?bucket=year",
+		"/stats?from=2026-02-01&to=2026-01-01",
+		"/stats?from=2020-01-01&to=2026-01-01&bucket=day",
+		"/stats?from=not-a-date",
+	}
+	for _, target := range tests {
+		req := httptest.NewRequest("GET", target, nil)
+		req = testutils.InjectUserID(req, uuid.New().String())
+		rr := httptest.NewRecorder()
+
+		h.GetStats(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 Bad Request, got %d", target, rr.Code)
+		}
+	}
+}
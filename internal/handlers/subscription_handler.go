@@ -6,29 +6,56 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/appstore"
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
 type SubscriptionScanner interface {
-	UpsertSubscription(ctx context.Context, userID uuid.UUID, originalTransactionID string, productID string, status string, expiresAt time.Time, environment string) (*models.Subscription, error)
+	SaveVerifiedSubscription(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error)
 	GetSubscriptionByUserID(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) (*models.Subscription, error)
 	GetSubscriptionByTransactionID(ctx context.Context, transactionID string, userID uuid.UUID) (*models.Subscription, error)
 	DeleteSubscription(ctx context.Context, userID uuid.UUID) error
 }
 
+// TransactionVerifier checks a signed App Store transaction.
+type TransactionVerifier interface {
+	VerifyTransaction(signed string) (*appstore.Transaction, error)
+}
+
+// PlayPurchaseVerifier fetches Google Play purchases from Google, which is
+// what makes them trustworthy, and acknowledges them.
+type PlayPurchaseVerifier interface {
+	GetSubscription(ctx context.Context, purchaseToken string) (*googleplay.SubscriptionPurchase, error)
+	Acknowledge(ctx context.Context, productID string, purchaseToken string) error
+}
+
+// PlanInvalidator forgets a user's cached plan after their subscription
+// changes.
+type PlanInvalidator interface {
+	Invalidate(userID uuid.UUID)
+}
+
 type SubscriptionHandler struct {
-	Repo SubscriptionScanner
+	Repo         SubscriptionScanner
+	Verifier     TransactionVerifier
+	Play         PlayPurchaseVerifier
+	Entitlements PlanInvalidator
 }
 
-func NewSubscriptionHandler(r SubscriptionScanner) *SubscriptionHandler {
-	return &SubscriptionHandler{Repo: r}
+func NewSubscriptionHandler(r SubscriptionScanner, v TransactionVerifier, p PlayPurchaseVerifier, e PlanInvalidator) *SubscriptionHandler {
+	return &SubscriptionHandler{Repo: r, Verifier: v, Play: p, Entitlements: e}
 }
 
+// UpsertSubscription records the caller's subscription from a signed App
+// Store transaction or a Google Play purchase token. Product, status and
+// expiry come from the verified purchase, never from the client.
 func (h *SubscriptionHandler) UpsertSubscription(w http.ResponseWriter, r *http.Request) {
 	// 1. Context Check
 	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
@@ -44,29 +71,104 @@ func (h *SubscriptionHandler) UpsertSubscription(w http.ResponseWriter, r *http.
 
 	// 2. Request Decoding
 	var req struct {
-		OriginalTransactionID string    `json:"original_transaction_id"`
-		ProductID             string    `json:"product_id"`
-		Status                string    `json:"status"`
-		ExpiresAt             time.Time `json:"expires_at"`
-		Environment           string    `json:"environment"`
+		Store             string `json:"store"`
+		SignedTransaction string `json:"signed_transaction"`
+		PurchaseToken     string `json:"purchase_token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
+	var verified models.VerifiedSubscription
+	// The app tags purchases with the user's ID: appAccountToken on iOS,
+	// obfuscatedAccountId on Android
+	var accountID string
+	var playPurchase *googleplay.SubscriptionPurchase
+	switch req.Store {
+	case "", models.StoreAppStore:
+		if req.SignedTransaction == "" {
+			http.Error(w, "signed_transaction is required", http.StatusBadRequest)
+			return
+		}
+		tx, err := h.Verifier.VerifyTransaction(req.SignedTransaction)
+		if err != nil {
+			log.Printf("Verify transaction error: %v", err)
+			http.Error(w, "Invalid transaction", http.StatusBadRequest)
+			return
+		}
+		accountID = tx.AppAccountToken
+		verified = models.VerifiedSubscription{
+			Store:                 models.StoreAppStore,
+			OriginalTransactionID: tx.OriginalTransactionID,
+			TransactionID:         tx.TransactionID,
+			ProductID:             tx.ProductID,
+			Status:                tx.Status(time.Now()),
+			ExpiresAt:             tx.ExpiresAt(),
+			Environment:           strings.ToLower(tx.Environment),
+			SignedAt:              tx.SignedAt(),
+			Payload:               tx.Raw,
+		}
+	case models.StoreGooglePlay:
+		if req.PurchaseToken == "" {
+			http.Error(w, "purchase_token is required", http.StatusBadRequest)
+			return
+		}
+		playPurchase, err = h.Play.GetSubscription(r.Context(), req.PurchaseToken)
+		if err != nil {
+			if errors.Is(err, googleplay.ErrInvalidPurchase) {
+				http.Error(w, "Invalid purchase", http.StatusBadRequest)
+				return
+			}
+			// Like App Store purchases without a root certificate, Play
+			// purchases are rejected while Play is not configured
+			if errors.Is(err, googleplay.ErrNotConfigured) {
+				log.Printf("Get Google Play purchase error: %v", err)
+				http.Error(w, "Invalid purchase", http.StatusBadRequest)
+				return
+			}
+			log.Printf("Get Google Play purchase error: %v", err)
+			http.Error(w, "Failed to verify purchase", http.StatusInternalServerError)
+			return
+		}
+		accountID = playPurchase.ExternalAccountIdentifiers.ObfuscatedExternalAccountID
+		verified = playPurchase.VerifiedSubscription(time.Now())
+	default:
+		http.Error(w, "Invalid store", http.StatusBadRequest)
+		return
+	}
+
+	if accountID != "" && !strings.EqualFold(accountID, userID.String()) {
+		http.Error(w, "Transaction belongs to another account", http.StatusForbidden)
+		return
+	}
+
 	// 3. Repo Call
-	sub, err := h.Repo.UpsertSubscription(r.Context(), userID, req.OriginalTransactionID, req.ProductID, req.Status, req.ExpiresAt, req.Environment)
+	sub, err := h.Repo.SaveVerifiedSubscription(r.Context(), userID, verified)
 
 	// 4. Error Mapping
 	if err != nil {
+		if errors.Is(err, repository.ErrSubscriptionClaimed) {
+			http.Error(w, "Subscription belongs to another account", http.StatusConflict)
+			return
+		}
 		log.Printf("Upsert subscription error: %v", err)
 		http.Error(w, "Failed to upsert subscription", http.StatusInternalServerError)
 		return
 	}
 
+	h.Entitlements.Invalidate(userID)
+
+	// Google refunds purchases that stay unacknowledged; a failure here is
+	// retried when the next developer notification arrives
+	if playPurchase != nil && playPurchase.NeedsAcknowledgement() {
+		if err := h.Play.Acknowledge(r.Context(), playPurchase.ProductID(), playPurchase.PurchaseToken); err != nil {
+			log.Printf("Acknowledge Google Play purchase error: %v", err)
+		}
+	}
+
 	// 5. Response Construction
-	w.WriteHeader(http.StatusOK) // or Created? Upsert is mixed. 200 OK is safe.
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(sub)
 }
 
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/appstore"
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository"
@@ -17,17 +19,17 @@ import (
 // --- Mocks ---
 
 type mockSubscriptionRepo struct {
-	UpsertSubscriptionFunc             func(ctx context.Context, userID uuid.UUID, originalTransactionID string, productID string, status string, expiresAt time.Time, environment string) (*models.Subscription, error)
+	SaveVerifiedSubscriptionFunc       func(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error)
 	GetSubscriptionByUserIDFunc        func(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) (*models.Subscription, error)
 	GetSubscriptionByTransactionIDFunc func(ctx context.Context, transactionID string, userID uuid.UUID) (*models.Subscription, error)
 	DeleteSubscriptionFunc             func(ctx context.Context, userID uuid.UUID) error
 }
 
-func (m *mockSubscriptionRepo) UpsertSubscription(ctx context.Context, userID uuid.UUID, originalTransactionID string, productID string, status string, expiresAt time.Time, environment string) (*models.Subscription, error) {
-	if m.UpsertSubscriptionFunc != nil {
-		return m.UpsertSubscriptionFunc(ctx, userID, originalTransactionID, productID, status, expiresAt, environment)
+func (m *mockSubscriptionRepo) SaveVerifiedSubscription(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error) {
+	if m.SaveVerifiedSubscriptionFunc != nil {
+		return m.SaveVerifiedSubscriptionFunc(ctx, userID, sub)
 	}
-	return &models.Subscription{UserID: userID, ProductID: productID}, nil
+	return &models.Subscription{UserID: userID, ProductID: sub.ProductID}, nil
 }
 
 func (m *mockSubscriptionRepo) GetSubscriptionByUserID(ctx context.Context, userID uuid.UUID, viewerID uuid.UUID) (*models.Subscription, error) {
@@ -51,14 +53,49 @@ func (m *mockSubscriptionRepo) DeleteSubscription(ctx context.Context, userID uu
 	return nil
 }
 
+type mockPlanInvalidator struct {
+	Invalidated []uuid.UUID
+}
+
+func (m *mockPlanInvalidator) Invalidate(userID uuid.UUID) {
+	m.Invalidated = append(m.Invalidated, userID)
+}
+
+type mockTransactionVerifier struct {
+	VerifyTransactionFunc func(signed string) (*appstore.Transaction, error)
+}
+
+func (m *mockTransactionVerifier) VerifyTransaction(signed string) (*appstore.Transaction, error) {
+	if m.VerifyTransactionFunc != nil {
+		return m.VerifyTransactionFunc(signed)
+	}
+	return &appstore.Transaction{
+		TransactionID:         "2",
+		OriginalTransactionID: "1",
+		ProductID:             "premium",
+		ExpiresDate:           time.Now().Add(time.Hour).UnixMilli(),
+		Environment:           "Sandbox",
+	}, nil
+}
+
 // --- Tests ---
 
 func TestUpsertSubscription_Success(t *testing.T) {
-	h := NewSubscriptionHandler(&mockSubscriptionRepo{})
+	var saved models.VerifiedSubscription
+	mockRepo := &mockSubscriptionRepo{
+		SaveVerifiedSubscriptionFunc: func(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error) {
+			saved = sub
+			return &models.Subscription{UserID: userID, ProductID: sub.ProductID, Status: sub.Status}, nil
+		},
+	}
+	invalidator := &mockPlanInvalidator{}
+	h := NewSubscriptionHandler(mockRepo, &mockTransactionVerifier{}, googleplay.NewFakeClient(), invalidator)
 
-	body := `{"product_id": "premium", "status": "active"}`
+	// Client-supplied status and product are ignored
+	body := `{"signed_transaction": "header.payload.sig", "product_id": "lifetime", "status": "active"}`
+	userID := uuid.New()
 	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(body))
-	req = testutils.InjectUserID(req, uuid.New().String())
+	req = testutils.InjectUserID(req, userID.String())
 	rr := httptest.NewRecorder()
 
 	h.UpsertSubscription(rr, req)
@@ -66,10 +103,192 @@ func TestUpsertSubscription_Success(t *testing.T) {
 	if rr.Code != http.StatusOK {
 		t.Errorf("expected 200 OK, got %d", rr.Code)
 	}
+	if saved.ProductID != "premium" || saved.Status != models.SubscriptionActive || saved.Environment != "sandbox" {
+		t.Errorf("expected the verified transaction to be saved, got %+v", saved)
+	}
+	if len(invalidator.Invalidated) != 1 || invalidator.Invalidated[0] != userID {
+		t.Errorf("expected the cached plan to be invalidated, got %v", invalidator.Invalidated)
+	}
+}
+
+func TestUpsertSubscription_Errors(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name     string
+		body     string
+		verify   func(signed string) (*appstore.Transaction, error)
+		repoErr  error
+		expected int
+	}{
+		{
+			name:     "Missing transaction",
+			body:     `{"product_id": "premium", "status": "active"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid signature",
+			body: `{"signed_transaction": "forged"}`,
+			verify: func(signed string) (*appstore.Transaction, error) {
+				return nil, appstore.ErrInvalidSignature
+			},
+			expected: http.StatusBadRequest,
+		},
+		{
+			name: "Other account",
+			body: `{"signed_transaction": "header.payload.sig"}`,
+			verify: func(signed string) (*appstore.Transaction, error) {
+				return &appstore.Transaction{OriginalTransactionID: "1", ProductID: "premium", AppAccountToken: uuid.New().String()}, nil
+			},
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "Claimed by another user",
+			body:     `{"signed_transaction": "header.payload.sig"}`,
+			repoErr:  repository.ErrSubscriptionClaimed,
+			expected: http.StatusConflict,
+		},
+		{
+			name:     "Unknown store",
+			body:     `{"store": "steam", "purchase_token": "token"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Missing purchase token",
+			body:     `{"store": "google_play"}`,
+			expected: http.StatusBadRequest,
+		},
+		{
+			name:     "Unknown purchase token",
+			body:     `{"store": "google_play", "purchase_token": "forged"}`,
+			expected: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockSubscriptionRepo{
+				SaveVerifiedSubscriptionFunc: func(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error) {
+					if tt.repoErr != nil {
+						return nil, tt.repoErr
+					}
+					return &models.Subscription{UserID: userID}, nil
+				},
+			}
+			h := NewSubscriptionHandler(mockRepo, &mockTransactionVerifier{VerifyTransactionFunc: tt.verify}, googleplay.NewFakeClient(), &mockPlanInvalidator{})
+
+			req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, userID.String())
+			rr := httptest.NewRecorder()
+
+			h.UpsertSubscription(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
+
+func TestUpsertSubscription_GooglePlay(t *testing.T) {
+	userID := uuid.New()
+	play := googleplay.NewFakeClient()
+	play.SetPurchase("play-token", &googleplay.SubscriptionPurchase{
+		SubscriptionState:    googleplay.StateActive,
+		LatestOrderID:        "GPA.1234",
+		AcknowledgementState: googleplay.AcknowledgementPending,
+		LineItems: []googleplay.LineItem{
+			{ProductID: "premium_monthly", ExpiryTime: time.Now().Add(30 * 24 * time.Hour)},
+		},
+	})
+	play.Purchases["play-token"].ExternalAccountIdentifiers.ObfuscatedExternalAccountID = userID.String()
+
+	var saved models.VerifiedSubscription
+	mockRepo := &mockSubscriptionRepo{
+		SaveVerifiedSubscriptionFunc: func(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error) {
+			saved = sub
+			return &models.Subscription{UserID: userID, Store: sub.Store}, nil
+		},
+	}
+	h := NewSubscriptionHandler(mockRepo, &mockTransactionVerifier{}, play, &mockPlanInvalidator{})
+
+	body := `{"store": "google_play", "purchase_token": "play-token"}`
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.UpsertSubscription(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 OK, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if saved.Store != models.StoreGooglePlay || saved.OriginalTransactionID != "play-token" ||
+		saved.TransactionID != "GPA.1234" || saved.ProductID != "premium_monthly" || saved.Status != models.SubscriptionActive {
+		t.Errorf("expected the fetched purchase to be saved, got %+v", saved)
+	}
+	if len(play.Acknowledged) != 1 || play.Acknowledged[0] != "play-token" {
+		t.Errorf("expected the purchase to be acknowledged, got %v", play.Acknowledged)
+	}
+}
+
+func TestUpsertSubscription_GooglePlayTestPurchase(t *testing.T) {
+	userID := uuid.New()
+	play := googleplay.NewFakeClient()
+	play.SetPurchase("tester-token", &googleplay.SubscriptionPurchase{
+		SubscriptionState: googleplay.StateActive,
+		LineItems: []googleplay.LineItem{
+			{ProductID: "premium_monthly", ExpiryTime: time.Now().Add(30 * 24 * time.Hour)},
+		},
+		TestPurchase: &struct{}{},
+	})
+
+	saved := false
+	mockRepo := &mockSubscriptionRepo{
+		SaveVerifiedSubscriptionFunc: func(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error) {
+			saved = true
+			return &models.Subscription{UserID: userID, Store: sub.Store}, nil
+		},
+	}
+	h := NewSubscriptionHandler(mockRepo, &mockTransactionVerifier{}, play, &mockPlanInvalidator{})
+
+	body := `{"store": "google_play", "purchase_token": "tester-token"}`
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.UpsertSubscription(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a license tester's purchase, got %d", rr.Code)
+	}
+	if saved {
+		t.Error("expected the test purchase not to be saved")
+	}
+}
+
+func TestUpsertSubscription_GooglePlayNotConfigured(t *testing.T) {
+	mockRepo := &mockSubscriptionRepo{
+		SaveVerifiedSubscriptionFunc: func(ctx context.Context, userID uuid.UUID, sub models.VerifiedSubscription) (*models.Subscription, error) {
+			t.Error("expected no purchase to be saved without Google Play")
+			return nil, nil
+		},
+	}
+	h := NewSubscriptionHandler(mockRepo, &mockTransactionVerifier{}, &googleplay.Client{}, &mockPlanInvalidator{})
+
+	body := `{"store": "google_play", "purchase_token": "play-token"}`
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.UpsertSubscription(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 while Google Play is not configured, got %d", rr.Code)
+	}
 }
 
 func TestGetMySubscription_Success(t *testing.T) {
-	h := NewSubscriptionHandler(&mockSubscriptionRepo{})
+	h := NewSubscriptionHandler(&mockSubscriptionRepo{}, &mockTransactionVerifier{}, googleplay.NewFakeClient(), &mockPlanInvalidator{})
 
 	req := httptest.NewRequest("GET", "/subscriptions/me", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -88,7 +307,7 @@ func TestGetMySubscription_NotFound(t *testing.T) {
 			return nil, repository.ErrSubscriptionNotFound
 		},
 	}
-	h := NewSubscriptionHandler(mockRepo)
+	h := NewSubscriptionHandler(mockRepo, &mockTransactionVerifier{}, googleplay.NewFakeClient(), &mockPlanInvalidator{})
 
 	req := httptest.NewRequest("GET", "/subscriptions/me", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
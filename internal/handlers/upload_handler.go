@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
+)
+
+type UploadScanner interface {
+	CreateUpload(ctx context.Context, userID uuid.UUID, objectKey string, contentType string, sizeBytes int64, expiresAt time.Time) (*models.Upload, error)
+}
+
+// UploadPresigner issues the URL a client transfers an upload's file to.
+type UploadPresigner interface {
+	PresignUpload(ctx context.Context, upload *models.Upload) (string, error)
+}
+
+type UploadHandler struct {
+	Repo    UploadScanner
+	Uploads UploadPresigner
+}
+
+func NewUploadHandler(r UploadScanner, u UploadPresigner) *UploadHandler {
+	return &UploadHandler{Repo: r, Uploads: u}
+}
+
+// CreateUpload starts an upload. The client PUTs the file straight to
+// blob storage at the returned URL, then commits the upload by its ID, for
+// example as a workout image.
+func (h *UploadHandler) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. Request Decoding
+	var req struct {
+		ContentType string `json:"content_type"`
+		SizeBytes   int64  `json:"size_bytes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := uploads.CheckUpload(req.ContentType, req.SizeBytes); err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	// 3. Repo Call
+	now := time.Now()
+	upload, err := h.Repo.CreateUpload(r.Context(), userID, uploads.NewUploadKey(userID), req.ContentType, req.SizeBytes, now.Add(uploads.CommitWindow))
+
+	// 4. Error Mapping
+	if err != nil {
+		log.Printf("Create upload error: %v", err)
+		http.Error(w, "Failed to create upload", http.StatusInternalServerError)
+		return
+	}
+
+	uploadURL, err := h.Uploads.PresignUpload(r.Context(), upload)
+	if err != nil {
+		log.Printf("Presign upload error: %v", err)
+		http.Error(w, "Failed to create upload", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.CreateUploadResponse{
+		UploadID:  upload.ID,
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   map[string]string{"Content-Type": upload.ContentType},
+		ExpiresAt: now.Add(uploads.UploadURLTTL).UTC(),
+	})
+}
+
+// writeUploadError maps a rejected upload to its status code.
+func writeUploadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, uploads.ErrUnsupportedType):
+		http.Error(w, "Only JPEG and PNG images are supported", http.StatusUnsupportedMediaType)
+	case errors.Is(err, uploads.ErrTooLarge):
+		http.Error(w, "Image is too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, uploads.ErrNotUploaded):
+		http.Error(w, "Upload has not been completed", http.StatusConflict)
+	case errors.Is(err, uploads.ErrInvalidImage):
+		http.Error(w, "Invalid image", http.StatusBadRequest)
+	default:
+		log.Printf("Process upload error: %v", err)
+		http.Error(w, "Failed to process upload", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+// --- Mocks ---
+
+type mockUploadRepo struct {
+	CreateUploadFunc func(ctx context.Context, userID uuid.UUID, objectKey string, contentType string, sizeBytes int64, expiresAt time.Time) (*models.Upload, error)
+}
+
+func (m *mockUploadRepo) CreateUpload(ctx context.Context, userID uuid.UUID, objectKey string, contentType string, sizeBytes int64, expiresAt time.Time) (*models.Upload, error) {
+	if m.CreateUploadFunc != nil {
+		return m.CreateUploadFunc(ctx, userID, objectKey, contentType, sizeBytes, expiresAt)
+	}
+	return &models.Upload{ID: uuid.New(), UserID: userID, ObjectKey: objectKey, ContentType: contentType, SizeBytes: sizeBytes, ExpiresAt: expiresAt}, nil
+}
+
+type mockUploadPresigner struct{}
+
+func (m *mockUploadPresigner) PresignUpload(ctx context.Context, upload *models.Upload) (string, error) {
+	return "https://upload.example/" + upload.ObjectKey, nil
+}
+
+// --- Tests ---
+
+func TestCreateUpload_Success(t *testing.T) {
+	userID := uuid.New()
+	var gotKey string
+	repo := &mockUploadRepo{
+		CreateUploadFunc: func(ctx context.Context, u uuid.UUID, objectKey string, contentType string, sizeBytes int64, expiresAt time.Time) (*models.Upload, error) {
+			gotKey = objectKey
+			return &models.Upload{ID: uuid.New(), UserID: u, ObjectKey: objectKey, ContentType: contentType, SizeBytes: sizeBytes}, nil
+		},
+	}
+	h := NewUploadHandler(repo, &mockUploadPresigner{})
+
+	req := httptest.NewRequest("POST", "/uploads", strings.NewReader(`{"content_type": "image/png", "size_bytes": 2048}`))
+	req = testutils.InjectUserID(req, userID.String())
+	rr := httptest.NewRecorder()
+
+	h.CreateUpload(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 Created, got %d", rr.Code)
+	}
+	if !strings.HasPrefix(gotKey, "uploads/"+userID.String()+"/") {
+		t.Errorf("expected a key under the user's uploads, got %q", gotKey)
+	}
+	var resp models.CreateUploadResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if resp.UploadURL != "https://upload.example/"+gotKey || resp.Method != http.MethodPut {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if resp.Headers["Content-Type"] != "image/png" {
+		t.Errorf("expected the Content-Type header to be required, got %v", resp.Headers)
+	}
+}
+
+func TestCreateUpload_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"Unsupported Type", `{"content_type": "image/gif", "size_bytes": 100}`, http.StatusUnsupportedMediaType},
+		{"Too Large", `{"content_type": "image/jpeg", "size_bytes": 104857600}`, http.StatusRequestEntityTooLarge},
+		{"Empty", `{"content_type": "image/jpeg", "size_bytes": 0}`, http.StatusBadRequest},
+		{"Malformed", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewUploadHandler(&mockUploadRepo{}, &mockUploadPresigner{})
+
+			req := httptest.NewRequest("POST", "/uploads", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.CreateUpload(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
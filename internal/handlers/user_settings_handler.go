@@ -6,11 +6,14 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/push"
 	"github.com/rotsu1/jimu-backend/internal/repository"
+	"github.com/rotsu1/jimu-backend/internal/streak"
 )
 
 type UserSettingsScanner interface {
@@ -83,6 +86,30 @@ func (h *UserSettingsHandler) UpdateMySettings(w http.ResponseWriter, r *http.Re
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.Timezone != nil {
+		// LoadLocation accepts "" and "Local", which mean the server's zone rather than the user's
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+			http.Error(w, "Invalid timezone", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.StreakRule != nil && *req.StreakRule != streak.RuleDaily && *req.StreakRule != streak.RuleWeekly {
+		http.Error(w, "Invalid streak rule", http.StatusBadRequest)
+		return
+	}
+	if req.StreakWeeklyTarget != nil && (*req.StreakWeeklyTarget < 1 || *req.StreakWeeklyTarget > 7) {
+		http.Error(w, "Invalid streak weekly target", http.StatusBadRequest)
+		return
+	}
+	for _, clock := range []*string{req.QuietHoursStart, req.QuietHoursEnd} {
+		if clock == nil || *clock == "" {
+			continue
+		}
+		if _, err := push.ParseClock(*clock); err != nil {
+			http.Error(w, "Invalid quiet hours", http.StatusBadRequest)
+			return
+		}
+	}
 
 	// 3. Repo Call
 	err := h.Repo.UpdateUserSettings(r.Context(), ctxID, req)
@@ -84,3 +84,49 @@ func TestUpdateMySettings_Error(t *testing.T) {
 		t.Errorf("expected 500 Internal Server Error, got %d", rr.Code)
 	}
 }
+
+func TestUpdateMySettings_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"valid timezone", `{"timezone": "Asia/Tokyo"}`, http.StatusNoContent},
+		{"unknown timezone", `{"timezone": "Mars/Olympus"}`, http.StatusBadRequest},
+		{"empty timezone", `{"timezone": ""}`, http.StatusBadRequest},
+		{"weekly rule", `{"streak_rule": "weekly", "streak_weekly_target": 3}`, http.StatusNoContent},
+		{"unknown rule", `{"streak_rule": "monthly"}`, http.StatusBadRequest},
+		{"target too low", `{"streak_weekly_target": 0}`, http.StatusBadRequest},
+		{"target too high", `{"streak_weekly_target": 8}`, http.StatusBadRequest},
+		{"quiet hours", `{"quiet_hours_start": "22:00", "quiet_hours_end": "07:00"}`, http.StatusNoContent},
+		{"clear quiet hours", `{"quiet_hours_start": "", "quiet_hours_end": ""}`, http.StatusNoContent},
+		{"invalid quiet hours", `{"quiet_hours_start": "25:00"}`, http.StatusBadRequest},
+		{"quiet hours without minutes", `{"quiet_hours_end": "7"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			mockRepo := &mockUserSettingsRepo{
+				UpdateUserSettingsFunc: func(ctx context.Context, id string, updates models.UpdateUserSettingsRequest) error {
+					called = true
+					return nil
+				},
+			}
+			h := NewUserSettingsHandler(mockRepo)
+
+			req := httptest.NewRequest("PUT", "/settings", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.UpdateMySettings(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if tt.expected == http.StatusBadRequest && called {
+				t.Error("repo should not be called for invalid settings")
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
 var ErrMissingPathParam = errors.New("missing path param")
@@ -40,3 +43,54 @@ func GetIDFromRequest(r *http.Request) (uuid.UUID, error) {
 	}
 	return uuid.Parse(parts[len(parts)-1])
 }
+
+// parsePageParams reads the cursor and limit query params of a list endpoint.
+// The limit defaults to pagination.DefaultLimit and is capped at
+// pagination.MaxLimit. Handlers fetch limit+1 rows and build the response with
+// pagination.NewPage so the extra row tells whether there is a next page.
+func parsePageParams(r *http.Request) (cursor *pagination.Cursor, limit int, err error) {
+	limit = pagination.DefaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, 0, errors.New("Invalid limit")
+		}
+	}
+	if limit > pagination.MaxLimit {
+		limit = pagination.MaxLimit
+	}
+	cursor, err = pagination.Decode(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, 0, errors.New("Invalid cursor")
+	}
+	return cursor, limit, nil
+}
+
+// parseTimelineInclude reads the sections a timeline client wants, as a
+// comma-separated include (or fields) query param such as "exercises,images".
+// Without either param every section is included.
+func parseTimelineInclude(r *http.Request) (models.TimelineInclude, error) {
+	raw := r.URL.Query().Get("include")
+	if raw == "" {
+		raw = r.URL.Query().Get("fields")
+	}
+	if raw == "" {
+		return models.TimelineIncludeAll, nil
+	}
+
+	var include models.TimelineInclude
+	for _, section := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(section) {
+		case "exercises":
+			include.Exercises = true
+		case "comments":
+			include.Comments = true
+		case "images":
+			include.Images = true
+		case "":
+		default:
+			return models.TimelineInclude{}, errors.New("Invalid include")
+		}
+	}
+	return include, nil
+}
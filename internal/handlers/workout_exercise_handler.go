@@ -15,8 +15,8 @@ import (
 
 type WorkoutExerciseScanner interface {
 	CreateWorkoutExercise(ctx context.Context, workoutID uuid.UUID, exerciseID uuid.UUID, orderIndex int, memo *string, restTimerSeconds *int, userID uuid.UUID) (*models.WorkoutExercise, error)
-	GetWorkoutExerciseByID(ctx context.Context, id uuid.UUID) (*models.WorkoutExercise, error)
-	GetWorkoutExercisesByWorkoutID(ctx context.Context, workoutID uuid.UUID) ([]*models.WorkoutExercise, error)
+	GetWorkoutExerciseByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutExercise, error)
+	GetWorkoutExercisesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutExercise, error)
 	UpdateWorkoutExercise(ctx context.Context, workoutExerciseID uuid.UUID, updates models.UpdateWorkoutExerciseRequest, userID uuid.UUID) error
 	DeleteWorkoutExercise(ctx context.Context, workoutExerciseID uuid.UUID, userID uuid.UUID) error
 }
@@ -16,8 +16,8 @@ import (
 
 type mockWorkoutExerciseRepo struct {
 	CreateWorkoutExerciseFunc          func(ctx context.Context, workoutID uuid.UUID, exerciseID uuid.UUID, orderIndex int, memo *string, restTimerSeconds *int, userID uuid.UUID) (*models.WorkoutExercise, error)
-	GetWorkoutExerciseByIDFunc         func(ctx context.Context, id uuid.UUID) (*models.WorkoutExercise, error)
-	GetWorkoutExercisesByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID) ([]*models.WorkoutExercise, error)
+	GetWorkoutExerciseByIDFunc         func(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutExercise, error)
+	GetWorkoutExercisesByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutExercise, error)
 	UpdateWorkoutExerciseFunc          func(ctx context.Context, workoutExerciseID uuid.UUID, updates models.UpdateWorkoutExerciseRequest, userID uuid.UUID) error
 	DeleteWorkoutExerciseFunc          func(ctx context.Context, workoutExerciseID uuid.UUID, userID uuid.UUID) error
 }
@@ -29,16 +29,16 @@ func (m *mockWorkoutExerciseRepo) CreateWorkoutExercise(ctx context.Context, wor
 	return &models.WorkoutExercise{ID: uuid.New(), WorkoutID: workoutID, ExerciseID: exerciseID}, nil
 }
 
-func (m *mockWorkoutExerciseRepo) GetWorkoutExerciseByID(ctx context.Context, id uuid.UUID) (*models.WorkoutExercise, error) {
+func (m *mockWorkoutExerciseRepo) GetWorkoutExerciseByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutExercise, error) {
 	if m.GetWorkoutExerciseByIDFunc != nil {
-		return m.GetWorkoutExerciseByIDFunc(ctx, id)
+		return m.GetWorkoutExerciseByIDFunc(ctx, id, viewerID)
 	}
 	return &models.WorkoutExercise{ID: id}, nil
 }
 
-func (m *mockWorkoutExerciseRepo) GetWorkoutExercisesByWorkoutID(ctx context.Context, workoutID uuid.UUID) ([]*models.WorkoutExercise, error) {
+func (m *mockWorkoutExerciseRepo) GetWorkoutExercisesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutExercise, error) {
 	if m.GetWorkoutExercisesByWorkoutIDFunc != nil {
-		return m.GetWorkoutExercisesByWorkoutIDFunc(ctx, workoutID)
+		return m.GetWorkoutExercisesByWorkoutIDFunc(ctx, workoutID, viewerID)
 	}
 	return []*models.WorkoutExercise{}, nil
 }
@@ -6,32 +6,32 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
 type WorkoutScanner interface {
 	Create(ctx context.Context, userID uuid.UUID, name *string, comment *string, startedAt time.Time, endedAt time.Time, durationSeconds int) (*models.Workout, error)
 	GetWorkoutByID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) (*models.Workout, error)
-	GetWorkoutsByUserID(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.Workout, error)
+	GetWorkoutsByUserID(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error)
 	UpdateWorkout(ctx context.Context, id uuid.UUID, updates models.UpdateWorkoutRequest, userID uuid.UUID) error
 	DeleteWorkout(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
-	GetTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error)
-	GetFollowingTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error)
-	GetForYouTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error)
+	GetTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error)
+	GetFollowingTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error)
 }
 
 type WorkoutHandler struct {
-	Repo WorkoutScanner
+	Repo   WorkoutScanner
+	Images ImageURLSigner
 }
 
-func NewWorkoutHandler(r WorkoutScanner) *WorkoutHandler {
-	return &WorkoutHandler{Repo: r}
+func NewWorkoutHandler(r WorkoutScanner, i ImageURLSigner) *WorkoutHandler {
+	return &WorkoutHandler{Repo: r, Images: i}
 }
 
 func (h *WorkoutHandler) CreateWorkout(w http.ResponseWriter, r *http.Request) {
@@ -141,11 +141,14 @@ func (h *WorkoutHandler) ListWorkouts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	limit := 20 // TODO: From query
-	offset := 0
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// 3. Repo Call
-	workouts, err := h.Repo.GetWorkoutsByUserID(r.Context(), targetID, userID, limit, offset)
+	workouts, err := h.Repo.GetWorkoutsByUserID(r.Context(), targetID, userID, cursor, limit+1)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -156,10 +159,7 @@ func (h *WorkoutHandler) ListWorkouts(w http.ResponseWriter, r *http.Request) {
 
 	// 5. Response Construction
 	w.Header().Set("Content-Type", "application/json")
-	if workouts == nil {
-		workouts = []*models.Workout{}
-	}
-	json.NewEncoder(w).Encode(workouts)
+	json.NewEncoder(w).Encode(pagination.NewPage(workouts, limit, workoutCursor))
 }
 
 func (h *WorkoutHandler) UpdateWorkout(w http.ResponseWriter, r *http.Request) {
@@ -269,14 +269,19 @@ func (h *WorkoutHandler) GetTimelineWorkouts(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	limit, offset, err := parseLimitOffset(r)
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	include, err := parseTimelineInclude(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// 3. Repo Call
-	workouts, err := h.Repo.GetTimelineWorkouts(r.Context(), userID, targetID, limit, offset)
+	workouts, err := h.Repo.GetTimelineWorkouts(r.Context(), userID, targetID, include, cursor, limit+1)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -286,11 +291,9 @@ func (h *WorkoutHandler) GetTimelineWorkouts(w http.ResponseWriter, r *http.Requ
 	}
 
 	// 5. Response Construction
+	signTimelineImages(r.Context(), h.Images, workouts)
 	w.Header().Set("Content-Type", "application/json")
-	if workouts == nil {
-		workouts = []*models.TimelineWorkout{}
-	}
-	json.NewEncoder(w).Encode(workouts)
+	json.NewEncoder(w).Encode(pagination.NewPage(workouts, limit, timelineWorkoutCursor))
 }
 
 func (h *WorkoutHandler) GetFollowingTimelineWorkouts(w http.ResponseWriter, r *http.Request) {
@@ -305,73 +308,33 @@ func (h *WorkoutHandler) GetFollowingTimelineWorkouts(w http.ResponseWriter, r *
 		return
 	}
 
-	limit, offset, err := parseLimitOffset(r)
+	cursor, limit, err := parsePageParams(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-
-	workouts, err := h.Repo.GetFollowingTimelineWorkouts(r.Context(), userID, limit, offset)
-	if err != nil {
-		log.Printf("Get following timeline workouts error: %v", err)
-		http.Error(w, "Failed to get following timeline workouts", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	if workouts == nil {
-		workouts = []*models.TimelineWorkout{}
-	}
-	json.NewEncoder(w).Encode(workouts)
-}
-
-func (h *WorkoutHandler) GetForYouTimelineWorkouts(w http.ResponseWriter, r *http.Request) {
-	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
-	if !ok {
-		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
-		return
-	}
-	userID, err := uuid.Parse(ctxID)
-	if err != nil {
-		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
-		return
-	}
-
-	limit, offset, err := parseLimitOffset(r)
+	include, err := parseTimelineInclude(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	workouts, err := h.Repo.GetForYouTimelineWorkouts(r.Context(), userID, limit, offset)
+	workouts, err := h.Repo.GetFollowingTimelineWorkouts(r.Context(), userID, include, cursor, limit+1)
 	if err != nil {
-		log.Printf("Get for-you timeline workouts error: %v", err)
-		http.Error(w, "Failed to get for-you timeline workouts", http.StatusInternalServerError)
+		log.Printf("Get following timeline workouts error: %v", err)
+		http.Error(w, "Failed to get following timeline workouts", http.StatusInternalServerError)
 		return
 	}
 
+	signTimelineImages(r.Context(), h.Images, workouts)
 	w.Header().Set("Content-Type", "application/json")
-	if workouts == nil {
-		workouts = []*models.TimelineWorkout{}
-	}
-	json.NewEncoder(w).Encode(workouts)
+	json.NewEncoder(w).Encode(pagination.NewPage(workouts, limit, timelineWorkoutCursor))
 }
 
-// parseLimitOffset reads limit and offset from request query; returns (20, 0) by default.
-func parseLimitOffset(r *http.Request) (limit int, offset int, err error) {
-	limit = 20
-	offset = 0
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil {
-			return 0, 0, errors.New("Invalid limit")
-		}
-	}
-	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
-		offset, err = strconv.Atoi(offsetStr)
-		if err != nil {
-			return 0, 0, errors.New("Invalid offset")
-		}
-	}
-	return limit, offset, nil
+func workoutCursor(w *models.Workout) pagination.Cursor {
+	return pagination.At(w.StartedAt, w.ID)
+}
+
+func timelineWorkoutCursor(w *models.TimelineWorkout) pagination.Cursor {
+	return pagination.At(w.StartedAt, w.ID)
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +12,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -19,10 +22,9 @@ import (
 type mockWorkoutRepo struct {
 	CreateFunc                        func(ctx context.Context, userID uuid.UUID, name *string, comment *string, startedAt time.Time, endedAt time.Time, durationSeconds int) (*models.Workout, error)
 	GetWorkoutByIDFunc                func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) (*models.Workout, error)
-	GetWorkoutsByUserIDFunc           func(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.Workout, error)
-	GetTimelineWorkoutsFunc           func(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error)
-	GetFollowingTimelineWorkoutsFunc  func(ctx context.Context, viewerID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error)
-	GetForYouTimelineWorkoutsFunc     func(ctx context.Context, viewerID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error)
+	GetWorkoutsByUserIDFunc           func(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error)
+	GetTimelineWorkoutsFunc           func(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error)
+	GetFollowingTimelineWorkoutsFunc  func(ctx context.Context, viewerID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error)
 	UpdateWorkoutFunc                 func(ctx context.Context, id uuid.UUID, updates models.UpdateWorkoutRequest, userID uuid.UUID) error
 	DeleteWorkoutFunc                 func(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
 }
@@ -41,9 +43,9 @@ func (m *mockWorkoutRepo) GetWorkoutByID(ctx context.Context, workoutID uuid.UUI
 	return &models.Workout{ID: workoutID}, nil
 }
 
-func (m *mockWorkoutRepo) GetWorkoutsByUserID(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.Workout, error) {
+func (m *mockWorkoutRepo) GetWorkoutsByUserID(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error) {
 	if m.GetWorkoutsByUserIDFunc != nil {
-		return m.GetWorkoutsByUserIDFunc(ctx, targetID, viewerID, limit, offset)
+		return m.GetWorkoutsByUserIDFunc(ctx, targetID, viewerID, cursor, limit)
 	}
 	return []*models.Workout{}, nil
 }
@@ -62,23 +64,16 @@ func (m *mockWorkoutRepo) DeleteWorkout(ctx context.Context, id uuid.UUID, userI
 	return nil
 }
 
-func (m *mockWorkoutRepo) GetTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error) {
+func (m *mockWorkoutRepo) GetTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, targetID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
 	if m.GetTimelineWorkoutsFunc != nil {
-		return m.GetTimelineWorkoutsFunc(ctx, viewerID, targetID, limit, offset)
+		return m.GetTimelineWorkoutsFunc(ctx, viewerID, targetID, include, cursor, limit)
 	}
 	return []*models.TimelineWorkout{}, nil
 }
 
-func (m *mockWorkoutRepo) GetFollowingTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error) {
+func (m *mockWorkoutRepo) GetFollowingTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
 	if m.GetFollowingTimelineWorkoutsFunc != nil {
-		return m.GetFollowingTimelineWorkoutsFunc(ctx, viewerID, limit, offset)
-	}
-	return []*models.TimelineWorkout{}, nil
-}
-
-func (m *mockWorkoutRepo) GetForYouTimelineWorkouts(ctx context.Context, viewerID uuid.UUID, limit int, offset int) ([]*models.TimelineWorkout, error) {
-	if m.GetForYouTimelineWorkoutsFunc != nil {
-		return m.GetForYouTimelineWorkoutsFunc(ctx, viewerID, limit, offset)
+		return m.GetFollowingTimelineWorkoutsFunc(ctx, viewerID, include, cursor, limit)
 	}
 	return []*models.TimelineWorkout{}, nil
 }
@@ -86,7 +81,7 @@ func (m *mockWorkoutRepo) GetForYouTimelineWorkouts(ctx context.Context, viewerI
 // --- Tests ---
 
 func TestCreateWorkout_Success(t *testing.T) {
-	h := NewWorkoutHandler(&mockWorkoutRepo{})
+	h := NewWorkoutHandler(&mockWorkoutRepo{}, &mockImageURLSigner{})
 
 	body := `{"name": "Morning Workout", "duration_seconds": 3600}`
 	req := httptest.NewRequest("POST", "/workouts", strings.NewReader(body))
@@ -101,7 +96,7 @@ func TestCreateWorkout_Success(t *testing.T) {
 }
 
 func TestGetWorkout_Success(t *testing.T) {
-	h := NewWorkoutHandler(&mockWorkoutRepo{})
+	h := NewWorkoutHandler(&mockWorkoutRepo{}, &mockImageURLSigner{})
 
 	req := httptest.NewRequest("GET", "/workouts/00000000-0000-0000-0000-000000000001", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -115,7 +110,7 @@ func TestGetWorkout_Success(t *testing.T) {
 }
 
 func TestListWorkouts_Success(t *testing.T) {
-	h := NewWorkoutHandler(&mockWorkoutRepo{})
+	h := NewWorkoutHandler(&mockWorkoutRepo{}, &mockImageURLSigner{})
 
 	req := httptest.NewRequest("GET", "/workouts", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -129,7 +124,7 @@ func TestListWorkouts_Success(t *testing.T) {
 }
 
 func TestUpdateWorkout_Success(t *testing.T) {
-	h := NewWorkoutHandler(&mockWorkoutRepo{})
+	h := NewWorkoutHandler(&mockWorkoutRepo{}, &mockImageURLSigner{})
 
 	body := `{"name": "Updated Name"}`
 	req := httptest.NewRequest("PUT", "/workouts/00000000-0000-0000-0000-000000000001", strings.NewReader(body))
@@ -144,7 +139,7 @@ func TestUpdateWorkout_Success(t *testing.T) {
 }
 
 func TestDeleteWorkout_Success(t *testing.T) {
-	h := NewWorkoutHandler(&mockWorkoutRepo{})
+	h := NewWorkoutHandler(&mockWorkoutRepo{}, &mockImageURLSigner{})
 
 	req := httptest.NewRequest("DELETE", "/workouts/00000000-0000-0000-0000-000000000001", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -163,7 +158,7 @@ func TestDeleteWorkout_NotFound(t *testing.T) {
 			return repository.ErrWorkoutNotFound
 		},
 	}
-	h := NewWorkoutHandler(mockRepo)
+	h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
 
 	req := httptest.NewRequest("DELETE", "/workouts/00000000-0000-0000-0000-000000000001", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -213,8 +208,8 @@ func TestGetTimelineWorkouts(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "Invalid offset - 400",
-			url:            "/workouts/timeline?offset=xyz",
+			name:           "Invalid cursor - 400",
+			url:            "/workouts/timeline?cursor=xyz",
 			injectUserID:   true,
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -230,14 +225,14 @@ func TestGetTimelineWorkouts(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockWorkoutRepo{
-				GetTimelineWorkoutsFunc: func(ctx context.Context, vID, tID uuid.UUID, limit, offset int) ([]*models.TimelineWorkout, error) {
+				GetTimelineWorkoutsFunc: func(ctx context.Context, vID, tID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
 					if tt.mockErr != nil {
 						return nil, tt.mockErr
 					}
 					return tt.mockWorkouts, nil
 				},
 			}
-			h := NewWorkoutHandler(mockRepo)
+			h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
 
 			req := httptest.NewRequest("GET", tt.url, nil)
 			if tt.injectUserID {
@@ -259,19 +254,19 @@ func TestGetTimelineWorkouts_Success_WithTargetUser(t *testing.T) {
 	targetID := uuid.New()
 
 	mockRepo := &mockWorkoutRepo{
-		GetTimelineWorkoutsFunc: func(ctx context.Context, vID, tID uuid.UUID, limit, offset int) ([]*models.TimelineWorkout, error) {
+		GetTimelineWorkoutsFunc: func(ctx context.Context, vID, tID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
 			if tID != targetID || vID != viewerID {
 				t.Errorf("repo called with targetID=%v viewerID=%v", tID, vID)
 			}
-			if limit != 10 || offset != 5 {
-				t.Errorf("repo called with limit=%d offset=%d", limit, offset)
+			if limit != 11 || cursor == nil {
+				t.Errorf("repo called with limit=%d cursor=%v", limit, cursor)
 			}
 			return []*models.TimelineWorkout{}, nil
 		},
 	}
-	h := NewWorkoutHandler(mockRepo)
+	h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
 
-	req := httptest.NewRequest("GET", "/workouts/timeline?user_id="+targetID.String()+"&limit=10&offset=5", nil)
+	req := httptest.NewRequest("GET", "/workouts/timeline?user_id="+targetID.String()+"&limit=10&cursor="+pagination.At(time.Now(), uuid.New()).Encode(), nil)
 	req = testutils.InjectUserID(req, viewerID.String())
 	rr := httptest.NewRecorder()
 
@@ -282,6 +277,39 @@ func TestGetTimelineWorkouts_Success_WithTargetUser(t *testing.T) {
 	}
 }
 
+func TestGetTimelineWorkouts_SignsImageURLs(t *testing.T) {
+	workoutID := uuid.New()
+	prefix := uploads.WorkoutImagePrefix(workoutID)
+	thumb := prefix + "w_thumb.jpg"
+	mockRepo := &mockWorkoutRepo{
+		GetTimelineWorkoutsFunc: func(ctx context.Context, vID, tID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+			return []*models.TimelineWorkout{{
+				ID:     workoutID,
+				Images: []*models.TimelineWorkoutImages{{ID: uuid.New(), StoragePath: prefix + "w.jpg", ThumbnailPath: &thumb}},
+			}}, nil
+		},
+	}
+	h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
+
+	req := httptest.NewRequest("GET", "/workouts/timeline", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.GetTimelineWorkouts(rr, req)
+
+	var page struct {
+		Items []*models.TimelineWorkout `json:"items"`
+	}
+	json.NewDecoder(rr.Body).Decode(&page)
+	if len(page.Items) != 1 || len(page.Items[0].Images) != 1 {
+		t.Fatalf("expected one workout with one image, got %s", rr.Body.String())
+	}
+	img := page.Items[0].Images[0]
+	if img.URL != "https://signed.example/"+prefix+"w.jpg" || img.ThumbnailURL != "https://signed.example/"+thumb {
+		t.Errorf("expected signed URLs, got %q and %q", img.URL, img.ThumbnailURL)
+	}
+}
+
 func TestGetFollowingTimelineWorkouts(t *testing.T) {
 	viewerID := uuid.New()
 
@@ -313,8 +341,8 @@ func TestGetFollowingTimelineWorkouts(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "Invalid offset - 400",
-			url:            "/workouts/timeline/following?offset=xyz",
+			name:           "Invalid cursor - 400",
+			url:            "/workouts/timeline/following?cursor=xyz",
 			injectUserID:   true,
 			expectedStatus: http.StatusBadRequest,
 		},
@@ -330,14 +358,14 @@ func TestGetFollowingTimelineWorkouts(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockWorkoutRepo{
-				GetFollowingTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, limit, offset int) ([]*models.TimelineWorkout, error) {
+				GetFollowingTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
 					if tt.mockErr != nil {
 						return nil, tt.mockErr
 					}
 					return tt.mockWorkouts, nil
 				},
 			}
-			h := NewWorkoutHandler(mockRepo)
+			h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
 
 			req := httptest.NewRequest("GET", tt.url, nil)
 			if tt.injectUserID {
@@ -358,19 +386,19 @@ func TestGetFollowingTimelineWorkouts_Success_WithLimitOffset(t *testing.T) {
 	viewerID := uuid.New()
 
 	mockRepo := &mockWorkoutRepo{
-		GetFollowingTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, limit, offset int) ([]*models.TimelineWorkout, error) {
+		GetFollowingTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
 			if vID != viewerID {
 				t.Errorf("repo called with viewerID=%v", vID)
 			}
-			if limit != 10 || offset != 5 {
-				t.Errorf("repo called with limit=%d offset=%d", limit, offset)
+			if limit != 11 || cursor == nil {
+				t.Errorf("repo called with limit=%d cursor=%v", limit, cursor)
 			}
 			return []*models.TimelineWorkout{}, nil
 		},
 	}
-	h := NewWorkoutHandler(mockRepo)
+	h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
 
-	req := httptest.NewRequest("GET", "/workouts/timeline/following?limit=10&offset=5", nil)
+	req := httptest.NewRequest("GET", "/workouts/timeline/following?limit=10&cursor="+pagination.At(time.Now(), uuid.New()).Encode(), nil)
 	req = testutils.InjectUserID(req, viewerID.String())
 	rr := httptest.NewRecorder()
 
@@ -381,70 +409,93 @@ func TestGetFollowingTimelineWorkouts_Success_WithLimitOffset(t *testing.T) {
 	}
 }
 
-func TestGetForYouTimelineWorkouts(t *testing.T) {
-	viewerID := uuid.New()
+func TestGetFollowingTimelineWorkouts_NextCursor(t *testing.T) {
+	now := time.Now().UTC()
+	workouts := []*models.TimelineWorkout{
+		{ID: uuid.New(), StartedAt: now},
+		{ID: uuid.New(), StartedAt: now.Add(-time.Hour)},
+		{ID: uuid.New(), StartedAt: now.Add(-2 * time.Hour)},
+	}
+	mockRepo := &mockWorkoutRepo{
+		GetFollowingTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+			return workouts[:limit], nil
+		},
+	}
+	h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
+
+	req := httptest.NewRequest("GET", "/workouts/timeline/following?limit=2", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
 
+	h.GetFollowingTimelineWorkouts(rr, req)
+
+	var page pagination.Page[*models.TimelineWorkout]
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+		t.Fatalf("failed to decode page: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == nil {
+		t.Fatalf("expected 2 items and a next cursor, got %d items, cursor %v", len(page.Items), page.NextCursor)
+	}
+	next, err := pagination.Decode(*page.NextCursor)
+	if err != nil || next.ID != workouts[1].ID || !next.TimeKey().Equal(workouts[1].StartedAt) {
+		t.Errorf("expected cursor at the last item returned, got %+v", next)
+	}
+}
+
+func TestListWorkouts_LimitCapped(t *testing.T) {
+	var gotLimit int
+	mockRepo := &mockWorkoutRepo{
+		GetWorkoutsByUserIDFunc: func(ctx context.Context, targetID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.Workout, error) {
+			gotLimit = limit
+			return nil, nil
+		},
+	}
+	h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
+
+	req := httptest.NewRequest("GET", "/workouts?limit=1000", nil)
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.ListWorkouts(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected 200 OK, got %d", rr.Code)
+	}
+	if gotLimit != pagination.MaxLimit+1 {
+		t.Errorf("expected limit capped at %d, got %d", pagination.MaxLimit+1, gotLimit)
+	}
+}
+
+func TestGetFollowingTimelineWorkouts_Include(t *testing.T) {
 	tests := []struct {
 		name           string
-		url            string
-		injectUserID   bool
-		mockErr        error
-		mockWorkouts   []*models.TimelineWorkout
+		query          string
+		expected       models.TimelineInclude
 		expectedStatus int
 	}{
-		{
-			name:           "Success - returns 200 and timeline",
-			url:            "/workouts/timeline/for-you",
-			injectUserID:   true,
-			mockWorkouts:   []*models.TimelineWorkout{},
-			expectedStatus: http.StatusOK,
-		},
-		{
-			name:           "Unauthorized - no user in context",
-			url:            "/workouts/timeline/for-you",
-			injectUserID:   false,
-			expectedStatus: http.StatusUnauthorized,
-		},
-		{
-			name:           "Invalid limit - 400",
-			url:            "/workouts/timeline/for-you?limit=abc",
-			injectUserID:   true,
-			expectedStatus: http.StatusBadRequest,
-		},
-		{
-			name:           "Invalid offset - 400",
-			url:            "/workouts/timeline/for-you?offset=xyz",
-			injectUserID:   true,
-			expectedStatus: http.StatusBadRequest,
-		},
-		{
-			name:           "DB error - 500",
-			url:            "/workouts/timeline/for-you",
-			injectUserID:   true,
-			mockErr:        context.DeadlineExceeded,
-			expectedStatus: http.StatusInternalServerError,
-		},
+		{"Default includes everything", "", models.TimelineIncludeAll, http.StatusOK},
+		{"Include subset", "?include=exercises,images", models.TimelineInclude{Exercises: true, Images: true}, http.StatusOK},
+		{"Fields alias", "?fields=comments", models.TimelineInclude{Comments: true}, http.StatusOK},
+		{"Unknown section - 400", "?include=exercises,likes", models.TimelineInclude{}, http.StatusBadRequest},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockWorkoutRepo{
-				GetForYouTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, limit, offset int) ([]*models.TimelineWorkout, error) {
-					if tt.mockErr != nil {
-						return nil, tt.mockErr
+				GetFollowingTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, include models.TimelineInclude, cursor *pagination.Cursor, limit int) ([]*models.TimelineWorkout, error) {
+					if include != tt.expected {
+						t.Errorf("repo called with include=%+v, want %+v", include, tt.expected)
 					}
-					return tt.mockWorkouts, nil
+					return []*models.TimelineWorkout{}, nil
 				},
 			}
-			h := NewWorkoutHandler(mockRepo)
+			h := NewWorkoutHandler(mockRepo, &mockImageURLSigner{})
 
-			req := httptest.NewRequest("GET", tt.url, nil)
-			if tt.injectUserID {
-				req = testutils.InjectUserID(req, viewerID.String())
-			}
+			req := httptest.NewRequest("GET", "/workouts/timeline/following"+tt.query, nil)
+			req = testutils.InjectUserID(req, uuid.New().String())
 			rr := httptest.NewRecorder()
 
-			h.GetForYouTimelineWorkouts(rr, req)
+			h.GetFollowingTimelineWorkouts(rr, req)
 
 			if rr.Code != tt.expectedStatus {
 				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
@@ -452,30 +503,3 @@ func TestGetForYouTimelineWorkouts(t *testing.T) {
 		})
 	}
 }
-
-func TestGetForYouTimelineWorkouts_Success_WithLimitOffset(t *testing.T) {
-	viewerID := uuid.New()
-
-	mockRepo := &mockWorkoutRepo{
-		GetForYouTimelineWorkoutsFunc: func(ctx context.Context, vID uuid.UUID, limit, offset int) ([]*models.TimelineWorkout, error) {
-			if vID != viewerID {
-				t.Errorf("repo called with viewerID=%v", vID)
-			}
-			if limit != 10 || offset != 5 {
-				t.Errorf("repo called with limit=%d offset=%d", limit, offset)
-			}
-			return []*models.TimelineWorkout{}, nil
-		},
-	}
-	h := NewWorkoutHandler(mockRepo)
-
-	req := httptest.NewRequest("GET", "/workouts/timeline/for-you?limit=10&offset=5", nil)
-	req = testutils.InjectUserID(req, viewerID.String())
-	rr := httptest.NewRecorder()
-
-	h.GetForYouTimelineWorkouts(rr, req)
-
-	if rr.Code != http.StatusOK {
-		t.Errorf("expected 200 OK, got %d", rr.Code)
-	}
-}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 
@@ -11,21 +12,41 @@ import (
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
 )
 
 type WorkoutImageScanner interface {
-	CreateWorkoutImage(ctx context.Context, workoutID uuid.UUID, storagePath string, displayOrder int, userID uuid.UUID) (*models.WorkoutImage, error)
-	GetWorkoutImageByID(ctx context.Context, id uuid.UUID) (*models.WorkoutImage, error)
-	GetWorkoutImagesByWorkoutID(ctx context.Context, workoutID uuid.UUID) ([]*models.WorkoutImage, error)
+	CreateWorkoutImageFromUpload(ctx context.Context, uploadID uuid.UUID, workoutID uuid.UUID, storagePath string, thumbnailPath string, displayOrder *int, userID uuid.UUID) (*models.WorkoutImage, error)
+	ReorderWorkoutImages(ctx context.Context, workoutID uuid.UUID, imageIDs []uuid.UUID, userID uuid.UUID) error
+	GetWorkoutImageByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutImage, error)
+	GetWorkoutImagesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutImage, error)
 	DeleteWorkoutImage(ctx context.Context, workoutImageID uuid.UUID, userID uuid.UUID) error
 }
 
+type PendingUploadScanner interface {
+	GetPendingUpload(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Upload, error)
+}
+
+// ImageURLSigner turns stored image paths into short-lived read URLs.
+type ImageURLSigner interface {
+	SignURL(ctx context.Context, key string) (string, error)
+}
+
+// WorkoutImageProcessor turns uploads into stored workout images.
+type WorkoutImageProcessor interface {
+	ImageURLSigner
+	ProcessWorkoutImage(ctx context.Context, upload *models.Upload, workoutID uuid.UUID) (*uploads.ProcessedImage, error)
+	Delete(ctx context.Context, keys ...string)
+}
+
 type WorkoutImageHandler struct {
-	Repo WorkoutImageScanner
+	Repo    WorkoutImageScanner
+	Uploads PendingUploadScanner
+	Images  WorkoutImageProcessor
 }
 
-func NewWorkoutImageHandler(r WorkoutImageScanner) *WorkoutImageHandler {
-	return &WorkoutImageHandler{Repo: r}
+func NewWorkoutImageHandler(r WorkoutImageScanner, u PendingUploadScanner, i WorkoutImageProcessor) *WorkoutImageHandler {
+	return &WorkoutImageHandler{Repo: r, Uploads: u, Images: i}
 }
 
 func (h *WorkoutImageHandler) AddImage(w http.ResponseWriter, r *http.Request) {
@@ -49,30 +70,57 @@ func (h *WorkoutImageHandler) AddImage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		StoragePath  string `json:"storage_path"`
-		DisplayOrder int    `json:"display_order"`
+		UploadID uuid.UUID `json:"upload_id"`
+		// Optional; without it the image goes last
+		DisplayOrder *int `json:"display_order"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if req.StoragePath == "" {
-		http.Error(w, "Storage path required", http.StatusBadRequest)
+	if req.UploadID == uuid.Nil {
+		http.Error(w, "Upload ID required", http.StatusBadRequest)
 		return
 	}
 
 	// 3. Repo Call
-	wi, err := h.Repo.CreateWorkoutImage(r.Context(), workoutID, req.StoragePath, req.DisplayOrder, userID)
+	upload, err := h.Uploads.GetPendingUpload(r.Context(), req.UploadID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUploadNotFound) {
+			http.Error(w, "Upload not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Get upload error: %v", err)
+		http.Error(w, "Failed to add image to workout", http.StatusInternalServerError)
+		return
+	}
+
+	processed, err := h.Images.ProcessWorkoutImage(r.Context(), upload, workoutID)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	wi, err := h.Repo.CreateWorkoutImageFromUpload(r.Context(), upload.ID, workoutID, processed.Path, processed.ThumbnailPath, req.DisplayOrder, userID)
 
 	// 4. Error Mapping
 	if err != nil {
+		h.Images.Delete(r.Context(), processed.Path, processed.ThumbnailPath)
+		if errors.Is(err, repository.ErrUploadNotFound) {
+			http.Error(w, "Upload not found", http.StatusNotFound)
+			return
+		}
 		if errors.Is(err, repository.ErrReferenceViolation) {
 			http.Error(w, "Workout not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, repository.ErrWorkoutImageLimit) {
+			http.Error(w, fmt.Sprintf("A workout can have at most %d images", models.MaxWorkoutImages), http.StatusConflict)
+			return
+		}
 		if errors.Is(err, repository.ErrAlreadyExists) {
-			http.Error(w, "Image already exists", http.StatusConflict) // Unlikely unless path has unique constraint
+			http.Error(w, "Display order already taken", http.StatusConflict)
 			return
 		}
 		log.Printf("Add workout image error: %v", err)
@@ -80,11 +128,64 @@ func (h *WorkoutImageHandler) AddImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The original may carry EXIF location data and is never served
+	h.Images.Delete(r.Context(), upload.ObjectKey)
+
 	// 5. Response Construction
+	signWorkoutImage(r.Context(), h.Images, wi)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(wi)
 }
 
+// ReorderImages sets the order of all of a workout's images at once.
+func (h *WorkoutImageHandler) ReorderImages(w http.ResponseWriter, r *http.Request) {
+	// 1. Context Check
+	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
+	if !ok {
+		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+		return
+	}
+	userID, err := uuid.Parse(ctxID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+		return
+	}
+
+	// 2. ID Extraction (path param only: /workouts/{id}/images/order)
+	workoutID, err := GetUUIDPathParam(r, 1)
+	if err != nil {
+		http.Error(w, "Invalid or missing workout ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReorderWorkoutImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// 3. Repo Call
+	err = h.Repo.ReorderWorkoutImages(r.Context(), workoutID, req.ImageIDs, userID)
+
+	// 4. Error Mapping
+	if err != nil {
+		if errors.Is(err, repository.ErrWorkoutNotFound) {
+			http.Error(w, "Workout not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrInvalidImageOrder) {
+			http.Error(w, "image_ids must list each of the workout's images once", http.StatusBadRequest)
+			return
+		}
+		log.Printf("Reorder workout images error: %v", err)
+		http.Error(w, "Failed to reorder workout images", http.StatusInternalServerError)
+		return
+	}
+
+	// 5. Response Construction
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *WorkoutImageHandler) RemoveImage(w http.ResponseWriter, r *http.Request) {
 	// 1. Context Check
 	ctxID, ok := r.Context().Value(middleware.UserIDKey).(string)
@@ -130,7 +231,7 @@ func (h *WorkoutImageHandler) ListImages(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Unauthenticated", http.StatusUnauthorized)
 		return
 	}
-	_, err := uuid.Parse(ctxID)
+	viewerID, err := uuid.Parse(ctxID)
 	if err != nil {
 		http.Error(w, "Invalid user ID", http.StatusUnauthorized)
 		return
@@ -144,7 +245,7 @@ func (h *WorkoutImageHandler) ListImages(w http.ResponseWriter, r *http.Request)
 	}
 
 	// 3. Repo Call
-	images, err := h.Repo.GetWorkoutImagesByWorkoutID(r.Context(), workoutID)
+	images, err := h.Repo.GetWorkoutImagesByWorkoutID(r.Context(), workoutID, viewerID)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -158,5 +259,43 @@ func (h *WorkoutImageHandler) ListImages(w http.ResponseWriter, r *http.Request)
 	if images == nil {
 		images = []*models.WorkoutImage{}
 	}
+	for _, wi := range images {
+		signWorkoutImage(r.Context(), h.Images, wi)
+	}
 	json.NewEncoder(w).Encode(images)
 }
+
+// signWorkoutImage fills in an image's read URLs.
+func signWorkoutImage(ctx context.Context, s ImageURLSigner, wi *models.WorkoutImage) {
+	wi.URL = signImageURL(ctx, s, wi.WorkoutID, wi.StoragePath)
+	if wi.ThumbnailPath != nil {
+		wi.ThumbnailURL = signImageURL(ctx, s, wi.WorkoutID, *wi.ThumbnailPath)
+	}
+}
+
+// signTimelineImages fills in the read URLs of timeline workouts' images.
+func signTimelineImages(ctx context.Context, s ImageURLSigner, workouts []*models.TimelineWorkout) {
+	for _, workout := range workouts {
+		for _, img := range workout.Images {
+			img.URL = signImageURL(ctx, s, workout.ID, img.StoragePath)
+			if img.ThumbnailPath != nil {
+				img.ThumbnailURL = signImageURL(ctx, s, workout.ID, *img.ThumbnailPath)
+			}
+		}
+	}
+}
+
+// signImageURL signs a read URL for one of a workout's stored images. Paths
+// outside the workout's own prefix, stored before uploads were managed, are
+// never signed; they and signing failures leave the URL empty.
+func signImageURL(ctx context.Context, s ImageURLSigner, workoutID uuid.UUID, key string) string {
+	if !uploads.IsWorkoutImageKey(workoutID, key) {
+		return ""
+	}
+	url, err := s.SignURL(ctx, key)
+	if err != nil {
+		log.Printf("Sign image URL for %q error: %v", key, err)
+		return ""
+	}
+	return url
+}
@@ -2,46 +2,58 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
 )
 
 // --- Mocks ---
 
 type mockWorkoutImageRepo struct {
-	CreateWorkoutImageFunc          func(ctx context.Context, workoutID uuid.UUID, storagePath string, displayOrder int, userID uuid.UUID) (*models.WorkoutImage, error)
-	GetWorkoutImageByIDFunc         func(ctx context.Context, id uuid.UUID) (*models.WorkoutImage, error)
-	GetWorkoutImagesByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID) ([]*models.WorkoutImage, error)
-	DeleteWorkoutImageFunc          func(ctx context.Context, workoutImageID uuid.UUID, userID uuid.UUID) error
+	CreateWorkoutImageFromUploadFunc func(ctx context.Context, uploadID uuid.UUID, workoutID uuid.UUID, storagePath string, thumbnailPath string, displayOrder *int, userID uuid.UUID) (*models.WorkoutImage, error)
+	GetWorkoutImageByIDFunc          func(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutImage, error)
+	GetWorkoutImagesByWorkoutIDFunc  func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutImage, error)
+	DeleteWorkoutImageFunc           func(ctx context.Context, workoutImageID uuid.UUID, userID uuid.UUID) error
+	ReorderWorkoutImagesFunc         func(ctx context.Context, workoutID uuid.UUID, imageIDs []uuid.UUID, userID uuid.UUID) error
 }
 
-func (m *mockWorkoutImageRepo) CreateWorkoutImage(ctx context.Context, workoutID uuid.UUID, storagePath string, displayOrder int, userID uuid.UUID) (*models.WorkoutImage, error) {
-	if m.CreateWorkoutImageFunc != nil {
-		return m.CreateWorkoutImageFunc(ctx, workoutID, storagePath, displayOrder, userID)
+func (m *mockWorkoutImageRepo) CreateWorkoutImageFromUpload(ctx context.Context, uploadID uuid.UUID, workoutID uuid.UUID, storagePath string, thumbnailPath string, displayOrder *int, userID uuid.UUID) (*models.WorkoutImage, error) {
+	if m.CreateWorkoutImageFromUploadFunc != nil {
+		return m.CreateWorkoutImageFromUploadFunc(ctx, uploadID, workoutID, storagePath, thumbnailPath, displayOrder, userID)
 	}
-	return &models.WorkoutImage{ID: uuid.New(), WorkoutID: workoutID, StoragePath: storagePath}, nil
+	return &models.WorkoutImage{ID: uuid.New(), WorkoutID: workoutID, StoragePath: storagePath, ThumbnailPath: &thumbnailPath}, nil
 }
 
-func (m *mockWorkoutImageRepo) GetWorkoutImageByID(ctx context.Context, id uuid.UUID) (*models.WorkoutImage, error) {
+func (m *mockWorkoutImageRepo) GetWorkoutImageByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutImage, error) {
 	if m.GetWorkoutImageByIDFunc != nil {
-		return m.GetWorkoutImageByIDFunc(ctx, id)
+		return m.GetWorkoutImageByIDFunc(ctx, id, viewerID)
 	}
 	return &models.WorkoutImage{ID: id}, nil
 }
 
-func (m *mockWorkoutImageRepo) GetWorkoutImagesByWorkoutID(ctx context.Context, workoutID uuid.UUID) ([]*models.WorkoutImage, error) {
+func (m *mockWorkoutImageRepo) GetWorkoutImagesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutImage, error) {
 	if m.GetWorkoutImagesByWorkoutIDFunc != nil {
-		return m.GetWorkoutImagesByWorkoutIDFunc(ctx, workoutID)
+		return m.GetWorkoutImagesByWorkoutIDFunc(ctx, workoutID, viewerID)
 	}
 	return []*models.WorkoutImage{}, nil
 }
 
+func (m *mockWorkoutImageRepo) ReorderWorkoutImages(ctx context.Context, workoutID uuid.UUID, imageIDs []uuid.UUID, userID uuid.UUID) error {
+	if m.ReorderWorkoutImagesFunc != nil {
+		return m.ReorderWorkoutImagesFunc(ctx, workoutID, imageIDs, userID)
+	}
+	return nil
+}
+
 func (m *mockWorkoutImageRepo) DeleteWorkoutImage(ctx context.Context, workoutImageID uuid.UUID, userID uuid.UUID) error {
 	if m.DeleteWorkoutImageFunc != nil {
 		return m.DeleteWorkoutImageFunc(ctx, workoutImageID, userID)
@@ -49,12 +61,54 @@ func (m *mockWorkoutImageRepo) DeleteWorkoutImage(ctx context.Context, workoutIm
 	return nil
 }
 
+type mockPendingUploadRepo struct {
+	GetPendingUploadFunc func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Upload, error)
+}
+
+func (m *mockPendingUploadRepo) GetPendingUpload(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Upload, error) {
+	if m.GetPendingUploadFunc != nil {
+		return m.GetPendingUploadFunc(ctx, id, userID)
+	}
+	return &models.Upload{ID: id, UserID: userID, ObjectKey: "uploads/" + id.String(), ContentType: "image/jpeg", SizeBytes: 100}, nil
+}
+
+// mockImageURLSigner signs every key by prefixing it.
+type mockImageURLSigner struct{}
+
+func (m *mockImageURLSigner) SignURL(ctx context.Context, key string) (string, error) {
+	return "https://signed.example/" + key, nil
+}
+
+type mockImageProcessor struct {
+	mockImageURLSigner
+	ProcessWorkoutImageFunc func(ctx context.Context, upload *models.Upload, workoutID uuid.UUID) (*uploads.ProcessedImage, error)
+	Deleted                 []string
+}
+
+func (m *mockImageProcessor) ProcessWorkoutImage(ctx context.Context, upload *models.Upload, workoutID uuid.UUID) (*uploads.ProcessedImage, error) {
+	if m.ProcessWorkoutImageFunc != nil {
+		return m.ProcessWorkoutImageFunc(ctx, upload, workoutID)
+	}
+	prefix := uploads.WorkoutImagePrefix(workoutID)
+	return &uploads.ProcessedImage{Path: prefix + "a.jpg", ThumbnailPath: prefix + "a_thumb.jpg"}, nil
+}
+
+func (m *mockImageProcessor) Delete(ctx context.Context, keys ...string) {
+	m.Deleted = append(m.Deleted, keys...)
+}
+
 // --- Tests ---
 
+// testImagePrefix is where the mocks store images of the workout the tests
+// request.
+var testImagePrefix = uploads.WorkoutImagePrefix(uuid.MustParse("00000000-0000-0000-0000-000000000001"))
+
 func TestAddImageToWorkout_Success(t *testing.T) {
-	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{})
+	images := &mockImageProcessor{}
+	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{}, &mockPendingUploadRepo{}, images)
 
-	body := `{"storage_path": "/path/to/image.jpg"}`
+	uploadID := uuid.New()
+	body := `{"upload_id": "` + uploadID.String() + `", "display_order": 1}`
 	req := httptest.NewRequest("POST", "/workouts/00000000-0000-0000-0000-000000000001/images", strings.NewReader(body))
 	req = testutils.InjectUserID(req, uuid.New().String())
 	rr := httptest.NewRecorder()
@@ -62,12 +116,180 @@ func TestAddImageToWorkout_Success(t *testing.T) {
 	h.AddImage(rr, req)
 
 	if rr.Code != http.StatusCreated {
-		t.Errorf("expected 201 Created, got %d", rr.Code)
+		t.Fatalf("expected 201 Created, got %d", rr.Code)
+	}
+	var wi models.WorkoutImage
+	json.NewDecoder(rr.Body).Decode(&wi)
+	if wi.URL != "https://signed.example/"+testImagePrefix+"a.jpg" || wi.ThumbnailURL != "https://signed.example/"+testImagePrefix+"a_thumb.jpg" {
+		t.Errorf("expected signed URLs, got %q and %q", wi.URL, wi.ThumbnailURL)
+	}
+	if !slices.Equal(images.Deleted, []string{"uploads/" + uploadID.String()}) {
+		t.Errorf("expected only the raw upload to be deleted, got %v", images.Deleted)
+	}
+}
+
+func TestAddImageToWorkout_MissingUploadID(t *testing.T) {
+	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{}, &mockPendingUploadRepo{}, &mockImageProcessor{})
+
+	req := httptest.NewRequest("POST", "/workouts/00000000-0000-0000-0000-000000000001/images", strings.NewReader(`{"storage_path": "/path/to/image.jpg"}`))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.AddImage(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 Bad Request, got %d", rr.Code)
+	}
+}
+
+func TestAddImageToWorkout_UploadNotFound(t *testing.T) {
+	uploadsRepo := &mockPendingUploadRepo{
+		GetPendingUploadFunc: func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Upload, error) {
+			return nil, repository.ErrUploadNotFound
+		},
+	}
+	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{}, uploadsRepo, &mockImageProcessor{})
+
+	body := `{"upload_id": "` + uuid.New().String() + `"}`
+	req := httptest.NewRequest("POST", "/workouts/00000000-0000-0000-0000-000000000001/images", strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.AddImage(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+}
+
+func TestAddImageToWorkout_RejectedUpload(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"Not Uploaded", uploads.ErrNotUploaded, http.StatusConflict},
+		{"Wrong Type", uploads.ErrUnsupportedType, http.StatusUnsupportedMediaType},
+		{"Too Large", uploads.ErrTooLarge, http.StatusRequestEntityTooLarge},
+		{"Not An Image", uploads.ErrInvalidImage, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images := &mockImageProcessor{
+				ProcessWorkoutImageFunc: func(ctx context.Context, upload *models.Upload, workoutID uuid.UUID) (*uploads.ProcessedImage, error) {
+					return nil, tt.err
+				},
+			}
+			h := NewWorkoutImageHandler(&mockWorkoutImageRepo{}, &mockPendingUploadRepo{}, images)
+
+			body := `{"upload_id": "` + uuid.New().String() + `"}`
+			req := httptest.NewRequest("POST", "/workouts/00000000-0000-0000-0000-000000000001/images", strings.NewReader(body))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.AddImage(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+		})
+	}
+}
+
+func TestAddImageToWorkout_WorkoutNotFound(t *testing.T) {
+	repo := &mockWorkoutImageRepo{
+		CreateWorkoutImageFromUploadFunc: func(ctx context.Context, uploadID uuid.UUID, workoutID uuid.UUID, storagePath string, thumbnailPath string, displayOrder *int, userID uuid.UUID) (*models.WorkoutImage, error) {
+			return nil, repository.ErrReferenceViolation
+		},
+	}
+	images := &mockImageProcessor{}
+	h := NewWorkoutImageHandler(repo, &mockPendingUploadRepo{}, images)
+
+	body := `{"upload_id": "` + uuid.New().String() + `"}`
+	req := httptest.NewRequest("POST", "/workouts/00000000-0000-0000-0000-000000000001/images", strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.AddImage(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 Not Found, got %d", rr.Code)
+	}
+	// The processed images are discarded; the upload can be committed again
+	if !slices.Equal(images.Deleted, []string{testImagePrefix + "a.jpg", testImagePrefix + "a_thumb.jpg"}) {
+		t.Errorf("expected the processed images to be deleted, got %v", images.Deleted)
+	}
+}
+
+func TestAddImageToWorkout_LimitReached(t *testing.T) {
+	repo := &mockWorkoutImageRepo{
+		CreateWorkoutImageFromUploadFunc: func(ctx context.Context, uploadID uuid.UUID, workoutID uuid.UUID, storagePath string, thumbnailPath string, displayOrder *int, userID uuid.UUID) (*models.WorkoutImage, error) {
+			return nil, repository.ErrWorkoutImageLimit
+		},
+	}
+	images := &mockImageProcessor{}
+	h := NewWorkoutImageHandler(repo, &mockPendingUploadRepo{}, images)
+
+	body := `{"upload_id": "` + uuid.New().String() + `"}`
+	req := httptest.NewRequest("POST", "/workouts/00000000-0000-0000-0000-000000000001/images", strings.NewReader(body))
+	req = testutils.InjectUserID(req, uuid.New().String())
+	rr := httptest.NewRecorder()
+
+	h.AddImage(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected 409 Conflict, got %d", rr.Code)
+	}
+	if len(images.Deleted) != 2 {
+		t.Errorf("expected the processed images to be deleted, got %v", images.Deleted)
+	}
+}
+
+func TestReorderImages(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	tests := []struct {
+		name     string
+		body     string
+		repoErr  error
+		expected int
+	}{
+		{"Success", `{"image_ids": ["` + ids[1].String() + `", "` + ids[0].String() + `"]}`, nil, http.StatusNoContent},
+		{"Invalid Body", `{"image_ids": "nope"}`, nil, http.StatusBadRequest},
+		{"Incomplete Order", `{"image_ids": []}`, repository.ErrInvalidImageOrder, http.StatusBadRequest},
+		{"Workout Not Found", `{"image_ids": []}`, repository.ErrWorkoutNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []uuid.UUID
+			repo := &mockWorkoutImageRepo{
+				ReorderWorkoutImagesFunc: func(ctx context.Context, workoutID uuid.UUID, imageIDs []uuid.UUID, userID uuid.UUID) error {
+					got = imageIDs
+					return tt.repoErr
+				},
+			}
+			h := NewWorkoutImageHandler(repo, &mockPendingUploadRepo{}, &mockImageProcessor{})
+
+			req := httptest.NewRequest("PUT", "/workouts/00000000-0000-0000-0000-000000000001/images/order", strings.NewReader(tt.body))
+			req = testutils.InjectUserID(req, uuid.New().String())
+			rr := httptest.NewRecorder()
+
+			h.ReorderImages(rr, req)
+
+			if rr.Code != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, rr.Code)
+			}
+			if tt.expected == http.StatusNoContent && !slices.Equal(got, []uuid.UUID{ids[1], ids[0]}) {
+				t.Errorf("expected the order to be passed through, got %v", got)
+			}
+		})
 	}
 }
 
 func TestRemoveImageFromWorkout_Success(t *testing.T) {
-	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{})
+	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{}, &mockPendingUploadRepo{}, &mockImageProcessor{})
 
 	req := httptest.NewRequest("DELETE", "/workouts/00000000-0000-0000-0000-000000000001/images/00000000-0000-0000-0000-000000000002", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -81,7 +303,17 @@ func TestRemoveImageFromWorkout_Success(t *testing.T) {
 }
 
 func TestListImagesForWorkout_Success(t *testing.T) {
-	h := NewWorkoutImageHandler(&mockWorkoutImageRepo{})
+	repo := &mockWorkoutImageRepo{
+		GetWorkoutImagesByWorkoutIDFunc: func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutImage, error) {
+			return []*models.WorkoutImage{
+				{ID: uuid.New(), WorkoutID: workoutID, StoragePath: uploads.WorkoutImagePrefix(workoutID) + "b.jpg"},
+				// Set by a client before uploads were processed
+				{ID: uuid.New(), WorkoutID: workoutID, StoragePath: "avatars/someone/512.jpg"},
+				{ID: uuid.New(), WorkoutID: workoutID, StoragePath: uploads.WorkoutImagePrefix(workoutID) + "../../../uploads/x"},
+			}, nil
+		},
+	}
+	h := NewWorkoutImageHandler(repo, &mockPendingUploadRepo{}, &mockImageProcessor{})
 
 	req := httptest.NewRequest("GET", "/workouts/00000000-0000-0000-0000-000000000001/images", nil)
 	req = testutils.InjectUserID(req, uuid.New().String())
@@ -90,6 +322,14 @@ func TestListImagesForWorkout_Success(t *testing.T) {
 	h.ListImages(rr, req)
 
 	if rr.Code != http.StatusOK {
-		t.Errorf("expected 200 OK, got %d", rr.Code)
+		t.Fatalf("expected 200 OK, got %d", rr.Code)
+	}
+	var images []models.WorkoutImage
+	json.NewDecoder(rr.Body).Decode(&images)
+	if len(images) != 3 || images[0].URL != "https://signed.example/"+testImagePrefix+"b.jpg" {
+		t.Fatalf("expected a signed URL, got %+v", images)
+	}
+	if images[1].URL != "" || images[2].URL != "" {
+		t.Errorf("expected paths outside the workout's images to stay unsigned, got %q and %q", images[1].URL, images[2].URL)
 	}
 }
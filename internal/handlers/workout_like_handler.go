@@ -6,11 +6,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -18,7 +18,7 @@ type WorkoutLikeScanner interface {
 	LikeWorkout(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) (*models.WorkoutLike, error)
 	UnlikeWorkout(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) error
 	GetWorkoutLikeByID(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) (*models.WorkoutLike, error)
-	GetWorkoutLikesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.WorkoutLikeDetail, error)
+	GetWorkoutLikesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.WorkoutLikeDetail, error)
 	IsWorkoutLiked(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) (bool, error)
 }
 
@@ -127,21 +127,14 @@ func (h *WorkoutLikeHandler) ListLikes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit := 20
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if v, err := strconv.Atoi(l); err == nil && v > 0 {
-			limit = v
-		}
-	}
-	offset := 0
-	if o := r.URL.Query().Get("offset"); o != "" {
-		if v, err := strconv.Atoi(o); err == nil && v >= 0 {
-			offset = v
-		}
+	cursor, limit, err := parsePageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// 3. Repo Call
-	likes, err := h.Repo.GetWorkoutLikesByWorkoutID(r.Context(), workoutID, userID, limit, offset)
+	likes, err := h.Repo.GetWorkoutLikesByWorkoutID(r.Context(), workoutID, userID, cursor, limit+1)
 
 	// 4. Error Mapping
 	if err != nil {
@@ -152,8 +145,9 @@ func (h *WorkoutLikeHandler) ListLikes(w http.ResponseWriter, r *http.Request) {
 
 	// 5. Response Construction
 	w.Header().Set("Content-Type", "application/json")
-	if likes == nil {
-		likes = []*models.WorkoutLikeDetail{}
-	}
-	json.NewEncoder(w).Encode(likes)
+	json.NewEncoder(w).Encode(pagination.NewPage(likes, limit, workoutLikeCursor))
+}
+
+func workoutLikeCursor(l *models.WorkoutLikeDetail) pagination.Cursor {
+	return pagination.At(l.CreatedAt, l.UserID)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/handlers/testutils"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 )
 
@@ -18,7 +19,7 @@ type mockWorkoutLikeRepo struct {
 	LikeWorkoutFunc                func(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) (*models.WorkoutLike, error)
 	UnlikeWorkoutFunc              func(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) error
 	GetWorkoutLikeByIDFunc         func(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) (*models.WorkoutLike, error)
-	GetWorkoutLikesByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.WorkoutLikeDetail, error)
+	GetWorkoutLikesByWorkoutIDFunc func(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.WorkoutLikeDetail, error)
 	IsWorkoutLikedFunc             func(ctx context.Context, userID uuid.UUID, workoutID uuid.UUID) (bool, error)
 }
 
@@ -43,9 +44,9 @@ func (m *mockWorkoutLikeRepo) GetWorkoutLikeByID(ctx context.Context, userID uui
 	return &models.WorkoutLike{UserID: userID, WorkoutID: workoutID}, nil
 }
 
-func (m *mockWorkoutLikeRepo) GetWorkoutLikesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, limit int, offset int) ([]*models.WorkoutLikeDetail, error) {
+func (m *mockWorkoutLikeRepo) GetWorkoutLikesByWorkoutID(ctx context.Context, workoutID uuid.UUID, viewerID uuid.UUID, cursor *pagination.Cursor, limit int) ([]*models.WorkoutLikeDetail, error) {
 	if m.GetWorkoutLikesByWorkoutIDFunc != nil {
-		return m.GetWorkoutLikesByWorkoutIDFunc(ctx, workoutID, viewerID, limit, offset)
+		return m.GetWorkoutLikesByWorkoutIDFunc(ctx, workoutID, viewerID, cursor, limit)
 	}
 	return []*models.WorkoutLikeDetail{}, nil
 }
@@ -15,8 +15,8 @@ import (
 
 type WorkoutSetScanner interface {
 	CreateWorkoutSet(ctx context.Context, workoutExerciseID uuid.UUID, weight *float64, reps *int, orderIndex int, userID uuid.UUID) (*models.WorkoutSet, error)
-	GetWorkoutSetByID(ctx context.Context, id uuid.UUID) (*models.WorkoutSet, error)
-	GetWorkoutSetsByWorkoutExerciseID(ctx context.Context, workoutExerciseID uuid.UUID) ([]*models.WorkoutSet, error)
+	GetWorkoutSetByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutSet, error)
+	GetWorkoutSetsByWorkoutExerciseID(ctx context.Context, workoutExerciseID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutSet, error)
 	UpdateWorkoutSet(ctx context.Context, workoutSetID uuid.UUID, userID uuid.UUID, updates models.UpdateWorkoutSetRequest) error
 	DeleteWorkoutSet(ctx context.Context, workoutSetID uuid.UUID, userID uuid.UUID) error
 }
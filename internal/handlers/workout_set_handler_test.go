@@ -16,8 +16,8 @@ import (
 
 type mockWorkoutSetRepo struct {
 	CreateWorkoutSetFunc                  func(ctx context.Context, workoutExerciseID uuid.UUID, weight *float64, reps *int, orderIndex int, userID uuid.UUID) (*models.WorkoutSet, error)
-	GetWorkoutSetByIDFunc                 func(ctx context.Context, id uuid.UUID) (*models.WorkoutSet, error)
-	GetWorkoutSetsByWorkoutExerciseIDFunc func(ctx context.Context, workoutExerciseID uuid.UUID) ([]*models.WorkoutSet, error)
+	GetWorkoutSetByIDFunc                 func(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutSet, error)
+	GetWorkoutSetsByWorkoutExerciseIDFunc func(ctx context.Context, workoutExerciseID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutSet, error)
 	UpdateWorkoutSetFunc                  func(ctx context.Context, workoutSetID uuid.UUID, userID uuid.UUID, updates models.UpdateWorkoutSetRequest) error
 	DeleteWorkoutSetFunc                  func(ctx context.Context, workoutSetID uuid.UUID, userID uuid.UUID) error
 }
@@ -29,16 +29,16 @@ func (m *mockWorkoutSetRepo) CreateWorkoutSet(ctx context.Context, workoutExerci
 	return &models.WorkoutSet{ID: uuid.New(), WorkoutExerciseID: workoutExerciseID}, nil
 }
 
-func (m *mockWorkoutSetRepo) GetWorkoutSetByID(ctx context.Context, id uuid.UUID) (*models.WorkoutSet, error) {
+func (m *mockWorkoutSetRepo) GetWorkoutSetByID(ctx context.Context, id uuid.UUID, viewerID uuid.UUID) (*models.WorkoutSet, error) {
 	if m.GetWorkoutSetByIDFunc != nil {
-		return m.GetWorkoutSetByIDFunc(ctx, id)
+		return m.GetWorkoutSetByIDFunc(ctx, id, viewerID)
 	}
 	return &models.WorkoutSet{ID: id}, nil
 }
 
-func (m *mockWorkoutSetRepo) GetWorkoutSetsByWorkoutExerciseID(ctx context.Context, workoutExerciseID uuid.UUID) ([]*models.WorkoutSet, error) {
+func (m *mockWorkoutSetRepo) GetWorkoutSetsByWorkoutExerciseID(ctx context.Context, workoutExerciseID uuid.UUID, viewerID uuid.UUID) ([]*models.WorkoutSet, error) {
 	if m.GetWorkoutSetsByWorkoutExerciseIDFunc != nil {
-		return m.GetWorkoutSetsByWorkoutExerciseIDFunc(ctx, workoutExerciseID)
+		return m.GetWorkoutSetsByWorkoutExerciseIDFunc(ctx, workoutExerciseID, viewerID)
 	}
 	return []*models.WorkoutSet{}, nil
 }
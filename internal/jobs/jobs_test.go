@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEveryRunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := 0
+	done := make(chan struct{})
+
+	go func() {
+		Every(ctx, "test", time.Millisecond, func(context.Context) error {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+			return errors.New("keeps going")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Every to return after cancel")
+	}
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+}
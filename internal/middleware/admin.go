@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// AdminChecker reports whether a user is a sys admin.
+type AdminChecker interface {
+	IsSysAdmin(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireAdmin rejects requests from users who are not sys admins with 403
+// Forbidden. It must run after AuthMiddleware.
+func RequireAdmin(checker AdminChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctxID, ok := r.Context().Value(UserIDKey).(string)
+			if !ok {
+				http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+				return
+			}
+			userID, err := uuid.Parse(ctxID)
+			if err != nil {
+				http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+				return
+			}
+
+			isAdmin, err := checker.IsSysAdmin(r.Context(), userID)
+			if err != nil {
+				log.Printf("Admin check error: %v", err)
+				http.Error(w, "Failed to check permissions", http.StatusInternalServerError)
+				return
+			}
+			if !isAdmin {
+				http.Error(w, "Admin access required", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
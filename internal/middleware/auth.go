@@ -2,9 +2,11 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/auth"
 )
 
@@ -12,7 +14,16 @@ type contextKey string
 
 const UserIDKey contextKey = "user_id"
 
-func AuthMiddleware(secret string) func(http.Handler) http.Handler {
+// AccountChecker reports whether a user's account is neither banned nor
+// suspended.
+type AccountChecker interface {
+	IsAccountActive(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// AuthMiddleware verifies the bearer token and, when accounts is set, rejects
+// requests from disabled accounts with 403 Forbidden so that a ban or
+// suspension takes effect before the access token expires.
+func AuthMiddleware(secret string, accounts AccountChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get the Authorization header
@@ -37,6 +48,24 @@ func AuthMiddleware(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
+			if accounts != nil {
+				id, err := uuid.Parse(userID)
+				if err != nil {
+					http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+					return
+				}
+				active, err := accounts.IsAccountActive(r.Context(), id)
+				if err != nil {
+					log.Printf("Account check error: %v", err)
+					http.Error(w, "Failed to check account", http.StatusInternalServerError)
+					return
+				}
+				if !active {
+					http.Error(w, "Account disabled", http.StatusForbidden)
+					return
+				}
+			}
+
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
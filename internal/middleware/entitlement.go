@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
+)
+
+// FeatureChecker reports whether a user's plan grants a feature.
+type FeatureChecker interface {
+	Has(ctx context.Context, userID uuid.UUID, feature entitlements.Feature) (bool, error)
+}
+
+// RequireFeature rejects requests from users whose plan lacks feature with
+// 402 Payment Required. It must run after AuthMiddleware.
+func RequireFeature(checker FeatureChecker, feature entitlements.Feature) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctxID, ok := r.Context().Value(UserIDKey).(string)
+			if !ok {
+				http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+				return
+			}
+			userID, err := uuid.Parse(ctxID)
+			if err != nil {
+				http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+				return
+			}
+
+			allowed, err := checker.Has(r.Context(), userID, feature)
+			if err != nil {
+				log.Printf("Entitlement check error: %v", err)
+				http.Error(w, "Failed to check subscription", http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Premium subscription required", http.StatusPaymentRequired)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions, as stored in admin_audit_log.action.
+const (
+	AuditSuspendUser        = "user.suspend"
+	AuditUnsuspendUser      = "user.unsuspend"
+	AuditBanUser            = "user.ban"
+	AuditUnbanUser          = "user.unban"
+	AuditRevokeSessions     = "user.revoke_sessions"
+	AuditRemoveComment      = "comment.remove"
+	AuditRemoveWorkout      = "workout.remove"
+	AuditRemoveWorkoutImage = "workout_image.remove"
+	AuditHideComment        = "comment.hide"
+	AuditUnhideComment      = "comment.unhide"
+	AuditHideWorkout        = "workout.hide"
+	AuditUnhideWorkout      = "workout.unhide"
+	AuditHideWorkoutImage   = "workout_image.hide"
+	AuditUnhideWorkoutImage = "workout_image.unhide"
+	AuditResolveReport      = "report.resolve"
+	AuditCreateExercise     = "exercise.create"
+	AuditUpdateExercise     = "exercise.update"
+	AuditDeleteExercise     = "exercise.delete"
+	AuditCreateMuscle       = "muscle.create"
+	AuditDeleteMuscle       = "muscle.delete"
+)
+
+// Audit target types, as stored in admin_audit_log.target_type.
+const (
+	AuditTargetUser         = "user"
+	AuditTargetComment      = "comment"
+	AuditTargetWorkout      = "workout"
+	AuditTargetWorkoutImage = "workout_image"
+	AuditTargetExercise     = "exercise"
+	AuditTargetMuscle       = "muscle"
+	AuditTargetReport       = "report"
+)
+
+// AdminUser is an account as admins see it: its profile plus the account
+// details regular users never see.
+type AdminUser struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	Username         *string    `json:"username" db:"username"`
+	DisplayName      *string    `json:"display_name" db:"display_name"`
+	Email            *string    `json:"email" db:"email"`
+	AvatarURL        *string    `json:"avatar_url" db:"avatar_url"`
+	IsAdmin          bool       `json:"is_admin" db:"is_admin"`
+	AccountStatus    string     `json:"account_status" db:"account_status"`
+	SuspendedUntil   *time.Time `json:"suspended_until" db:"suspended_until"`
+	SuspensionReason *string    `json:"suspension_reason" db:"suspension_reason"`
+	TotalWorkouts    int        `json:"total_workouts" db:"total_workouts"`
+	FollowersCount   int        `json:"followers_count" db:"followers_count"`
+	ActiveSessions   int        `json:"active_sessions" db:"active_sessions"`
+	LastSignInAt     *time.Time `json:"last_sign_in_at" db:"last_sign_in_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+type SuspendUserRequest struct {
+	Until  time.Time `json:"until"`
+	Reason *string   `json:"reason"`
+}
+
+type BanUserRequest struct {
+	Reason *string `json:"reason"`
+}
+
+type CreateSystemExerciseRequest struct {
+	Name                 string  `json:"name"`
+	SuggestedRestSeconds *int    `json:"suggested_rest_seconds"`
+	Icon                 *string `json:"icon"`
+}
+
+// AuditLogEntry is one action an admin took. Details holds what the action
+// changed, including a copy of any content that was removed.
+type AuditLogEntry struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	AdminID    uuid.UUID       `json:"admin_id" db:"admin_id"`
+	Action     string          `json:"action" db:"action"`
+	TargetType string          `json:"target_type" db:"target_type"`
+	TargetID   uuid.UUID       `json:"target_id" db:"target_id"`
+	Details    json.RawMessage `json:"details" db:"details"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditLogFilter narrows the audit log. Nil fields match everything.
+type AuditLogFilter struct {
+	AdminID    *uuid.UUID
+	TargetType *string
+	TargetID   *uuid.UUID
+}
+
+type RevokeSessionsResponse struct {
+	SessionsRevoked int64 `json:"sessions_revoked"`
+}
@@ -14,4 +14,11 @@ type Comment struct {
 	Content    string     `json:"content" db:"content"`
 	LikesCount int        `json:"likes_count" db:"likes_count"`
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+	EditedAt   *time.Time `json:"edited_at,omitempty" db:"edited_at"`
+	IsDeleted  bool       `json:"is_deleted" db:"is_deleted"`
+}
+
+type UpdateCommentRequest struct {
+	Content string `json:"content"`
 }
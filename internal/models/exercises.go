@@ -21,3 +21,14 @@ type UpdateExerciseRequest struct {
 	SuggestedRestSeconds *int    `json:"suggested_rest_seconds" db:"suggested_rest_seconds"`
 	Icon                 *string `json:"icon" db:"icon"`
 }
+
+// ExerciseSession is one past performance of an exercise by the caller:
+// the workout it was logged in and the sets that were done.
+type ExerciseSession struct {
+	WorkoutID         uuid.UUID             `json:"workout_id" db:"workout_id"`
+	WorkoutExerciseID uuid.UUID             `json:"workout_exercise_id" db:"workout_exercise_id"`
+	WorkoutName       *string               `json:"workout_name,omitempty" db:"workout_name"`
+	StartedAt         time.Time             `json:"started_at" db:"started_at"`
+	Memo              *string               `json:"memo,omitempty" db:"memo"`
+	Sets              []*TimelineWorkoutSet `json:"sets" db:"sets"`
+}
@@ -12,3 +12,17 @@ type Follow struct {
 	Status      string    `json:"status" db:"status"` // 'pending', 'accepted'
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
+
+// FollowRequest is a pending follow as shown in the follow request inbox.
+// UserID is the requester for incoming requests and the target for outgoing ones.
+type FollowRequest struct {
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Username    *string   `json:"username" db:"username"`
+	DisplayName *string   `json:"display_name" db:"display_name"`
+	AvatarURL   *string   `json:"avatar_url" db:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type AcceptAllFollowRequestsResponse struct {
+	Accepted int64 `json:"accepted"`
+}
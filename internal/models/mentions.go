@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Mention is an @mention of the viewer as shown in their mentions inbox.
+// CommentID is nil when the mention is in the workout's caption, and Content is
+// the caption or comment text it appeared in.
+type Mention struct {
+	AuthorID    uuid.UUID  `json:"author_id" db:"author_id"`
+	Username    *string    `json:"username" db:"username"`
+	DisplayName *string    `json:"display_name" db:"display_name"`
+	AvatarURL   *string    `json:"avatar_url" db:"avatar_url"`
+	WorkoutID   uuid.UUID  `json:"workout_id" db:"workout_id"`
+	CommentID   *uuid.UUID `json:"comment_id,omitempty" db:"comment_id"`
+	Content     *string    `json:"content" db:"content"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
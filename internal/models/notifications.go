@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// Notification types, as stored in notifications.type.
+const (
+	NotificationFollow        = "follow"
+	NotificationFollowRequest = "follow_request"
+	NotificationWorkoutLike   = "workout_like"
+	NotificationCommentLike   = "comment_like"
+	NotificationComment       = "comment"
+	NotificationReply         = "reply"
+	NotificationMention       = "mention"
+)
+
+// Notification is one inbox entry. Events of the same group, such as every
+// like on a workout, are folded into a single entry: ID, Actor and CreatedAt
+// describe the latest event and ActorCount counts everyone involved. The
+// entry is unread while any of its events is.
+type Notification struct {
+	ID         uuid.UUID         `json:"id" db:"id"`
+	Type       string            `json:"type" db:"type"`
+	Actor      NotificationActor `json:"actor"`
+	ActorCount int               `json:"actor_count" db:"actor_count"`
+	WorkoutID  *uuid.UUID        `json:"workout_id,omitempty" db:"workout_id"`
+	CommentID  *uuid.UUID        `json:"comment_id,omitempty" db:"comment_id"`
+	IsRead     bool              `json:"is_read" db:"is_read"`
+	Message    string            `json:"message"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
+
+type NotificationActor struct {
+	ID          uuid.UUID `json:"id" db:"actor_id"`
+	Username    *string   `json:"username" db:"username"`
+	DisplayName *string   `json:"display_name" db:"display_name"`
+	AvatarURL   *string   `json:"avatar_url" db:"avatar_url"`
+}
+
+// NotificationInbox is a page of notifications with the unread entry count.
+type NotificationInbox struct {
+	pagination.Page[*Notification]
+	UnreadCount int `json:"unread_count"`
+}
+
+type UnreadNotificationsResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+type MarkNotificationsReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// NotificationMessage renders the text of a notification, such as
+// "alex and 12 others liked your workout". others is the number of actors
+// besides the named one.
+func NotificationMessage(kind string, actor string, others int) string {
+	who := actor
+	switch {
+	case others == 1:
+		who = actor + " and 1 other"
+	case others > 1:
+		who = fmt.Sprintf("%s and %d others", actor, others)
+	}
+
+	switch kind {
+	case NotificationFollow:
+		return who + " started following you"
+	case NotificationFollowRequest:
+		return who + " requested to follow you"
+	case NotificationWorkoutLike:
+		return who + " liked your workout"
+	case NotificationCommentLike:
+		return who + " liked your comment"
+	case NotificationComment:
+		return who + " commented on your workout"
+	case NotificationReply:
+		return who + " replied to your comment"
+	case NotificationMention:
+		return who + " mentioned you"
+	default:
+		return who + " interacted with you"
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RecordTypeMaxWeight    = "max_weight"
+	RecordTypeEstimated1RM = "estimated_1rm"
+	RecordTypeMaxReps      = "max_reps" // per weight
+	RecordTypeMaxVolume    = "max_volume"
+)
+
+type PersonalRecord struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	ExerciseID   uuid.UUID  `json:"exercise_id" db:"exercise_id"`
+	ExerciseName string     `json:"exercise_name" db:"exercise_name"`
+	WorkoutID    uuid.UUID  `json:"workout_id" db:"workout_id"`
+	WorkoutSetID *uuid.UUID `json:"workout_set_id,omitempty" db:"workout_set_id"`
+	RecordType   string     `json:"record_type" db:"record_type"`
+	Weight       *float64   `json:"weight,omitempty" db:"weight"`
+	Reps         *int       `json:"reps,omitempty" db:"reps"`
+	Value        float64    `json:"value" db:"value"`
+	AchievedAt   time.Time  `json:"achieved_at" db:"achieved_at"`
+}
@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Account statuses, as stored in profiles.account_status. A suspended
+// account becomes active again once its suspended_until has passed.
+const (
+	AccountStatusActive    = "active"
+	AccountStatusSuspended = "suspended"
+	AccountStatusBanned    = "banned"
+)
+
 type Profile struct {
 	ID               uuid.UUID  `json:"id" db:"id"`
 	Username         *string    `json:"username" db:"username"`
@@ -19,11 +27,27 @@ type Profile struct {
 	LastWorkedOutAt  *time.Time `json:"last_worked_out_at" db:"last_worked_out_at"`
 	TotalWorkouts    int        `json:"total_workouts" db:"total_workouts"`
 	CurrentStreak    int        `json:"current_streak" db:"current_streak"`
+	LongestStreak    int        `json:"longest_streak" db:"longest_streak"`
 	TotalWeight      float64    `json:"total_weight" db:"total_weight"`
 	FollowersCount   int        `json:"followers_count" db:"followers_count"`
 	FollowingCount   int        `json:"following_count" db:"following_count"`
 	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+	// Only filled in at sign-in; never sent to clients
+	AccountStatus  string     `json:"-" db:"account_status"`
+	SuspendedUntil *time.Time `json:"-" db:"suspended_until"`
+}
+
+// AccountActive reports whether an account with the given status may sign in
+// at now. It mirrors public.is_account_active.
+func AccountActive(status string, suspendedUntil *time.Time, now time.Time) bool {
+	switch status {
+	case AccountStatusBanned:
+		return false
+	case AccountStatusSuspended:
+		return suspendedUntil == nil || !suspendedUntil.After(now)
+	}
+	return true
 }
 
 type UpdateProfileRequest struct {
@@ -32,7 +56,10 @@ type UpdateProfileRequest struct {
 	Bio              *string    `json:"bio" db:"bio"`
 	Location         *string    `json:"location" db:"location"`
 	BirthDate        *time.Time `json:"birth_date" db:"birth_date"`
-	AvatarURL        *string    `json:"avatar_url" db:"avatar_url"`
-	SubscriptionPlan *string    `json:"subscription_plan" db:"subscription_plan"`
 	IsPrivateAccount *bool      `json:"is_private_account" db:"is_private_account"`
 }
+
+// AvatarResponse carries the managed URL of a newly set avatar.
+type AvatarResponse struct {
+	AvatarURL string `json:"avatar_url"`
+}
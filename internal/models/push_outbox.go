@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Push outbox statuses, as stored in push_outbox.status.
+const (
+	PushPending = "pending"
+	PushSent    = "sent"
+	PushFailed  = "failed"
+)
+
+// PushDelivery is a claimed outbox row with everything needed to send it:
+// the notification it announces, the recipient's device tokens and their
+// quiet hours.
+type PushDelivery struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	NotificationID  uuid.UUID  `json:"notification_id" db:"notification_id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Type            string     `json:"type" db:"type"`
+	ActorUsername   *string    `json:"actor_username" db:"username"`
+	WorkoutID       *uuid.UUID `json:"workout_id" db:"workout_id"`
+	CommentID       *uuid.UUID `json:"comment_id" db:"comment_id"`
+	Attempts        int        `json:"attempts" db:"attempts"`
+	Timezone        string     `json:"timezone" db:"timezone"`
+	QuietHoursStart *string    `json:"quiet_hours_start" db:"quiet_hours_start"`
+	QuietHoursEnd   *string    `json:"quiet_hours_end" db:"quiet_hours_end"`
+	Tokens          []string   `json:"tokens"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	// ClaimedUntil is when the worker's lease ends. Updates made under the
+	// claim only apply while the row still carries it.
+	ClaimedUntil time.Time `json:"claimed_until" db:"next_attempt_at"`
+}
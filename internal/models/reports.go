@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report target types, as stored in reports.target_type.
+const (
+	ReportTargetComment      = "comment"
+	ReportTargetWorkout      = "workout"
+	ReportTargetWorkoutImage = "workout_image"
+	ReportTargetUser         = "user"
+)
+
+// Report statuses. A report is open until a moderator resolves it.
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Moderator actions that resolve a report.
+const (
+	ReportActionHideContent = "hide_content"
+	ReportActionSuspendUser = "suspend_user"
+	ReportActionDismiss     = "dismiss"
+)
+
+// ReportReasons lists the reasons a user may give for a report.
+var ReportReasons = map[string]bool{
+	"spam":        true,
+	"harassment":  true,
+	"hate_speech": true,
+	"nudity":      true,
+	"violence":    true,
+	"self_harm":   true,
+	"other":       true,
+}
+
+// MaxReportsPerHour is how many reports a user may file in an hour.
+const MaxReportsPerHour = 10
+
+// MaxReportNoteLength caps the free-text note on a report, in characters.
+const MaxReportNoteLength = 1000
+
+type Report struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ReporterID   uuid.UUID  `json:"reporter_id" db:"reporter_id"`
+	TargetType   string     `json:"target_type" db:"target_type"`
+	TargetID     uuid.UUID  `json:"target_id" db:"target_id"`
+	TargetUserID uuid.UUID  `json:"target_user_id" db:"target_user_id"`
+	Reason       string     `json:"reason" db:"reason"`
+	Note         *string    `json:"note,omitempty" db:"note"`
+	Status       string     `json:"status" db:"status"`
+	Resolution   *string    `json:"resolution,omitempty" db:"resolution"`
+	ResolvedBy   *uuid.UUID `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateReportRequest struct {
+	TargetType string    `json:"target_type"`
+	TargetID   uuid.UUID `json:"target_id"`
+	Reason     string    `json:"reason"`
+	Note       *string   `json:"note"`
+}
+
+// QueuedReport is a report as moderators see it in the queue, with how many
+// reports are open on the same target.
+type QueuedReport struct {
+	Report
+	ReporterUsername *string `json:"reporter_username" db:"reporter_username"`
+	OpenReports      int     `json:"open_reports" db:"open_reports"`
+}
+
+// ResolveReportRequest resolves a report and every other open report on the
+// same target. SuspendUntil is required to suspend the target's author.
+type ResolveReportRequest struct {
+	Action       string     `json:"action"`
+	SuspendUntil *time.Time `json:"suspend_until"`
+	Note         *string    `json:"note"`
+}
+
+type ResolveReportResponse struct {
+	ReportsResolved int64 `json:"reports_resolved"`
+}
+
+// ReportFilter narrows the moderation queue. Nil fields match everything.
+type ReportFilter struct {
+	Status     *string
+	TargetType *string
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+)
+
+// TrainingStats aggregates a user's workouts over [From, To). Weights are
+// expressed in Unit (the user's unit_weight setting).
+type TrainingStats struct {
+	Unit         string            `json:"unit"`
+	Bucket       string            `json:"bucket"`
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	Buckets      []*StatsBucket    `json:"buckets"`
+	MuscleSets   []*MuscleSetCount `json:"muscle_sets"`
+	TopExercises []*ExerciseStat   `json:"top_exercises"`
+}
+
+type StatsBucket struct {
+	Start           time.Time         `json:"start"`
+	Volume          float64           `json:"volume"`
+	WorkoutCount    int               `json:"workout_count"`
+	DurationSeconds int               `json:"duration_seconds"`
+	MuscleSets      []*MuscleSetCount `json:"muscle_sets"`
+}
+
+type MuscleSetCount struct {
+	MuscleID uuid.UUID `json:"muscle_id" db:"muscle_id"`
+	Name     string    `json:"name" db:"name"`
+	Sets     int       `json:"sets" db:"sets"`
+}
+
+type ExerciseStat struct {
+	ExerciseID   uuid.UUID `json:"exercise_id" db:"exercise_id"`
+	Name         string    `json:"name" db:"name"`
+	Sets         int       `json:"sets" db:"sets"`
+	Volume       float64   `json:"volume" db:"volume"`
+	WorkoutCount int       `json:"workout_count" db:"workout_count"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,6 +10,7 @@ import (
 type Subscription struct {
 	ID                    uuid.UUID `json:"id" db:"id"`
 	UserID                uuid.UUID `json:"user_id" db:"user_id"`
+	Store                 string    `json:"store" db:"store"`
 	OriginalTransactionID string    `json:"original_transaction_id,omitempty" db:"original_transaction_id"`
 	ProductID             string    `json:"product_id,omitempty" db:"product_id"`
 	Status                string    `json:"status,omitempty" db:"status"`
@@ -17,3 +19,57 @@ type Subscription struct {
 	CreatedAt             time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Stores a subscription can be bought from.
+const (
+	StoreAppStore   = "app_store"
+	StoreGooglePlay = "google_play"
+)
+
+// EnvironmentProduction is the environment of paid purchases, as stored in
+// subscriptions.environment. Store sandboxes and test purchases are free.
+const EnvironmentProduction = "production"
+
+// Subscription statuses, derived from the verified store transaction.
+const (
+	SubscriptionActive       = "active"
+	SubscriptionGracePeriod  = "grace_period"
+	SubscriptionBillingRetry = "billing_retry"
+	SubscriptionExpired      = "expired"
+	SubscriptionRevoked      = "revoked"
+)
+
+// VerifiedSubscription is a store purchase whose authenticity has been
+// checked. OriginalTransactionID identifies the purchase within its store:
+// the App Store's original transaction ID or Google Play's purchase token.
+// Payload is the purchase as the store reported it.
+type VerifiedSubscription struct {
+	Store                 string
+	OriginalTransactionID string
+	// LinkedTransactionID is a purchase this one replaces, such as Google
+	// Play's linkedPurchaseToken after an upgrade
+	LinkedTransactionID string
+	TransactionID       string
+	ProductID           string
+	Status              string
+	ExpiresAt           time.Time
+	Environment         string
+	SignedAt            time.Time
+	Payload             json.RawMessage
+}
+
+// SubscriptionEvent is one processed store notification. Events are
+// append-only; Store and NotificationID make processing idempotent.
+type SubscriptionEvent struct {
+	ID                    uuid.UUID       `json:"id" db:"id"`
+	Store                 string          `json:"store" db:"store"`
+	NotificationID        string          `json:"notification_id" db:"notification_id"`
+	NotificationType      string          `json:"notification_type" db:"notification_type"`
+	Subtype               string          `json:"subtype,omitempty" db:"subtype"`
+	OriginalTransactionID string          `json:"original_transaction_id,omitempty" db:"original_transaction_id"`
+	UserID                *uuid.UUID      `json:"user_id,omitempty" db:"user_id"`
+	Status                string          `json:"status,omitempty" db:"status"`
+	SignedAt              time.Time       `json:"signed_at" db:"signed_at"`
+	Payload               json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt             time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Upload statuses, as stored in uploads.status.
+const (
+	UploadPending   = "pending"
+	UploadCommitted = "committed"
+)
+
+// Upload is a file a client was allowed to put into blob storage. It stays
+// pending until it is committed as, say, a workout image.
+type Upload struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	ObjectKey   string    `json:"-" db:"object_key"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	SizeBytes   int64     `json:"size_bytes" db:"size_bytes"`
+	Status      string    `json:"status" db:"status"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// CreateUploadResponse tells the client where to send an upload's file.
+// The request must use Method and carry Headers exactly as given.
+type CreateUploadResponse struct {
+	UploadID  uuid.UUID         `json:"upload_id"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// BlobDeletion is a stored object queued for deletion because nothing
+// references it anymore.
+type BlobDeletion struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	ObjectKey string    `json:"object_key" db:"object_key"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -18,6 +18,11 @@ type UserSetting struct {
 	UnitWeight             string    `json:"unit_weight" db:"unit_weight"`
 	UnitDistance           string    `json:"unit_distance" db:"unit_distance"`
 	UnitLength             string    `json:"unit_length" db:"unit_length"`
+	Timezone               string    `json:"timezone" db:"timezone"`
+	StreakRule             string    `json:"streak_rule" db:"streak_rule"`
+	StreakWeeklyTarget     int       `json:"streak_weekly_target" db:"streak_weekly_target"`
+	QuietHoursStart        *string   `json:"quiet_hours_start" db:"quiet_hours_start"`
+	QuietHoursEnd          *string   `json:"quiet_hours_end" db:"quiet_hours_end"`
 	CreatedAt              time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt              time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -33,4 +38,10 @@ type UpdateUserSettingsRequest struct {
 	UnitWeight             *string `json:"unit_weight" db:"unit_weight"`
 	UnitDistance           *string `json:"unit_distance" db:"unit_distance"`
 	UnitLength             *string `json:"unit_length" db:"unit_length"`
+	Timezone               *string `json:"timezone" db:"timezone"`
+	StreakRule             *string `json:"streak_rule" db:"streak_rule"`
+	StreakWeeklyTarget     *int    `json:"streak_weekly_target" db:"streak_weekly_target"`
+	// Quiet hours are "HH:MM"; an empty string turns them off
+	QuietHoursStart *string `json:"quiet_hours_start" db:"quiet_hours_start"`
+	QuietHoursEnd   *string `json:"quiet_hours_end" db:"quiet_hours_end"`
 }
@@ -6,11 +6,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// MaxWorkoutImages is how many images a workout may have.
+const MaxWorkoutImages = 10
+
 type WorkoutImage struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	WorkoutID    uuid.UUID `json:"workout_id" db:"workout_id"`
-	StoragePath  string    `json:"storage_path" db:"storage_path"`
-	DisplayOrder int       `json:"display_order,omitempty" db:"display_order"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID            uuid.UUID `json:"id" db:"id"`
+	WorkoutID     uuid.UUID `json:"workout_id" db:"workout_id"`
+	StoragePath   string    `json:"storage_path" db:"storage_path"`
+	ThumbnailPath *string   `json:"thumbnail_path,omitempty" db:"thumbnail_path"`
+	DisplayOrder  int       `json:"display_order,omitempty" db:"display_order"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	// URL and ThumbnailURL are short-lived signed URLs to read the images,
+	// filled in by handlers.
+	URL          string `json:"url,omitempty" db:"-"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty" db:"-"`
+}
+
+// ReorderWorkoutImagesRequest lists all of a workout's images in their new
+// order.
+type ReorderWorkoutImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids"`
 }
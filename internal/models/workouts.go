@@ -7,18 +7,19 @@ import (
 )
 
 type Workout struct {
-	ID              uuid.UUID `json:"id" db:"id"`
-	UserID          uuid.UUID `json:"user_id" db:"user_id"`
-	Name            *string   `json:"name,omitempty" db:"name"`
-	Comment         *string   `json:"comment,omitempty" db:"comment"`
-	StartedAt       time.Time `json:"started_at" db:"started_at"`
-	EndedAt         time.Time `json:"ended_at,omitempty" db:"ended_at"`
-	DurationSeconds int       `json:"duration_seconds,omitempty" db:"duration_seconds"`
-	TotalWeight     float64   `json:"total_weight,omitempty" db:"total_weight"`
-	LikesCount      int       `json:"likes_count" db:"likes_count"`
-	CommentsCount   int       `json:"comments_count" db:"comments_count"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	RoutineID       *uuid.UUID `json:"routine_id,omitempty" db:"routine_id"`
+	Name            *string    `json:"name,omitempty" db:"name"`
+	Comment         *string    `json:"comment,omitempty" db:"comment"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	EndedAt         time.Time  `json:"ended_at,omitempty" db:"ended_at"`
+	DurationSeconds int        `json:"duration_seconds,omitempty" db:"duration_seconds"`
+	TotalWeight     float64    `json:"total_weight,omitempty" db:"total_weight"`
+	LikesCount      int        `json:"likes_count" db:"likes_count"`
+	CommentsCount   int        `json:"comments_count" db:"comments_count"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 type UpdateWorkoutRequest struct {
@@ -33,6 +34,7 @@ type TimelineWorkout struct {
 	ID            uuid.UUID                  `json:"id" db:"id"`
 	UserID        uuid.UUID                  `json:"user_id" db:"user_id"`
 	Username      string                     `json:"username" db:"username"`
+	DisplayName   *string                    `json:"display_name" db:"display_name"`
 	AvatarURL     *string                    `json:"avatar_url" db:"avatar_url"`
 	Name          *string                    `json:"name,omitempty" db:"name"`
 	Comment       *string                    `json:"comment,omitempty" db:"comment"`
@@ -42,11 +44,25 @@ type TimelineWorkout struct {
 	LikesCount    int                        `json:"likes_count" db:"likes_count"`
 	CommentsCount int                        `json:"comments_count" db:"comments_count"`
 	UpdatedAt     time.Time                  `json:"updated_at" db:"updated_at"`
+	LikedByMe     bool                       `json:"liked_by_me" db:"liked_by_me"`
 	Exercises     []*TimelineWorkoutExercise `json:"exercises" db:"exercises"`
 	Comments      []*TimelineWorkoutComment  `json:"comments" db:"comments"`
 	Images        []*TimelineWorkoutImages   `json:"images" db:"images"`
+	// Score is the For-You ranking score; it only feeds the page cursor.
+	Score float64 `json:"-" db:"-"`
 }
 
+// TimelineInclude selects the optional sections of a timeline workout.
+// Sections left out are returned as null.
+type TimelineInclude struct {
+	Exercises bool
+	Comments  bool // latest few top-level comments only
+	Images    bool
+}
+
+// TimelineIncludeAll is the default when a client does not choose sections.
+var TimelineIncludeAll = TimelineInclude{Exercises: true, Comments: true, Images: true}
+
 type TimelineWorkoutExercise struct {
 	ID         uuid.UUID             `json:"id" db:"id"`
 	ExerciseID uuid.UUID             `json:"exercise_id" db:"exercise_id"`
@@ -60,6 +76,7 @@ type TimelineWorkoutSet struct {
 	Weight     *float64  `json:"weight,omitempty" db:"weight"`
 	Reps       *int      `json:"reps,omitempty" db:"reps"`
 	OrderIndex int       `json:"order_index,omitempty" db:"order_index"`
+	IsPR       bool      `json:"is_pr" db:"is_pr"`
 }
 
 type TimelineWorkoutComment struct {
@@ -74,7 +91,27 @@ type TimelineWorkoutComment struct {
 }
 
 type TimelineWorkoutImages struct {
-	ID           uuid.UUID `json:"id" db:"id"`
-	StoragePath  string    `json:"storage_path" db:"storage_path"`
-	DisplayOrder int       `json:"display_order,omitempty" db:"display_order"`
+	ID            uuid.UUID `json:"id" db:"id"`
+	StoragePath   string    `json:"storage_path" db:"storage_path"`
+	ThumbnailPath *string   `json:"thumbnail_path,omitempty" db:"thumbnail_path"`
+	DisplayOrder  int       `json:"display_order,omitempty" db:"display_order"`
+	// URL and ThumbnailURL are signed by handlers, as on WorkoutImage.
+	URL          string `json:"url,omitempty" db:"-"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty" db:"-"`
+}
+
+// StartedWorkout is a workout freshly created from a routine, returned
+// together with the exercises and sets that were copied into it.
+type StartedWorkout struct {
+	Workout
+	Exercises []*StartedWorkoutExercise `json:"exercises"`
+}
+
+type StartedWorkoutExercise struct {
+	WorkoutExercise
+	Sets []*WorkoutSet `json:"sets"`
+}
+
+type StartRoutineRequest struct {
+	StartedAt *time.Time `json:"started_at"`
 }
@@ -0,0 +1,105 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset position: the sort key of the last item on the previous
+// page. Each list fills in only the fields it sorts by; ID is always set and
+// breaks ties. Clients only ever see it encoded, so its shape can change freely.
+type Cursor struct {
+	Score *float64   `json:"s,omitempty"`
+	Time  *time.Time `json:"t,omitempty"`
+	ID    uuid.UUID  `json:"id"`
+}
+
+// At is a cursor for lists sorted by a timestamp.
+func At(t time.Time, id uuid.UUID) Cursor {
+	return Cursor{Time: &t, ID: id}
+}
+
+// Scored is a cursor for lists sorted by a score, then a timestamp.
+func Scored(score float64, t time.Time, id uuid.UUID) Cursor {
+	return Cursor{Score: &score, Time: &t, ID: id}
+}
+
+// Encode returns the opaque string form handed to clients as next_cursor.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode parses a cursor produced by Encode. An empty string means the first
+// page and yields a nil cursor.
+func Decode(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// The key accessors are nil-safe so a first-page (nil) cursor can be passed
+// straight through as NULL query arguments.
+
+func (c *Cursor) ScoreKey() *float64 {
+	if c == nil {
+		return nil
+	}
+	return c.Score
+}
+
+func (c *Cursor) TimeKey() *time.Time {
+	if c == nil {
+		return nil
+	}
+	return c.Time
+}
+
+func (c *Cursor) IDKey() *uuid.UUID {
+	if c == nil {
+		return nil
+	}
+	return &c.ID
+}
+
+// Page is the standard envelope for list responses. NextCursor is nil on the
+// last page.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// NewPage builds a page from the result of a query that fetched up to limit+1
+// items. The extra item only signals that another page exists and is dropped;
+// the next cursor points at the last item kept.
+func NewPage[T any](items []T, limit int, key func(T) Cursor) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	if len(items) <= limit {
+		return Page[T]{Items: items}
+	}
+	items = items[:limit]
+	next := key(items[len(items)-1]).Encode()
+	return Page[T]{Items: items, NextCursor: &next}
+}
@@ -0,0 +1,61 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 10, 12, 0, 0, 123456000, time.UTC)
+	id := uuid.New()
+
+	c, err := Decode(Scored(4.5, at, id).Encode())
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if *c.ScoreKey() != 4.5 || !c.TimeKey().Equal(at) || *c.IDKey() != id {
+		t.Errorf("round trip mismatch: %+v", c)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	c, err := Decode("")
+	if err != nil || c != nil {
+		t.Errorf("empty cursor: got %v, %v; want nil, nil", c, err)
+	}
+	if c.TimeKey() != nil || c.IDKey() != nil || c.ScoreKey() != nil {
+		t.Error("nil cursor keys should be nil")
+	}
+
+	for _, s := range []string{"not base64!", "bm90IGpzb24", At(time.Now(), uuid.Nil).Encode()} {
+		if _, err := Decode(s); err != ErrInvalidCursor {
+			t.Errorf("Decode(%q): got %v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	key := func(id uuid.UUID) Cursor { return Cursor{ID: id} }
+
+	page := NewPage(ids, 2, key)
+	if len(page.Items) != 2 || page.NextCursor == nil {
+		t.Fatalf("expected 2 items and a next cursor, got %d items, cursor %v", len(page.Items), page.NextCursor)
+	}
+	next, _ := Decode(*page.NextCursor)
+	if next.ID != ids[1] {
+		t.Errorf("next cursor should point at the last item kept")
+	}
+
+	last := NewPage(ids, 3, key)
+	if len(last.Items) != 3 || last.NextCursor != nil {
+		t.Errorf("expected last page without cursor, got %d items, cursor %v", len(last.Items), last.NextCursor)
+	}
+
+	empty := NewPage[uuid.UUID](nil, 3, key)
+	if empty.Items == nil {
+		t.Error("expected empty items to be a non-nil slice")
+	}
+}
@@ -0,0 +1,31 @@
+package push
+
+import (
+	"context"
+	"sync"
+)
+
+// SentPush is a message recorded by FakeSender.
+type SentPush struct {
+	Token   string
+	Message Message
+}
+
+// FakeSender records pushes instead of sending them. Errors maps tokens to
+// the error Send returns for them.
+type FakeSender struct {
+	mu     sync.Mutex
+	Sent   []SentPush
+	Errors map[string]error
+}
+
+func (f *FakeSender) Send(ctx context.Context, token string, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors[token]; err != nil {
+		return err
+	}
+	f.Sent = append(f.Sent, SentPush{Token: token, Message: msg})
+	return nil
+}
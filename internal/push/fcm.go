@@ -0,0 +1,123 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	fcmEndpoint = "https://fcm.googleapis.com"
+	fcmScope    = "https://www.googleapis.com/auth/firebase.messaging"
+
+	// fcmTimeout bounds each send and each token refresh, so a stalled
+	// connection can't hold up the delivery job
+	fcmTimeout = 10 * time.Second
+)
+
+// FCMSender sends pushes through the Firebase Cloud Messaging HTTP v1 API.
+type FCMSender struct {
+	ProjectID string
+	// Client must attach OAuth2 credentials to each request
+	Client   *http.Client
+	Endpoint string
+}
+
+// NewFCMSender authenticates with Application Default Credentials, such as
+// the service account named by GOOGLE_APPLICATION_CREDENTIALS.
+func NewFCMSender(ctx context.Context, projectID string) (*FCMSender, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: fcmTimeout})
+	creds, err := google.FindDefaultCredentials(ctx, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find FCM credentials: %w", err)
+	}
+	if projectID == "" {
+		projectID = creds.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("FCM project ID is not set")
+	}
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	client.Timeout = fcmTimeout
+	return &FCMSender{
+		ProjectID: projectID,
+		Client:    client,
+		Endpoint:  fcmEndpoint,
+	}, nil
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			Type      string `json:"@type"`
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (s *FCMSender) Send(ctx context.Context, token string, msg Message) error {
+	body, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to encode FCM message: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", s.Endpoint, s.ProjectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send FCM message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var fcmErr fcmErrorResponse
+	json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&fcmErr)
+	for _, d := range fcmErr.Error.Details {
+		if d.ErrorCode == "UNREGISTERED" {
+			return ErrUnregistered
+		}
+	}
+	// A 404 without details also means the token is gone
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUnregistered
+	}
+	return fmt.Errorf("FCM returned %d %s: %s", resp.StatusCode, fcmErr.Error.Status, fcmErr.Error.Message)
+}
@@ -0,0 +1,55 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFCMSenderSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantErr    bool
+		unregister bool
+	}{
+		{"ok", http.StatusOK, `{"name":"projects/p/messages/1"}`, false, false},
+		{"unregistered", http.StatusNotFound, `{"error":{"code":404,"status":"NOT_FOUND","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`, true, true},
+		{"unavailable", http.StatusServiceUnavailable, `{"error":{"code":503,"status":"UNAVAILABLE","message":"try later"}}`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got fcmRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/projects/jimu-test/messages:send" {
+					t.Errorf("Unexpected path %s", r.URL.Path)
+				}
+				json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			sender := &FCMSender{ProjectID: "jimu-test", Client: srv.Client(), Endpoint: srv.URL}
+			err := sender.Send(context.Background(), "token-1", Message{
+				Title: "Jimu",
+				Body:  "alex liked your workout",
+				Data:  map[string]string{"type": "workout_like"},
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, ErrUnregistered) != tt.unregister {
+				t.Errorf("Expected unregistered=%v, got %v", tt.unregister, err)
+			}
+			if got.Message.Token != "token-1" || got.Message.Notification.Body != "alex liked your workout" || got.Message.Data["type"] != "workout_like" {
+				t.Errorf("Unexpected request payload: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnregistered is returned by a Sender when the device token is no longer
+// valid, for example because the app was uninstalled. The token should be
+// forgotten rather than retried.
+var ErrUnregistered = errors.New("device token is unregistered")
+
+// Message is the content of one push notification.
+type Message struct {
+	Title string
+	Body  string
+	// Data is delivered to the app alongside the notification
+	Data map[string]string
+}
+
+// PushSender delivers a message to a single device.
+type PushSender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// ParseClock parses an "HH:MM" time of day into minutes after midnight.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil || len(s) != 5 {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// QuietHours is a daily window, in minutes after local midnight, during which
+// pushes are held. A window whose end is before its start wraps past midnight.
+type QuietHours struct {
+	Start int
+	End   int
+	Loc   *time.Location
+}
+
+// NewQuietHours builds a window from "HH:MM" strings in the named timezone.
+// ok is false when either end is unset or invalid, meaning no quiet hours.
+func NewQuietHours(start, end *string, timezone string) (QuietHours, bool) {
+	if start == nil || end == nil {
+		return QuietHours{}, false
+	}
+	s, err := ParseClock(*start)
+	if err != nil {
+		return QuietHours{}, false
+	}
+	e, err := ParseClock(*end)
+	if err != nil || s == e {
+		return QuietHours{}, false
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return QuietHours{Start: s, End: e, Loc: loc}, true
+}
+
+// Until reports whether now falls inside the window and, if so, when the
+// window ends.
+func (q QuietHours) Until(now time.Time) (time.Time, bool) {
+	loc := q.Loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	var quiet bool
+	if q.Start < q.End {
+		quiet = minute >= q.Start && minute < q.End
+	} else {
+		quiet = minute >= q.Start || minute < q.End
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	y, m, d := local.Date()
+	end := time.Date(y, m, d, q.End/60, q.End%60, 0, 0, loc)
+	if !end.After(now) {
+		end = time.Date(y, m, d+1, q.End/60, q.End%60, 0, 0, loc)
+	}
+	return end, true
+}
+
+// Backoff is the delay before retry number attempt (1-based): base doubled
+// per attempt, capped at max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
@@ -0,0 +1,101 @@
+package push
+
+import (
+	"testing"
+	"time"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"07:30", 450, false},
+		{"23:59", 1439, false},
+		{"24:00", 0, true},
+		{"7:30", 0, true},
+		{"07:30:00", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseClock(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseClock(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseClock(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHoursUntil(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	overnight, ok := NewQuietHours(strPtr("22:00"), strPtr("07:00"), "Asia/Tokyo")
+	if !ok {
+		t.Fatal("Expected overnight quiet hours to be valid")
+	}
+	daytime, _ := NewQuietHours(strPtr("13:00"), strPtr("14:00"), "Asia/Tokyo")
+
+	tests := []struct {
+		name  string
+		q     QuietHours
+		now   time.Time
+		quiet bool
+		until time.Time
+	}{
+		{"before midnight", overnight, time.Date(2026, 3, 1, 23, 15, 0, 0, tokyo), true, time.Date(2026, 3, 2, 7, 0, 0, 0, tokyo)},
+		{"after midnight", overnight, time.Date(2026, 3, 2, 6, 59, 0, 0, tokyo), true, time.Date(2026, 3, 2, 7, 0, 0, 0, tokyo)},
+		{"at the end", overnight, time.Date(2026, 3, 2, 7, 0, 0, 0, tokyo), false, time.Time{}},
+		{"daytime", overnight, time.Date(2026, 3, 2, 12, 0, 0, 0, tokyo), false, time.Time{}},
+		{"inside same-day window", daytime, time.Date(2026, 3, 2, 13, 30, 0, 0, tokyo), true, time.Date(2026, 3, 2, 14, 0, 0, 0, tokyo)},
+		{"outside same-day window", daytime, time.Date(2026, 3, 2, 22, 0, 0, 0, tokyo), false, time.Time{}},
+		// The window is evaluated in the user's zone, not the server's
+		{"utc instant", overnight, time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC), true, time.Date(2026, 3, 2, 7, 0, 0, 0, tokyo)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, quiet := tt.q.Until(tt.now)
+			if quiet != tt.quiet {
+				t.Fatalf("Expected quiet=%v, got %v", tt.quiet, quiet)
+			}
+			if quiet && !until.Equal(tt.until) {
+				t.Errorf("Expected quiet until %v, got %v", tt.until, until)
+			}
+		})
+	}
+}
+
+func TestNewQuietHours_Disabled(t *testing.T) {
+	cases := []struct {
+		start, end *string
+	}{
+		{nil, nil},
+		{strPtr("22:00"), nil},
+		{strPtr("22:00"), strPtr("22:00")},
+		{strPtr("bad"), strPtr("07:00")},
+	}
+	for _, c := range cases {
+		if _, ok := NewQuietHours(c.start, c.end, "UTC"); ok {
+			t.Errorf("Expected no quiet hours for %v-%v", c.start, c.end)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 10*time.Minute
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, w := range want {
+		if got := Backoff(i+1, base, max); got != w {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
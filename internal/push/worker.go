@@ -0,0 +1,167 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+const (
+	pushTitle = "Jimu"
+	// claimLease is how long a claimed delivery is hidden from other workers.
+	// A worker that dies mid-batch leaves its deliveries to be retried after it.
+	// The lease is renewed as each delivery starts, so it need only cover one.
+	claimLease = 5 * time.Minute
+	// claimMargin is kept free at the end of a lease for recording the outcome;
+	// sends still running by then are abandoned.
+	claimMargin = 30 * time.Second
+	// outboxRetention is how long sent and failed deliveries are kept.
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Outbox is the durable queue of pending push deliveries.
+type Outbox interface {
+	ClaimPushDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.PushDelivery, error)
+	// RenewPushClaim returns the zero time if the claim was lost to another worker
+	RenewPushClaim(ctx context.Context, id uuid.UUID, claimedUntil time.Time, lease time.Duration) (time.Time, error)
+	// The calls below apply only while the delivery still holds claimedUntil
+	MarkPushSent(ctx context.Context, id uuid.UUID, claimedUntil time.Time) error
+	// DeferPush reschedules a delivery without counting an attempt
+	DeferPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, until time.Time) error
+	RetryPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, at time.Time, lastErr string) error
+	FailPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, lastErr string) error
+	DeleteDeviceToken(ctx context.Context, token string) error
+	PruneOutbox(ctx context.Context, before time.Time) error
+}
+
+// Worker drains the outbox, sending each delivery to all of the recipient's
+// devices. A delivery succeeds once any device accepts it; if none does, it is
+// retried with exponential backoff until MaxAttempts is reached.
+type Worker struct {
+	Outbox      Outbox
+	Sender      PushSender
+	BatchSize   int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	Now         func() time.Time
+}
+
+func NewWorker(outbox Outbox, sender PushSender) *Worker {
+	return &Worker{
+		Outbox:      outbox,
+		Sender:      sender,
+		BatchSize:   100,
+		MaxAttempts: 8,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  2 * time.Hour,
+		Now:         time.Now,
+	}
+}
+
+// Run delivers everything that is currently due.
+func (w *Worker) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deliveries, err := w.Outbox.ClaimPushDeliveries(ctx, w.BatchSize, claimLease)
+		if err != nil {
+			return err
+		}
+		for _, d := range deliveries {
+			if err := w.deliver(ctx, d); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < w.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// Prune forgets deliveries that finished more than a week ago.
+func (w *Worker) Prune(ctx context.Context) error {
+	return w.Outbox.PruneOutbox(ctx, w.Now().Add(-outboxRetention))
+}
+
+func (w *Worker) deliver(ctx context.Context, d *models.PushDelivery) error {
+	// Earlier deliveries in the batch may have used up much of the lease
+	claimedUntil, err := w.Outbox.RenewPushClaim(ctx, d.ID, d.ClaimedUntil, claimLease)
+	if err != nil {
+		return err
+	}
+	if claimedUntil.IsZero() {
+		log.Printf("Push %s was claimed by another worker", d.ID)
+		return nil
+	}
+
+	now := w.Now()
+	if quiet, ok := NewQuietHours(d.QuietHoursStart, d.QuietHoursEnd, d.Timezone); ok {
+		if until, ok := quiet.Until(now); ok {
+			return w.Outbox.DeferPush(ctx, d.ID, claimedUntil, until)
+		}
+	}
+
+	// Sends must not outlive the claim, or another worker could send again
+	sendCtx, cancel := context.WithDeadline(ctx, claimedUntil.Add(-claimMargin))
+	defer cancel()
+
+	msg := DeliveryMessage(d)
+	delivered := 0
+	var lastErr error
+	for _, token := range d.Tokens {
+		err := w.Sender.Send(sendCtx, token, msg)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, ErrUnregistered):
+			if err := w.Outbox.DeleteDeviceToken(ctx, token); err != nil {
+				return err
+			}
+		default:
+			lastErr = err
+		}
+	}
+
+	switch {
+	case delivered > 0:
+		return w.Outbox.MarkPushSent(ctx, d.ID, claimedUntil)
+	case lastErr == nil:
+		return w.Outbox.FailPush(ctx, d.ID, claimedUntil, "no registered devices")
+	case d.Attempts+1 >= w.MaxAttempts:
+		log.Printf("Push %s failed after %d attempts: %v", d.ID, d.Attempts+1, lastErr)
+		return w.Outbox.FailPush(ctx, d.ID, claimedUntil, lastErr.Error())
+	default:
+		at := now.Add(Backoff(d.Attempts+1, w.BaseBackoff, w.MaxBackoff))
+		return w.Outbox.RetryPush(ctx, d.ID, claimedUntil, at, lastErr.Error())
+	}
+}
+
+// DeliveryMessage renders the push for a delivery. Pushes announce single
+// events, so unlike the inbox they never aggregate actors.
+func DeliveryMessage(d *models.PushDelivery) Message {
+	actor := "Someone"
+	if d.ActorUsername != nil && *d.ActorUsername != "" {
+		actor = *d.ActorUsername
+	}
+
+	data := map[string]string{
+		"type":            d.Type,
+		"notification_id": d.NotificationID.String(),
+	}
+	if d.WorkoutID != nil {
+		data["workout_id"] = d.WorkoutID.String()
+	}
+	if d.CommentID != nil {
+		data["comment_id"] = d.CommentID.String()
+	}
+
+	return Message{
+		Title: pushTitle,
+		Body:  models.NotificationMessage(d.Type, actor, 0),
+		Data:  data,
+	}
+}
@@ -0,0 +1,252 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+type outboxCall struct {
+	Op      string
+	ID      uuid.UUID
+	Claim   time.Time
+	At      time.Time
+	LastErr string
+}
+
+type mockOutbox struct {
+	deliveries    []*models.PushDelivery
+	calls         []outboxCall
+	deletedTokens []string
+	// lost holds deliveries whose claim another worker has taken over
+	lost   map[uuid.UUID]bool
+	claims map[uuid.UUID]time.Time
+}
+
+func (m *mockOutbox) ClaimPushDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.PushDelivery, error) {
+	n := min(limit, len(m.deliveries))
+	claimed := m.deliveries[:n]
+	m.deliveries = m.deliveries[n:]
+	return claimed, nil
+}
+
+func (m *mockOutbox) RenewPushClaim(ctx context.Context, id uuid.UUID, claimedUntil time.Time, lease time.Duration) (time.Time, error) {
+	if m.lost[id] {
+		return time.Time{}, nil
+	}
+	renewed := time.Now().Add(lease)
+	if m.claims == nil {
+		m.claims = map[uuid.UUID]time.Time{}
+	}
+	m.claims[id] = renewed
+	return renewed, nil
+}
+
+func (m *mockOutbox) MarkPushSent(ctx context.Context, id uuid.UUID, claimedUntil time.Time) error {
+	m.calls = append(m.calls, outboxCall{Op: "sent", ID: id, Claim: claimedUntil})
+	return nil
+}
+
+func (m *mockOutbox) DeferPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, until time.Time) error {
+	m.calls = append(m.calls, outboxCall{Op: "defer", ID: id, Claim: claimedUntil, At: until})
+	return nil
+}
+
+func (m *mockOutbox) RetryPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, at time.Time, lastErr string) error {
+	m.calls = append(m.calls, outboxCall{Op: "retry", ID: id, Claim: claimedUntil, At: at, LastErr: lastErr})
+	return nil
+}
+
+func (m *mockOutbox) FailPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, lastErr string) error {
+	m.calls = append(m.calls, outboxCall{Op: "fail", ID: id, Claim: claimedUntil, LastErr: lastErr})
+	return nil
+}
+
+func (m *mockOutbox) DeleteDeviceToken(ctx context.Context, token string) error {
+	m.deletedTokens = append(m.deletedTokens, token)
+	return nil
+}
+
+func (m *mockOutbox) PruneOutbox(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func newDelivery(tokens ...string) *models.PushDelivery {
+	username := "alex"
+	return &models.PushDelivery{
+		ID:             uuid.New(),
+		NotificationID: uuid.New(),
+		UserID:         uuid.New(),
+		Type:           models.NotificationWorkoutLike,
+		ActorUsername:  &username,
+		Timezone:       "UTC",
+		Tokens:         tokens,
+	}
+}
+
+func TestWorkerRun(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	errUnavailable := errors.New("FCM returned 503")
+
+	tests := []struct {
+		name        string
+		delivery    func() *models.PushDelivery
+		errors      map[string]error
+		wantOp      string
+		wantSent    int
+		wantDeleted []string
+		wantAt      time.Time
+	}{
+		{
+			name:     "Sends to every device",
+			delivery: func() *models.PushDelivery { return newDelivery("a", "b") },
+			wantOp:   "sent",
+			wantSent: 2,
+		},
+		{
+			name:        "Prunes unregistered tokens",
+			delivery:    func() *models.PushDelivery { return newDelivery("a", "gone") },
+			errors:      map[string]error{"gone": ErrUnregistered},
+			wantOp:      "sent",
+			wantSent:    1,
+			wantDeleted: []string{"gone"},
+		},
+		{
+			name:        "Fails when no device is left",
+			delivery:    func() *models.PushDelivery { return newDelivery("gone") },
+			errors:      map[string]error{"gone": ErrUnregistered},
+			wantOp:      "fail",
+			wantDeleted: []string{"gone"},
+		},
+		{
+			name: "Retries transient errors with backoff",
+			delivery: func() *models.PushDelivery {
+				d := newDelivery("a")
+				d.Attempts = 2
+				return d
+			},
+			errors: map[string]error{"a": errUnavailable},
+			wantOp: "retry",
+			wantAt: now.Add(2 * time.Minute),
+		},
+		{
+			name: "Gives up after max attempts",
+			delivery: func() *models.PushDelivery {
+				d := newDelivery("a")
+				d.Attempts = 7
+				return d
+			},
+			errors: map[string]error{"a": errUnavailable},
+			wantOp: "fail",
+		},
+		{
+			name: "Holds pushes during quiet hours",
+			delivery: func() *models.PushDelivery {
+				d := newDelivery("a")
+				d.QuietHoursStart, d.QuietHoursEnd = strPtr("11:00"), strPtr("13:30")
+				return d
+			},
+			wantOp: "defer",
+			wantAt: time.Date(2026, 3, 2, 13, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.delivery()
+			outbox := &mockOutbox{deliveries: []*models.PushDelivery{d}}
+			sender := &FakeSender{Errors: tt.errors}
+			w := NewWorker(outbox, sender)
+			w.Now = func() time.Time { return now }
+
+			if err := w.Run(context.Background()); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if len(outbox.calls) != 1 || outbox.calls[0].Op != tt.wantOp || outbox.calls[0].ID != d.ID {
+				t.Fatalf("Expected a single %q, got %+v", tt.wantOp, outbox.calls)
+			}
+			if !outbox.calls[0].Claim.Equal(outbox.claims[d.ID]) {
+				t.Errorf("Expected the outcome under the renewed claim %v, got %v", outbox.claims[d.ID], outbox.calls[0].Claim)
+			}
+			if !tt.wantAt.IsZero() && !outbox.calls[0].At.Equal(tt.wantAt) {
+				t.Errorf("Expected rescheduling at %v, got %v", tt.wantAt, outbox.calls[0].At)
+			}
+			if len(sender.Sent) != tt.wantSent {
+				t.Errorf("Expected %d pushes sent, got %d", tt.wantSent, len(sender.Sent))
+			}
+			if len(outbox.deletedTokens) != len(tt.wantDeleted) {
+				t.Errorf("Expected deleted tokens %v, got %v", tt.wantDeleted, outbox.deletedTokens)
+			}
+		})
+	}
+}
+
+func TestWorkerRunDrainsBatches(t *testing.T) {
+	outbox := &mockOutbox{}
+	for i := 0; i < 5; i++ {
+		outbox.deliveries = append(outbox.deliveries, newDelivery("a"))
+	}
+	sender := &FakeSender{}
+	w := NewWorker(outbox, sender)
+	w.BatchSize = 2
+
+	if err := w.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(sender.Sent) != 5 {
+		t.Errorf("Expected all 5 deliveries sent, got %d", len(sender.Sent))
+	}
+	if sender.Sent[0].Message.Body != "alex liked your workout" {
+		t.Errorf("Unexpected body %q", sender.Sent[0].Message.Body)
+	}
+}
+
+func TestWorkerRunSkipsLostClaims(t *testing.T) {
+	lost := newDelivery("a")
+	kept := newDelivery("b")
+	outbox := &mockOutbox{
+		deliveries: []*models.PushDelivery{lost, kept},
+		lost:       map[uuid.UUID]bool{lost.ID: true},
+	}
+	sender := &FakeSender{}
+	w := NewWorker(outbox, sender)
+
+	if err := w.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(sender.Sent) != 1 || sender.Sent[0].Token != "b" {
+		t.Errorf("Expected only the still-claimed delivery to be sent, got %+v", sender.Sent)
+	}
+	if len(outbox.calls) != 1 || outbox.calls[0].ID != kept.ID {
+		t.Errorf("Expected no outcome recorded for the lost delivery, got %+v", outbox.calls)
+	}
+}
+
+func TestWorkerBoundsSendsByClaim(t *testing.T) {
+	outbox := &mockOutbox{deliveries: []*models.PushDelivery{newDelivery("a")}}
+	var deadline time.Time
+	sender := senderFunc(func(ctx context.Context, token string, msg Message) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+	w := NewWorker(outbox, sender)
+
+	if err := w.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	claim := outbox.claims[outbox.calls[0].ID]
+	if want := claim.Add(-claimMargin); !deadline.Equal(want) {
+		t.Errorf("Expected sends to end by %v, got %v", want, deadline)
+	}
+}
+
+type senderFunc func(ctx context.Context, token string, msg Message) error
+
+func (f senderFunc) Send(ctx context.Context, token string, msg Message) error {
+	return f(ctx, token, msg)
+}
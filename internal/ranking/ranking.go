@@ -0,0 +1,275 @@
+package ranking
+
+import (
+	"bytes"
+	"hash/fnv"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// Candidate is a workout eligible for the For-You feed along with the signals
+// a Scorer may use.
+type Candidate struct {
+	WorkoutID   uuid.UUID
+	AuthorID    uuid.UUID
+	StartedAt   time.Time
+	Likes       int
+	Comments    int
+	ExerciseIDs []uuid.UUID
+}
+
+// Affinity describes what the viewer cares about. Authors maps users the
+// viewer interacts with to a weight in [0, 1]; Exercises holds the exercises
+// the viewer does themselves.
+type Affinity struct {
+	Authors   map[uuid.UUID]float64
+	Exercises map[uuid.UUID]bool
+}
+
+// followWeight is the author affinity of simply following someone, and
+// interactionScale the number of likes and comments that take an author
+// halfway from there to full affinity.
+const (
+	followWeight     = 0.5
+	interactionScale = 5.0
+)
+
+// NewAffinity builds an Affinity from the viewer's likes and comments per
+// author, the users they follow and the exercises they have done. Author
+// weights saturate towards 1 so one heavy interaction history cannot dominate.
+func NewAffinity(interactions map[uuid.UUID]int, following []uuid.UUID, exercises []uuid.UUID) Affinity {
+	a := Affinity{Authors: map[uuid.UUID]float64{}, Exercises: map[uuid.UUID]bool{}}
+	for _, id := range following {
+		a.Authors[id] = followWeight
+	}
+	for id, n := range interactions {
+		base := a.Authors[id]
+		a.Authors[id] = base + (1-base)*float64(n)/(float64(n)+interactionScale)
+	}
+	for _, id := range exercises {
+		a.Exercises[id] = true
+	}
+	return a
+}
+
+// Scorer assigns a relevance score to a candidate for one viewer. Higher is
+// better, and scores are on a log2 scale: a difference of 1 means twice as
+// relevant. Scores must not depend on the current time, so that a cursor taken
+// on one page still points at the same place when the next page is requested.
+type Scorer interface {
+	Name() string
+	Score(c Candidate, a Affinity) float64
+}
+
+// Engagement is the original For-You ordering: raw likes plus comments, with
+// no decay or personalization. It is kept as a control arm for experiments.
+type Engagement struct{}
+
+func (Engagement) Name() string { return "engagement" }
+
+func (Engagement) Score(c Candidate, _ Affinity) float64 {
+	return math.Log2(1 + float64(c.Likes+c.Comments))
+}
+
+// epoch anchors time-decayed scores. Any fixed instant works; it only keeps
+// the numbers small.
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Decayed scores engagement that halves every HalfLife, boosted by the viewer's
+// affinity for the author and by overlap with the exercises they do.
+//
+// Rather than decaying old posts relative to now, newer posts get a bonus of 1
+// per HalfLife since a fixed epoch. On a log2 scale that orders posts exactly
+// as exponential decay would, while keeping scores stable over time.
+type Decayed struct {
+	HalfLife      time.Duration
+	CommentWeight float64 // a comment counts this many likes
+	AuthorBoost   float64 // multiplier at full author affinity is 1+AuthorBoost
+	ExerciseBoost float64 // multiplier at full exercise overlap is 1+ExerciseBoost
+}
+
+func (d Decayed) Name() string { return "decayed" }
+
+func (d Decayed) Score(c Candidate, a Affinity) float64 {
+	// The +1 lets fresh posts without engagement still rank by recency
+	engagement := 1 + float64(c.Likes) + d.CommentWeight*float64(c.Comments)
+
+	freshness := 0.0
+	if d.HalfLife > 0 {
+		freshness = float64(c.StartedAt.Sub(epoch)) / float64(d.HalfLife)
+	}
+
+	boost := 1 + d.AuthorBoost*a.Authors[c.AuthorID]
+	if len(c.ExerciseIDs) > 0 && len(a.Exercises) > 0 {
+		shared := 0
+		for _, id := range c.ExerciseIDs {
+			if a.Exercises[id] {
+				shared++
+			}
+		}
+		boost *= 1 + d.ExerciseBoost*float64(shared)/float64(len(c.ExerciseIDs))
+	}
+
+	return math.Log2(engagement*boost) + freshness
+}
+
+// DefaultScorer is the scorer used outside of experiments.
+var DefaultScorer Scorer = Decayed{
+	HalfLife:      24 * time.Hour,
+	CommentWeight: 2,
+	AuthorBoost:   1,
+	ExerciseBoost: 0.5,
+}
+
+// Experiment splits viewers between scorers. Assignment is a stable hash of
+// the experiment name and viewer ID, so a viewer keeps their arm across
+// requests and renaming the experiment reshuffles everyone.
+type Experiment struct {
+	Name     string
+	Variants []Scorer
+}
+
+// DefaultExperiment runs DefaultScorer for everyone.
+func DefaultExperiment() Experiment {
+	return Experiment{Name: "for-you", Variants: []Scorer{DefaultScorer}}
+}
+
+// Assign returns the scorer for a viewer.
+func (e Experiment) Assign(viewerID uuid.UUID) Scorer {
+	if len(e.Variants) == 0 {
+		return DefaultScorer
+	}
+	h := fnv.New32a()
+	h.Write([]byte(e.Name))
+	h.Write(viewerID[:])
+	return e.Variants[h.Sum32()%uint32(len(e.Variants))]
+}
+
+// DiversityDecay is applied once per earlier, higher-scored post by the same
+// author, so an author's second post counts at 70%, their third at 49% and so
+// on. It spreads a popular author's posts out instead of stacking them.
+const DiversityDecay = 0.7
+
+// diversityPenalty is DiversityDecay on the log2 score scale.
+var diversityPenalty = -math.Log2(DiversityDecay)
+
+// Ranked is a scored candidate.
+type Ranked struct {
+	Candidate
+	Score float64
+}
+
+// Cursor is the pagination position of a ranked item.
+func (r Ranked) Cursor() pagination.Cursor {
+	return pagination.Scored(r.Score, r.StartedAt, r.WorkoutID)
+}
+
+// Rank scores candidates, applies the per-author diversity decay and returns
+// them best first. Ties are broken by recency, then by workout ID.
+func Rank(candidates []Candidate, a Affinity, s Scorer) []Ranked {
+	ranked := make([]Ranked, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = Ranked{Candidate: c, Score: s.Score(c, a)}
+	}
+	sortRanked(ranked)
+
+	seen := map[uuid.UUID]int{}
+	for i := range ranked {
+		n := seen[ranked[i].AuthorID]
+		ranked[i].Score -= diversityPenalty * float64(n)
+		seen[ranked[i].AuthorID] = n + 1
+	}
+	sortRanked(ranked)
+
+	return ranked
+}
+
+// Spread reorders ranked items so that no author has more than perAuthor
+// items in any window consecutive ones. Items over the cap are deferred until
+// the window has moved past their author, and only when every remaining
+// author is capped does the best of them go next, so nothing is dropped.
+// A deferred item's score is lowered just below the item before it; the
+// result stays sorted, so cursors taken from it never skip an item.
+func Spread(ranked []Ranked, perAuthor int, window int) []Ranked {
+	if perAuthor <= 0 || window <= perAuthor {
+		return ranked
+	}
+	pending := slices.Clone(ranked)
+	spread := make([]Ranked, 0, len(ranked))
+	inWindow := map[uuid.UUID]int{}
+	for len(pending) > 0 {
+		next := 0
+		for i, r := range pending {
+			if inWindow[r.AuthorID] < perAuthor {
+				next = i
+				break
+			}
+		}
+		r := pending[next]
+		pending = slices.Delete(pending, next, next+1)
+
+		if n := len(spread); n > 0 {
+			prev := spread[n-1]
+			if !before(prev.Score, prev.StartedAt, prev.WorkoutID, r.Score, r.StartedAt, r.WorkoutID) {
+				r.Score = math.Nextafter(prev.Score, math.Inf(-1))
+			}
+		}
+		spread = append(spread, r)
+		inWindow[r.AuthorID]++
+		if n := len(spread); n >= window {
+			inWindow[spread[n-window].AuthorID]--
+		}
+	}
+	return spread
+}
+
+// Page selects up to limit items that come after the cursor.
+func Page(ranked []Ranked, after *pagination.Cursor, limit int) []Ranked {
+	page := []Ranked{}
+	for _, r := range ranked {
+		if len(page) == limit {
+			break
+		}
+		if after != nil && !isAfter(r, after) {
+			continue
+		}
+		page = append(page, r)
+	}
+	return page
+}
+
+func sortRanked(ranked []Ranked) {
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return before(ranked[i].Score, ranked[i].StartedAt, ranked[i].WorkoutID,
+			ranked[j].Score, ranked[j].StartedAt, ranked[j].WorkoutID)
+	})
+}
+
+// isAfter reports whether r sorts strictly after the cursor position.
+func isAfter(r Ranked, c *pagination.Cursor) bool {
+	score := 0.0
+	if s := c.ScoreKey(); s != nil {
+		score = *s
+	}
+	var at time.Time
+	if t := c.TimeKey(); t != nil {
+		at = *t
+	}
+	return before(score, at, c.ID, r.Score, r.StartedAt, r.WorkoutID)
+}
+
+// before reports whether item a ranks ahead of item b.
+func before(scoreA float64, atA time.Time, idA uuid.UUID, scoreB float64, atB time.Time, idB uuid.UUID) bool {
+	if scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	if !atA.Equal(atB) {
+		return atA.After(atB)
+	}
+	return bytes.Compare(idA[:], idB[:]) > 0
+}
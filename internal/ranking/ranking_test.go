@@ -0,0 +1,160 @@
+package ranking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func candidate(author uuid.UUID, hoursAgo int, likes int) Candidate {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	return Candidate{
+		WorkoutID: uuid.New(),
+		AuthorID:  author,
+		StartedAt: now.Add(-time.Duration(hoursAgo) * time.Hour),
+		Likes:     likes,
+	}
+}
+
+func TestDecayedOldViralPostsFade(t *testing.T) {
+	s := Decayed{HalfLife: 24 * time.Hour, CommentWeight: 2}
+	author := uuid.New()
+
+	viral := candidate(author, 24*10, 500) // ten half-lives old
+	fresh := candidate(author, 1, 5)
+
+	if s.Score(viral, Affinity{}) >= s.Score(fresh, Affinity{}) {
+		t.Error("expected a fresh post to outrank a ten-day-old viral one")
+	}
+
+	recentViral := candidate(author, 2, 500)
+	if s.Score(recentViral, Affinity{}) <= s.Score(fresh, Affinity{}) {
+		t.Error("expected engagement to win between posts of similar age")
+	}
+}
+
+func TestDecayedAffinityBoost(t *testing.T) {
+	s := DefaultScorer
+	friend, stranger := uuid.New(), uuid.New()
+	squat := uuid.New()
+
+	a := Affinity{
+		Authors:   map[uuid.UUID]float64{friend: 1},
+		Exercises: map[uuid.UUID]bool{squat: true},
+	}
+
+	fromFriend := candidate(friend, 3, 2)
+	fromStranger := candidate(stranger, 3, 2)
+	fromStranger.WorkoutID = fromFriend.WorkoutID
+	if s.Score(fromFriend, a) <= s.Score(fromStranger, a) {
+		t.Error("expected author affinity to boost the score")
+	}
+
+	sameExercise := fromStranger
+	sameExercise.ExerciseIDs = []uuid.UUID{squat}
+	if s.Score(sameExercise, a) <= s.Score(fromStranger, a) {
+		t.Error("expected exercise overlap to boost the score")
+	}
+}
+
+func TestRankDiversityAndPage(t *testing.T) {
+	popular, other := uuid.New(), uuid.New()
+	candidates := []Candidate{
+		candidate(popular, 1, 100),
+		candidate(popular, 2, 90),
+		candidate(popular, 3, 80),
+		candidate(popular, 4, 70),
+		candidate(other, 5, 10),
+	}
+
+	ranked := Spread(Rank(candidates, Affinity{}, Engagement{}), 2, 3)
+	page := Page(ranked, nil, 3)
+	if len(page) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(page))
+	}
+	count := 0
+	for _, r := range page {
+		if r.AuthorID == popular {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected the per-author cap to allow 2 posts, got %d", count)
+	}
+
+	// Paging after the last item serves every other item exactly once
+	served := map[uuid.UUID]int{}
+	for _, r := range page {
+		served[r.WorkoutID]++
+	}
+	for len(page) > 0 {
+		cursor := page[len(page)-1].Cursor()
+		page = Page(ranked, &cursor, 3)
+		for _, r := range page {
+			served[r.WorkoutID]++
+		}
+	}
+	for _, c := range candidates {
+		if served[c.WorkoutID] != 1 {
+			t.Errorf("workout %v served %d times", c.WorkoutID, served[c.WorkoutID])
+		}
+	}
+}
+
+func TestSpreadDefersCappedAuthors(t *testing.T) {
+	popular, a, b := uuid.New(), uuid.New(), uuid.New()
+	candidates := []Candidate{
+		candidate(popular, 1, 100),
+		candidate(popular, 2, 90),
+		candidate(popular, 3, 80),
+		candidate(a, 4, 10),
+		candidate(b, 5, 5),
+	}
+
+	spread := Spread(Rank(candidates, Affinity{}, Engagement{}), 1, 2)
+	want := []uuid.UUID{popular, a, popular, b, popular}
+	for i, r := range spread {
+		if r.AuthorID != want[i] {
+			t.Fatalf("position %d: expected author %v, got %v", i, want[i], r.AuthorID)
+		}
+		if i > 0 && spread[i-1].Score <= r.Score {
+			t.Errorf("position %d: expected scores to keep decreasing, got %v after %v", i, r.Score, spread[i-1].Score)
+		}
+	}
+}
+
+func TestExperimentAssignIsStable(t *testing.T) {
+	e := Experiment{Name: "test", Variants: []Scorer{Engagement{}, DefaultScorer}}
+	counts := map[string]int{}
+	for i := 0; i < 200; i++ {
+		viewer := uuid.New()
+		arm := e.Assign(viewer)
+		if e.Assign(viewer).Name() != arm.Name() {
+			t.Fatal("expected a viewer to stay in the same arm")
+		}
+		counts[arm.Name()]++
+	}
+	if counts["engagement"] == 0 || counts["decayed"] == 0 {
+		t.Errorf("expected viewers in both arms, got %v", counts)
+	}
+
+	if (Experiment{}).Assign(uuid.New()) != DefaultScorer {
+		t.Error("expected an empty experiment to fall back to DefaultScorer")
+	}
+}
+
+func TestNewAffinity(t *testing.T) {
+	followed, engaged, stranger := uuid.New(), uuid.New(), uuid.New()
+	a := NewAffinity(map[uuid.UUID]int{followed: 5, engaged: 100}, []uuid.UUID{followed}, nil)
+
+	if a.Authors[stranger] != 0 {
+		t.Errorf("expected no affinity for a stranger, got %v", a.Authors[stranger])
+	}
+	if got := a.Authors[followed]; got != 0.75 {
+		t.Errorf("expected followed author with 5 interactions at 0.75, got %v", got)
+	}
+	if got := a.Authors[engaged]; got <= 0.9 || got >= 1 {
+		t.Errorf("expected heavy interaction to approach but not reach 1, got %v", got)
+	}
+}
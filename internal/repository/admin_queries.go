@@ -0,0 +1,259 @@
+package repository
+
+const isSysAdminQuery = `
+  SELECT EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $1)
+`
+
+// insertAuditLogQuery doubles as the admin guard: a requester who is not a
+// sys admin inserts nothing, and the caller rolls the action back.
+const insertAuditLogQuery = `
+  INSERT INTO public.admin_audit_log (admin_id, action, target_type, target_id, details)
+  SELECT $1, $2, $3, $4, $5::jsonb
+  WHERE EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $1)
+`
+
+// Admin user queries share one SELECT list, completed by their own filters.
+const adminUserSelect = `
+  SELECT
+    p.id,
+    p.username,
+    p.display_name,
+    (
+      SELECT ui.provider_email FROM public.user_identities ui
+      WHERE ui.user_id = p.id
+      ORDER BY ui.last_sign_in_at DESC NULLS LAST
+      LIMIT 1
+    ) AS email,
+    p.avatar_url,
+    EXISTS (SELECT 1 FROM public.sys_admins sa WHERE sa.user_id = p.id) AS is_admin,
+    p.account_status,
+    p.suspended_until,
+    p.suspension_reason,
+    p.total_workouts,
+    p.followers_count,
+    (
+      SELECT count(*) FROM public.user_sessions s
+      WHERE s.user_id = p.id AND NOT s.is_revoked AND s.expires_at > now()
+    )::integer AS active_sessions,
+    (SELECT max(ui.last_sign_in_at) FROM public.user_identities ui WHERE ui.user_id = p.id) AS last_sign_in_at,
+    p.created_at
+  FROM public.profiles p
+`
+
+// searchUsersQuery matches $1, a LIKE pattern, against usernames, display
+// names and sign-in emails. A NULL pattern lists everyone, newest first.
+const searchUsersQuery = adminUserSelect + `
+  WHERE (
+    $1::text IS NULL
+    OR p.username ILIKE $1
+    OR p.display_name ILIKE $1
+    OR EXISTS (
+      SELECT 1 FROM public.user_identities ui
+      WHERE ui.user_id = p.id AND ui.provider_email ILIKE $1
+    )
+  )
+    -- Keyset Cursor: resume after the last user of the previous page (newest first)
+    AND ($2::timestamptz IS NULL OR (p.created_at, p.id) < ($2::timestamptz, $3::uuid))
+  ORDER BY p.created_at DESC, p.id DESC
+  LIMIT $4
+`
+
+const getAdminUserByIDQuery = adminUserSelect + `
+  WHERE p.id = $1
+`
+
+// suspendUserQuery leaves banned accounts alone; a ban outranks a suspension.
+const suspendUserQuery = `
+  UPDATE public.profiles
+  SET account_status = 'suspended', suspended_until = $2, suspension_reason = $3
+  WHERE id = $1 AND account_status <> 'banned'
+`
+
+const unsuspendUserQuery = `
+  UPDATE public.profiles
+  SET account_status = 'active', suspended_until = NULL, suspension_reason = NULL
+  WHERE id = $1 AND account_status <> 'banned'
+`
+
+const banUserQuery = `
+  UPDATE public.profiles
+  SET account_status = 'banned', suspended_until = NULL, suspension_reason = $2
+  WHERE id = $1
+`
+
+const unbanUserQuery = `
+  UPDATE public.profiles
+  SET account_status = 'active', suspension_reason = NULL
+  WHERE id = $1 AND account_status = 'banned'
+`
+
+// restoreExpiredSuspensionsQuery reactivates accounts whose suspension has
+// run out. Reads already treat them as active; this clears the record.
+const restoreExpiredSuspensionsQuery = `
+  UPDATE public.profiles
+  SET account_status = 'active', suspended_until = NULL, suspension_reason = NULL
+  WHERE account_status = 'suspended' AND suspended_until <= now()
+`
+
+const profileExistsQuery = `
+  SELECT EXISTS (SELECT 1 FROM public.profiles WHERE id = $1)
+`
+
+// revokeAllUserSessionsQuery has no viewer guard; the audit insert guards it.
+const revokeAllUserSessionsQuery = `
+  UPDATE public.user_sessions
+  SET is_revoked = true
+  WHERE user_id = $1 AND is_revoked = false
+`
+
+// Takedown queries return what they removed so the audit log keeps a copy.
+
+const softRemoveCommentQuery = `
+  WITH old AS (
+    SELECT id, user_id, workout_id, content
+    FROM public.comments
+    WHERE id = $1 AND deleted_at IS NULL
+    FOR UPDATE
+  )
+  UPDATE public.comments c
+  SET content = '[deleted]', deleted_at = now()
+  FROM old
+  WHERE c.id = old.id
+  RETURNING old.user_id, old.workout_id, old.content
+`
+
+const removeCommentQuery = `
+  DELETE FROM public.comments
+  WHERE id = $1
+  RETURNING user_id, workout_id, content, parent_id
+`
+
+const removeWorkoutQuery = `
+  DELETE FROM public.workouts
+  WHERE id = $1
+  RETURNING user_id, name
+`
+
+const removeWorkoutImageQuery = `
+  DELETE FROM public.workout_images wi
+  USING public.workouts w
+  WHERE wi.id = $1 AND w.id = wi.workout_id
+  RETURNING wi.workout_id, w.user_id, wi.storage_path
+`
+
+const getSystemExercisesQuery = `
+  SELECT id, user_id, name, suggested_rest_seconds, icon, created_at, updated_at
+  FROM public.exercises
+  WHERE user_id IS NULL
+  ORDER BY name ASC
+`
+
+const insertSystemExerciseQuery = `
+  INSERT INTO public.exercises (user_id, name, suggested_rest_seconds, icon)
+  VALUES (NULL, $1, $2, $3)
+  RETURNING id, user_id, name, suggested_rest_seconds, icon, created_at, updated_at
+`
+
+const deleteSystemExerciseQuery = `
+  DELETE FROM public.exercises
+  WHERE id = $1 AND user_id IS NULL
+  RETURNING name
+`
+
+// Keyset pagination runs newest first; the filters are optional.
+const getAuditLogQuery = `
+  SELECT id, admin_id, action, target_type, target_id, details, created_at
+  FROM public.admin_audit_log
+  WHERE ($1::uuid IS NULL OR admin_id = $1)
+    AND ($2::text IS NULL OR target_type = $2)
+    AND ($3::uuid IS NULL OR target_id = $3)
+    -- Keyset Cursor: resume after the last entry of the previous page (newest first)
+    AND ($4::timestamptz IS NULL OR (created_at, id) < ($4::timestamptz, $5::uuid))
+  ORDER BY created_at DESC, id DESC
+  LIMIT $6
+`
+
+// Moderation queries set or clear hidden_at and return the content's author.
+// Hiding is idempotent and keeps the time the content was first hidden.
+const hideCommentQuery = `
+  UPDATE public.comments
+  SET hidden_at = COALESCE(hidden_at, now())
+  WHERE id = $1 AND deleted_at IS NULL
+  RETURNING user_id
+`
+
+const unhideCommentQuery = `
+  UPDATE public.comments
+  SET hidden_at = NULL
+  WHERE id = $1 AND hidden_at IS NOT NULL
+  RETURNING user_id
+`
+
+const hideWorkoutQuery = `
+  UPDATE public.workouts
+  SET hidden_at = COALESCE(hidden_at, now())
+  WHERE id = $1
+  RETURNING user_id
+`
+
+const unhideWorkoutQuery = `
+  UPDATE public.workouts
+  SET hidden_at = NULL
+  WHERE id = $1 AND hidden_at IS NOT NULL
+  RETURNING user_id
+`
+
+const hideWorkoutImageQuery = `
+  UPDATE public.workout_images wi
+  SET hidden_at = COALESCE(wi.hidden_at, now())
+  FROM public.workouts w
+  WHERE wi.id = $1 AND w.id = wi.workout_id
+  RETURNING w.user_id
+`
+
+const unhideWorkoutImageQuery = `
+  UPDATE public.workout_images wi
+  SET hidden_at = NULL
+  FROM public.workouts w
+  WHERE wi.id = $1 AND w.id = wi.workout_id AND wi.hidden_at IS NOT NULL
+  RETURNING w.user_id
+`
+
+// getReportsQuery is the moderation queue, newest first. Each report carries
+// how many reports are open on its target, so repeat offenders stand out.
+const getReportsQuery = `
+  SELECT
+    r.id, r.reporter_id, r.target_type, r.target_id, r.target_user_id, r.reason, r.note,
+    r.status, r.resolution, r.resolved_by, r.resolved_at, r.created_at,
+    p.username,
+    (
+      SELECT count(*) FROM public.reports o
+      WHERE o.target_type = r.target_type
+        AND o.target_id = r.target_id
+        AND o.status = 'open'
+    ) AS open_reports
+  FROM public.reports r
+  JOIN public.profiles p ON p.id = r.reporter_id
+  WHERE ($1::text IS NULL OR r.status = $1)
+    AND ($2::text IS NULL OR r.target_type = $2)
+    -- Keyset Cursor: resume after the last report of the previous page (newest first)
+    AND ($3::timestamptz IS NULL OR (r.created_at, r.id) < ($3::timestamptz, $4::uuid))
+  ORDER BY r.created_at DESC, r.id DESC
+  LIMIT $5
+`
+
+const lockReportQuery = `
+  SELECT target_type, target_id, target_user_id, reason, status
+  FROM public.reports
+  WHERE id = $1
+  FOR UPDATE
+`
+
+// resolveReportsQuery closes every open report on a target at once.
+const resolveReportsQuery = `
+  UPDATE public.reports
+  SET status = $3, resolution = $4, resolved_by = $5, resolved_at = now()
+  WHERE target_type = $1
+    AND target_id = $2
+    AND status = 'open'
+`
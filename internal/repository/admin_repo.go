@@ -0,0 +1,933 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+// AdminRepository backs the admin API. Every write runs in one transaction
+// with its audit log entry, so an action is either committed and logged or
+// not taken at all.
+type AdminRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewAdminRepository(db *pgxpool.Pool) *AdminRepository {
+	return &AdminRepository{
+		DB: db,
+	}
+}
+
+// audit records an admin action inside the action's transaction. It returns
+// ErrUnauthorizedAction when adminID is not a sys admin, which must roll the
+// action back.
+func audit(
+	ctx context.Context,
+	tx pgx.Tx,
+	adminID uuid.UUID,
+	action string,
+	targetType string,
+	targetID uuid.UUID,
+	details map[string]any,
+) error {
+	if details == nil {
+		details = map[string]any{}
+	}
+	b, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit details: %w", err)
+	}
+
+	commandTag, err := tx.Exec(ctx, insertAuditLogQuery, adminID, action, targetType, targetID, string(b))
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrUnauthorizedAction
+	}
+	return nil
+}
+
+func (r *AdminRepository) IsSysAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var isAdmin bool
+	if err := r.DB.QueryRow(ctx, isSysAdminQuery, userID).Scan(&isAdmin); err != nil {
+		return false, fmt.Errorf("failed to check admin: %w", err)
+	}
+	return isAdmin, nil
+}
+
+// SearchUsers lists accounts whose username, display name or email contains
+// query, newest first. An empty query lists every account.
+func (r *AdminRepository) SearchUsers(
+	ctx context.Context,
+	query string,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.AdminUser, error) {
+	var pattern *string
+	if query = strings.TrimSpace(query); query != "" {
+		p := "%" + escapeLike(query) + "%"
+		pattern = &p
+	}
+
+	rows, err := r.DB.Query(ctx, searchUsersQuery, pattern, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.AdminUser
+	for rows.Next() {
+		u, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *AdminRepository) GetUser(ctx context.Context, id uuid.UUID) (*models.AdminUser, error) {
+	u, err := scanAdminUser(r.DB.QueryRow(ctx, getAdminUserByIDQuery, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func scanAdminUser(row pgx.Row) (*models.AdminUser, error) {
+	var u models.AdminUser
+	err := row.Scan(
+		&u.ID,
+		&u.Username,
+		&u.DisplayName,
+		&u.Email,
+		&u.AvatarURL,
+		&u.IsAdmin,
+		&u.AccountStatus,
+		&u.SuspendedUntil,
+		&u.SuspensionReason,
+		&u.TotalWorkouts,
+		&u.FollowersCount,
+		&u.ActiveSessions,
+		&u.LastSignInAt,
+		&u.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+	return &u, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// SuspendUser blocks sign-in for userID until the given time and signs the
+// user out everywhere.
+func (r *AdminRepository) SuspendUser(
+	ctx context.Context,
+	adminID uuid.UUID,
+	userID uuid.UUID,
+	until time.Time,
+	reason *string,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := suspendUser(ctx, tx, adminID, userID, until, reason); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// suspendUser suspends userID, signs them out and audits it inside tx.
+func suspendUser(
+	ctx context.Context,
+	tx pgx.Tx,
+	adminID uuid.UUID,
+	userID uuid.UUID,
+	until time.Time,
+	reason *string,
+) error {
+	commandTag, err := tx.Exec(ctx, suspendUserQuery, userID, until, reason)
+	if err != nil {
+		return fmt.Errorf("failed to suspend user: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return missingProfileOr(ctx, tx, userID, ErrAccountBanned)
+	}
+
+	commandTag, err = tx.Exec(ctx, revokeAllUserSessionsQuery, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return audit(ctx, tx, adminID, models.AuditSuspendUser, models.AuditTargetUser, userID, map[string]any{
+		"until":            until,
+		"reason":           reason,
+		"sessions_revoked": commandTag.RowsAffected(),
+	})
+}
+
+func (r *AdminRepository) UnsuspendUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, unsuspendUserQuery, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return missingProfileOr(ctx, tx, userID, ErrAccountBanned)
+	}
+
+	if err := audit(ctx, tx, adminID, models.AuditUnsuspendUser, models.AuditTargetUser, userID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// BanUser disables userID until an admin lifts the ban and signs the user
+// out everywhere. A ban replaces any suspension.
+func (r *AdminRepository) BanUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID, reason *string) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, banUserQuery, userID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to ban user: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return ErrProfileNotFound
+	}
+
+	commandTag, err = tx.Exec(ctx, revokeAllUserSessionsQuery, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	if err := audit(ctx, tx, adminID, models.AuditBanUser, models.AuditTargetUser, userID, map[string]any{
+		"reason":           reason,
+		"sessions_revoked": commandTag.RowsAffected(),
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UnbanUser restores a banned account, which brings its content back.
+func (r *AdminRepository) UnbanUser(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, unbanUserQuery, userID)
+	if err != nil {
+		return fmt.Errorf("failed to unban user: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return missingProfileOr(ctx, tx, userID, ErrAccountNotBanned)
+	}
+
+	if err := audit(ctx, tx, adminID, models.AuditUnbanUser, models.AuditTargetUser, userID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// missingProfileOr explains why an account update matched no row: the
+// profile does not exist, or its status ruled the update out (err).
+func missingProfileOr(ctx context.Context, tx pgx.Tx, userID uuid.UUID, err error) error {
+	var exists bool
+	if scanErr := tx.QueryRow(ctx, profileExistsQuery, userID).Scan(&exists); scanErr != nil {
+		return fmt.Errorf("failed to check profile: %w", scanErr)
+	}
+	if !exists {
+		return ErrProfileNotFound
+	}
+	return err
+}
+
+// RestoreExpiredSuspensions marks accounts whose suspension has ended as
+// active again. It runs as a background job and is not audited.
+func (r *AdminRepository) RestoreExpiredSuspensions(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, restoreExpiredSuspensionsQuery); err != nil {
+		return fmt.Errorf("failed to restore expired suspensions: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserSessions signs userID out of every device and returns how many
+// sessions were revoked.
+func (r *AdminRepository) RevokeUserSessions(ctx context.Context, adminID uuid.UUID, userID uuid.UUID) (int64, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	if err := tx.QueryRow(ctx, profileExistsQuery, userID).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("failed to check profile: %w", err)
+	}
+	if !exists {
+		return 0, ErrProfileNotFound
+	}
+
+	commandTag, err := tx.Exec(ctx, revokeAllUserSessionsQuery, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	revoked := commandTag.RowsAffected()
+
+	err = audit(ctx, tx, adminID, models.AuditRevokeSessions, models.AuditTargetUser, userID, map[string]any{
+		"sessions_revoked": revoked,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return revoked, nil
+}
+
+// RemoveComment takes down any user's comment the way its author would
+// delete it: a comment with replies becomes a "[deleted]" tombstone, any
+// other comment is removed along with a tombstone parent it leaves orphaned.
+func (r *AdminRepository) RemoveComment(
+	ctx context.Context,
+	adminID uuid.UUID,
+	id uuid.UUID,
+	reason *string,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockComment(ctx, tx, id); err != nil {
+		return err
+	}
+
+	var hasReplies bool
+	if err := tx.QueryRow(ctx, hasRepliesQuery, id).Scan(&hasReplies); err != nil {
+		return fmt.Errorf("failed to check replies: %w", err)
+	}
+
+	var authorID, workoutID uuid.UUID
+	var content string
+	if hasReplies {
+		err = tx.QueryRow(ctx, softRemoveCommentQuery, id).Scan(&authorID, &workoutID, &content)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to remove comment: %w", err)
+		}
+		if _, err := tx.Exec(ctx, deleteCommentRevisionsQuery, id); err != nil {
+			return fmt.Errorf("failed to delete comment revisions: %w", err)
+		}
+		if err := syncCommentEntities(ctx, tx, id, authorID, nil); err != nil {
+			return err
+		}
+	} else {
+		var parentID *uuid.UUID
+		err = tx.QueryRow(ctx, removeCommentQuery, id).Scan(&authorID, &workoutID, &content, &parentID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to remove comment: %w", err)
+		}
+		if parentID != nil {
+			if _, err := tx.Exec(ctx, deleteOrphanedTombstoneQuery, *parentID); err != nil {
+				return fmt.Errorf("failed to clean up deleted parent: %w", err)
+			}
+		}
+	}
+
+	err = audit(ctx, tx, adminID, models.AuditRemoveComment, models.AuditTargetComment, id, map[string]any{
+		"author_id":  authorID,
+		"workout_id": workoutID,
+		"content":    content,
+		"reason":     reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RemoveWorkout deletes any user's workout and recomputes the owner's streak.
+func (r *AdminRepository) RemoveWorkout(
+	ctx context.Context,
+	adminID uuid.UUID,
+	id uuid.UUID,
+	reason *string,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exerciseIDs, err := getWorkoutExerciseIDs(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	var ownerID uuid.UUID
+	var name *string
+	err = tx.QueryRow(ctx, removeWorkoutQuery, id).Scan(&ownerID, &name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkoutNotFound
+		}
+		return fmt.Errorf("failed to remove workout: %w", err)
+	}
+
+	if err := recomputeStreak(ctx, tx, ownerID); err != nil {
+		return err
+	}
+
+	// Sessions after the deleted one may now hold the records it held
+	for _, exerciseID := range exerciseIDs {
+		if err := recomputeExercisePersonalRecords(ctx, tx, ownerID, exerciseID); err != nil {
+			return err
+		}
+	}
+
+	err = audit(ctx, tx, adminID, models.AuditRemoveWorkout, models.AuditTargetWorkout, id, map[string]any{
+		"owner_id": ownerID,
+		"name":     name,
+		"reason":   reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RemoveWorkoutImage deletes any workout's image. Its files are queued for
+// deletion by the same trigger that handles owner deletes.
+func (r *AdminRepository) RemoveWorkoutImage(
+	ctx context.Context,
+	adminID uuid.UUID,
+	id uuid.UUID,
+	reason *string,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var workoutID, ownerID uuid.UUID
+	var storagePath string
+	err = tx.QueryRow(ctx, removeWorkoutImageQuery, id).Scan(&workoutID, &ownerID, &storagePath)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkoutImageNotFound
+		}
+		return fmt.Errorf("failed to remove workout image: %w", err)
+	}
+
+	err = audit(ctx, tx, adminID, models.AuditRemoveWorkoutImage, models.AuditTargetWorkoutImage, id, map[string]any{
+		"workout_id":   workoutID,
+		"owner_id":     ownerID,
+		"storage_path": storagePath,
+		"reason":       reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetSystemExercises lists the global exercise library by name.
+func (r *AdminRepository) GetSystemExercises(ctx context.Context) ([]*models.Exercise, error) {
+	rows, err := r.DB.Query(ctx, getSystemExercisesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system exercises: %w", err)
+	}
+	defer rows.Close()
+
+	var exercises []*models.Exercise
+	for rows.Next() {
+		var e models.Exercise
+		err := rows.Scan(
+			&e.ID,
+			&e.UserID,
+			&e.Name,
+			&e.SuggestedRestSeconds,
+			&e.Icon,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exercise: %w", err)
+		}
+		exercises = append(exercises, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate exercises: %w", err)
+	}
+
+	return exercises, nil
+}
+
+func (r *AdminRepository) CreateSystemExercise(
+	ctx context.Context,
+	adminID uuid.UUID,
+	req models.CreateSystemExerciseRequest,
+) (*models.Exercise, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var e models.Exercise
+	err = tx.QueryRow(ctx, insertSystemExerciseQuery, req.Name, req.SuggestedRestSeconds, req.Icon).Scan(
+		&e.ID,
+		&e.UserID,
+		&e.Name,
+		&e.SuggestedRestSeconds,
+		&e.Icon,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exercise: %w", err)
+	}
+
+	err = audit(ctx, tx, adminID, models.AuditCreateExercise, models.AuditTargetExercise, e.ID, map[string]any{
+		"name":                   e.Name,
+		"suggested_rest_seconds": e.SuggestedRestSeconds,
+		"icon":                   e.Icon,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &e, nil
+}
+
+// UpdateSystemExercise applies a partial update to a library exercise. As
+// with user exercises, an empty icon or a zero rest time clears the field.
+func (r *AdminRepository) UpdateSystemExercise(
+	ctx context.Context,
+	adminID uuid.UUID,
+	id uuid.UUID,
+	updates models.UpdateExerciseRequest,
+) (*models.Exercise, error) {
+	var sets []string
+	var args []interface{}
+	i := 1
+
+	if updates.Name != nil {
+		sets = append(sets, fmt.Sprintf("name = $%d", i))
+		args = append(args, *updates.Name)
+		i++
+	}
+	if updates.SuggestedRestSeconds != nil {
+		sets = append(sets, fmt.Sprintf("suggested_rest_seconds = $%d", i))
+		if *updates.SuggestedRestSeconds == 0 {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.SuggestedRestSeconds)
+		}
+		i++
+	}
+	if updates.Icon != nil {
+		sets = append(sets, fmt.Sprintf("icon = $%d", i))
+		if *updates.Icon == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.Icon)
+		}
+		i++
+	}
+
+	// An empty update still returns the exercise, so it must exist
+	if len(sets) == 0 {
+		sets = append(sets, "name = name")
+	}
+
+	query := fmt.Sprintf(`
+    UPDATE public.exercises
+    SET %s
+    WHERE id = $%d AND user_id IS NULL
+    RETURNING id, user_id, name, suggested_rest_seconds, icon, created_at, updated_at`,
+		strings.Join(sets, ", "),
+		i, // The Exercise ID ($%d)
+	)
+	args = append(args, id)
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var e models.Exercise
+	err = tx.QueryRow(ctx, query, args...).Scan(
+		&e.ID,
+		&e.UserID,
+		&e.Name,
+		&e.SuggestedRestSeconds,
+		&e.Icon,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseNotFound
+		}
+		return nil, fmt.Errorf("failed to update exercise: %w", err)
+	}
+
+	err = audit(ctx, tx, adminID, models.AuditUpdateExercise, models.AuditTargetExercise, id, map[string]any{
+		"changes": updates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &e, nil
+}
+
+// DeleteSystemExercise removes a library exercise. One that is still logged
+// in a workout is refused with ErrReferenceViolation.
+func (r *AdminRepository) DeleteSystemExercise(ctx context.Context, adminID uuid.UUID, id uuid.UUID) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var name string
+	err = tx.QueryRow(ctx, deleteSystemExerciseQuery, id).Scan(&name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrExerciseNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // Foreign Key Violation
+			return ErrReferenceViolation
+		}
+		return fmt.Errorf("failed to delete exercise: %w", err)
+	}
+
+	err = audit(ctx, tx, adminID, models.AuditDeleteExercise, models.AuditTargetExercise, id, map[string]any{
+		"name": name,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetAuditLog lists audit entries matching filter, newest first.
+func (r *AdminRepository) GetAuditLog(
+	ctx context.Context,
+	filter models.AuditLogFilter,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.AuditLogEntry, error) {
+	rows, err := r.DB.Query(ctx, getAuditLogQuery,
+		filter.AdminID,
+		filter.TargetType,
+		filter.TargetID,
+		cursor.TimeKey(),
+		cursor.IDKey(),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditLogEntry
+	for rows.Next() {
+		var e models.AuditLogEntry
+		err := rows.Scan(
+			&e.ID,
+			&e.AdminID,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&e.Details,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// moderation holds what hiding and restoring one kind of content takes.
+type moderation struct {
+	hideQuery    string
+	unhideQuery  string
+	hideAction   string
+	unhideAction string
+	auditTarget  string
+	notFound     error
+}
+
+// moderatedContent maps the report target types moderators can hide.
+var moderatedContent = map[string]moderation{
+	models.ReportTargetComment: {
+		hideQuery:    hideCommentQuery,
+		unhideQuery:  unhideCommentQuery,
+		hideAction:   models.AuditHideComment,
+		unhideAction: models.AuditUnhideComment,
+		auditTarget:  models.AuditTargetComment,
+		notFound:     ErrCommentNotFound,
+	},
+	models.ReportTargetWorkout: {
+		hideQuery:    hideWorkoutQuery,
+		unhideQuery:  unhideWorkoutQuery,
+		hideAction:   models.AuditHideWorkout,
+		unhideAction: models.AuditUnhideWorkout,
+		auditTarget:  models.AuditTargetWorkout,
+		notFound:     ErrWorkoutNotFound,
+	},
+	models.ReportTargetWorkoutImage: {
+		hideQuery:    hideWorkoutImageQuery,
+		unhideQuery:  unhideWorkoutImageQuery,
+		hideAction:   models.AuditHideWorkoutImage,
+		unhideAction: models.AuditUnhideWorkoutImage,
+		auditTarget:  models.AuditTargetWorkoutImage,
+		notFound:     ErrWorkoutImageNotFound,
+	},
+}
+
+// GetReports lists the moderation queue matching filter, newest first.
+func (r *AdminRepository) GetReports(
+	ctx context.Context,
+	filter models.ReportFilter,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.QueuedReport, error) {
+	rows, err := r.DB.Query(ctx, getReportsQuery,
+		filter.Status,
+		filter.TargetType,
+		cursor.TimeKey(),
+		cursor.IDKey(),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.QueuedReport
+	for rows.Next() {
+		var q models.QueuedReport
+		err := rows.Scan(
+			&q.ID,
+			&q.ReporterID,
+			&q.TargetType,
+			&q.TargetID,
+			&q.TargetUserID,
+			&q.Reason,
+			&q.Note,
+			&q.Status,
+			&q.Resolution,
+			&q.ResolvedBy,
+			&q.ResolvedAt,
+			&q.CreatedAt,
+			&q.ReporterUsername,
+			&q.OpenReports,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report: %w", err)
+		}
+		reports = append(reports, &q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// ResolveReport takes req.Action on a report's target and closes every open
+// report on that target. It returns how many reports were closed.
+func (r *AdminRepository) ResolveReport(
+	ctx context.Context,
+	adminID uuid.UUID,
+	reportID uuid.UUID,
+	req models.ResolveReportRequest,
+) (int64, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var targetType, reason, status string
+	var targetID, targetUserID uuid.UUID
+	err = tx.QueryRow(ctx, lockReportQuery, reportID).Scan(&targetType, &targetID, &targetUserID, &reason, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrReportNotFound
+		}
+		return 0, fmt.Errorf("failed to get report: %w", err)
+	}
+	if status != models.ReportStatusOpen {
+		return 0, ErrReportResolved
+	}
+
+	newStatus := models.ReportStatusActioned
+	switch req.Action {
+	case models.ReportActionHideContent:
+		m, ok := moderatedContent[targetType]
+		if !ok {
+			return 0, ErrInvalidReportAction
+		}
+		var ownerID uuid.UUID
+		if err := tx.QueryRow(ctx, m.hideQuery, targetID).Scan(&ownerID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, m.notFound
+			}
+			return 0, fmt.Errorf("failed to hide content: %w", err)
+		}
+		err = audit(ctx, tx, adminID, m.hideAction, m.auditTarget, targetID, map[string]any{
+			"owner_id":  ownerID,
+			"report_id": reportID,
+			"reason":    reason,
+		})
+		if err != nil {
+			return 0, err
+		}
+	case models.ReportActionSuspendUser:
+		if req.SuspendUntil == nil {
+			return 0, ErrInvalidReportAction
+		}
+		if err := suspendUser(ctx, tx, adminID, targetUserID, *req.SuspendUntil, &reason); err != nil {
+			return 0, err
+		}
+	case models.ReportActionDismiss:
+		newStatus = models.ReportStatusDismissed
+	default:
+		return 0, ErrInvalidReportAction
+	}
+
+	commandTag, err := tx.Exec(ctx, resolveReportsQuery, targetType, targetID, newStatus, req.Action, adminID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+	resolved := commandTag.RowsAffected()
+
+	err = audit(ctx, tx, adminID, models.AuditResolveReport, models.AuditTargetReport, reportID, map[string]any{
+		"action":           req.Action,
+		"target_type":      targetType,
+		"target_id":        targetID,
+		"reports_resolved": resolved,
+		"note":             req.Note,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return resolved, nil
+}
+
+// UnhideContent restores content a moderator hid.
+func (r *AdminRepository) UnhideContent(
+	ctx context.Context,
+	adminID uuid.UUID,
+	targetType string,
+	id uuid.UUID,
+) error {
+	m, ok := moderatedContent[targetType]
+	if !ok {
+		return ErrInvalidReportAction
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var ownerID uuid.UUID
+	if err := tx.QueryRow(ctx, m.unhideQuery, id).Scan(&ownerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.notFound
+		}
+		return fmt.Errorf("failed to unhide content: %w", err)
+	}
+
+	err = audit(ctx, tx, adminID, m.unhideAction, m.auditTarget, id, map[string]any{
+		"owner_id": ownerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,641 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+// auditActions returns the actions logged against targetID, oldest first.
+func auditActions(t *testing.T, db *pgxpool.Pool, targetID uuid.UUID) []string {
+	t.Helper()
+	rows, err := db.Query(context.Background(),
+		"SELECT action FROM admin_audit_log WHERE target_id = $1 ORDER BY created_at, id", targetID)
+	if err != nil {
+		t.Fatalf("Failed to query audit log: %v", err)
+	}
+	defer rows.Close()
+
+	var actions []string
+	for rows.Next() {
+		var a string
+		rows.Scan(&a)
+		actions = append(actions, a)
+	}
+	return actions
+}
+
+func TestIsSysAdmin(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	if ok, err := repo.IsSysAdmin(ctx, adminID); err != nil || !ok {
+		t.Errorf("Expected admin to be a sys admin, got %v, %v", ok, err)
+	}
+	if ok, err := repo.IsSysAdmin(ctx, userID); err != nil || ok {
+		t.Errorf("Expected user not to be a sys admin, got %v, %v", ok, err)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	testutil.InsertProfile(ctx, db, "lifter_one")
+	testutil.InsertProfile(ctx, db, "lifterXone")
+	testutil.InsertProfile(ctx, db, "runner")
+
+	users, err := repo.SearchUsers(ctx, "LIFTER", nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to search users: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("Expected a case-insensitive match on 2 users, got %d", len(users))
+	}
+
+	// Wildcards in the query match literally
+	users, _ = repo.SearchUsers(ctx, "r_o", nil, 10)
+	if len(users) != 1 || *users[0].Username != "lifter_one" {
+		t.Errorf("Expected only lifter_one, got %v", users)
+	}
+
+	users, _ = repo.SearchUsers(ctx, "", nil, 10)
+	if len(users) != 3 {
+		t.Errorf("Expected an empty query to list everyone, got %d", len(users))
+	}
+}
+
+func TestSuspendUser(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	sessionRepo := NewUserSessionRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+	sessionRepo.CreateSession(ctx, userID, "refresh-token", nil, nil, time.Now().Add(time.Hour))
+
+	until := time.Now().Add(24 * time.Hour)
+	reason := "spam"
+	if err := repo.SuspendUser(ctx, adminID, userID, until, &reason); err != nil {
+		t.Fatalf("Failed to suspend user: %v", err)
+	}
+
+	user, err := repo.GetUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if user.AccountStatus != models.AccountStatusSuspended || user.SuspendedUntil == nil || !user.SuspendedUntil.Equal(until.Truncate(time.Microsecond)) {
+		t.Errorf("Expected suspended until %v, got %v", until, user.SuspendedUntil)
+	}
+	if user.ActiveSessions != 0 {
+		t.Errorf("Expected the user's sessions to be revoked, got %d active", user.ActiveSessions)
+	}
+
+	if err := repo.UnsuspendUser(ctx, adminID, userID); err != nil {
+		t.Fatalf("Failed to unsuspend user: %v", err)
+	}
+	user, _ = repo.GetUser(ctx, userID)
+	if user.AccountStatus != models.AccountStatusActive || user.SuspendedUntil != nil || user.SuspensionReason != nil {
+		t.Errorf("Expected the suspension to be lifted, got %v", user.SuspendedUntil)
+	}
+
+	got := auditActions(t, db, userID)
+	if len(got) != 2 || got[0] != models.AuditSuspendUser || got[1] != models.AuditUnsuspendUser {
+		t.Errorf("Expected suspend and unsuspend to be audited, got %v", got)
+	}
+}
+
+func TestSuspendUser_NotAdmin(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	callerID, _, _ := testutil.InsertProfile(ctx, db, "caller")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+
+	err := repo.SuspendUser(ctx, callerID, userID, time.Now().Add(time.Hour), nil)
+	if !errors.Is(err, ErrUnauthorizedAction) {
+		t.Fatalf("Expected ErrUnauthorizedAction, got %v", err)
+	}
+
+	// The suspension was rolled back with the missing audit entry
+	user, _ := repo.GetUser(ctx, userID)
+	if user.SuspendedUntil != nil {
+		t.Error("Expected no suspension without an audit entry")
+	}
+}
+
+func TestSuspendUser_NotFound(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	err := repo.SuspendUser(ctx, adminID, uuid.New(), time.Now().Add(time.Hour), nil)
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestBanUser(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	userRepo := NewUserRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	likeRepo := NewWorkoutLikeRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	viewerID, _, _ := testutil.InsertProfile(ctx, db, "viewer")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+	viewerWorkout, _ := workoutRepo.Create(ctx, viewerID, nil, nil, time.Now(), time.Now(), 0)
+	comment, _ := commentRepo.CreateComment(ctx, userID, viewerWorkout.ID, nil, "abuse")
+	likeRepo.LikeWorkout(ctx, userID, viewerWorkout.ID)
+
+	reason := "spam ring"
+	if err := repo.BanUser(ctx, adminID, userID, &reason); err != nil {
+		t.Fatalf("Failed to ban user: %v", err)
+	}
+
+	user, _ := repo.GetUser(ctx, userID)
+	if user.AccountStatus != models.AccountStatusBanned || user.SuspendedUntil != nil {
+		t.Errorf("Expected the user to be banned, got %+v", user)
+	}
+	if active, err := userRepo.IsAccountActive(ctx, userID); err != nil || active {
+		t.Errorf("Expected a banned account to be inactive, got %v, %v", active, err)
+	}
+
+	// The banned user's content is gone for everyone else
+	if _, err := workoutRepo.GetWorkoutByID(ctx, workout.ID, viewerID); !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected the banned user's workout to be hidden, got %v", err)
+	}
+	if comments, _ := commentRepo.GetCommentsByWorkoutID(ctx, viewerWorkout.ID, viewerID, nil, 10); len(comments) != 0 {
+		t.Errorf("Expected the banned user's comments to be hidden, got %d", len(comments))
+	}
+	if _, err := commentRepo.GetCommentByUserID(ctx, comment.ID, viewerID); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("Expected the banned user's comment to be hidden by id, got %v", err)
+	}
+	if likes, _ := likeRepo.GetWorkoutLikesByWorkoutID(ctx, viewerWorkout.ID, viewerID, nil, 10); len(likes) != 0 {
+		t.Errorf("Expected the banned user's likes to be hidden, got %d", len(likes))
+	}
+
+	// A ban outranks a suspension
+	err := repo.SuspendUser(ctx, adminID, userID, time.Now().Add(time.Hour), nil)
+	if !errors.Is(err, ErrAccountBanned) {
+		t.Errorf("Expected ErrAccountBanned, got %v", err)
+	}
+
+	if err := repo.UnbanUser(ctx, adminID, userID); err != nil {
+		t.Fatalf("Failed to unban user: %v", err)
+	}
+	if _, err := workoutRepo.GetWorkoutByID(ctx, workout.ID, viewerID); err != nil {
+		t.Errorf("Expected the workout to be visible again, got %v", err)
+	}
+	if comments, _ := commentRepo.GetCommentsByWorkoutID(ctx, viewerWorkout.ID, viewerID, nil, 10); len(comments) != 1 {
+		t.Errorf("Expected the comment to be visible again, got %d", len(comments))
+	}
+	if _, err := commentRepo.GetCommentByUserID(ctx, comment.ID, viewerID); err != nil {
+		t.Errorf("Expected the comment to be visible again by id, got %v", err)
+	}
+	if err := repo.UnbanUser(ctx, adminID, userID); !errors.Is(err, ErrAccountNotBanned) {
+		t.Errorf("Expected ErrAccountNotBanned, got %v", err)
+	}
+
+	got := auditActions(t, db, userID)
+	if len(got) != 2 || got[0] != models.AuditBanUser || got[1] != models.AuditUnbanUser {
+		t.Errorf("Expected ban and unban to be audited, got %v", got)
+	}
+}
+
+func TestRestoreExpiredSuspensions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	expiredID, _, _ := testutil.InsertProfile(ctx, db, "expired")
+	currentID, _, _ := testutil.InsertProfile(ctx, db, "current")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	repo.SuspendUser(ctx, adminID, expiredID, time.Now().Add(time.Hour), nil)
+	repo.SuspendUser(ctx, adminID, currentID, time.Now().Add(time.Hour), nil)
+	db.Exec(ctx, "UPDATE public.profiles SET suspended_until = now() - interval '1 minute' WHERE id = $1", expiredID)
+
+	// An expired suspension no longer disables the account, even before the sweep
+	if active, _ := userRepo.IsAccountActive(ctx, expiredID); !active {
+		t.Error("Expected an expired suspension to count as active")
+	}
+
+	if err := repo.RestoreExpiredSuspensions(ctx); err != nil {
+		t.Fatalf("Failed to restore suspensions: %v", err)
+	}
+
+	user, _ := repo.GetUser(ctx, expiredID)
+	if user.AccountStatus != models.AccountStatusActive || user.SuspendedUntil != nil {
+		t.Errorf("Expected the expired suspension to be cleared, got %+v", user)
+	}
+	user, _ = repo.GetUser(ctx, currentID)
+	if user.AccountStatus != models.AccountStatusSuspended {
+		t.Errorf("Expected the current suspension to remain, got %s", user.AccountStatus)
+	}
+	if active, _ := userRepo.IsAccountActive(ctx, currentID); active {
+		t.Error("Expected a suspended account to be inactive")
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	sessionRepo := NewUserSessionRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+	sessionRepo.CreateSession(ctx, userID, "token-1", nil, nil, time.Now().Add(time.Hour))
+	sessionRepo.CreateSession(ctx, userID, "token-2", nil, nil, time.Now().Add(time.Hour))
+
+	revoked, err := repo.RevokeUserSessions(ctx, adminID, userID)
+	if err != nil {
+		t.Fatalf("Failed to revoke sessions: %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("Expected 2 sessions revoked, got %d", revoked)
+	}
+
+	if _, err := repo.RevokeUserSessions(ctx, adminID, uuid.New()); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+func TestRemoveComment(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	commentRepo := NewCommentRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+
+	parent, _ := commentRepo.CreateComment(ctx, userID, workout.ID, nil, "Parent")
+	reply, _ := commentRepo.CreateComment(ctx, userID, workout.ID, &parent.ID, "Reply")
+
+	// A comment with replies becomes a tombstone
+	reason := "harassment"
+	if err := repo.RemoveComment(ctx, adminID, parent.ID, &reason); err != nil {
+		t.Fatalf("Failed to remove comment: %v", err)
+	}
+	got, err := commentRepo.GetCommentByUserID(ctx, parent.ID, userID)
+	if err != nil || !got.IsDeleted || got.Content != "[deleted]" {
+		t.Errorf("Expected a tombstone, got %+v, %v", got, err)
+	}
+
+	// Removing the last reply also clears the tombstone
+	if err := repo.RemoveComment(ctx, adminID, reply.ID, nil); err != nil {
+		t.Fatalf("Failed to remove reply: %v", err)
+	}
+	if _, err := commentRepo.GetCommentByUserID(ctx, parent.ID, userID); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("Expected the tombstone to be cleaned up, got %v", err)
+	}
+
+	var content string
+	db.QueryRow(ctx, "SELECT details->>'content' FROM admin_audit_log WHERE target_id = $1", parent.ID).Scan(&content)
+	if content != "Parent" {
+		t.Errorf("Expected the removed content to be kept in the audit log, got %q", content)
+	}
+
+	if err := repo.RemoveComment(ctx, adminID, uuid.New(), nil); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("Expected ErrCommentNotFound, got %v", err)
+	}
+}
+
+func TestRemoveWorkout(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+
+	if err := repo.RemoveWorkout(ctx, adminID, workout.ID, nil); err != nil {
+		t.Fatalf("Failed to remove workout: %v", err)
+	}
+	if _, err := workoutRepo.GetWorkoutByID(ctx, workout.ID, userID); !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected the workout to be gone, got %v", err)
+	}
+	if got := auditActions(t, db, workout.ID); len(got) != 1 || got[0] != models.AuditRemoveWorkout {
+		t.Errorf("Expected the takedown to be audited, got %v", got)
+	}
+
+	if err := repo.RemoveWorkout(ctx, adminID, workout.ID, nil); !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected ErrWorkoutNotFound, got %v", err)
+	}
+}
+
+func TestSystemExerciseCRUD(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	rest := 90
+	exercise, err := repo.CreateSystemExercise(ctx, adminID, models.CreateSystemExerciseRequest{
+		Name:                 "Bench Press",
+		SuggestedRestSeconds: &rest,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create exercise: %v", err)
+	}
+	if exercise.UserID != nil {
+		t.Error("Expected a system exercise to have no owner")
+	}
+
+	name := "Flat Bench Press"
+	updated, err := repo.UpdateSystemExercise(ctx, adminID, exercise.ID, models.UpdateExerciseRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("Failed to update exercise: %v", err)
+	}
+	if updated.Name != name || updated.SuggestedRestSeconds == nil || *updated.SuggestedRestSeconds != rest {
+		t.Errorf("Expected only the name to change, got %+v", updated)
+	}
+
+	// A user's own exercise is not part of the library
+	exerciseRepo := NewExerciseRepository(db)
+	own, _ := exerciseRepo.CreateExercise(ctx, &userID, "Mine", nil, nil, userID)
+	if _, err := repo.UpdateSystemExercise(ctx, adminID, own.ID, models.UpdateExerciseRequest{Name: &name}); !errors.Is(err, ErrExerciseNotFound) {
+		t.Errorf("Expected ErrExerciseNotFound for a user exercise, got %v", err)
+	}
+
+	exercises, _ := repo.GetSystemExercises(ctx)
+	if len(exercises) != 1 {
+		t.Errorf("Expected 1 system exercise, got %d", len(exercises))
+	}
+
+	if err := repo.DeleteSystemExercise(ctx, adminID, exercise.ID); err != nil {
+		t.Fatalf("Failed to delete exercise: %v", err)
+	}
+
+	got := auditActions(t, db, exercise.ID)
+	want := []string{models.AuditCreateExercise, models.AuditUpdateExercise, models.AuditDeleteExercise}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userA, _, _ := testutil.InsertProfile(ctx, db, "user_a")
+	userB, _, _ := testutil.InsertProfile(ctx, db, "user_b")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	repo.RevokeUserSessions(ctx, adminID, userA)
+	repo.RevokeUserSessions(ctx, adminID, userB)
+	repo.SuspendUser(ctx, adminID, userB, time.Now().Add(time.Hour), nil)
+
+	all, err := repo.GetAuditLog(ctx, models.AuditLogFilter{}, nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to get audit log: %v", err)
+	}
+	if len(all) != 3 || all[0].Action != models.AuditSuspendUser {
+		t.Errorf("Expected 3 entries newest first, got %d", len(all))
+	}
+
+	entries, _ := repo.GetAuditLog(ctx, models.AuditLogFilter{TargetID: &userB}, nil, 10)
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries for user_b, got %d", len(entries))
+	}
+}
+
+// TestResolveReport_HideContent checks a hidden workout and comment drop out
+// of the read paths for everyone but their authors, and come back when
+// restored.
+func TestResolveReport_HideContent(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	reportRepo := NewReportRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	followRepo := NewFollowRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	reporterID, _, _ := testutil.InsertProfile(ctx, db, "reporter")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+	followRepo.Follow(ctx, reporterID, authorID)
+
+	workout, _ := workoutRepo.Create(ctx, authorID, nil, nil, time.Now(), time.Now(), 0)
+	comment, _ := commentRepo.CreateComment(ctx, otherID, workout.ID, nil, "abuse")
+
+	report, err := reportRepo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetWorkout,
+		TargetID:   workout.ID,
+		Reason:     "violence",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create report: %v", err)
+	}
+	reportRepo.CreateReport(ctx, otherID, models.CreateReportRequest{
+		TargetType: models.ReportTargetWorkout,
+		TargetID:   workout.ID,
+		Reason:     "violence",
+	})
+
+	resolved, err := repo.ResolveReport(ctx, adminID, report.ID, models.ResolveReportRequest{
+		Action: models.ReportActionHideContent,
+	})
+	if err != nil {
+		t.Fatalf("Failed to resolve report: %v", err)
+	}
+	if resolved != 2 {
+		t.Errorf("Expected both open reports on the workout to be resolved, got %d", resolved)
+	}
+
+	// Hidden from others, still shown to its author
+	if _, err := workoutRepo.GetWorkoutByID(ctx, workout.ID, reporterID); !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected the hidden workout to be gone for the reporter, got %v", err)
+	}
+	if ws, _ := workoutRepo.GetFollowingTimelineWorkouts(ctx, reporterID, models.TimelineIncludeAll, nil, 10); len(ws) != 0 {
+		t.Errorf("Expected the hidden workout to leave the home feed, got %d", len(ws))
+	}
+	if w, err := workoutRepo.GetWorkoutByID(ctx, workout.ID, authorID); err != nil || w == nil {
+		t.Errorf("Expected the author to still see the workout, got %v", err)
+	}
+
+	// Resolving twice is refused
+	_, err = repo.ResolveReport(ctx, adminID, report.ID, models.ResolveReportRequest{Action: models.ReportActionDismiss})
+	if !errors.Is(err, ErrReportResolved) {
+		t.Errorf("Expected ErrReportResolved, got %v", err)
+	}
+
+	// Restoring brings it back
+	if err := repo.UnhideContent(ctx, adminID, models.ReportTargetWorkout, workout.ID); err != nil {
+		t.Fatalf("Failed to unhide workout: %v", err)
+	}
+	if w, err := workoutRepo.GetWorkoutByID(ctx, workout.ID, reporterID); err != nil || w == nil {
+		t.Errorf("Expected the restored workout to be visible, got %v", err)
+	}
+	if err := repo.UnhideContent(ctx, adminID, models.ReportTargetWorkout, workout.ID); !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected a workout that is not hidden to be not found, got %v", err)
+	}
+	if got := auditActions(t, db, workout.ID); len(got) != 2 || got[0] != models.AuditHideWorkout || got[1] != models.AuditUnhideWorkout {
+		t.Errorf("Expected hide then unhide to be audited, got %v", got)
+	}
+
+	// A hidden comment is left out of the thread
+	report, _ = reportRepo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetComment,
+		TargetID:   comment.ID,
+		Reason:     "harassment",
+	})
+	if _, err := repo.ResolveReport(ctx, adminID, report.ID, models.ResolveReportRequest{Action: models.ReportActionHideContent}); err != nil {
+		t.Fatalf("Failed to hide comment: %v", err)
+	}
+	if cs, _ := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, reporterID, nil, 10); len(cs) != 0 {
+		t.Errorf("Expected the hidden comment to be left out, got %d", len(cs))
+	}
+	if cs, _ := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, otherID, nil, 10); len(cs) != 1 {
+		t.Errorf("Expected the commenter to still see their comment, got %d", len(cs))
+	}
+}
+
+func TestResolveReport_SuspendUser(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	reportRepo := NewReportRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	reporterID, _, _ := testutil.InsertProfile(ctx, db, "reporter")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	report, _ := reportRepo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetUser,
+		TargetID:   userID,
+		Reason:     "harassment",
+	})
+
+	// Hiding does not apply to a profile
+	_, err := repo.ResolveReport(ctx, adminID, report.ID, models.ResolveReportRequest{Action: models.ReportActionHideContent})
+	if !errors.Is(err, ErrInvalidReportAction) {
+		t.Errorf("Expected ErrInvalidReportAction, got %v", err)
+	}
+
+	until := time.Now().Add(24 * time.Hour)
+	if _, err := repo.ResolveReport(ctx, adminID, report.ID, models.ResolveReportRequest{
+		Action:       models.ReportActionSuspendUser,
+		SuspendUntil: &until,
+	}); err != nil {
+		t.Fatalf("Failed to resolve report: %v", err)
+	}
+
+	user, _ := repo.GetUser(ctx, userID)
+	if user.SuspendedUntil == nil || user.SuspensionReason == nil || *user.SuspensionReason != "harassment" {
+		t.Errorf("Expected the user to be suspended for the report's reason, got %+v", user)
+	}
+
+	reports, _ := repo.GetReports(ctx, models.ReportFilter{}, nil, 10)
+	if len(reports) != 1 || reports[0].Status != models.ReportStatusActioned || reports[0].ResolvedBy == nil {
+		t.Errorf("Expected the report to be actioned, got %+v", reports)
+	}
+	if got := auditActions(t, db, report.ID); len(got) != 1 || got[0] != models.AuditResolveReport {
+		t.Errorf("Expected the resolution to be audited, got %v", got)
+	}
+}
+
+func TestGetReports(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewAdminRepository(db)
+	reportRepo := NewReportRepository(db)
+	ctx := context.Background()
+
+	adminID, _, _ := testutil.InsertProfile(ctx, db, "admin")
+	targetID, _, _ := testutil.InsertProfile(ctx, db, "target")
+	firstID, _, _ := testutil.InsertProfile(ctx, db, "first")
+	secondID, _, _ := testutil.InsertProfile(ctx, db, "second")
+	testutil.InsertSysAdmin(ctx, db, adminID)
+
+	first, _ := reportRepo.CreateReport(ctx, firstID, models.CreateReportRequest{
+		TargetType: models.ReportTargetUser, TargetID: targetID, Reason: "spam",
+	})
+	reportRepo.CreateReport(ctx, secondID, models.CreateReportRequest{
+		TargetType: models.ReportTargetUser, TargetID: targetID, Reason: "spam",
+	})
+
+	open := models.ReportStatusOpen
+	reports, err := repo.GetReports(ctx, models.ReportFilter{Status: &open}, nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to get reports: %v", err)
+	}
+	if len(reports) != 2 || reports[0].OpenReports != 2 || reports[1].ID != first.ID {
+		t.Errorf("Expected 2 open reports, newest first, got %+v", reports)
+	}
+
+	// Dismissing closes both
+	if _, err := repo.ResolveReport(ctx, adminID, first.ID, models.ResolveReportRequest{Action: models.ReportActionDismiss}); err != nil {
+		t.Fatalf("Failed to dismiss report: %v", err)
+	}
+	if reports, _ := repo.GetReports(ctx, models.ReportFilter{Status: &open}, nil, 10); len(reports) != 0 {
+		t.Errorf("Expected an empty queue, got %d", len(reports))
+	}
+	dismissed := models.ReportStatusDismissed
+	if reports, _ := repo.GetReports(ctx, models.ReportFilter{Status: &dismissed}, nil, 10); len(reports) != 2 {
+		t.Errorf("Expected 2 dismissed reports, got %d", len(reports))
+	}
+}
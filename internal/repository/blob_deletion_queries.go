@@ -0,0 +1,13 @@
+package repository
+
+const listBlobDeletionsQuery = `
+	SELECT id, object_key, created_at
+	FROM public.blob_deletions
+	ORDER BY created_at
+	LIMIT $1
+`
+
+const deleteBlobDeletionsQuery = `
+	DELETE FROM public.blob_deletions
+	WHERE id = ANY($1)
+`
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+type BlobDeletionRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewBlobDeletionRepository(db *pgxpool.Pool) *BlobDeletionRepository {
+	return &BlobDeletionRepository{
+		DB: db,
+	}
+}
+
+// ListBlobDeletions returns the oldest queued deletions. Deleting an object
+// twice is harmless, so concurrent sweepers need no claims.
+func (r *BlobDeletionRepository) ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error) {
+	rows, err := r.DB.Query(ctx, listBlobDeletionsQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blob deletions: %w", err)
+	}
+	defer rows.Close()
+
+	var deletions []*models.BlobDeletion
+	for rows.Next() {
+		var d models.BlobDeletion
+		if err := rows.Scan(&d.ID, &d.ObjectKey, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blob deletion: %w", err)
+		}
+		deletions = append(deletions, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate blob deletions: %w", err)
+	}
+
+	return deletions, nil
+}
+
+// DeleteBlobDeletions dequeues deletions whose objects are gone.
+func (r *BlobDeletionRepository) DeleteBlobDeletions(ctx context.Context, ids []uuid.UUID) error {
+	if _, err := r.DB.Exec(ctx, deleteBlobDeletionsQuery, ids); err != nil {
+		return fmt.Errorf("failed to delete blob deletions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestListAndDeleteBlobDeletions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewBlobDeletionRepository(db)
+	ctx := context.Background()
+
+	for _, key := range []string{"avatars/a.jpg", "avatars/b.jpg", "avatars/c.jpg"} {
+		if _, err := db.Exec(ctx, "INSERT INTO blob_deletions (object_key) VALUES ($1)", key); err != nil {
+			t.Fatalf("Failed to queue blob: %v", err)
+		}
+	}
+
+	batch, err := repo.ListBlobDeletions(ctx, 2)
+	if err != nil {
+		t.Fatalf("Failed to list blob deletions: %v", err)
+	}
+	if len(batch) != 2 {
+		t.Fatalf("Expected 2 deletions, got %d", len(batch))
+	}
+
+	if err := repo.DeleteBlobDeletions(ctx, []uuid.UUID{batch[0].ID, batch[1].ID}); err != nil {
+		t.Fatalf("Failed to delete blob deletions: %v", err)
+	}
+
+	rest, err := repo.ListBlobDeletions(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to list blob deletions: %v", err)
+	}
+	if len(rest) != 1 || rest[0].ID == batch[0].ID || rest[0].ID == batch[1].ID {
+		t.Errorf("Expected only the unlisted deletion to remain, got %+v", rest)
+	}
+}
@@ -26,9 +26,5 @@ const deleteBlockedUserQuery = `
 `
 
 const isBlockedQuery = `
-	SELECT EXISTS(
-		SELECT 1 FROM public.blocked_users
-		WHERE (blocker_id = $1 AND blocked_id = $2)
-		   OR (blocker_id = $2 AND blocked_id = $1)
-	)
+	SELECT public.is_blocked_between($1, $2)
 `
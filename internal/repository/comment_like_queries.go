@@ -5,25 +5,11 @@ const insertCommentLikeQuery = `
   SELECT $1, c.id
   FROM public.comments c
   JOIN public.workouts w ON c.workout_id = w.id
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE c.id = $2
-    -- 1. Block Guard: Neither the liker nor the workout owner have blocked each other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $1)
-           OR (b.blocker_id = $1 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR own workout OR accepted follower
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $1
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $1 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
+    AND c.deleted_at IS NULL
+    AND NOT public.is_hidden_from($1, c.user_id, c.hidden_at)
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
   ON CONFLICT (user_id, comment_id) DO NOTHING
   RETURNING user_id, comment_id, created_at
 `
@@ -33,25 +19,9 @@ const getCommentLikeQuery = `
   FROM public.comment_likes l
   JOIN public.comments c ON l.comment_id = c.id
   JOIN public.workouts w ON c.workout_id = w.id
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE l.user_id = $1 AND l.comment_id = $2
-    -- 1. Block Guard: Neither the viewer nor the workout owner have blocked each other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $1)
-           OR (b.blocker_id = $1 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR viewing own workout OR accepted follower
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $1
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $1 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
 `
 
 const getCommentLikesByCommentIDQuery = `
@@ -66,32 +36,25 @@ const getCommentLikesByCommentIDQuery = `
   JOIN public.profiles liker_p ON l.user_id = liker_p.id -- Join the Liker
   JOIN public.comments c ON l.comment_id = c.id
   JOIN public.workouts w ON c.workout_id = w.id
-  JOIN public.profiles owner_p ON w.user_id = owner_p.id -- Join the Workout Owner
   WHERE l.comment_id = $1
     -- Admin Bypass: If viewer is admin, skip all social guards
     AND (
       EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2)
       OR (
-        -- 1. Context Guard (Can I see the Workout Owner's content?)
-        NOT EXISTS (
-            SELECT 1 FROM public.blocked_users b
-            WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-               OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-        )
-        AND (owner_p.is_private_account = false OR w.user_id = $2 OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 AND f.following_id = w.user_id AND f.status = 'accepted'
-        ))
-        -- 2. Ghost Filter (Block between Viewer and the specific Liker)
-        AND NOT EXISTS (
-            SELECT 1 FROM public.blocked_users b
-            WHERE (b.blocker_id = l.user_id AND b.blocked_id = $2)
-               OR (b.blocker_id = $2 AND b.blocked_id = l.user_id)
-        )
+        -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+        public.can_view_content($2, w.user_id, w.hidden_at)
+        -- Ghost Filter (Block between Viewer and the specific Liker)
+        AND NOT public.is_blocked_between(l.user_id, $2)
+        -- Account Filter: Hide likers who are suspended or banned
+        AND public.is_account_active(l.user_id)
+        -- Moderation Filter: Hide likes of a comment taken down by a moderator
+        AND NOT public.is_hidden_from($2, c.user_id, c.hidden_at)
       )
     )
-    ORDER BY l.created_at DESC
-    LIMIT $3 OFFSET $4
+    -- Keyset Cursor: resume after the last like of the previous page
+    AND ($3::timestamptz IS NULL OR (l.created_at, l.user_id) < ($3::timestamptz, $4::uuid))
+    ORDER BY l.created_at DESC, l.user_id DESC
+    LIMIT $5
 `
 
 const deleteCommentLikeQuery = `
@@ -104,24 +67,8 @@ const isCommentLikedQuery = `
     SELECT 1 FROM public.comment_likes l
     JOIN public.comments c ON l.comment_id = c.id
     JOIN public.workouts w ON c.workout_id = w.id
-    JOIN public.profiles p ON w.user_id = p.id
     WHERE l.user_id = $1 AND l.comment_id = $2
-      -- 1. Block Guard: Neither the viewer nor the workout owner have blocked each other
-      AND NOT EXISTS (
-          SELECT 1 FROM public.blocked_users b
-          WHERE (b.blocker_id = w.user_id AND b.blocked_id = $1)
-             OR (b.blocker_id = $1 AND b.blocked_id = w.user_id)
-      )
-      -- 2. Privacy Guard: Public account OR viewing own workout OR accepted follower
-      AND (
-          p.is_private_account = false 
-          OR w.user_id = $1
-          OR EXISTS (
-              SELECT 1 FROM public.follows f
-              WHERE f.follower_id = $1 
-                AND f.following_id = w.user_id 
-                AND f.status = 'accepted'
-          )
-      )
+      -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+      AND public.can_view_content($1, w.user_id, w.hidden_at)
   )
 `
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
 type CommentLikeRepository struct {
@@ -95,10 +96,10 @@ func (r *CommentLikeRepository) GetCommentLikesByCommentID(
 	ctx context.Context,
 	commentID uuid.UUID,
 	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.CommentLikeDetail, error) {
-	rows, err := r.DB.Query(ctx, getCommentLikesByCommentIDQuery, commentID, viewerID, limit, offset)
+	rows, err := r.DB.Query(ctx, getCommentLikesByCommentIDQuery, commentID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch comment likes: %w", err)
 	}
@@ -202,7 +202,7 @@ func TestGetCommentLikesByCommentID(t *testing.T) {
 
 	likeRepo.LikeComment(ctx, userID, comment.ID)
 
-	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, comment.ID, userID, 10, 0)
+	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, comment.ID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
@@ -218,7 +218,7 @@ func TestGetCommentLikesByCommentIDCommentNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
-	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, uuid.New(), userID, 10, 0)
+	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, uuid.New(), userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
@@ -253,7 +253,7 @@ func TestGetCommentLikesByCommentIDBlockedUser(t *testing.T) {
 		t.Fatalf("Failed to insert blocked user: %v", err)
 	}
 
-	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, comment.ID, blockedUserID, 10, 0)
+	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, comment.ID, blockedUserID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
@@ -284,7 +284,7 @@ func TestLikeDeleteOnCascadeComment(t *testing.T) {
 		t.Fatalf("Failed to delete comment: %v", err)
 	}
 
-	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, comment.ID, userID, 10, 0)
+	likes, err := likeRepo.GetCommentLikesByCommentID(ctx, comment.ID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
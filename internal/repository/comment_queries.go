@@ -1,124 +1,136 @@
 package repository
 
 const getCommentByIDQuery = `
-  SELECT c.id, c.user_id, c.workout_id, c.parent_id, c.content, c.likes_count, c.created_at
+  SELECT c.id, c.user_id, c.workout_id, c.parent_id, c.content, c.likes_count, c.created_at,
+         c.updated_at, c.edited_at, c.deleted_at IS NOT NULL
   FROM public.comments c
   JOIN public.workouts w ON c.workout_id = w.id
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE c.id = $1
-    -- 1. Block Guard: Neither the viewer nor the workout owner have blocked each other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR viewing own workout OR accepted follower
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($2, w.user_id, w.hidden_at)
+    -- Ghost Filter: Hide comments from blocked users
+    AND NOT public.is_blocked_between(c.user_id, $2)
+    -- Account Filter: Hide comments from suspended and banned users
+    AND public.is_account_active(c.user_id)
+    -- Moderation Filter: Hide comments taken down by a moderator
+    AND NOT public.is_hidden_from($2, c.user_id, c.hidden_at)
 `
 
 const getCommentsByWorkoutIDQuery = `
-  SELECT c.id, c.user_id, c.workout_id, c.parent_id, c.content, c.likes_count, c.created_at
+  SELECT c.id, c.user_id, c.workout_id, c.parent_id, c.content, c.likes_count, c.created_at,
+         c.updated_at, c.edited_at, c.deleted_at IS NOT NULL
   FROM public.comments c
   JOIN public.workouts w ON c.workout_id = w.id
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE c.workout_id = $1 AND c.parent_id IS NULL
-    -- 1. Block Guard: Neither the viewer nor the workout owner have blocked each other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR viewing own workout OR accepted follower
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-    -- 3. Ghost Filter: Hide comments from blocked users
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = c.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = c.user_id)
-    )
-  ORDER BY c.created_at ASC
-  LIMIT $3 OFFSET $4
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($2, w.user_id, w.hidden_at)
+    -- Ghost Filter: Hide comments from blocked users
+    AND NOT public.is_blocked_between(c.user_id, $2)
+    -- Account Filter: Hide comments from suspended and banned users
+    AND public.is_account_active(c.user_id)
+    -- Moderation Filter: Hide comments taken down by a moderator
+    AND NOT public.is_hidden_from($2, c.user_id, c.hidden_at)
+    -- Keyset Cursor: resume after the last comment of the previous page (oldest first)
+    AND ($3::timestamptz IS NULL OR (c.created_at, c.id) > ($3::timestamptz, $4::uuid))
+  ORDER BY c.created_at ASC, c.id ASC
+  LIMIT $5
 `
 
 const getRepliesByCommentIDQuery = `
-  SELECT c.id, c.user_id, c.workout_id, c.parent_id, c.content, c.likes_count, c.created_at
+  SELECT c.id, c.user_id, c.workout_id, c.parent_id, c.content, c.likes_count, c.created_at,
+         c.updated_at, c.edited_at, c.deleted_at IS NOT NULL
   FROM public.comments c
   JOIN public.comments parent ON c.parent_id = parent.id
   JOIN public.workouts w ON parent.workout_id = w.id
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE c.parent_id = $1
-    -- 1. Block Guard: Neither the viewer nor the workout owner have blocked each other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR viewing own workout OR accepted follower
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-    -- 3. Ghost Filter: Hide replies from blocked users
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = c.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = c.user_id)
-    )
-  ORDER BY c.created_at ASC
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($2, w.user_id, w.hidden_at)
+    -- Ghost Filter: Hide replies from blocked users
+    AND NOT public.is_blocked_between(c.user_id, $2)
+    -- Account Filter: Hide replies from suspended and banned users
+    AND public.is_account_active(c.user_id)
+    -- Moderation Filter: Hide replies taken down by a moderator
+    AND NOT public.is_hidden_from($2, c.user_id, c.hidden_at)
+    -- Keyset Cursor: resume after the last reply of the previous page (oldest first)
+    AND ($3::timestamptz IS NULL OR (c.created_at, c.id) > ($3::timestamptz, $4::uuid))
+  ORDER BY c.created_at ASC, c.id ASC
+  LIMIT $5
 `
 
 const insertCommentQuery = `
   INSERT INTO public.comments (user_id, workout_id, parent_id, content)
   SELECT $1, w.id, $3, $4
   FROM public.workouts w
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE w.id = $2
-    -- 1. Block Guard: Neither the commenter nor the workout owner have blocked each other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $1)
-           OR (b.blocker_id = $1 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR commenting on own workout OR accepted follower
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $1
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $1 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-  RETURNING id, user_id, workout_id, parent_id, content, likes_count, created_at
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
+  RETURNING id, user_id, workout_id, parent_id, content, likes_count, created_at,
+            updated_at, edited_at, deleted_at IS NOT NULL
 `
 
+const getCommentForUpdateQuery = `
+  SELECT content, created_at
+  FROM public.comments
+  WHERE id = $1
+    AND user_id = $2
+    AND deleted_at IS NULL
+  FOR UPDATE
+`
+
+const insertCommentRevisionQuery = `
+  INSERT INTO public.comment_revisions (comment_id, content)
+  VALUES ($1, $2)
+`
+
+const updateCommentContentQuery = `
+  UPDATE public.comments
+  SET content = $2, edited_at = now()
+  WHERE id = $1
+  RETURNING id, user_id, workout_id, parent_id, content, likes_count, created_at,
+            updated_at, edited_at, deleted_at IS NOT NULL
+`
+
+// lockCommentQuery holds the comment for the rest of a delete so no reply can
+// be added between the replies check and the delete.
+const lockCommentQuery = `
+  SELECT id
+  FROM public.comments
+  WHERE id = $1
+  FOR UPDATE
+`
+
+// hasRepliesQuery decides between a soft and a hard delete.
+const hasRepliesQuery = `
+  SELECT EXISTS (SELECT 1 FROM public.comments WHERE parent_id = $1)
+`
+
+// deleteCommentByIDQuery hard-deletes a comment and returns its parent so an
+// orphaned tombstone can be cleaned up.
 const deleteCommentByIDQuery = `
   DELETE FROM public.comments
   WHERE id = $1 
   AND user_id = $2
+  RETURNING parent_id
+`
+
+// softDeleteCommentQuery keeps the row, and with it the reply thread, but drops the content.
+const softDeleteCommentQuery = `
+  UPDATE public.comments
+  SET content = '[deleted]', deleted_at = now()
+  WHERE id = $1
+    AND user_id = $2
+    AND deleted_at IS NULL
+`
+
+const deleteCommentRevisionsQuery = `
+  DELETE FROM public.comment_revisions
+  WHERE comment_id = $1
+`
+
+// deleteOrphanedTombstoneQuery removes a soft-deleted parent once its last reply is gone.
+const deleteOrphanedTombstoneQuery = `
+  DELETE FROM public.comments c
+  WHERE c.id = $1
+    AND c.deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM public.comments r WHERE r.parent_id = c.id)
 `
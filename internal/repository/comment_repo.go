@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
+// CommentEditWindow is how long after posting a comment its author may edit it.
+const CommentEditWindow = 15 * time.Minute
+
 type CommentRepository struct {
 	DB *pgxpool.Pool
 }
@@ -29,10 +34,16 @@ func (r *CommentRepository) CreateComment(
 	parentID *uuid.UUID,
 	content string,
 ) (*models.Comment, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var comment models.Comment
 
 	// userID is passed twice: once as the commenter ($1) and used for access checks
-	err := r.DB.QueryRow(ctx, insertCommentQuery, userID, workoutID, parentID, content).Scan(
+	err = tx.QueryRow(ctx, insertCommentQuery, userID, workoutID, parentID, content).Scan(
 		&comment.ID,
 		&comment.UserID,
 		&comment.WorkoutID,
@@ -40,6 +51,9 @@ func (r *CommentRepository) CreateComment(
 		&comment.Content,
 		&comment.LikesCount,
 		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditedAt,
+		&comment.IsDeleted,
 	)
 	if err != nil {
 		// If no rows returned, the user doesn't have access to this workout
@@ -56,6 +70,14 @@ func (r *CommentRepository) CreateComment(
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
 
+	if err := syncCommentEntities(ctx, tx, comment.ID, userID, &content); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &comment, nil
 }
 
@@ -74,6 +96,9 @@ func (r *CommentRepository) GetCommentByUserID(
 		&comment.Content,
 		&comment.LikesCount,
 		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditedAt,
+		&comment.IsDeleted,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -89,10 +114,10 @@ func (r *CommentRepository) GetCommentsByWorkoutID(
 	ctx context.Context,
 	workoutID uuid.UUID,
 	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.Comment, error) {
-	rows, err := r.DB.Query(ctx, getCommentsByWorkoutIDQuery, workoutID, viewerID, limit, offset)
+	rows, err := r.DB.Query(ctx, getCommentsByWorkoutIDQuery, workoutID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get comments: %w", err)
 	}
@@ -109,6 +134,9 @@ func (r *CommentRepository) GetCommentsByWorkoutID(
 			&c.Content,
 			&c.LikesCount,
 			&c.CreatedAt,
+			&c.UpdatedAt,
+			&c.EditedAt,
+			&c.IsDeleted,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -123,8 +151,10 @@ func (r *CommentRepository) GetReplies(
 	ctx context.Context,
 	commentID uuid.UUID,
 	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
 ) ([]*models.Comment, error) {
-	rows, err := r.DB.Query(ctx, getRepliesByCommentIDQuery, commentID, viewerID)
+	rows, err := r.DB.Query(ctx, getRepliesByCommentIDQuery, commentID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get replies: %w", err)
 	}
@@ -141,6 +171,9 @@ func (r *CommentRepository) GetReplies(
 			&c.Content,
 			&c.LikesCount,
 			&c.CreatedAt,
+			&c.UpdatedAt,
+			&c.EditedAt,
+			&c.IsDeleted,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan reply: %w", err)
@@ -151,19 +184,133 @@ func (r *CommentRepository) GetReplies(
 	return comments, nil
 }
 
+// UpdateComment replaces the content of the user's own comment, keeping the
+// previous content as a revision. Comments can only be edited within
+// CommentEditWindow of being posted.
+func (r *CommentRepository) UpdateComment(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+	content string,
+) (*models.Comment, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var previous string
+	var createdAt time.Time
+	err = tx.QueryRow(ctx, getCommentForUpdateQuery, id, userID).Scan(&previous, &createdAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if time.Since(createdAt) > CommentEditWindow {
+		return nil, ErrCommentEditWindowExpired
+	}
+
+	if _, err := tx.Exec(ctx, insertCommentRevisionQuery, id, previous); err != nil {
+		return nil, fmt.Errorf("failed to save comment revision: %w", err)
+	}
+
+	var comment models.Comment
+	err = tx.QueryRow(ctx, updateCommentContentQuery, id, content).Scan(
+		&comment.ID,
+		&comment.UserID,
+		&comment.WorkoutID,
+		&comment.ParentID,
+		&comment.Content,
+		&comment.LikesCount,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.EditedAt,
+		&comment.IsDeleted,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if err := syncCommentEntities(ctx, tx, id, userID, &content); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// DeleteComment removes the user's own comment. A comment with replies is
+// soft-deleted to "[deleted]" so the thread survives; otherwise it is removed,
+// along with its parent if that was a tombstone waiting on this last reply.
 func (r *CommentRepository) DeleteComment(
 	ctx context.Context,
 	id uuid.UUID,
 	userID uuid.UUID,
 ) error {
-	commandTag, err := r.DB.Exec(ctx, deleteCommentByIDQuery, id, userID)
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete comment: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockComment(ctx, tx, id); err != nil {
+		return err
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return ErrCommentNotFound
+	var hasReplies bool
+	if err := tx.QueryRow(ctx, hasRepliesQuery, id).Scan(&hasReplies); err != nil {
+		return fmt.Errorf("failed to check replies: %w", err)
 	}
 
+	if hasReplies {
+		commandTag, err := tx.Exec(ctx, softDeleteCommentQuery, id, userID)
+		if err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if commandTag.RowsAffected() == 0 {
+			return ErrCommentNotFound
+		}
+		if _, err := tx.Exec(ctx, deleteCommentRevisionsQuery, id); err != nil {
+			return fmt.Errorf("failed to delete comment revisions: %w", err)
+		}
+		if err := syncCommentEntities(ctx, tx, id, userID, nil); err != nil {
+			return err
+		}
+	} else {
+		var parentID *uuid.UUID
+		err := tx.QueryRow(ctx, deleteCommentByIDQuery, id, userID).Scan(&parentID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if parentID != nil {
+			if _, err := tx.Exec(ctx, deleteOrphanedTombstoneQuery, *parentID); err != nil {
+				return fmt.Errorf("failed to clean up deleted parent: %w", err)
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockComment row-locks a comment inside tx. A reply insert needs a key-share
+// lock on its parent, so holding this keeps the replies check in a delete
+// valid until commit.
+func lockComment(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	var lockedID uuid.UUID
+	if err := tx.QueryRow(ctx, lockCommentQuery, id).Scan(&lockedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("failed to lock comment: %w", err)
+	}
 	return nil
 }
@@ -137,7 +137,7 @@ func TestGetCommentsByWorkoutID(t *testing.T) {
 	commentRepo.CreateComment(ctx, userID, workout.ID, nil, "Comment 1")
 	commentRepo.CreateComment(ctx, userID, workout.ID, nil, "Comment 2")
 
-	comments, err := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, userID, 10, 0)
+	comments, err := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get comments: %v", err)
 	}
@@ -161,7 +161,7 @@ func TestGetReplies(t *testing.T) {
 	commentRepo.CreateComment(ctx, userID, workout.ID, &parent.ID, "Reply 1")
 	commentRepo.CreateComment(ctx, userID, workout.ID, &parent.ID, "Reply 2")
 
-	replies, err := commentRepo.GetReplies(ctx, parent.ID, userID)
+	replies, err := commentRepo.GetReplies(ctx, parent.ID, userID, nil, 20)
 	if err != nil {
 		t.Fatalf("Failed to get replies: %v", err)
 	}
@@ -224,7 +224,7 @@ func TestCommentCascadeOnWorkoutDelete(t *testing.T) {
 		t.Fatalf("Failed to delete workout: %v", err)
 	}
 
-	rows, err := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, userID, 10, 0)
+	rows, err := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get comments: %v", err)
 	}
@@ -233,3 +233,119 @@ func TestCommentCascadeOnWorkoutDelete(t *testing.T) {
 		t.Errorf("Expected 0 comments, got %d", len(rows))
 	}
 }
+
+func TestUpdateComment(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	commentRepo := NewCommentRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "otheruser")
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+	comment, _ := commentRepo.CreateComment(ctx, userID, workout.ID, nil, "Original")
+
+	updated, err := commentRepo.UpdateComment(ctx, comment.ID, userID, "Edited")
+	if err != nil {
+		t.Fatalf("Failed to update comment: %v", err)
+	}
+	if updated.Content != "Edited" {
+		t.Errorf("Content mismatch: got %v, want %v", updated.Content, "Edited")
+	}
+	if updated.EditedAt == nil {
+		t.Error("Expected EditedAt to be set")
+	}
+
+	var revision string
+	err = db.QueryRow(ctx, "SELECT content FROM public.comment_revisions WHERE comment_id = $1", comment.ID).Scan(&revision)
+	if err != nil {
+		t.Fatalf("Failed to get revision: %v", err)
+	}
+	if revision != "Original" {
+		t.Errorf("Revision mismatch: got %v, want %v", revision, "Original")
+	}
+
+	// Only the author can edit
+	_, err = commentRepo.UpdateComment(ctx, comment.ID, otherID, "Hijacked")
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("Expected ErrCommentNotFound, but got %v", err)
+	}
+}
+
+func TestUpdateCommentEditWindowExpired(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	commentRepo := NewCommentRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+	comment, _ := commentRepo.CreateComment(ctx, userID, workout.ID, nil, "Old comment")
+
+	_, err := db.Exec(
+		ctx,
+		"UPDATE public.comments SET created_at = $2 WHERE id = $1",
+		comment.ID,
+		time.Now().Add(-CommentEditWindow-time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Failed to backdate comment: %v", err)
+	}
+
+	_, err = commentRepo.UpdateComment(ctx, comment.ID, userID, "Too late")
+	if !errors.Is(err, ErrCommentEditWindowExpired) {
+		t.Errorf("Expected ErrCommentEditWindowExpired, but got %v", err)
+	}
+}
+
+func TestDeleteCommentWithRepliesSoftDeletes(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	commentRepo := NewCommentRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+	parent, _ := commentRepo.CreateComment(ctx, userID, workout.ID, nil, "Parent")
+	reply, _ := commentRepo.CreateComment(ctx, userID, workout.ID, &parent.ID, "Reply")
+
+	if err := commentRepo.DeleteComment(ctx, parent.ID, userID); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	tombstone, err := commentRepo.GetCommentByUserID(ctx, parent.ID, userID)
+	if err != nil {
+		t.Fatalf("Failed to get soft-deleted comment: %v", err)
+	}
+	if !tombstone.IsDeleted || tombstone.Content != "[deleted]" {
+		t.Errorf("Expected a [deleted] tombstone, got %+v", tombstone)
+	}
+
+	replies, _ := commentRepo.GetReplies(ctx, parent.ID, userID, nil, 20)
+	if len(replies) != 1 {
+		t.Errorf("Expected reply to survive, got %d replies", len(replies))
+	}
+
+	w, _ := workoutRepo.GetWorkoutByID(ctx, workout.ID, userID)
+	if w.CommentsCount != 0 {
+		t.Errorf("CommentsCount mismatch after soft delete: got %v, want 0", w.CommentsCount)
+	}
+
+	// Removing the last reply also removes the tombstone without touching the count again
+	if err := commentRepo.DeleteComment(ctx, reply.ID, userID); err != nil {
+		t.Fatalf("Failed to delete reply: %v", err)
+	}
+
+	_, err = commentRepo.GetCommentByUserID(ctx, parent.ID, userID)
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("Expected tombstone to be removed, but got %v", err)
+	}
+
+	w, _ = workoutRepo.GetWorkoutByID(ctx, workout.ID, userID)
+	if w.CommentsCount != 0 {
+		t.Errorf("CommentsCount mismatch after cleanup: got %v, want 0", w.CommentsCount)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+// Mentions resolve against profiles.username case-insensitively. Authors can't
+// mention themselves, and mentions across a block are silently dropped.
+const insertWorkoutMentionsQuery = `
+  INSERT INTO public.workout_mentions (workout_id, user_id)
+  SELECT $1, p.id
+  FROM public.profiles p
+  WHERE lower(p.username) = ANY($3::text[])
+    AND p.id <> $2
+    AND NOT public.is_blocked_between($2, p.id)
+  ON CONFLICT (workout_id, user_id) DO NOTHING
+`
+
+const deleteStaleWorkoutMentionsQuery = `
+  DELETE FROM public.workout_mentions m
+  USING public.profiles p
+  WHERE m.workout_id = $1
+    AND p.id = m.user_id
+    AND NOT (lower(p.username) = ANY($2::text[]))
+`
+
+const insertCommentMentionsQuery = `
+  INSERT INTO public.comment_mentions (comment_id, user_id)
+  SELECT $1, p.id
+  FROM public.profiles p
+  WHERE lower(p.username) = ANY($3::text[])
+    AND p.id <> $2
+    AND NOT public.is_blocked_between($2, p.id)
+  ON CONFLICT (comment_id, user_id) DO NOTHING
+`
+
+const deleteStaleCommentMentionsQuery = `
+  DELETE FROM public.comment_mentions m
+  USING public.profiles p
+  WHERE m.comment_id = $1
+    AND p.id = m.user_id
+    AND NOT (lower(p.username) = ANY($2::text[]))
+`
+
+const insertWorkoutHashtagsQuery = `
+  INSERT INTO public.workout_hashtags (workout_id, tag)
+  SELECT $1, unnest($2::text[])
+  ON CONFLICT (workout_id, tag) DO NOTHING
+`
+
+const deleteStaleWorkoutHashtagsQuery = `
+  DELETE FROM public.workout_hashtags
+  WHERE workout_id = $1
+    AND NOT (tag = ANY($2::text[]))
+`
+
+const insertCommentHashtagsQuery = `
+  INSERT INTO public.comment_hashtags (comment_id, tag)
+  SELECT $1, unnest($2::text[])
+  ON CONFLICT (comment_id, tag) DO NOTHING
+`
+
+const deleteStaleCommentHashtagsQuery = `
+  DELETE FROM public.comment_hashtags
+  WHERE comment_id = $1
+    AND NOT (tag = ANY($2::text[]))
+`
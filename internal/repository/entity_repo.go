@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rotsu1/jimu-backend/internal/entities"
+)
+
+// syncWorkoutEntities brings the mention and hashtag rows of a workout in line
+// with its caption. Existing rows that are still present are left untouched so
+// an edit doesn't re-notify users who were already mentioned. It must run in the
+// same transaction as the caption change.
+func syncWorkoutEntities(ctx context.Context, tx pgx.Tx, workoutID uuid.UUID, authorID uuid.UUID, text *string) error {
+	e := extractEntities(text)
+
+	if _, err := tx.Exec(ctx, deleteStaleWorkoutMentionsQuery, workoutID, e.Mentions); err != nil {
+		return fmt.Errorf("failed to delete workout mentions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertWorkoutMentionsQuery, workoutID, authorID, e.Mentions); err != nil {
+		return fmt.Errorf("failed to insert workout mentions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, deleteStaleWorkoutHashtagsQuery, workoutID, e.Hashtags); err != nil {
+		return fmt.Errorf("failed to delete workout hashtags: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertWorkoutHashtagsQuery, workoutID, e.Hashtags); err != nil {
+		return fmt.Errorf("failed to insert workout hashtags: %w", err)
+	}
+
+	return nil
+}
+
+// syncCommentEntities is syncWorkoutEntities for comment content. Passing nil
+// clears all of the comment's entities, as done when it is soft-deleted.
+func syncCommentEntities(ctx context.Context, tx pgx.Tx, commentID uuid.UUID, authorID uuid.UUID, text *string) error {
+	e := extractEntities(text)
+
+	if _, err := tx.Exec(ctx, deleteStaleCommentMentionsQuery, commentID, e.Mentions); err != nil {
+		return fmt.Errorf("failed to delete comment mentions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertCommentMentionsQuery, commentID, authorID, e.Mentions); err != nil {
+		return fmt.Errorf("failed to insert comment mentions: %w", err)
+	}
+	if _, err := tx.Exec(ctx, deleteStaleCommentHashtagsQuery, commentID, e.Hashtags); err != nil {
+		return fmt.Errorf("failed to delete comment hashtags: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertCommentHashtagsQuery, commentID, e.Hashtags); err != nil {
+		return fmt.Errorf("failed to insert comment hashtags: %w", err)
+	}
+
+	return nil
+}
+
+// extractEntities parses text, always returning non-nil slices so they encode
+// as empty arrays rather than NULL.
+func extractEntities(text *string) entities.Entities {
+	var e entities.Entities
+	if text != nil {
+		e = entities.Extract(*text)
+	}
+	if e.Mentions == nil {
+		e.Mentions = []string{}
+	}
+	if e.Hashtags == nil {
+		e.Hashtags = []string{}
+	}
+	return e
+}
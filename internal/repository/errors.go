@@ -14,11 +14,14 @@ var (
 	ErrProfileNotFound       = errors.New("profile not found")
 	ErrFailedToUpdateProfile = errors.New("failed to update profile")
 	ErrUsernameTaken         = errors.New("username already taken")
+	ErrAccountBanned         = errors.New("account is banned")
+	ErrAccountNotBanned      = errors.New("account is not banned")
 )
 
 // Exercise errors
 var (
-	ErrExerciseNotFound = errors.New("exercise not found")
+	ErrExerciseNotFound        = errors.New("exercise not found")
+	ErrExerciseSessionNotFound = errors.New("exercise session not found")
 )
 
 // Workout errors
@@ -39,6 +42,13 @@ var (
 // WorkoutImage errors
 var (
 	ErrWorkoutImageNotFound = errors.New("workout image not found")
+	ErrWorkoutImageLimit    = errors.New("workout image limit reached")
+	ErrInvalidImageOrder    = errors.New("image order must list each of the workout's images once")
+)
+
+// Upload errors
+var (
+	ErrUploadNotFound = errors.New("upload not found")
 )
 
 // Routine errors
@@ -71,6 +81,7 @@ var (
 var (
 	ErrCommentNotFound              = errors.New("comment not found")
 	ErrCommentInteractionNotAllowed = errors.New("comment interaction not allowed")
+	ErrCommentEditWindowExpired     = errors.New("comment edit window expired")
 )
 
 // WorkoutLike errors
@@ -87,6 +98,7 @@ var (
 // Subscription errors
 var (
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionClaimed  = errors.New("subscription belongs to another user")
 )
 
 // UserDevice errors
@@ -104,3 +116,14 @@ var (
 	ErrMuscleNotFound     = errors.New("muscle not found")
 	ErrUnauthorizedAction = errors.New("unauthorized action")
 )
+
+// Report errors
+var (
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportTargetNotFound = errors.New("report target not found")
+	ErrAlreadyReported      = errors.New("target already reported")
+	ErrReportLimit          = errors.New("report limit reached")
+	ErrCannotReportSelf     = errors.New("cannot report your own content")
+	ErrReportResolved       = errors.New("report already resolved")
+	ErrInvalidReportAction  = errors.New("action does not apply to the report's target")
+)
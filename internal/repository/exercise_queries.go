@@ -14,23 +14,9 @@ const getExerciseByIDQuery = `
       OR EXISTS (
           SELECT 1 FROM public.workout_exercises we
           JOIN public.workouts w ON we.workout_id = w.id
-          JOIN public.profiles p ON w.user_id = p.id
           WHERE we.exercise_id = e.id 
-            AND w.user_id != $2 -- Don't check blocks against yourself
-            -- GUARD: Requester is not blocked by the owner
-            AND NOT EXISTS (
-                SELECT 1 FROM public.blocked_users
-                WHERE (blocker_id = w.user_id AND blocked_id = $2)
-                   OR (blocker_id = $2 AND blocked_id = w.user_id)
-            )
-            -- GUARD: If profile is private, requester must be an accepted follower
-            AND (
-                p.is_private_account = false 
-                OR EXISTS (
-                    SELECT 1 FROM public.follows 
-                    WHERE follower_id = $2 AND following_id = w.user_id AND status = 'accepted'
-                )
-            )
+            -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+            AND public.can_view_content($2, w.user_id, w.hidden_at)
       )
     )
 `
@@ -38,27 +24,14 @@ const getExerciseByIDQuery = `
 const getExercisesByUserIDQuery = `
   SELECT e.id, e.user_id, e.name, e.suggested_rest_seconds, e.icon, e.created_at, e.updated_at
   FROM public.exercises e
-  LEFT JOIN public.profiles p ON e.user_id = p.id
   WHERE (
     -- 1. Always show system library
     e.user_id IS NULL 
     -- 2. Show the specific user's exercises ONLY IF not blocked and permitted
     OR (
       e.user_id = $1
-      AND NOT EXISTS (
-				SELECT 1 FROM public.blocked_users
-				WHERE (blocker_id = $1 AND blocked_id = $2) 
-					OR (blocker_id = $2 AND blocked_id = $1)
-			)
-      AND (
-			p.is_private_account = false 
-				OR e.user_id = $2 
-				OR EXISTS (
-					SELECT 1 FROM public.follows 
-					WHERE follower_id = $2 
-						AND following_id = $1 
-						AND status = 'accepted')
-				)
+      -- Visibility Policy: viewer may see the owner's content (see can_view_user)
+      AND public.can_view_user($2, $1)
     )
   )
   ORDER BY (e.user_id IS NOT NULL) ASC, e.name ASC
@@ -84,3 +57,38 @@ const deleteExerciseByIDQuery = `
         OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2)
     )
 `
+
+// Only the caller's own workouts are returned, so no block/privacy guard is needed.
+// Sessions without any logged sets are skipped.
+const getExerciseHistoryQuery = `
+  SELECT
+    w.id,
+    we.id,
+    w.name,
+    w.started_at,
+    we.memo,
+    COALESCE(
+      (
+        SELECT json_agg(
+          json_build_object(
+            'id', ws.id,
+            'weight', ws.weight,
+            'reps', ws.reps,
+            'order_index', ws.order_index,
+            'is_pr', EXISTS (SELECT 1 FROM public.personal_records pr WHERE pr.workout_set_id = ws.id)
+          ) ORDER BY ws.order_index ASC
+        )
+        FROM public.workout_sets ws
+        WHERE ws.workout_exercise_id = we.id
+      ), '[]'::json
+    ) AS sets
+  FROM public.workout_exercises we
+  JOIN public.workouts w ON we.workout_id = w.id
+  WHERE we.exercise_id = $1
+    AND w.user_id = $2
+    AND EXISTS (SELECT 1 FROM public.workout_sets ws WHERE ws.workout_exercise_id = we.id)
+    -- Keyset Cursor: resume after the last session of the previous page
+    AND ($3::timestamptz IS NULL OR (w.started_at, we.id) < ($3::timestamptz, $4::uuid))
+  ORDER BY w.started_at DESC, we.id DESC
+  LIMIT $5
+`
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
 type ExerciseRepository struct {
@@ -206,3 +208,64 @@ func (r *ExerciseRepository) DeleteExercise(
 
 	return nil
 }
+
+// GetExerciseHistory returns the user's most recent sessions of an exercise,
+// newest first, each with its sets in order.
+func (r *ExerciseRepository) GetExerciseHistory(
+	ctx context.Context,
+	exerciseID uuid.UUID,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.ExerciseSession, error) {
+	rows, err := r.DB.Query(ctx, getExerciseHistoryQuery, exerciseID, userID, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exercise history: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.ExerciseSession{}
+	for rows.Next() {
+		var session models.ExerciseSession
+		var setsJSON []byte
+		err := rows.Scan(
+			&session.WorkoutID,
+			&session.WorkoutExerciseID,
+			&session.WorkoutName,
+			&session.StartedAt,
+			&session.Memo,
+			&setsJSON,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exercise session: %w", err)
+		}
+		if len(setsJSON) > 0 {
+			if err := json.Unmarshal(setsJSON, &session.Sets); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal sets: %w", err)
+			}
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get exercise history: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetLastExerciseSession returns the user's most recent session of an exercise.
+func (r *ExerciseRepository) GetLastExerciseSession(
+	ctx context.Context,
+	exerciseID uuid.UUID,
+	userID uuid.UUID,
+) (*models.ExerciseSession, error) {
+	sessions, err := r.GetExerciseHistory(ctx, exerciseID, userID, nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrExerciseSessionNotFound
+	}
+
+	return sessions[0], nil
+}
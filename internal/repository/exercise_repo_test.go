@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
 )
 
@@ -433,3 +435,76 @@ func TestDeleteExerciseNotFound(t *testing.T) {
 		t.Errorf("Expected ErrExerciseNotFound, but got %v", err)
 	}
 }
+
+func TestGetExerciseHistory(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
+	exercise, _ := repo.CreateExercise(ctx, &userID, "Bench Press", nil, nil, userID)
+
+	now := time.Now()
+	reps := 8
+	for i, weight := range []float64{70, 75, 80} {
+		startedAt := now.Add(time.Duration(i-3) * 24 * time.Hour)
+		w, _ := workoutRepo.Create(ctx, userID, nil, nil, startedAt, startedAt.Add(time.Hour), 3600)
+		we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+		wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, userID)
+	}
+
+	// Another user's session and an empty session must not show up
+	ow, _ := workoutRepo.Create(ctx, otherID, nil, nil, now, now, 0)
+	owe, _ := weRepo.CreateWorkoutExercise(ctx, ow.ID, exercise.ID, 0, nil, nil, otherID)
+	heavy := 200.0
+	wsRepo.CreateWorkoutSet(ctx, owe.ID, &heavy, &reps, 0, otherID)
+	ew, _ := workoutRepo.Create(ctx, userID, nil, nil, now, now, 0)
+	weRepo.CreateWorkoutExercise(ctx, ew.ID, exercise.ID, 0, nil, nil, userID)
+
+	sessions, err := repo.GetExerciseHistory(ctx, exercise.ID, userID, nil, 2)
+	if err != nil {
+		t.Fatalf("Failed to get exercise history: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if *sessions[0].Sets[0].Weight != 80 {
+		t.Errorf("Expected newest session first, got weight %v", *sessions[0].Sets[0].Weight)
+	}
+
+	next, err := repo.GetExerciseHistory(ctx, exercise.ID, userID, &pagination.Cursor{Time: &sessions[1].StartedAt, ID: sessions[1].WorkoutExerciseID}, 2)
+	if err != nil {
+		t.Fatalf("Failed to get exercise history page: %v", err)
+	}
+	if len(next) != 1 || *next[0].Sets[0].Weight != 70 {
+		t.Errorf("Expected oldest session on second page, got %+v", next)
+	}
+
+	last, err := repo.GetLastExerciseSession(ctx, exercise.ID, userID)
+	if err != nil {
+		t.Fatalf("Failed to get last session: %v", err)
+	}
+	if last.WorkoutID != sessions[0].WorkoutID {
+		t.Errorf("Last session mismatch: got %v, want %v", last.WorkoutID, sessions[0].WorkoutID)
+	}
+}
+
+func TestGetLastExerciseSessionNotFound(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	exercise, _ := repo.CreateExercise(ctx, &userID, "Bench Press", nil, nil, userID)
+
+	_, err := repo.GetLastExerciseSession(ctx, exercise.ID, userID)
+	if !errors.Is(err, ErrExerciseSessionNotFound) {
+		t.Errorf("Expected ErrExerciseSessionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+// feedWorkoutIDs returns the IDs on the first page of the viewer's home feed.
+func feedWorkoutIDs(t *testing.T, repo *WorkoutRepository, viewerID uuid.UUID) map[uuid.UUID]bool {
+	t.Helper()
+	workouts, err := repo.GetFollowingTimelineWorkouts(context.Background(), viewerID, models.TimelineIncludeAll, nil, 50)
+	if err != nil {
+		t.Fatalf("Failed to get following timeline: %v", err)
+	}
+	ids := map[uuid.UUID]bool{}
+	for _, w := range workouts {
+		ids[w.ID] = true
+	}
+	return ids
+}
+
+func TestFeedFanOutOnCreate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	workoutRepo := NewWorkoutRepository(db)
+	followRepo := NewFollowRepository(db)
+
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	if _, err := followRepo.Follow(ctx, followerID, authorID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+
+	now := time.Now()
+	workout, err := workoutRepo.Create(ctx, authorID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	var n int
+	err = db.QueryRow(ctx, "SELECT count(*) FROM public.feed_items WHERE workout_id = $1", workout.ID).Scan(&n)
+	if err != nil {
+		t.Fatalf("Failed to count feed items: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected feed items for the author and their follower, got %d", n)
+	}
+	if !feedWorkoutIDs(t, workoutRepo, followerID)[workout.ID] {
+		t.Error("Expected the follower's feed to contain the new workout")
+	}
+	if !feedWorkoutIDs(t, workoutRepo, authorID)[workout.ID] {
+		t.Error("Expected the author's feed to contain their own workout")
+	}
+}
+
+func TestFeedBackfillAndCleanupOnFollowChanges(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	workoutRepo := NewWorkoutRepository(db)
+	followRepo := NewFollowRepository(db)
+	blockRepo := NewBlockedUserRepository(db)
+
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+
+	now := time.Now()
+	workout, err := workoutRepo.Create(ctx, authorID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	// Following backfills the author's existing workouts
+	if _, err := followRepo.Follow(ctx, followerID, authorID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	if !feedWorkoutIDs(t, workoutRepo, followerID)[workout.ID] {
+		t.Fatal("Expected a new follow to backfill the author's workout")
+	}
+
+	// Unfollowing clears it
+	if err := followRepo.Unfollow(ctx, followerID, authorID); err != nil {
+		t.Fatalf("Failed to unfollow: %v", err)
+	}
+	if feedWorkoutIDs(t, workoutRepo, followerID)[workout.ID] {
+		t.Error("Expected unfollow to remove the author's workout from the feed")
+	}
+
+	// Blocking removes the follow, and with it the feed items
+	if _, err := followRepo.Follow(ctx, followerID, authorID); err != nil {
+		t.Fatalf("Failed to follow again: %v", err)
+	}
+	if _, err := blockRepo.Block(ctx, authorID, followerID); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+	var n int
+	err = db.QueryRow(ctx, "SELECT count(*) FROM public.feed_items WHERE user_id = $1", followerID).Scan(&n)
+	if err != nil {
+		t.Fatalf("Failed to count feed items: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Expected block to clear the follower's feed, got %d items", n)
+	}
+}
+
+func TestFeedPullsWorkoutsFromPopularAuthors(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	workoutRepo := NewWorkoutRepository(db)
+	followRepo := NewFollowRepository(db)
+
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+	celebrityID, _, _ := testutil.InsertProfile(ctx, db, "celebrity")
+	if _, err := followRepo.Follow(ctx, followerID, celebrityID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	_, err := db.Exec(ctx, "UPDATE public.profiles SET followers_count = public.feed_fanout_threshold() WHERE id = $1", celebrityID)
+	if err != nil {
+		t.Fatalf("Failed to update followers count: %v", err)
+	}
+
+	now := time.Now()
+	workout, err := workoutRepo.Create(ctx, celebrityID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	var n int
+	err = db.QueryRow(ctx, "SELECT count(*) FROM public.feed_items WHERE user_id = $1 AND workout_id = $2", followerID, workout.ID).Scan(&n)
+	if err != nil {
+		t.Fatalf("Failed to count feed items: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Expected no fan-out above the threshold, got %d items", n)
+	}
+	if !feedWorkoutIDs(t, workoutRepo, followerID)[workout.ID] {
+		t.Error("Expected the popular author's workout to be pulled into the feed")
+	}
+}
+
+func TestFeedKeepsWorkoutsWhenAuthorCrossesThreshold(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	workoutRepo := NewWorkoutRepository(db)
+	followRepo := NewFollowRepository(db)
+
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	if _, err := followRepo.Follow(ctx, followerID, authorID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	setFollowers := func(query string) {
+		t.Helper()
+		if _, err := db.Exec(ctx, "UPDATE public.profiles SET followers_count = "+query+" WHERE id = $1", authorID); err != nil {
+			t.Fatalf("Failed to update followers count: %v", err)
+		}
+	}
+
+	now := time.Now()
+	fannedOut, err := workoutRepo.Create(ctx, authorID, nil, nil, now.Add(-2*time.Hour), now.Add(-2*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	setFollowers("public.feed_fanout_threshold()")
+	pulled, err := workoutRepo.Create(ctx, authorID, nil, nil, now.Add(-time.Hour), now.Add(-time.Hour), 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	feed := feedWorkoutIDs(t, workoutRepo, followerID)
+	if !feed[fannedOut.ID] || !feed[pulled.ID] {
+		t.Errorf("Expected both workouts above the threshold, got %v", feed)
+	}
+
+	// Dropping back below the threshold keeps the pulled workout
+	setFollowers("1")
+	feed = feedWorkoutIDs(t, workoutRepo, followerID)
+	if !feed[fannedOut.ID] || !feed[pulled.ID] {
+		t.Errorf("Expected both workouts below the threshold, got %v", feed)
+	}
+
+	// A new follower gets both too
+	newFollowerID, _, _ := testutil.InsertProfile(ctx, db, "newfollower")
+	if _, err := followRepo.Follow(ctx, newFollowerID, authorID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	setFollowers("public.feed_fanout_threshold()")
+	feed = feedWorkoutIDs(t, workoutRepo, newFollowerID)
+	if !feed[fannedOut.ID] || !feed[pulled.ID] {
+		t.Errorf("Expected a new follower to see both workouts, got %v", feed)
+	}
+}
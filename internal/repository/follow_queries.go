@@ -26,11 +26,7 @@ const getFollowQuery = `
   FROM public.follows f
   WHERE f.follower_id = $1 AND f.following_id = $2
   -- The "Guard": Only return the row if NO block exists
-  AND NOT EXISTS (
-      SELECT 1 FROM public.blocked_users b
-      WHERE (b.blocker_id = $1 AND b.blocked_id = $2)
-         OR (b.blocker_id = $2 AND b.blocked_id = $1)
-  )
+  AND NOT public.is_blocked_between($1, $2)
 `
 
 const getFollowersByUserIDQuery = `
@@ -38,13 +34,13 @@ const getFollowersByUserIDQuery = `
   FROM public.follows f
   WHERE f.following_id = $1 
     AND f.status = 'accepted' -- Usually, you only want to show active followers
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = f.follower_id AND b.blocked_id = f.following_id)
-           OR (b.blocker_id = f.following_id AND b.blocked_id = f.follower_id)
-    )
-  ORDER BY f.created_at DESC
-  LIMIT $2 OFFSET $3
+    AND NOT public.is_blocked_between(f.follower_id, f.following_id)
+    -- Visibility Policy: a private account's connections are only visible to those who can see it
+    AND public.can_view_user($2, $1)
+    -- Keyset Cursor: resume after the last follower of the previous page
+    AND ($3::timestamptz IS NULL OR (f.created_at, f.follower_id) < ($3::timestamptz, $4::uuid))
+  ORDER BY f.created_at DESC, f.follower_id DESC
+  LIMIT $5
 `
 
 const getFollowingByUserIDQuery = `
@@ -52,13 +48,13 @@ const getFollowingByUserIDQuery = `
   FROM public.follows f
   WHERE f.follower_id = $1        -- The user is the one doing the following
     AND f.status = 'accepted'      -- Only show active connections
-    AND NOT EXISTS (               -- The "Double Guard" (just to be safe!)
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = f.follower_id AND b.blocked_id = f.following_id)
-           OR (b.blocker_id = f.following_id AND b.blocked_id = f.follower_id)
-    )
-  ORDER BY f.created_at DESC
-  LIMIT $2 OFFSET $3
+    AND NOT public.is_blocked_between(f.follower_id, f.following_id) -- The "Double Guard" (just to be safe!)
+    -- Visibility Policy: a private account's connections are only visible to those who can see it
+    AND public.can_view_user($2, $1)
+    -- Keyset Cursor: resume after the last followed user of the previous page
+    AND ($3::timestamptz IS NULL OR (f.created_at, f.following_id) < ($3::timestamptz, $4::uuid))
+  ORDER BY f.created_at DESC, f.following_id DESC
+  LIMIT $5
 `
 
 const updateFollowStatusQuery = `
@@ -69,6 +65,44 @@ const updateFollowStatusQuery = `
     AND status = 'pending'
 `
 
+const acceptAllPendingFollowsQuery = `
+  UPDATE public.follows
+  SET status = 'accepted'
+  WHERE following_id = $1
+    AND status = 'pending'
+`
+
+const deletePendingFollowQuery = `
+  DELETE FROM public.follows
+  WHERE follower_id = $1 
+    AND following_id = $2 
+    AND status = 'pending'
+`
+
+const getIncomingFollowRequestsQuery = `
+  SELECT p.id, p.username, p.display_name, p.avatar_url, f.created_at
+  FROM public.follows f
+  JOIN public.profiles p ON p.id = f.follower_id
+  WHERE f.following_id = $1
+    AND f.status = 'pending'
+    -- Keyset Cursor: resume after the last request of the previous page
+    AND ($2::timestamptz IS NULL OR (f.created_at, p.id) < ($2::timestamptz, $3::uuid))
+  ORDER BY f.created_at DESC, p.id DESC
+  LIMIT $4
+`
+
+const getOutgoingFollowRequestsQuery = `
+  SELECT p.id, p.username, p.display_name, p.avatar_url, f.created_at
+  FROM public.follows f
+  JOIN public.profiles p ON p.id = f.following_id
+  WHERE f.follower_id = $1
+    AND f.status = 'pending'
+    -- Keyset Cursor: resume after the last request of the previous page
+    AND ($2::timestamptz IS NULL OR (f.created_at, p.id) < ($2::timestamptz, $3::uuid))
+  ORDER BY f.created_at DESC, p.id DESC
+  LIMIT $4
+`
+
 const deleteFollowQuery = `
 	DELETE FROM public.follows
 	WHERE follower_id = $1 AND following_id = $2
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
 type FollowRepository struct {
@@ -117,14 +118,98 @@ func (r *FollowRepository) AcceptFollow(
 	return nil
 }
 
+// RejectFollow deletes a pending follow request sent by followerID to followingID.
+func (r *FollowRepository) RejectFollow(
+	ctx context.Context,
+	followerID uuid.UUID,
+	followingID uuid.UUID,
+) error {
+	commandTag, err := r.DB.Exec(ctx, deletePendingFollowQuery, followerID, followingID)
+	if err != nil {
+		return fmt.Errorf("failed to reject follow: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return ErrFollowNotFound
+	}
+
+	return nil
+}
+
+// AcceptAllFollows accepts every pending request sent to the user and returns how many were accepted.
+func (r *FollowRepository) AcceptAllFollows(
+	ctx context.Context,
+	followingID uuid.UUID,
+) (int64, error) {
+	commandTag, err := r.DB.Exec(ctx, acceptAllPendingFollowsQuery, followingID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to accept all follows: %w", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// GetIncomingFollowRequests gets pending requests sent to the user, newest first.
+func (r *FollowRepository) GetIncomingFollowRequests(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.FollowRequest, error) {
+	return r.getFollowRequests(ctx, getIncomingFollowRequestsQuery, userID, cursor, limit)
+}
+
+// GetOutgoingFollowRequests gets pending requests the user has sent, newest first.
+func (r *FollowRepository) GetOutgoingFollowRequests(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.FollowRequest, error) {
+	return r.getFollowRequests(ctx, getOutgoingFollowRequestsQuery, userID, cursor, limit)
+}
+
+func (r *FollowRepository) getFollowRequests(
+	ctx context.Context,
+	query string,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.FollowRequest, error) {
+	rows, err := r.DB.Query(ctx, query, userID, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follow requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*models.FollowRequest
+	for rows.Next() {
+		var fr models.FollowRequest
+		err := rows.Scan(
+			&fr.UserID,
+			&fr.Username,
+			&fr.DisplayName,
+			&fr.AvatarURL,
+			&fr.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan follow request: %w", err)
+		}
+		requests = append(requests, &fr)
+	}
+
+	return requests, nil
+}
+
 // GetFollowers gets users who follow the specified user.
 func (r *FollowRepository) GetFollowers(
 	ctx context.Context,
 	userID uuid.UUID,
+	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.Follow, error) {
-	rows, err := r.DB.Query(ctx, getFollowersByUserIDQuery, userID, limit, offset)
+	rows, err := r.DB.Query(ctx, getFollowersByUserIDQuery, userID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get followers: %w", err)
 	}
@@ -152,10 +237,11 @@ func (r *FollowRepository) GetFollowers(
 func (r *FollowRepository) GetFollowing(
 	ctx context.Context,
 	userID uuid.UUID,
+	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.Follow, error) {
-	rows, err := r.DB.Query(ctx, getFollowingByUserIDQuery, userID, limit, offset)
+	rows, err := r.DB.Query(ctx, getFollowingByUserIDQuery, userID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get following: %w", err)
 	}
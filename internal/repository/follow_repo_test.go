@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
 )
 
@@ -245,7 +246,7 @@ func TestGetFollowers(t *testing.T) {
 		t.Fatalf("Failed to follow: %v", err)
 	}
 
-	followers, err := repo.GetFollowers(ctx, user1ID, 10, 0)
+	followers, err := repo.GetFollowers(ctx, user1ID, user1ID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get followers: %v", err)
 	}
@@ -274,7 +275,7 @@ func TestGetFollowing(t *testing.T) {
 		t.Fatalf("Failed to follow: %v", err)
 	}
 
-	following, err := repo.GetFollowing(ctx, user1ID, 10, 0)
+	following, err := repo.GetFollowing(ctx, user1ID, user1ID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get following: %v", err)
 	}
@@ -283,3 +284,93 @@ func TestGetFollowing(t *testing.T) {
 		t.Errorf("Expected 2 following, got %d", len(following))
 	}
 }
+
+func TestFollowRequestInbox(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewFollowRepository(db)
+	ctx := context.Background()
+
+	privateID, _, _ := testutil.InsertProfile(ctx, db, "private")
+	user1ID, _, _ := testutil.InsertProfile(ctx, db, "user1")
+	user2ID, _, _ := testutil.InsertProfile(ctx, db, "user2")
+
+	_, err := db.Exec(
+		ctx,
+		"UPDATE public.profiles SET is_private_account = true WHERE id = $1",
+		privateID,
+	)
+	if err != nil {
+		t.Fatalf("Failed to make profile private: %v", err)
+	}
+
+	repo.Follow(ctx, user1ID, privateID)
+	repo.Follow(ctx, user2ID, privateID)
+
+	incoming, err := repo.GetIncomingFollowRequests(ctx, privateID, nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to get incoming follow requests: %v", err)
+	}
+	if len(incoming) != 2 {
+		t.Fatalf("Expected 2 incoming requests, got %d", len(incoming))
+	}
+	if incoming[0].Username == nil {
+		t.Error("Expected requester username to be set")
+	}
+
+	outgoing, err := repo.GetOutgoingFollowRequests(ctx, user1ID, nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to get outgoing follow requests: %v", err)
+	}
+	if len(outgoing) != 1 || outgoing[0].UserID != privateID {
+		t.Errorf("Unexpected outgoing requests: %+v", outgoing)
+	}
+
+	if err := repo.RejectFollow(ctx, user1ID, privateID); err != nil {
+		t.Fatalf("Failed to reject follow: %v", err)
+	}
+	if err := repo.RejectFollow(ctx, user1ID, privateID); !errors.Is(err, ErrFollowNotFound) {
+		t.Errorf("Expected ErrFollowNotFound on second reject, got %v", err)
+	}
+
+	accepted, err := repo.AcceptAllFollows(ctx, privateID)
+	if err != nil {
+		t.Fatalf("Failed to accept all follows: %v", err)
+	}
+	if accepted != 1 {
+		t.Errorf("Accepted mismatch: got %d, want 1", accepted)
+	}
+
+	follow, _ := repo.GetFollowStatus(ctx, user2ID, privateID)
+	if follow == nil || follow.Status != "accepted" {
+		t.Errorf("Expected user2 follow to be accepted, got %+v", follow)
+	}
+}
+
+func TestGoingPublicAcceptsPendingFollows(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewFollowRepository(db)
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+
+	privateID, _, _ := testutil.InsertProfile(ctx, db, "private")
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+
+	isPrivate := true
+	userRepo.UpdateProfile(ctx, privateID, models.UpdateProfileRequest{IsPrivateAccount: &isPrivate})
+	repo.Follow(ctx, followerID, privateID)
+
+	isPrivate = false
+	if err := userRepo.UpdateProfile(ctx, privateID, models.UpdateProfileRequest{IsPrivateAccount: &isPrivate}); err != nil {
+		t.Fatalf("Failed to update profile: %v", err)
+	}
+
+	follow, err := repo.GetFollowStatus(ctx, followerID, privateID)
+	if err != nil {
+		t.Fatalf("Failed to get follow status: %v", err)
+	}
+	if follow.Status != "accepted" {
+		t.Errorf("Status mismatch: got %v, want accepted", follow.Status)
+	}
+}
@@ -0,0 +1,96 @@
+package repository
+
+const deleteForYouCandidatesQuery = `
+  DELETE FROM public.for_you_candidates
+`
+
+// insertForYouCandidatesQuery rebuilds the candidate pool from the most engaging
+// workouts started since $1, keeping at most $2 of them.
+// Engagement counts are snapshotted so scores stay stable between refreshes.
+const insertForYouCandidatesQuery = `
+  INSERT INTO public.for_you_candidates (workout_id, user_id, started_at, likes_count, comments_count, exercise_ids)
+  SELECT
+    w.id, w.user_id, w.started_at, w.likes_count, w.comments_count,
+    COALESCE(
+      (SELECT array_agg(DISTINCT we.exercise_id) FROM public.workout_exercises we WHERE we.workout_id = w.id),
+      '{}'
+    )
+  FROM public.workouts w
+  WHERE w.started_at >= $1
+    AND w.started_at <= now()
+  ORDER BY (w.likes_count + w.comments_count) DESC, w.started_at DESC
+  LIMIT $2
+`
+
+// getForYouCandidatesQuery returns the candidates a viewer may be shown: not
+// their own, not already seen, and visible to them.
+// $1 = viewerID, $2 = max candidates
+const getForYouCandidatesQuery = `
+  SELECT c.workout_id, c.user_id, w.started_at, c.likes_count, c.comments_count, c.exercise_ids
+  FROM public.for_you_candidates c
+  JOIN public.workouts w ON w.id = c.workout_id
+  WHERE c.user_id <> $1
+    AND NOT EXISTS (
+      SELECT 1 FROM public.for_you_impressions i
+      WHERE i.user_id = $1 AND i.workout_id = c.workout_id
+    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, c.user_id, w.hidden_at)
+  ORDER BY w.started_at DESC
+  LIMIT $2
+`
+
+// getAuthorInteractionsQuery counts the viewer's likes and comments per author since $2.
+const getAuthorInteractionsQuery = `
+  SELECT author_id, count(*)
+  FROM (
+    SELECT w.user_id AS author_id
+    FROM public.workout_likes l
+    JOIN public.workouts w ON w.id = l.workout_id
+    WHERE l.user_id = $1 AND l.created_at >= $2
+
+    UNION ALL
+
+    SELECT w.user_id
+    FROM public.comments c
+    JOIN public.workouts w ON w.id = c.workout_id
+    WHERE c.user_id = $1 AND c.created_at >= $2 AND c.deleted_at IS NULL
+  ) interactions
+  WHERE author_id <> $1
+  GROUP BY author_id
+`
+
+const getAcceptedFollowingIDsQuery = `
+  SELECT following_id FROM public.follows
+  WHERE follower_id = $1 AND status = 'accepted'
+`
+
+const getRecentExerciseIDsByUserIDQuery = `
+  SELECT DISTINCT we.exercise_id
+  FROM public.workout_exercises we
+  JOIN public.workouts w ON w.id = we.workout_id
+  WHERE w.user_id = $1 AND w.started_at >= $2
+`
+
+// getTimelineWorkoutsByIDsFrom selects timeline payloads for already-ranked workouts.
+// $1 = viewerID, $2 = workout IDs
+const getTimelineWorkoutsByIDsFrom = `
+  FROM public.workouts w
+  JOIN public.profiles p ON w.user_id = p.id
+  WHERE w.id = ANY($2::uuid[])
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
+`
+
+const insertForYouImpressionsQuery = `
+  INSERT INTO public.for_you_impressions (user_id, workout_id, variant)
+  SELECT $1, w.id, $3
+  FROM public.workouts w
+  WHERE w.id = ANY($2::uuid[])
+  ON CONFLICT (user_id, workout_id) DO NOTHING
+`
+
+const deleteOldForYouImpressionsQuery = `
+  DELETE FROM public.for_you_impressions
+  WHERE seen_at < $1
+`
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/ranking"
+)
+
+const (
+	// forYouCandidateWindow is how far back the candidate pool reaches.
+	forYouCandidateWindow = 14 * 24 * time.Hour
+	// forYouMaxCandidates caps the size of the shared candidate pool.
+	forYouMaxCandidates = 5000
+	// forYouCandidatesPerViewer caps how many candidates are ranked per request.
+	forYouCandidatesPerViewer = 1000
+	// forYouPerAuthor caps how many workouts one author may have in any
+	// forYouSpreadWindow consecutive ones, which is at most that many per
+	// default-sized page.
+	forYouPerAuthor    = 2
+	forYouSpreadWindow = pagination.DefaultLimit
+	// forYouAffinityWindow is how far back the viewer's activity shapes their affinity.
+	forYouAffinityWindow = 90 * 24 * time.Hour
+	// forYouImpressionTTL is how long a seen workout stays hidden from the viewer.
+	forYouImpressionTTL = 30 * 24 * time.Hour
+)
+
+type ForYouRepository struct {
+	DB         *pgxpool.Pool
+	Experiment ranking.Experiment
+}
+
+func NewForYouRepository(db *pgxpool.Pool) *ForYouRepository {
+	return &ForYouRepository{
+		DB:         db,
+		Experiment: ranking.DefaultExperiment(),
+	}
+}
+
+// Refresh rebuilds the candidate pool and forgets old impressions. It is run
+// periodically by a background job.
+func (r *ForYouRepository) Refresh(ctx context.Context) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, deleteForYouCandidatesQuery); err != nil {
+		return fmt.Errorf("failed to clear for-you candidates: %w", err)
+	}
+	since := time.Now().Add(-forYouCandidateWindow)
+	if _, err := tx.Exec(ctx, insertForYouCandidatesQuery, since, forYouMaxCandidates); err != nil {
+		return fmt.Errorf("failed to insert for-you candidates: %w", err)
+	}
+	if _, err := tx.Exec(ctx, deleteOldForYouImpressionsQuery, time.Now().Add(-forYouImpressionTTL)); err != nil {
+		return fmt.Errorf("failed to prune for-you impressions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordImpressions marks workouts as seen by the viewer so they drop out of
+// their For-You feed. Each impression records the experiment arm that served it.
+func (r *ForYouRepository) RecordImpressions(ctx context.Context, viewerID uuid.UUID, workoutIDs []uuid.UUID) error {
+	variant := r.Experiment.Assign(viewerID).Name()
+	_, err := r.DB.Exec(ctx, insertForYouImpressionsQuery, viewerID, workoutIDs, variant)
+	if err != nil {
+		return fmt.Errorf("failed to record for-you impressions: %w", err)
+	}
+	return nil
+}
+
+// GetForYouTimelineWorkouts ranks the candidate pool for the viewer and
+// returns the page after the cursor. Each workout carries its ranking score.
+func (r *ForYouRepository) GetForYouTimelineWorkouts(
+	ctx context.Context,
+	viewerID uuid.UUID,
+	include models.TimelineInclude,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.TimelineWorkout, error) {
+	candidates, err := r.getCandidates(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []*models.TimelineWorkout{}, nil
+	}
+	affinity, err := r.getAffinity(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := ranking.Rank(candidates, affinity, r.Experiment.Assign(viewerID))
+	ranked = ranking.Spread(ranked, forYouPerAuthor, forYouSpreadWindow)
+	page := ranking.Page(ranked, cursor, limit)
+	if len(page) == 0 {
+		return []*models.TimelineWorkout{}, nil
+	}
+
+	ids := make([]uuid.UUID, len(page))
+	for i, item := range page {
+		ids[i] = item.WorkoutID
+	}
+	query := timelineWorkoutSelect(include) + getTimelineWorkoutsByIDsFrom
+	rows, err := r.DB.Query(ctx, query, viewerID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get for-you timeline workouts: %w", err)
+	}
+	defer rows.Close()
+	loaded, err := scanTimelineWorkoutRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.TimelineWorkout, len(loaded))
+	for _, w := range loaded {
+		byID[w.ID] = w
+	}
+	workouts := make([]*models.TimelineWorkout, 0, len(page))
+	for _, item := range page {
+		// A workout deleted or hidden since the pool was built is skipped
+		w, ok := byID[item.WorkoutID]
+		if !ok {
+			continue
+		}
+		w.Score = item.Score
+		// Keep the cursor consistent with the timestamp that was ranked
+		w.StartedAt = item.StartedAt
+		workouts = append(workouts, w)
+	}
+	return workouts, nil
+}
+
+func (r *ForYouRepository) getCandidates(ctx context.Context, viewerID uuid.UUID) ([]ranking.Candidate, error) {
+	rows, err := r.DB.Query(ctx, getForYouCandidatesQuery, viewerID, forYouCandidatesPerViewer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get for-you candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []ranking.Candidate
+	for rows.Next() {
+		var c ranking.Candidate
+		err := rows.Scan(&c.WorkoutID, &c.AuthorID, &c.StartedAt, &c.Likes, &c.Comments, &c.ExerciseIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan for-you candidate: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate for-you candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// getAffinity loads the signals that personalize the viewer's ranking.
+func (r *ForYouRepository) getAffinity(ctx context.Context, viewerID uuid.UUID) (ranking.Affinity, error) {
+	since := time.Now().Add(-forYouAffinityWindow)
+
+	rows, err := r.DB.Query(ctx, getAuthorInteractionsQuery, viewerID, since)
+	if err != nil {
+		return ranking.Affinity{}, fmt.Errorf("failed to get author interactions: %w", err)
+	}
+	interactions := map[uuid.UUID]int{}
+	for rows.Next() {
+		var authorID uuid.UUID
+		var n int
+		if err := rows.Scan(&authorID, &n); err != nil {
+			rows.Close()
+			return ranking.Affinity{}, fmt.Errorf("failed to scan author interaction: %w", err)
+		}
+		interactions[authorID] = n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return ranking.Affinity{}, fmt.Errorf("failed to iterate author interactions: %w", err)
+	}
+
+	following, err := r.queryIDs(ctx, getAcceptedFollowingIDsQuery, viewerID)
+	if err != nil {
+		return ranking.Affinity{}, fmt.Errorf("failed to get followed users: %w", err)
+	}
+	exercises, err := r.queryIDs(ctx, getRecentExerciseIDsByUserIDQuery, viewerID, since)
+	if err != nil {
+		return ranking.Affinity{}, fmt.Errorf("failed to get recent exercises: %w", err)
+	}
+
+	return ranking.NewAffinity(interactions, following, exercises), nil
+}
+
+func (r *ForYouRepository) queryIDs(ctx context.Context, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
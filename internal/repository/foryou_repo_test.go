@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestGetForYouTimelineWorkouts(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewForYouRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	viewerID, _, err := testutil.InsertProfile(ctx, db, "viewer")
+	if err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+	otherID, _, err := testutil.InsertProfile(ctx, db, "other")
+	if err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+
+	now := time.Now().Add(-time.Hour)
+	other, err := workoutRepo.Create(ctx, otherID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	own, err := workoutRepo.Create(ctx, viewerID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatalf("Failed to refresh candidates: %v", err)
+	}
+
+	workouts, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, models.TimelineIncludeAll, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get for-you timeline workouts: %v", err)
+	}
+	if len(workouts) != 1 || workouts[0].ID != other.ID {
+		t.Fatalf("Expected only the other user's workout, got %d workouts", len(workouts))
+	}
+	for _, w := range workouts {
+		if w.ID == own.ID {
+			t.Error("Expected the viewer's own workout to be excluded")
+		}
+	}
+}
+
+func TestGetForYouTimelineWorkouts_FreshBeatsOldViral(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewForYouRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	viewerID, _, _ := testutil.InsertProfile(ctx, db, "viewer")
+	userA, _, _ := testutil.InsertProfile(ctx, db, "usera")
+	userB, _, _ := testutil.InsertProfile(ctx, db, "userb")
+
+	old := time.Now().Add(-5 * 24 * time.Hour)
+	viral, err := workoutRepo.Create(ctx, userA, nil, nil, old, old, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	recent := time.Now().Add(-time.Hour)
+	fresh, err := workoutRepo.Create(ctx, userB, nil, nil, recent, recent, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	_, err = db.Exec(ctx, "UPDATE public.workouts SET likes_count = 20 WHERE id = $1", viral.ID)
+	if err != nil {
+		t.Fatalf("Failed to update workout: %v", err)
+	}
+	_, err = db.Exec(ctx, "UPDATE public.workouts SET likes_count = 2 WHERE id = $1", fresh.ID)
+	if err != nil {
+		t.Fatalf("Failed to update workout: %v", err)
+	}
+
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatalf("Failed to refresh candidates: %v", err)
+	}
+
+	workouts, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, models.TimelineIncludeAll, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get for-you timeline workouts: %v", err)
+	}
+	if len(workouts) != 2 {
+		t.Fatalf("Expected 2 workouts, got %d", len(workouts))
+	}
+	if workouts[0].ID != fresh.ID {
+		t.Errorf("Expected the fresh workout first, got %v", workouts[0].ID)
+	}
+	if workouts[0].Score <= workouts[1].Score {
+		t.Errorf("Expected descending scores, got %v then %v", workouts[0].Score, workouts[1].Score)
+	}
+}
+
+func TestGetForYouTimelineWorkouts_CapsPerAuthor(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewForYouRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	viewerID, _, _ := testutil.InsertProfile(ctx, db, "viewer")
+	prolificID, _, _ := testutil.InsertProfile(ctx, db, "prolific")
+
+	for i := 0; i < forYouPerAuthor+2; i++ {
+		at := time.Now().Add(-time.Duration(i+1) * time.Hour)
+		if _, err := workoutRepo.Create(ctx, prolificID, nil, nil, at, at, 0); err != nil {
+			t.Fatalf("Failed to create workout: %v", err)
+		}
+	}
+	// Older workouts by others, enough to fill a page
+	for i := 0; i < forYouSpreadWindow; i++ {
+		otherID, _, _ := testutil.InsertProfile(ctx, db, fmt.Sprintf("other%d", i))
+		at := time.Now().Add(-time.Duration(i+24) * time.Hour)
+		if _, err := workoutRepo.Create(ctx, otherID, nil, nil, at, at, 0); err != nil {
+			t.Fatalf("Failed to create workout: %v", err)
+		}
+	}
+
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatalf("Failed to refresh candidates: %v", err)
+	}
+
+	workouts, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, models.TimelineIncludeAll, nil, forYouSpreadWindow)
+	if err != nil {
+		t.Fatalf("Failed to get for-you timeline workouts: %v", err)
+	}
+	prolific := 0
+	for _, w := range workouts {
+		if w.UserID == prolificID {
+			prolific++
+		}
+	}
+	if prolific != forYouPerAuthor {
+		t.Errorf("Expected %d workouts from one author, got %d", forYouPerAuthor, prolific)
+	}
+
+	// The capped workouts are deferred to later pages, not dropped
+	cursor := pagination.Scored(workouts[len(workouts)-1].Score, workouts[len(workouts)-1].StartedAt, workouts[len(workouts)-1].ID)
+	rest, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, models.TimelineIncludeAll, &cursor, forYouSpreadWindow)
+	if err != nil {
+		t.Fatalf("Failed to get the next page: %v", err)
+	}
+	for _, w := range rest {
+		if w.UserID == prolificID {
+			prolific++
+		}
+	}
+	if prolific != forYouPerAuthor+2 {
+		t.Errorf("Expected all %d workouts from the author across pages, got %d", forYouPerAuthor+2, prolific)
+	}
+}
+
+func TestRecordImpressionsHidesSeenWorkouts(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewForYouRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	viewerID, _, _ := testutil.InsertProfile(ctx, db, "viewer")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
+
+	now := time.Now().Add(-time.Hour)
+	seen, err := workoutRepo.Create(ctx, otherID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	unseen, err := workoutRepo.Create(ctx, otherID, nil, nil, now.Add(-time.Minute), now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatalf("Failed to refresh candidates: %v", err)
+	}
+	// Unknown IDs are ignored rather than failing the batch
+	if err := repo.RecordImpressions(ctx, viewerID, []uuid.UUID{seen.ID, uuid.New()}); err != nil {
+		t.Fatalf("Failed to record impressions: %v", err)
+	}
+	// Recording twice is a no-op
+	if err := repo.RecordImpressions(ctx, viewerID, []uuid.UUID{seen.ID}); err != nil {
+		t.Fatalf("Failed to record impressions again: %v", err)
+	}
+
+	workouts, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, models.TimelineIncludeAll, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get for-you timeline workouts: %v", err)
+	}
+	if len(workouts) != 1 || workouts[0].ID != unseen.ID {
+		t.Errorf("Expected only the unseen workout, got %d workouts", len(workouts))
+	}
+
+	var variant string
+	err = db.QueryRow(ctx, "SELECT variant FROM public.for_you_impressions WHERE user_id = $1 AND workout_id = $2", viewerID, seen.ID).Scan(&variant)
+	if err != nil {
+		t.Fatalf("Failed to read impression: %v", err)
+	}
+	if variant != repo.Experiment.Assign(viewerID).Name() {
+		t.Errorf("Expected impression variant %q, got %q", repo.Experiment.Assign(viewerID).Name(), variant)
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+// A workout is tagged if its caption carries the tag or a visible comment on it does.
+const getWorkoutsByHashtagQuery = `
+  SELECT 
+    w.id, w.user_id, w.routine_id, w.name, w.comment, w.started_at, w.ended_at, 
+    w.duration_seconds, w.total_weight, w.likes_count, w.comments_count, 
+    w.created_at, w.updated_at
+  FROM public.workouts w
+  WHERE (
+      EXISTS (
+        SELECT 1 FROM public.workout_hashtags wh
+        WHERE wh.workout_id = w.id AND wh.tag = $1
+      )
+      OR EXISTS (
+        SELECT 1 FROM public.comment_hashtags ch
+        JOIN public.comments c ON c.id = ch.comment_id
+        WHERE c.workout_id = w.id
+          AND ch.tag = $1
+          AND c.deleted_at IS NULL
+          -- Ghost Filter: Ignore tags from blocked users' comments
+          AND NOT public.is_blocked_between(c.user_id, $2)
+          -- Account Filter: Ignore tags from suspended and banned users' comments
+          AND public.is_account_active(c.user_id)
+          -- Moderation Filter: Ignore tags from comments taken down by a moderator
+          AND NOT public.is_hidden_from($2, c.user_id, c.hidden_at)
+      )
+    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($2, w.user_id, w.hidden_at)
+    -- Keyset Cursor: resume after the last workout of the previous page
+    AND ($3::timestamptz IS NULL OR (w.started_at, w.id) < ($3::timestamptz, $4::uuid))
+  ORDER BY w.started_at DESC, w.id DESC
+  LIMIT $5
+`
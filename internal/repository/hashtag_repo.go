@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type HashtagRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewHashtagRepository(db *pgxpool.Pool) *HashtagRepository {
+	return &HashtagRepository{
+		DB: db,
+	}
+}
+
+// GetWorkoutsByHashtag lists workouts the viewer can see that are tagged with
+// tag, newest first. The tag is matched case-insensitively, with or without '#'.
+func (r *HashtagRepository) GetWorkoutsByHashtag(
+	ctx context.Context,
+	tag string,
+	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.Workout, error) {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+
+	rows, err := r.DB.Query(ctx, getWorkoutsByHashtagQuery, tag, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workouts by hashtag: %w", err)
+	}
+	defer rows.Close()
+
+	var workouts []*models.Workout
+	for rows.Next() {
+		var workout models.Workout
+		err := rows.Scan(
+			&workout.ID,
+			&workout.UserID,
+			&workout.RoutineID,
+			&workout.Name,
+			&workout.Comment,
+			&workout.StartedAt,
+			&workout.EndedAt,
+			&workout.DurationSeconds,
+			&workout.TotalWeight,
+			&workout.LikesCount,
+			&workout.CommentsCount,
+			&workout.CreatedAt,
+			&workout.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan workout: %w", err)
+		}
+		workouts = append(workouts, &workout)
+	}
+
+	return workouts, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestGetWorkoutsByHashtag(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	hashtagRepo := NewHashtagRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	ownerID, _, _ := testutil.InsertProfile(ctx, db, "owner")
+	privateID, _, _ := testutil.InsertProfile(ctx, db, "private")
+	viewerID, _, _ := testutil.InsertProfile(ctx, db, "viewer")
+	_, _ = db.Exec(ctx, "UPDATE public.profiles SET is_private_account = true WHERE id = $1", privateID)
+
+	caption := "#LegDay done"
+	tagged, _ := workoutRepo.Create(ctx, ownerID, nil, &caption, time.Now(), time.Now(), 0)
+	untagged, _ := workoutRepo.Create(ctx, ownerID, nil, nil, time.Now().Add(-time.Hour), time.Now(), 0)
+	_, _ = workoutRepo.Create(ctx, privateID, nil, &caption, time.Now(), time.Now(), 0)
+
+	if _, err := commentRepo.CreateComment(ctx, viewerID, untagged.ID, nil, "Should have been #legday"); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	workouts, err := hashtagRepo.GetWorkoutsByHashtag(ctx, "#LEGDAY", viewerID, nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to get workouts by hashtag: %v", err)
+	}
+	// The private account's workout is hidden from a non-follower
+	if len(workouts) != 2 {
+		t.Fatalf("Expected 2 workouts, got %d", len(workouts))
+	}
+	if workouts[0].ID != tagged.ID || workouts[1].ID != untagged.ID {
+		t.Errorf("Unexpected order: got %v, %v", workouts[0].ID, workouts[1].ID)
+	}
+
+	// Editing the tag out of the caption removes the workout from the feed
+	edited := "done"
+	if err := workoutRepo.UpdateWorkout(ctx, tagged.ID, models.UpdateWorkoutRequest{Comment: &edited}, ownerID); err != nil {
+		t.Fatalf("Failed to update workout: %v", err)
+	}
+	workouts, _ = hashtagRepo.GetWorkoutsByHashtag(ctx, "legday", viewerID, nil, 10)
+	if len(workouts) != 1 {
+		t.Errorf("Expected 1 workout after edit, got %d", len(workouts))
+	}
+}
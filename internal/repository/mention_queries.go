@@ -0,0 +1,39 @@
+package repository
+
+const getMentionsByUserIDQuery = `
+  SELECT author_id, username, display_name, avatar_url, workout_id, comment_id, content, created_at
+  FROM (
+    SELECT author.id AS author_id, author.username, author.display_name, author.avatar_url,
+           w.id AS workout_id, NULL::uuid AS comment_id, w.comment AS content, m.created_at
+    FROM public.workout_mentions m
+    JOIN public.workouts w ON w.id = m.workout_id
+    JOIN public.profiles author ON author.id = w.user_id
+    WHERE m.user_id = $1
+      -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+      AND public.can_view_content($1, w.user_id, w.hidden_at)
+
+    UNION ALL
+
+    SELECT author.id, author.username, author.display_name, author.avatar_url,
+           w.id, c.id, c.content, m.created_at
+    FROM public.comment_mentions m
+    JOIN public.comments c ON c.id = m.comment_id
+    JOIN public.workouts w ON w.id = c.workout_id
+    JOIN public.profiles author ON author.id = c.user_id
+    WHERE m.user_id = $1
+      AND c.deleted_at IS NULL
+      -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+      AND public.can_view_content($1, w.user_id, w.hidden_at)
+      -- Ghost Filter: Hide mentions by blocked users
+      AND NOT public.is_blocked_between(c.user_id, $1)
+      -- Account Filter: Hide mentions by suspended and banned users
+      AND public.is_account_active(c.user_id)
+      -- Moderation Filter: Hide mentions in comments taken down by a moderator
+      AND NOT public.is_hidden_from($1, c.user_id, c.hidden_at)
+  ) mentions
+  -- Keyset Cursor: resume after the last mention of the previous page
+  WHERE $2::timestamptz IS NULL
+    OR (created_at, COALESCE(comment_id, workout_id)) < ($2::timestamptz, $3::uuid)
+  ORDER BY created_at DESC, COALESCE(comment_id, workout_id) DESC
+  LIMIT $4
+`
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type MentionRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewMentionRepository(db *pgxpool.Pool) *MentionRepository {
+	return &MentionRepository{
+		DB: db,
+	}
+}
+
+// GetMentions lists the workouts and comments that mention the user, newest
+// first, skipping any the user can no longer see.
+func (r *MentionRepository) GetMentions(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.Mention, error) {
+	rows, err := r.DB.Query(ctx, getMentionsByUserIDQuery, userID, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var mentions []*models.Mention
+	for rows.Next() {
+		var m models.Mention
+		err := rows.Scan(
+			&m.AuthorID,
+			&m.Username,
+			&m.DisplayName,
+			&m.AvatarURL,
+			&m.WorkoutID,
+			&m.CommentID,
+			&m.Content,
+			&m.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions = append(mentions, &m)
+	}
+
+	return mentions, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestGetMentions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	mentionRepo := NewMentionRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	aliceID, _, _ := testutil.InsertProfile(ctx, db, "Alice")
+
+	caption := "Squats with @alice and @nobody #LegDay"
+	workout, err := workoutRepo.Create(ctx, authorID, nil, &caption, time.Now(), time.Now(), 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	comment, err := commentRepo.CreateComment(ctx, authorID, workout.ID, nil, "Nice one @ALICE")
+	if err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	mentions, err := mentionRepo.GetMentions(ctx, aliceID, nil, 10)
+	if err != nil {
+		t.Fatalf("Failed to get mentions: %v", err)
+	}
+	if len(mentions) != 2 {
+		t.Fatalf("Expected 2 mentions, got %d", len(mentions))
+	}
+	// Newest first: the comment was posted after the workout
+	if mentions[0].CommentID == nil || *mentions[0].CommentID != comment.ID {
+		t.Errorf("Expected comment mention first, got %+v", mentions[0])
+	}
+	if mentions[1].CommentID != nil || mentions[1].WorkoutID != workout.ID {
+		t.Errorf("Expected caption mention second, got %+v", mentions[1])
+	}
+
+	// Self-mentions are ignored
+	authorMentions, _ := mentionRepo.GetMentions(ctx, authorID, nil, 10)
+	if len(authorMentions) != 0 {
+		t.Errorf("Expected no self-mentions, got %d", len(authorMentions))
+	}
+}
+
+func TestMentionsFollowEdits(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	mentionRepo := NewMentionRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	aliceID, _, _ := testutil.InsertProfile(ctx, db, "alice")
+	bobID, _, _ := testutil.InsertProfile(ctx, db, "bob")
+
+	workout, _ := workoutRepo.Create(ctx, authorID, nil, nil, time.Now(), time.Now(), 0)
+	comment, _ := commentRepo.CreateComment(ctx, authorID, workout.ID, nil, "Hey @alice")
+
+	if _, err := commentRepo.UpdateComment(ctx, comment.ID, authorID, "Hey @bob"); err != nil {
+		t.Fatalf("Failed to update comment: %v", err)
+	}
+	aliceMentions, _ := mentionRepo.GetMentions(ctx, aliceID, nil, 10)
+	if len(aliceMentions) != 0 {
+		t.Errorf("Expected alice's mention to be removed by the edit, got %d", len(aliceMentions))
+	}
+	bobMentions, _ := mentionRepo.GetMentions(ctx, bobID, nil, 10)
+	if len(bobMentions) != 1 {
+		t.Errorf("Expected bob to be mentioned after the edit, got %d", len(bobMentions))
+	}
+
+	caption := "With @alice"
+	err := workoutRepo.UpdateWorkout(ctx, workout.ID, models.UpdateWorkoutRequest{Comment: &caption}, authorID)
+	if err != nil {
+		t.Fatalf("Failed to update workout: %v", err)
+	}
+	aliceMentions, _ = mentionRepo.GetMentions(ctx, aliceID, nil, 10)
+	if len(aliceMentions) != 1 {
+		t.Errorf("Expected alice to be mentioned in the caption, got %d", len(aliceMentions))
+	}
+
+	empty := ""
+	_ = workoutRepo.UpdateWorkout(ctx, workout.ID, models.UpdateWorkoutRequest{Comment: &empty}, authorID)
+	aliceMentions, _ = mentionRepo.GetMentions(ctx, aliceID, nil, 10)
+	if len(aliceMentions) != 0 {
+		t.Errorf("Expected clearing the caption to remove the mention, got %d", len(aliceMentions))
+	}
+}
+
+func TestMentionsRespectBlocks(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	workoutRepo := NewWorkoutRepository(db)
+	blockRepo := NewBlockedUserRepository(db)
+	ctx := context.Background()
+
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	aliceID, _, _ := testutil.InsertProfile(ctx, db, "alice")
+
+	if _, err := blockRepo.Block(ctx, aliceID, authorID); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+
+	caption := "Hi @alice"
+	if _, err := workoutRepo.Create(ctx, authorID, nil, &caption, time.Now(), time.Now(), 0); err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+
+	var count int
+	_ = db.QueryRow(ctx, "SELECT count(*) FROM public.workout_mentions WHERE user_id = $1", aliceID).Scan(&count)
+	if count != 0 {
+		t.Errorf("Expected mention across a block to be dropped, got %d rows", count)
+	}
+}
@@ -34,4 +34,5 @@ const deleteMuscleQuery = `
       -- Guard: Only allow deletion if the requester is in the sys_admins table
       SELECT 1 FROM public.sys_admins WHERE user_id = $2
   )
+  RETURNING name
 `
@@ -85,14 +85,22 @@ func (r *MuscleRepository) GetMuscleByName(ctx context.Context, name string) (*m
 	return &muscle, nil
 }
 
+// CreateMuscle adds a muscle to the global library. Only sys admins may, and
+// the change is written to the admin audit log.
 func (r *MuscleRepository) CreateMuscle(
 	ctx context.Context,
 	name string,
 	userID uuid.UUID,
 ) (*models.Muscle, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var muscle models.Muscle
 
-	err := r.DB.QueryRow(ctx, createMuscleQuery, name, userID).Scan(
+	err = tx.QueryRow(ctx, createMuscleQuery, name, userID).Scan(
 		&muscle.ID,
 		&muscle.Name,
 		&muscle.CreatedAt,
@@ -113,35 +121,55 @@ func (r *MuscleRepository) CreateMuscle(
 		return nil, fmt.Errorf("failed to create muscle: %w", err)
 	}
 
+	err = audit(ctx, tx, userID, models.AuditCreateMuscle, models.AuditTargetMuscle, muscle.ID, map[string]any{
+		"name": muscle.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &muscle, nil
 }
 
+// DeleteMuscle removes a muscle from the global library. Only sys admins
+// may, and the change is written to the admin audit log.
 func (r *MuscleRepository) DeleteMuscle(
 	ctx context.Context,
 	id uuid.UUID,
 	userID uuid.UUID, // The ID of the person trying to delete
 ) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	// Execute the query passing the Muscle ID and the Requester's ID
-	res, err := r.DB.Exec(ctx, deleteMuscleQuery, id, userID)
+	var name string
+	err = tx.QueryRow(ctx, deleteMuscleQuery, id, userID).Scan(&name)
 	if err != nil {
+		// If no rows were affected, the ID didn't exist OR the user isn't an admin
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUnauthorizedAction
+		}
 		// Check for Foreign Key violations (e.g., this muscle is still linked to an exercise)
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			switch pgErr.Code {
-			case "23505": // Unique Violation
-				return ErrAlreadyExists
-			case "23503": // Foreign Key Violation
-				return ErrReferenceViolation
-			}
-			return fmt.Errorf("failed to create muscle: %w", err)
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrReferenceViolation
 		}
-
+		return fmt.Errorf("failed to delete muscle: %w", err)
 	}
 
-	// If no rows were affected, the ID didn't exist OR the user isn't an admin
-	if res.RowsAffected() == 0 {
-		return ErrUnauthorizedAction
+	err = audit(ctx, tx, userID, models.AuditDeleteMuscle, models.AuditTargetMuscle, id, map[string]any{
+		"name": name,
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,84 @@
+package repository
+
+// getNotificationsQuery folds the recipient's notifications into one entry per
+// group, headed by the group's latest event. Events from users now blocked in
+// either direction or now disabled, or about content a moderator hid, are
+// left out.
+// $1 = userID, $2 = cursor created_at, $3 = cursor id, $4 = limit
+const getNotificationsQuery = `
+  WITH visible AS (
+    SELECT n.*
+    FROM public.notifications n
+    WHERE n.user_id = $1
+      AND NOT public.is_blocked_between(n.actor_id, $1)
+      -- Account Filter: Hide events from suspended and banned users
+      AND public.is_account_active(n.actor_id)
+      -- Moderation Filter: Hide events about content taken down by a moderator
+      AND NOT EXISTS (
+        SELECT 1 FROM public.workouts hw
+        WHERE hw.id = n.workout_id AND public.is_hidden_from($1, hw.user_id, hw.hidden_at)
+      )
+      AND NOT EXISTS (
+        SELECT 1 FROM public.comments hc
+        WHERE hc.id = n.comment_id AND public.is_hidden_from($1, hc.user_id, hc.hidden_at)
+      )
+  ),
+  latest AS (
+    SELECT DISTINCT ON (v.group_key)
+      v.group_key, v.id, v.type, v.actor_id, v.workout_id, v.comment_id, v.created_at
+    FROM visible v
+    ORDER BY v.group_key, v.created_at DESC, v.id DESC
+  ),
+  totals AS (
+    SELECT v.group_key, count(DISTINCT v.actor_id) AS actor_count, bool_and(v.read_at IS NOT NULL) AS is_read
+    FROM visible v
+    GROUP BY v.group_key
+  )
+  SELECT
+    l.id, l.type, l.actor_id, p.username, p.display_name, p.avatar_url,
+    t.actor_count, l.workout_id, l.comment_id, t.is_read, l.created_at
+  FROM latest l
+  JOIN totals t ON t.group_key = l.group_key
+  JOIN public.profiles p ON p.id = l.actor_id
+  -- Keyset Cursor: resume after the last entry of the previous page
+  WHERE ($2::timestamptz IS NULL OR (l.created_at, l.id) < ($2::timestamptz, $3::uuid))
+  ORDER BY l.created_at DESC, l.id DESC
+  LIMIT $4
+`
+
+// countUnreadNotificationsQuery counts unread inbox entries, not events.
+const countUnreadNotificationsQuery = `
+  SELECT count(DISTINCT n.group_key)
+  FROM public.notifications n
+  WHERE n.user_id = $1
+    AND n.read_at IS NULL
+    AND NOT public.is_blocked_between(n.actor_id, $1)
+    AND public.is_account_active(n.actor_id)
+    AND NOT EXISTS (
+      SELECT 1 FROM public.workouts hw
+      WHERE hw.id = n.workout_id AND public.is_hidden_from($1, hw.user_id, hw.hidden_at)
+    )
+    AND NOT EXISTS (
+      SELECT 1 FROM public.comments hc
+      WHERE hc.id = n.comment_id AND public.is_hidden_from($1, hc.user_id, hc.hidden_at)
+    )
+`
+
+// markNotificationsReadQuery marks the whole group of each given entry as read.
+const markNotificationsReadQuery = `
+  UPDATE public.notifications
+  SET read_at = now()
+  WHERE user_id = $1
+    AND read_at IS NULL
+    AND group_key IN (
+      SELECT group_key FROM public.notifications
+      WHERE user_id = $1 AND id = ANY($2::uuid[])
+    )
+`
+
+const markAllNotificationsReadQuery = `
+  UPDATE public.notifications
+  SET read_at = now()
+  WHERE user_id = $1
+    AND read_at IS NULL
+`
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type NotificationRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{
+		DB: db,
+	}
+}
+
+// GetNotifications lists the user's inbox entries, newest first.
+func (r *NotificationRepository) GetNotifications(
+	ctx context.Context,
+	userID uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.Notification, error) {
+	rows, err := r.DB.Query(ctx, getNotificationsQuery, userID, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.Type,
+			&n.Actor.ID,
+			&n.Actor.Username,
+			&n.Actor.DisplayName,
+			&n.Actor.AvatarURL,
+			&n.ActorCount,
+			&n.WorkoutID,
+			&n.CommentID,
+			&n.IsRead,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		name := ""
+		if n.Actor.Username != nil {
+			name = *n.Actor.Username
+		}
+		n.Message = models.NotificationMessage(n.Type, name, n.ActorCount-1)
+		notifications = append(notifications, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread returns how many inbox entries have unread events.
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	if err := r.DB.QueryRow(ctx, countUnreadNotificationsQuery, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks the given inbox entries, and every event folded into them,
+// as read. It returns the number of events marked.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int64, error) {
+	commandTag, err := r.DB.Exec(ctx, markNotificationsReadQuery, userID, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+// MarkAllRead marks every notification of the user as read.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	commandTag, err := r.DB.Exec(ctx, markAllNotificationsReadQuery, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark all notifications read: %w", err)
+	}
+	return commandTag.RowsAffected(), nil
+}
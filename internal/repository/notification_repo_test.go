@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestNotificationsAggregateLikes(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	repo := NewNotificationRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	likeRepo := NewWorkoutLikeRepository(db)
+
+	ownerID, _, _ := testutil.InsertProfile(ctx, db, "owner")
+	alexID, _, _ := testutil.InsertProfile(ctx, db, "alex")
+	samID, _, _ := testutil.InsertProfile(ctx, db, "sam")
+
+	now := time.Now()
+	workout, err := workoutRepo.Create(ctx, ownerID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	for _, likerID := range []uuid.UUID{samID, alexID, ownerID} {
+		if _, err := likeRepo.LikeWorkout(ctx, likerID, workout.ID); err != nil {
+			t.Fatalf("Failed to like workout: %v", err)
+		}
+	}
+
+	notifications, err := repo.GetNotifications(ctx, ownerID, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get notifications: %v", err)
+	}
+	if len(notifications) != 1 {
+		t.Fatalf("Expected likes folded into 1 entry, got %d", len(notifications))
+	}
+	n := notifications[0]
+	if n.Type != models.NotificationWorkoutLike || n.ActorCount != 2 {
+		t.Errorf("Expected a workout_like entry with 2 actors (self-like ignored), got %q with %d", n.Type, n.ActorCount)
+	}
+	if n.Actor.ID != alexID {
+		t.Errorf("Expected the latest liker to head the entry, got %v", n.Actor.ID)
+	}
+	if n.Message != "alex and 1 other liked your workout" {
+		t.Errorf("Unexpected message %q", n.Message)
+	}
+
+	unread, err := repo.CountUnread(ctx, ownerID)
+	if err != nil {
+		t.Fatalf("Failed to count unread: %v", err)
+	}
+	if unread != 1 {
+		t.Errorf("Expected 1 unread entry, got %d", unread)
+	}
+
+	marked, err := repo.MarkRead(ctx, ownerID, []uuid.UUID{n.ID})
+	if err != nil {
+		t.Fatalf("Failed to mark read: %v", err)
+	}
+	if marked != 2 {
+		t.Errorf("Expected both events in the group marked, got %d", marked)
+	}
+	unread, _ = repo.CountUnread(ctx, ownerID)
+	if unread != 0 {
+		t.Errorf("Expected no unread entries, got %d", unread)
+	}
+
+	// Unliking withdraws the event
+	if err := likeRepo.UnlikeWorkout(ctx, alexID, workout.ID); err != nil {
+		t.Fatalf("Failed to unlike: %v", err)
+	}
+	notifications, _ = repo.GetNotifications(ctx, ownerID, nil, 20)
+	if len(notifications) != 1 || notifications[0].ActorCount != 1 || notifications[0].Actor.ID != samID {
+		t.Errorf("Expected only sam's like to remain")
+	}
+}
+
+func TestNotificationsHonorSettings(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	repo := NewNotificationRepository(db)
+	followRepo := NewFollowRepository(db)
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "quiet")
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+	_, err := db.Exec(ctx, `
+		INSERT INTO public.user_settings (user_id, notify_new_follower) VALUES ($1, false)
+		ON CONFLICT (user_id) DO UPDATE SET notify_new_follower = false`, userID)
+	if err != nil {
+		t.Fatalf("Failed to update settings: %v", err)
+	}
+
+	if _, err := followRepo.Follow(ctx, followerID, userID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+
+	notifications, err := repo.GetNotifications(ctx, userID, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get notifications: %v", err)
+	}
+	if len(notifications) != 0 {
+		t.Errorf("Expected no follow notification with notify_new_follower off, got %d", len(notifications))
+	}
+}
+
+func TestNotificationsFollowRequestAndBlocks(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	repo := NewNotificationRepository(db)
+	followRepo := NewFollowRepository(db)
+	blockRepo := NewBlockedUserRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+
+	privateID, _, _ := testutil.InsertProfile(ctx, db, "private")
+	requesterID, _, _ := testutil.InsertProfile(ctx, db, "requester")
+	commenterID, _, _ := testutil.InsertProfile(ctx, db, "commenter")
+	_, err := db.Exec(ctx, "UPDATE public.profiles SET is_private_account = true WHERE id = $1", privateID)
+	if err != nil {
+		t.Fatalf("Failed to make profile private: %v", err)
+	}
+
+	if _, err := followRepo.Follow(ctx, requesterID, privateID); err != nil {
+		t.Fatalf("Failed to request follow: %v", err)
+	}
+	notifications, _ := repo.GetNotifications(ctx, privateID, nil, 20)
+	if len(notifications) != 1 || notifications[0].Type != models.NotificationFollowRequest {
+		t.Fatalf("Expected a follow_request notification, got %d entries", len(notifications))
+	}
+
+	// Accepting clears the request from the inbox
+	if err := followRepo.AcceptFollow(ctx, requesterID, privateID); err != nil {
+		t.Fatalf("Failed to accept: %v", err)
+	}
+	notifications, _ = repo.GetNotifications(ctx, privateID, nil, 20)
+	if len(notifications) != 0 {
+		t.Errorf("Expected the accepted request to leave the inbox, got %d entries", len(notifications))
+	}
+
+	// A comment from a user who is later blocked disappears
+	_, err = db.Exec(ctx, "UPDATE public.profiles SET is_private_account = false WHERE id = $1", privateID)
+	if err != nil {
+		t.Fatalf("Failed to make profile public: %v", err)
+	}
+	now := time.Now()
+	workout, err := workoutRepo.Create(ctx, privateID, nil, nil, now, now, 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	if _, err := commentRepo.CreateComment(ctx, commenterID, workout.ID, nil, "nice"); err != nil {
+		t.Fatalf("Failed to comment: %v", err)
+	}
+	notifications, _ = repo.GetNotifications(ctx, privateID, nil, 20)
+	if len(notifications) != 1 || notifications[0].Type != models.NotificationComment {
+		t.Fatalf("Expected a comment notification, got %d entries", len(notifications))
+	}
+	if _, err := blockRepo.Block(ctx, privateID, commenterID); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+	notifications, _ = repo.GetNotifications(ctx, privateID, nil, 20)
+	if len(notifications) != 0 {
+		t.Errorf("Expected notifications from a blocked user to be hidden, got %d", len(notifications))
+	}
+}
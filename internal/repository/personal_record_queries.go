@@ -0,0 +1,136 @@
+package repository
+
+// The (user, exercise) pair that owns the given workout exercise.
+const getWorkoutExerciseOwnerQuery = `
+  SELECT w.user_id, we.exercise_id
+  FROM public.workout_exercises we
+  JOIN public.workouts w ON we.workout_id = w.id
+  WHERE we.id = $1
+`
+
+// The distinct exercises logged in a workout.
+const getWorkoutExerciseIDsQuery = `
+  SELECT DISTINCT exercise_id
+  FROM public.workout_exercises
+  WHERE workout_id = $1
+`
+
+// Removes every PR event for the (user, exercise) pair.
+const deletePersonalRecordsByExerciseQuery = `
+  DELETE FROM public.personal_records
+  WHERE user_id = $1
+    AND exercise_id = $2
+`
+
+// Rebuilds the PR events for the (user, exercise) pair.
+// For each session the best set per record type is compared against the best of all earlier
+// sessions; it is a PR when nothing came before or it beats everything that did.
+// Estimated 1RM uses Brzycki up to 10 reps and Epley above (the two agree at 10 reps).
+const insertPersonalRecordsByExerciseQuery = `
+  WITH target AS (
+    SELECT $1::uuid AS user_id, $2::uuid AS exercise_id
+  ),
+  performed AS (
+    SELECT
+      ws.id AS set_id,
+      w.id AS workout_id,
+      w.started_at,
+      ws.weight,
+      ws.reps,
+      CASE
+        WHEN ws.reps = 1 THEN ws.weight
+        WHEN ws.reps <= 10 THEN ws.weight * 36 / (37 - ws.reps)
+        ELSE ws.weight * (1 + ws.reps / 30.0)
+      END AS e1rm,
+      ROW_NUMBER() OVER (
+        PARTITION BY w.id ORDER BY we.order_index, ws.order_index, ws.created_at, ws.id
+      ) AS seq
+    FROM target t
+    JOIN public.workouts w ON w.user_id = t.user_id
+    JOIN public.workout_exercises we ON we.workout_id = w.id AND we.exercise_id = t.exercise_id
+    JOIN public.workout_sets ws ON ws.workout_exercise_id = we.id
+    WHERE ws.weight > 0 AND ws.reps > 0
+  ),
+  -- Best set of each session, per record type
+  best_weight AS (
+    SELECT DISTINCT ON (workout_id) workout_id, started_at, set_id, weight, reps, weight AS value
+    FROM performed
+    ORDER BY workout_id, weight DESC, reps DESC, seq
+  ),
+  best_e1rm AS (
+    SELECT DISTINCT ON (workout_id) workout_id, started_at, set_id, weight, reps, e1rm AS value
+    FROM performed
+    ORDER BY workout_id, e1rm DESC, seq
+  ),
+  best_reps AS (
+    SELECT DISTINCT ON (workout_id, weight) workout_id, started_at, set_id, weight, reps, reps::numeric AS value
+    FROM performed
+    ORDER BY workout_id, weight, reps DESC, seq
+  ),
+  best_volume AS (
+    SELECT workout_id, MIN(started_at) AS started_at, NULL::uuid AS set_id, NULL::numeric AS weight,
+           NULL::integer AS reps, SUM(weight * reps) AS value
+    FROM performed
+    GROUP BY workout_id
+  ),
+  candidates AS (
+    SELECT 'max_weight' AS record_type, NULL::numeric AS rep_weight, * FROM best_weight
+    UNION ALL
+    SELECT 'estimated_1rm', NULL, * FROM best_e1rm
+    UNION ALL
+    SELECT 'max_reps', weight, * FROM best_reps
+    UNION ALL
+    SELECT 'max_volume', NULL, * FROM best_volume
+  ),
+  ranked AS (
+    SELECT c.*,
+      MAX(c.value) OVER (
+        PARTITION BY c.record_type, c.rep_weight
+        ORDER BY c.started_at, c.workout_id
+        ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+      ) AS previous_best
+    FROM candidates c
+  )
+  INSERT INTO public.personal_records
+    (user_id, exercise_id, workout_id, workout_set_id, record_type, weight, reps, value, achieved_at)
+  SELECT t.user_id, t.exercise_id, r.workout_id, r.set_id, r.record_type, r.weight, r.reps, r.value, r.started_at
+  FROM ranked r
+  CROSS JOIN target t
+  WHERE r.previous_best IS NULL OR r.value > r.previous_best
+`
+
+// Current bests: the highest-valued event per exercise and record type
+// (and per weight for max_reps).
+const getPersonalRecordsByUserIDQuery = `
+  SELECT id, user_id, exercise_id, exercise_name, workout_id, workout_set_id,
+         record_type, weight, reps, value, achieved_at
+  FROM (
+    SELECT DISTINCT ON (pr.exercise_id, pr.record_type, CASE WHEN pr.record_type = 'max_reps' THEN pr.weight END)
+      pr.id, pr.user_id, pr.exercise_id, e.name AS exercise_name, pr.workout_id, pr.workout_set_id,
+      pr.record_type, pr.weight, pr.reps, pr.value, pr.achieved_at
+    FROM public.personal_records pr
+    JOIN public.exercises e ON pr.exercise_id = e.id
+    WHERE pr.user_id = $1
+      AND ($3::uuid IS NULL OR pr.exercise_id = $3)
+      -- Visibility Policy: viewer may see the owner's content (see can_view_user)
+      AND public.can_view_user($2, pr.user_id)
+    ORDER BY pr.exercise_id, pr.record_type, CASE WHEN pr.record_type = 'max_reps' THEN pr.weight END,
+             pr.value DESC, pr.achieved_at ASC
+  ) best
+  ORDER BY exercise_name ASC, record_type ASC, weight ASC NULLS FIRST
+`
+
+const getPersonalRecordHistoryByUserIDQuery = `
+  SELECT pr.id, pr.user_id, pr.exercise_id, e.name, pr.workout_id, pr.workout_set_id,
+         pr.record_type, pr.weight, pr.reps, pr.value, pr.achieved_at
+  FROM public.personal_records pr
+  JOIN public.exercises e ON pr.exercise_id = e.id
+  WHERE pr.user_id = $1
+    AND ($3::uuid IS NULL OR pr.exercise_id = $3)
+    -- Visibility Policy: viewer may see the owner's content (see can_view_user)
+    AND public.can_view_user($2, pr.user_id)
+    -- Keyset Cursor: resume after the last record of the previous page
+    AND ($4::timestamptz IS NULL OR (pr.achieved_at, pr.id) < ($4::timestamptz, $5::uuid))
+  ORDER BY pr.achieved_at DESC, pr.id DESC
+  LIMIT $6
+`
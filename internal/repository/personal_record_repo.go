@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+)
+
+type PersonalRecordRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewPersonalRecordRepository(db *pgxpool.Pool) *PersonalRecordRepository {
+	return &PersonalRecordRepository{
+		DB: db,
+	}
+}
+
+// recomputePersonalRecords rebuilds the PR events for the user and exercise that
+// own the given workout exercise. It must run in the same transaction as the set
+// change that triggered it.
+func recomputePersonalRecords(ctx context.Context, tx pgx.Tx, workoutExerciseID uuid.UUID) error {
+	var userID, exerciseID uuid.UUID
+	err := tx.QueryRow(ctx, getWorkoutExerciseOwnerQuery, workoutExerciseID).Scan(&userID, &exerciseID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get workout exercise: %w", err)
+	}
+	return recomputeExercisePersonalRecords(ctx, tx, userID, exerciseID)
+}
+
+// recomputeExercisePersonalRecords rebuilds the user's PR events for one
+// exercise.
+func recomputeExercisePersonalRecords(ctx context.Context, tx pgx.Tx, userID uuid.UUID, exerciseID uuid.UUID) error {
+	if _, err := tx.Exec(ctx, deletePersonalRecordsByExerciseQuery, userID, exerciseID); err != nil {
+		return fmt.Errorf("failed to clear personal records: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertPersonalRecordsByExerciseQuery, userID, exerciseID); err != nil {
+		return fmt.Errorf("failed to recompute personal records: %w", err)
+	}
+	return nil
+}
+
+// getWorkoutExerciseIDs returns the exercises logged in a workout, so their
+// PRs can be recomputed once it is deleted.
+func getWorkoutExerciseIDs(ctx context.Context, tx pgx.Tx, workoutID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := tx.Query(ctx, getWorkoutExerciseIDsQuery, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workout exercises: %w", err)
+	}
+	exerciseIDs, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workout exercises: %w", err)
+	}
+	return exerciseIDs, nil
+}
+
+// GetPersonalRecords returns the target user's current bests, optionally
+// limited to one exercise.
+func (r *PersonalRecordRepository) GetPersonalRecords(
+	ctx context.Context,
+	targetID uuid.UUID,
+	viewerID uuid.UUID,
+	exerciseID *uuid.UUID,
+) ([]*models.PersonalRecord, error) {
+	rows, err := r.DB.Query(ctx, getPersonalRecordsByUserIDQuery, targetID, viewerID, exerciseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal records: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPersonalRecordRows(rows)
+}
+
+// GetPersonalRecordHistory returns the target user's PR events, newest first.
+func (r *PersonalRecordRepository) GetPersonalRecordHistory(
+	ctx context.Context,
+	targetID uuid.UUID,
+	viewerID uuid.UUID,
+	exerciseID *uuid.UUID,
+	cursor *pagination.Cursor,
+	limit int,
+) ([]*models.PersonalRecord, error) {
+	rows, err := r.DB.Query(ctx, getPersonalRecordHistoryByUserIDQuery, targetID, viewerID, exerciseID, cursor.TimeKey(), cursor.IDKey(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal record history: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPersonalRecordRows(rows)
+}
+
+func scanPersonalRecordRows(rows pgx.Rows) ([]*models.PersonalRecord, error) {
+	records := []*models.PersonalRecord{}
+	for rows.Next() {
+		var pr models.PersonalRecord
+		err := rows.Scan(
+			&pr.ID,
+			&pr.UserID,
+			&pr.ExerciseID,
+			&pr.ExerciseName,
+			&pr.WorkoutID,
+			&pr.WorkoutSetID,
+			&pr.RecordType,
+			&pr.Weight,
+			&pr.Reps,
+			&pr.Value,
+			&pr.AchievedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan personal record: %w", err)
+		}
+		records = append(records, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read personal records: %w", err)
+	}
+	return records, nil
+}
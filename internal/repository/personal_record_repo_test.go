@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func findRecord(records []*models.PersonalRecord, recordType string) *models.PersonalRecord {
+	for _, pr := range records {
+		if pr.RecordType == recordType {
+			return pr
+		}
+	}
+	return nil
+}
+
+func TestPersonalRecordsOnSetChanges(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	prRepo := NewPersonalRecordRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Bench Press", nil, nil, userID)
+
+	now := time.Now()
+	reps := 5
+
+	// Session 1: 80kg x 5
+	w1, _ := workoutRepo.Create(ctx, userID, nil, nil, now.Add(-48*time.Hour), now.Add(-47*time.Hour), 3600)
+	we1, _ := weRepo.CreateWorkoutExercise(ctx, w1.ID, exercise.ID, 0, nil, nil, userID)
+	w1Weight := 80.0
+	if _, err := wsRepo.CreateWorkoutSet(ctx, we1.ID, &w1Weight, &reps, 0, userID); err != nil {
+		t.Fatalf("Failed to create workout set: %v", err)
+	}
+
+	// Session 2: 90kg x 5 beats it
+	w2, _ := workoutRepo.Create(ctx, userID, nil, nil, now.Add(-24*time.Hour), now.Add(-23*time.Hour), 3600)
+	we2, _ := weRepo.CreateWorkoutExercise(ctx, w2.ID, exercise.ID, 0, nil, nil, userID)
+	w2Weight := 90.0
+	prSet, err := wsRepo.CreateWorkoutSet(ctx, we2.ID, &w2Weight, &reps, 0, userID)
+	if err != nil {
+		t.Fatalf("Failed to create workout set: %v", err)
+	}
+
+	records, err := prRepo.GetPersonalRecords(ctx, userID, userID, &exercise.ID)
+	if err != nil {
+		t.Fatalf("Failed to get personal records: %v", err)
+	}
+	maxWeight := findRecord(records, models.RecordTypeMaxWeight)
+	if maxWeight == nil || maxWeight.Value != 90 {
+		t.Fatalf("Expected max weight 90, got %+v", maxWeight)
+	}
+	if maxWeight.WorkoutSetID == nil || *maxWeight.WorkoutSetID != prSet.ID {
+		t.Errorf("Expected record to point at set %v, got %v", prSet.ID, maxWeight.WorkoutSetID)
+	}
+	if e1rm := findRecord(records, models.RecordTypeEstimated1RM); e1rm == nil || e1rm.Value <= 90 {
+		t.Errorf("Expected estimated 1RM above 90, got %+v", e1rm)
+	}
+	if volume := findRecord(records, models.RecordTypeMaxVolume); volume == nil || volume.Value != 450 {
+		t.Errorf("Expected max volume 450, got %+v", volume)
+	}
+
+	history, err := prRepo.GetPersonalRecordHistory(ctx, userID, userID, &exercise.ID, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get personal record history: %v", err)
+	}
+	if len(history) == 0 || history[0].WorkoutID != w2.ID {
+		t.Errorf("Expected newest event from second session, got %+v", history)
+	}
+
+	// Deleting the PR set falls back to the earlier best
+	if err := wsRepo.DeleteWorkoutSet(ctx, prSet.ID, userID); err != nil {
+		t.Fatalf("Failed to delete workout set: %v", err)
+	}
+	records, _ = prRepo.GetPersonalRecords(ctx, userID, userID, &exercise.ID)
+	if maxWeight := findRecord(records, models.RecordTypeMaxWeight); maxWeight == nil || maxWeight.Value != 80 {
+		t.Errorf("Expected max weight 80 after delete, got %+v", maxWeight)
+	}
+}
+
+func TestPersonalRecordsOnSetUpdate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	prRepo := NewPersonalRecordRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Squat", nil, nil, userID)
+	w, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+	we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+
+	weight := 100.0
+	reps := 3
+	ws, _ := wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, userID)
+
+	newWeight := 110.0
+	err := wsRepo.UpdateWorkoutSet(ctx, ws.ID, userID, models.UpdateWorkoutSetRequest{Weight: &newWeight})
+	if err != nil {
+		t.Fatalf("Failed to update workout set: %v", err)
+	}
+
+	records, _ := prRepo.GetPersonalRecords(ctx, userID, userID, &exercise.ID)
+	if maxWeight := findRecord(records, models.RecordTypeMaxWeight); maxWeight == nil || maxWeight.Value != newWeight {
+		t.Errorf("Expected max weight %v after update, got %+v", newWeight, maxWeight)
+	}
+}
+
+func TestPersonalRecordsOnWorkoutDelete(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	prRepo := NewPersonalRecordRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Deadlift", nil, nil, userID)
+
+	now := time.Now()
+	reps := 5
+	session := func(hoursAgo int, weight float64) uuid.UUID {
+		t.Helper()
+		w, _ := workoutRepo.Create(ctx, userID, nil, nil, now.Add(-time.Duration(hoursAgo)*time.Hour), now.Add(-time.Duration(hoursAgo-1)*time.Hour), 3600)
+		we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+		if _, err := wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, userID); err != nil {
+			t.Fatalf("Failed to create workout set: %v", err)
+		}
+		return w.ID
+	}
+
+	// 140kg, then 150kg, then 145kg, which is no PR while 150kg stands
+	session(72, 140)
+	best := session(48, 150)
+	later := session(24, 145)
+
+	// Deleting the 150kg session makes the 145kg one a PR
+	if err := workoutRepo.DeleteWorkout(ctx, best, userID); err != nil {
+		t.Fatalf("Failed to delete workout: %v", err)
+	}
+	records, err := prRepo.GetPersonalRecords(ctx, userID, userID, &exercise.ID)
+	if err != nil {
+		t.Fatalf("Failed to get personal records: %v", err)
+	}
+	maxWeight := findRecord(records, models.RecordTypeMaxWeight)
+	if maxWeight == nil || maxWeight.Value != 145 || maxWeight.WorkoutID != later {
+		t.Errorf("Expected the later 145kg session to hold max weight, got %+v", maxWeight)
+	}
+}
+
+func TestPersonalRecordsOnWorkoutExerciseDelete(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	prRepo := NewPersonalRecordRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Deadlift", nil, nil, userID)
+
+	now := time.Now()
+	reps := 5
+	session := func(hoursAgo int, weight float64) (uuid.UUID, uuid.UUID) {
+		t.Helper()
+		w, _ := workoutRepo.Create(ctx, userID, nil, nil, now.Add(-time.Duration(hoursAgo)*time.Hour), now.Add(-time.Duration(hoursAgo-1)*time.Hour), 3600)
+		we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+		if _, err := wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, userID); err != nil {
+			t.Fatalf("Failed to create workout set: %v", err)
+		}
+		return w.ID, we.ID
+	}
+
+	// 140kg, then 150kg, then 145kg, which is no PR while 150kg stands
+	session(72, 140)
+	best, bestExercise := session(48, 150)
+	later, _ := session(24, 145)
+
+	// Removing the exercise from the 150kg session makes the 145kg one a PR
+	if err := weRepo.DeleteWorkoutExercise(ctx, bestExercise, userID); err != nil {
+		t.Fatalf("Failed to delete workout exercise: %v", err)
+	}
+	records, err := prRepo.GetPersonalRecords(ctx, userID, userID, &exercise.ID)
+	if err != nil {
+		t.Fatalf("Failed to get personal records: %v", err)
+	}
+	maxWeight := findRecord(records, models.RecordTypeMaxWeight)
+	if maxWeight == nil || maxWeight.Value != 145 || maxWeight.WorkoutID != later {
+		t.Errorf("Expected the later 145kg session to hold max weight, got %+v", maxWeight)
+	}
+
+	// Nothing is left pointing at the emptied workout, volume included
+	var remaining int
+	db.QueryRow(ctx, "SELECT COUNT(*) FROM public.personal_records WHERE workout_id = $1", best).Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("Expected no records for the emptied workout, got %d", remaining)
+	}
+}
+
+func TestPersonalRecordsOnWorkoutReorder(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	prRepo := NewPersonalRecordRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Deadlift", nil, nil, userID)
+
+	now := time.Now()
+	reps := 5
+	session := func(hoursAgo int, weight float64) uuid.UUID {
+		t.Helper()
+		w, _ := workoutRepo.Create(ctx, userID, nil, nil, now.Add(-time.Duration(hoursAgo)*time.Hour), now.Add(-time.Duration(hoursAgo-1)*time.Hour), 3600)
+		we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+		if _, err := wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, userID); err != nil {
+			t.Fatalf("Failed to create workout set: %v", err)
+		}
+		return w.ID
+	}
+
+	// 140kg, then 150kg: both are PRs
+	earlier := session(48, 140)
+	heavier := session(24, 150)
+
+	// Moving the 150kg session before the 140kg one leaves 140kg no PR
+	startedAt := now.Add(-72 * time.Hour)
+	err := workoutRepo.UpdateWorkout(ctx, heavier, models.UpdateWorkoutRequest{StartedAt: &startedAt}, userID)
+	if err != nil {
+		t.Fatalf("Failed to update workout: %v", err)
+	}
+
+	var earlierRecords int
+	db.QueryRow(ctx,
+		"SELECT COUNT(*) FROM public.personal_records WHERE workout_id = $1 AND record_type = 'max_weight'",
+		earlier,
+	).Scan(&earlierRecords)
+	if earlierRecords != 0 {
+		t.Errorf("Expected the 140kg session to lose its max weight PR, got %d", earlierRecords)
+	}
+
+	records, err := prRepo.GetPersonalRecords(ctx, userID, userID, &exercise.ID)
+	if err != nil {
+		t.Fatalf("Failed to get personal records: %v", err)
+	}
+	maxWeight := findRecord(records, models.RecordTypeMaxWeight)
+	if maxWeight == nil || maxWeight.WorkoutID != heavier || !maxWeight.AchievedAt.Equal(startedAt.Truncate(time.Microsecond)) {
+		t.Errorf("Expected the moved 150kg session to hold max weight from its new start, got %+v", maxWeight)
+	}
+}
+
+func TestGetPersonalRecordsPrivate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	prRepo := NewPersonalRecordRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	ownerID, _, _ := testutil.InsertProfile(ctx, db, "owner")
+	viewerID, _, _ := testutil.InsertProfile(ctx, db, "viewer")
+	db.Exec(ctx, "UPDATE public.profiles SET is_private_account = true WHERE id = $1", ownerID)
+
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &ownerID, "Deadlift", nil, nil, ownerID)
+	w, _ := workoutRepo.Create(ctx, ownerID, nil, nil, time.Now(), time.Now(), 0)
+	we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, ownerID)
+	weight := 150.0
+	reps := 1
+	wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, ownerID)
+
+	records, err := prRepo.GetPersonalRecords(ctx, ownerID, viewerID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get personal records: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected no records for non-follower of private account, got %d", len(records))
+	}
+
+	records, _ = prRepo.GetPersonalRecords(ctx, ownerID, ownerID, nil)
+	if len(records) == 0 {
+		t.Error("Expected owner to see their own records")
+	}
+}
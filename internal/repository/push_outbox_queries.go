@@ -0,0 +1,89 @@
+package repository
+
+// claimPushDeliveriesQuery leases due deliveries by pushing next_attempt_at
+// past the lease, so concurrent workers skip them and a crashed worker's
+// claims become due again on their own.
+const claimPushDeliveriesQuery = `
+	WITH due AS (
+		SELECT id
+		FROM public.push_outbox
+		WHERE status = 'pending' AND next_attempt_at <= now()
+		ORDER BY next_attempt_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	), claimed AS (
+		UPDATE public.push_outbox o
+		SET next_attempt_at = $2
+		FROM due
+		WHERE o.id = due.id
+		RETURNING o.id, o.notification_id, o.user_id, o.attempts, o.created_at, o.next_attempt_at
+	)
+	SELECT
+		c.id,
+		c.notification_id,
+		c.user_id,
+		n.type,
+		p.username,
+		n.workout_id,
+		n.comment_id,
+		c.attempts,
+		COALESCE(s.timezone, 'UTC'),
+		s.quiet_hours_start,
+		s.quiet_hours_end,
+		COALESCE((
+			SELECT array_agg(d.fcm_token ORDER BY d.updated_at DESC)
+			FROM public.user_devices d
+			WHERE d.user_id = c.user_id
+		), '{}'),
+		c.created_at,
+		c.next_attempt_at
+	FROM claimed c
+	JOIN public.notifications n ON n.id = c.notification_id
+	LEFT JOIN public.profiles p ON p.id = n.actor_id
+	LEFT JOIN public.user_settings s ON s.user_id = c.user_id
+`
+
+// The queries below act on a claimed delivery and match the lease it was
+// claimed with ($2), so a worker whose lease ran out and was re-claimed by
+// another cannot overwrite that worker's outcome.
+
+const renewPushClaimQuery = `
+	UPDATE public.push_outbox
+	SET next_attempt_at = $3
+	WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+	RETURNING next_attempt_at
+`
+
+const markPushSentQuery = `
+	UPDATE public.push_outbox
+	SET status = 'sent', attempts = attempts + 1, sent_at = now(), last_error = NULL
+	WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+`
+
+const deferPushQuery = `
+	UPDATE public.push_outbox
+	SET next_attempt_at = $3
+	WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+`
+
+const retryPushQuery = `
+	UPDATE public.push_outbox
+	SET attempts = attempts + 1, next_attempt_at = $3, last_error = $4
+	WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+`
+
+const failPushQuery = `
+	UPDATE public.push_outbox
+	SET status = 'failed', attempts = attempts + 1, last_error = $3
+	WHERE id = $1 AND status = 'pending' AND next_attempt_at = $2
+`
+
+const deleteUserDeviceByFCMTokenQuery = `
+	DELETE FROM public.user_devices
+	WHERE fcm_token = $1
+`
+
+const prunePushOutboxQuery = `
+	DELETE FROM public.push_outbox
+	WHERE status <> 'pending' AND created_at < $1
+`
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+type PushOutboxRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewPushOutboxRepository(db *pgxpool.Pool) *PushOutboxRepository {
+	return &PushOutboxRepository{
+		DB: db,
+	}
+}
+
+// ClaimPushDeliveries leases up to limit due deliveries for lease. Each one
+// carries the recipient's current device tokens and quiet hours.
+func (r *PushOutboxRepository) ClaimPushDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]*models.PushDelivery, error) {
+	rows, err := r.DB.Query(ctx, claimPushDeliveriesQuery, limit, time.Now().Add(lease))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim push deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*models.PushDelivery
+	for rows.Next() {
+		var d models.PushDelivery
+		err := rows.Scan(
+			&d.ID,
+			&d.NotificationID,
+			&d.UserID,
+			&d.Type,
+			&d.ActorUsername,
+			&d.WorkoutID,
+			&d.CommentID,
+			&d.Attempts,
+			&d.Timezone,
+			&d.QuietHoursStart,
+			&d.QuietHoursEnd,
+			&d.Tokens,
+			&d.CreatedAt,
+			&d.ClaimedUntil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan push delivery: %w", err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate push deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RenewPushClaim extends a claim by lease from now and returns the new
+// lease end. It returns the zero time if the claim has already run out and
+// the delivery was claimed again.
+func (r *PushOutboxRepository) RenewPushClaim(
+	ctx context.Context,
+	id uuid.UUID,
+	claimedUntil time.Time,
+	lease time.Duration,
+) (time.Time, error) {
+	var renewed time.Time
+	err := r.DB.QueryRow(ctx, renewPushClaimQuery, id, claimedUntil, time.Now().Add(lease)).Scan(&renewed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to renew push claim: %w", err)
+	}
+	return renewed, nil
+}
+
+// MarkPushSent, like DeferPush, RetryPush and FailPush, does nothing once
+// claimedUntil is no longer the delivery's claim.
+func (r *PushOutboxRepository) MarkPushSent(ctx context.Context, id uuid.UUID, claimedUntil time.Time) error {
+	if _, err := r.DB.Exec(ctx, markPushSentQuery, id, claimedUntil); err != nil {
+		return fmt.Errorf("failed to mark push sent: %w", err)
+	}
+	return nil
+}
+
+// DeferPush holds a delivery until the given time without counting an attempt.
+func (r *PushOutboxRepository) DeferPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, until time.Time) error {
+	if _, err := r.DB.Exec(ctx, deferPushQuery, id, claimedUntil, until); err != nil {
+		return fmt.Errorf("failed to defer push: %w", err)
+	}
+	return nil
+}
+
+func (r *PushOutboxRepository) RetryPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, at time.Time, lastErr string) error {
+	if _, err := r.DB.Exec(ctx, retryPushQuery, id, claimedUntil, at, lastErr); err != nil {
+		return fmt.Errorf("failed to schedule push retry: %w", err)
+	}
+	return nil
+}
+
+func (r *PushOutboxRepository) FailPush(ctx context.Context, id uuid.UUID, claimedUntil time.Time, lastErr string) error {
+	if _, err := r.DB.Exec(ctx, failPushQuery, id, claimedUntil, lastErr); err != nil {
+		return fmt.Errorf("failed to mark push failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteDeviceToken forgets a token that FCM no longer accepts.
+func (r *PushOutboxRepository) DeleteDeviceToken(ctx context.Context, token string) error {
+	if _, err := r.DB.Exec(ctx, deleteUserDeviceByFCMTokenQuery, token); err != nil {
+		return fmt.Errorf("failed to delete device token: %w", err)
+	}
+	return nil
+}
+
+// PruneOutbox deletes sent and failed deliveries created before the cutoff.
+func (r *PushOutboxRepository) PruneOutbox(ctx context.Context, before time.Time) error {
+	if _, err := r.DB.Exec(ctx, prunePushOutboxQuery, before); err != nil {
+		return fmt.Errorf("failed to prune push outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestPushOutboxQueuesNotificationsForDevices(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	repo := NewPushOutboxRepository(db)
+	deviceRepo := NewUserDeviceRepository(db)
+	followRepo := NewFollowRepository(db)
+
+	withDeviceID, _, _ := testutil.InsertProfile(ctx, db, "withdevice")
+	withoutDeviceID, _, _ := testutil.InsertProfile(ctx, db, "withoutdevice")
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+
+	if _, err := deviceRepo.UpsertUserDevice(ctx, withDeviceID, "token-1", "ios", withDeviceID); err != nil {
+		t.Fatalf("Failed to register device: %v", err)
+	}
+	if _, err := followRepo.Follow(ctx, followerID, withDeviceID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	if _, err := followRepo.Follow(ctx, followerID, withoutDeviceID); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+
+	deliveries, err := repo.ClaimPushDeliveries(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to claim deliveries: %v", err)
+	}
+	if len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery for the user with a device, got %d", len(deliveries))
+	}
+	d := deliveries[0]
+	if d.UserID != withDeviceID || d.Type != models.NotificationFollow {
+		t.Errorf("Unexpected delivery %+v", d)
+	}
+	if len(d.Tokens) != 1 || d.Tokens[0] != "token-1" {
+		t.Errorf("Expected the device token, got %v", d.Tokens)
+	}
+	if d.ActorUsername == nil || *d.ActorUsername != "follower" {
+		t.Errorf("Expected the actor's username")
+	}
+
+	// Claimed deliveries are leased
+	again, _ := repo.ClaimPushDeliveries(ctx, 10, time.Minute)
+	if len(again) != 0 {
+		t.Errorf("Expected a leased delivery not to be claimed twice, got %d", len(again))
+	}
+
+	// A claim that was renewed past is stale and cannot finish the delivery
+	renewed, err := repo.RenewPushClaim(ctx, d.ID, d.ClaimedUntil, time.Minute)
+	if err != nil || renewed.IsZero() {
+		t.Fatalf("Failed to renew claim: %v, %v", renewed, err)
+	}
+	if lost, _ := repo.RenewPushClaim(ctx, d.ID, d.ClaimedUntil, time.Minute); !lost.IsZero() {
+		t.Errorf("Expected a stale claim not to renew, got %v", lost)
+	}
+	if err := repo.MarkPushSent(ctx, d.ID, d.ClaimedUntil); err != nil {
+		t.Fatalf("Failed to mark sent: %v", err)
+	}
+	var status string
+	db.QueryRow(ctx, "SELECT status FROM public.push_outbox WHERE id = $1", d.ID).Scan(&status)
+	if status != models.PushPending {
+		t.Errorf("Expected a stale claim not to mark the delivery sent, got %q", status)
+	}
+
+	if err := repo.MarkPushSent(ctx, d.ID, renewed); err != nil {
+		t.Fatalf("Failed to mark sent: %v", err)
+	}
+	if err := db.QueryRow(ctx, "SELECT status FROM public.push_outbox WHERE id = $1", d.ID).Scan(&status); err != nil {
+		t.Fatalf("Failed to read outbox: %v", err)
+	}
+	if status != models.PushSent {
+		t.Errorf("Expected status %q, got %q", models.PushSent, status)
+	}
+
+	if err := repo.PruneOutbox(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Failed to prune: %v", err)
+	}
+	var remaining int
+	db.QueryRow(ctx, "SELECT count(*) FROM public.push_outbox").Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("Expected sent deliveries to be pruned, got %d", remaining)
+	}
+}
+
+func TestPushOutboxRetryAndDeleteToken(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	repo := NewPushOutboxRepository(db)
+	deviceRepo := NewUserDeviceRepository(db)
+	followRepo := NewFollowRepository(db)
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "user")
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+	deviceRepo.UpsertUserDevice(ctx, userID, "stale-token", "android", userID)
+	followRepo.Follow(ctx, followerID, userID)
+
+	deliveries, err := repo.ClaimPushDeliveries(ctx, 10, time.Minute)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d (%v)", len(deliveries), err)
+	}
+	if err := repo.RetryPush(ctx, deliveries[0].ID, deliveries[0].ClaimedUntil, time.Now().Add(-time.Second), "unavailable"); err != nil {
+		t.Fatalf("Failed to schedule retry: %v", err)
+	}
+
+	retried, _ := repo.ClaimPushDeliveries(ctx, 10, time.Minute)
+	if len(retried) != 1 || retried[0].Attempts != 1 {
+		t.Fatalf("Expected the retry to be due with 1 attempt")
+	}
+
+	if err := repo.DeleteDeviceToken(ctx, "stale-token"); err != nil {
+		t.Fatalf("Failed to delete token: %v", err)
+	}
+	devices, _ := deviceRepo.GetUserDevicesByUserID(ctx, userID, userID)
+	if len(devices) != 0 {
+		t.Errorf("Expected the stale token to be removed, got %d devices", len(devices))
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+// lockReporterQuery serializes a user's reports, so concurrent requests
+// cannot slip past the hourly limit.
+const lockReporterQuery = `
+  SELECT id FROM public.profiles
+  WHERE id = $1
+  FOR UPDATE
+`
+
+const countRecentReportsQuery = `
+  SELECT count(*) FROM public.reports
+  WHERE reporter_id = $1
+    AND created_at > now() - interval '1 hour'
+`
+
+// The report target queries return the author of content the reporter can
+// see. Users can only report what is shown to them.
+// $1 = reporterID, $2 = targetID
+const getReportedCommentAuthorQuery = `
+  SELECT c.user_id
+  FROM public.comments c
+  JOIN public.workouts w ON c.workout_id = w.id
+  WHERE c.id = $2
+    AND c.deleted_at IS NULL
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
+    AND NOT public.is_blocked_between(c.user_id, $1)
+    AND NOT public.is_hidden_from($1, c.user_id, c.hidden_at)
+`
+
+const getReportedWorkoutAuthorQuery = `
+  SELECT w.user_id
+  FROM public.workouts w
+  WHERE w.id = $2
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
+`
+
+const getReportedWorkoutImageAuthorQuery = `
+  SELECT w.user_id
+  FROM public.workout_images wi
+  JOIN public.workouts w ON wi.workout_id = w.id
+  WHERE wi.id = $2
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
+    AND NOT public.is_hidden_from($1, w.user_id, wi.hidden_at)
+`
+
+// Any other profile can be reported, including one the reporter has blocked.
+const getReportedUserQuery = `
+  SELECT p.id
+  FROM public.profiles p
+  WHERE p.id = $2
+    AND p.id <> $1
+`
+
+const insertReportQuery = `
+  INSERT INTO public.reports (reporter_id, target_type, target_id, target_user_id, reason, note)
+  VALUES ($1, $2, $3, $4, $5, $6)
+  RETURNING id, reporter_id, target_type, target_id, target_user_id, reason, note,
+            status, resolution, resolved_by, resolved_at, created_at
+`
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+type ReportRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewReportRepository(db *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{
+		DB: db,
+	}
+}
+
+// reportTargetQueries maps each report target type to the query that finds
+// the target's author.
+var reportTargetQueries = map[string]string{
+	models.ReportTargetComment:      getReportedCommentAuthorQuery,
+	models.ReportTargetWorkout:      getReportedWorkoutAuthorQuery,
+	models.ReportTargetWorkoutImage: getReportedWorkoutImageAuthorQuery,
+	models.ReportTargetUser:         getReportedUserQuery,
+}
+
+// CreateReport files a report against content or a profile the reporter can
+// see. A reporter has at most one open report per target and may file
+// models.MaxReportsPerHour reports an hour.
+func (r *ReportRepository) CreateReport(
+	ctx context.Context,
+	reporterID uuid.UUID,
+	req models.CreateReportRequest,
+) (*models.Report, error) {
+	targetQuery, ok := reportTargetQueries[req.TargetType]
+	if !ok {
+		return nil, ErrReportTargetNotFound
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var lockedID uuid.UUID
+	if err := tx.QueryRow(ctx, lockReporterQuery, reporterID).Scan(&lockedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to lock reporter: %w", err)
+	}
+
+	var recent int
+	if err := tx.QueryRow(ctx, countRecentReportsQuery, reporterID).Scan(&recent); err != nil {
+		return nil, fmt.Errorf("failed to count recent reports: %w", err)
+	}
+	if recent >= models.MaxReportsPerHour {
+		return nil, ErrReportLimit
+	}
+
+	var targetUserID uuid.UUID
+	if err := tx.QueryRow(ctx, targetQuery, reporterID, req.TargetID).Scan(&targetUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReportTargetNotFound
+		}
+		return nil, fmt.Errorf("failed to get report target: %w", err)
+	}
+	if targetUserID == reporterID {
+		return nil, ErrCannotReportSelf
+	}
+
+	report, err := scanReport(tx.QueryRow(ctx, insertReportQuery,
+		reporterID,
+		req.TargetType,
+		req.TargetID,
+		targetUserID,
+		req.Reason,
+		req.Note,
+	))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique violation
+			return nil, ErrAlreadyReported
+		}
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return report, nil
+}
+
+func scanReport(row pgx.Row) (*models.Report, error) {
+	var report models.Report
+	err := row.Scan(
+		&report.ID,
+		&report.ReporterID,
+		&report.TargetType,
+		&report.TargetID,
+		&report.TargetUserID,
+		&report.Reason,
+		&report.Note,
+		&report.Status,
+		&report.Resolution,
+		&report.ResolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestCreateReport(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewReportRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	ctx := context.Background()
+
+	reporterID, _, _ := testutil.InsertProfile(ctx, db, "reporter")
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	workout, _ := workoutRepo.Create(ctx, authorID, nil, nil, time.Now(), time.Now(), 0)
+	comment, _ := commentRepo.CreateComment(ctx, authorID, workout.ID, nil, "spam spam spam")
+
+	note := "link farm"
+	report, err := repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetComment,
+		TargetID:   comment.ID,
+		Reason:     "spam",
+		Note:       &note,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create report: %v", err)
+	}
+	if report.TargetUserID != authorID || report.Status != models.ReportStatusOpen || report.Note == nil {
+		t.Errorf("Unexpected report %+v", report)
+	}
+
+	// One open report per target
+	_, err = repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetComment,
+		TargetID:   comment.ID,
+		Reason:     "harassment",
+	})
+	if !errors.Is(err, ErrAlreadyReported) {
+		t.Errorf("Expected ErrAlreadyReported, got %v", err)
+	}
+
+	// Own content
+	_, err = repo.CreateReport(ctx, authorID, models.CreateReportRequest{
+		TargetType: models.ReportTargetWorkout,
+		TargetID:   workout.ID,
+		Reason:     "spam",
+	})
+	if !errors.Is(err, ErrCannotReportSelf) {
+		t.Errorf("Expected ErrCannotReportSelf, got %v", err)
+	}
+
+	// Missing target
+	_, err = repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetWorkoutImage,
+		TargetID:   uuid.New(),
+		Reason:     "nudity",
+	})
+	if !errors.Is(err, ErrReportTargetNotFound) {
+		t.Errorf("Expected ErrReportTargetNotFound, got %v", err)
+	}
+
+	// A profile can be reported
+	report, err = repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetUser,
+		TargetID:   authorID,
+		Reason:     "harassment",
+	})
+	if err != nil || report.TargetUserID != authorID {
+		t.Errorf("Expected a profile report, got %+v, %v", report, err)
+	}
+}
+
+func TestCreateReport_PrivateContent(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewReportRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	reporterID, _, _ := testutil.InsertProfile(ctx, db, "reporter")
+	authorID, _, _ := testutil.InsertProfile(ctx, db, "author")
+	db.Exec(ctx, "UPDATE public.profiles SET is_private_account = true WHERE id = $1", authorID)
+	workout, _ := workoutRepo.Create(ctx, authorID, nil, nil, time.Now(), time.Now(), 0)
+
+	_, err := repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetWorkout,
+		TargetID:   workout.ID,
+		Reason:     "spam",
+	})
+	if !errors.Is(err, ErrReportTargetNotFound) {
+		t.Errorf("Expected content the reporter cannot see to be unreportable, got %v", err)
+	}
+}
+
+func TestCreateReport_RateLimit(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewReportRepository(db)
+	ctx := context.Background()
+
+	reporterID, _, _ := testutil.InsertProfile(ctx, db, "reporter")
+
+	for i := 0; i < models.MaxReportsPerHour; i++ {
+		targetID, _, _ := testutil.InsertProfile(ctx, db, "target"+uuid.NewString()[:8])
+		_, err := repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+			TargetType: models.ReportTargetUser,
+			TargetID:   targetID,
+			Reason:     "spam",
+		})
+		if err != nil {
+			t.Fatalf("Failed to create report %d: %v", i, err)
+		}
+	}
+
+	targetID, _, _ := testutil.InsertProfile(ctx, db, "one-too-many")
+	_, err := repo.CreateReport(ctx, reporterID, models.CreateReportRequest{
+		TargetType: models.ReportTargetUser,
+		TargetID:   targetID,
+		Reason:     "spam",
+	})
+	if !errors.Is(err, ErrReportLimit) {
+		t.Errorf("Expected ErrReportLimit, got %v", err)
+	}
+}
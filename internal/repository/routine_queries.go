@@ -3,52 +3,24 @@ package repository
 const getRoutineByIDQuery = `
   SELECT r.id, r.user_id, r.name, r.created_at, r.updated_at
   FROM public.routines r
-  JOIN public.profiles p ON r.user_id = p.id
   WHERE r.id = $1
-    -- 1. Block Guard: Neither user has blocked the other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = r.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = r.user_id)
-    )
-    -- 2. Privacy Guard: Public account OR (User is following AND accepted) OR User is viewing their own routine
-    AND (
-        p.is_private_account = false 
-        OR r.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = r.user_id 
-              AND f.status = 'accepted'
-        )
-    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_user)
+    AND public.can_view_user($2, r.user_id)
 `
 
 const getRoutinesByUserIDQuery = `
   SELECT r.id, r.user_id, r.name, r.created_at, r.updated_at
   FROM public.routines r
-  JOIN public.profiles p ON r.user_id = p.id
   WHERE r.user_id = $1
-    -- 1. Block Guard
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = r.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = r.user_id)
-    )
-    -- 2. Privacy Guard
-    AND (
-        p.is_private_account = false 
-        OR r.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = r.user_id 
-              AND f.status = 'accepted'
-        )
-    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_user)
+    AND public.can_view_user($2, r.user_id)
   ORDER BY r.name ASC
 `
 
+const countRoutinesByUserIDQuery = `
+  SELECT count(*) FROM public.routines WHERE user_id = $1
+`
+
 const insertRoutineQuery = `
 	INSERT INTO public.routines (user_id, name)
 	VALUES ($1, $2)
@@ -69,3 +41,60 @@ const deleteRoutineByIDQuery = `
       OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2)
   )
 `
+
+const getAutoFillPreviousValuesQuery = `
+  SELECT COALESCE(
+    (SELECT auto_fill_previous_values FROM public.user_settings WHERE user_id = $1),
+    false
+  )
+`
+
+// Copies the routine header into a new workout. Only the routine owner may start it.
+const insertWorkoutFromRoutineQuery = `
+  INSERT INTO public.workouts (user_id, name, started_at, ended_at, duration_seconds, routine_id)
+  SELECT r.user_id, r.name, $3, $3, 0, r.id
+  FROM public.routines r
+  WHERE r.id = $1 AND r.user_id = $2
+  RETURNING id, user_id, routine_id, name, comment, started_at, ended_at, duration_seconds, total_weight, likes_count, comments_count, created_at, updated_at
+`
+
+// Copies the planned sets of one routine exercise into a workout exercise.
+// When $6 is true, weights are taken position-by-position from the most recent
+// other workout in which the user performed the same exercise, falling back to
+// the planned weight when there is no previous value.
+const insertWorkoutSetsFromRoutineQuery = `
+  WITH planned AS (
+    SELECT rs.weight, rs.reps, rs.order_index,
+           ROW_NUMBER() OVER (ORDER BY rs.order_index ASC, rs.created_at ASC) AS pos
+    FROM public.routine_sets rs
+    WHERE rs.routine_exercise_id = $2
+  ),
+  last_exercise AS (
+    SELECT we.id
+    FROM public.workout_exercises we
+    JOIN public.workouts w ON we.workout_id = w.id
+    WHERE $6::boolean
+      AND w.user_id = $3
+      AND w.id <> $5
+      AND we.exercise_id = $4
+      AND EXISTS (SELECT 1 FROM public.workout_sets ws WHERE ws.workout_exercise_id = we.id)
+    ORDER BY w.started_at DESC, we.order_index ASC
+    LIMIT 1
+  ),
+  previous AS (
+    SELECT ws.weight,
+           ROW_NUMBER() OVER (ORDER BY ws.order_index ASC, ws.created_at ASC) AS pos
+    FROM public.workout_sets ws
+    WHERE ws.workout_exercise_id = (SELECT id FROM last_exercise)
+  )
+  INSERT INTO public.workout_sets (workout_exercise_id, weight, reps, order_index)
+  SELECT $1, COALESCE(prev.weight, p.weight), p.reps, p.order_index
+  FROM planned p
+  LEFT JOIN previous prev ON prev.pos = p.pos
+  ORDER BY p.pos
+  RETURNING id, workout_exercise_id, weight, reps, order_index, created_at, updated_at
+`
+
+const getWorkoutTotalWeightQuery = `
+  SELECT total_weight FROM public.workouts WHERE id = $1
+`
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -75,6 +76,14 @@ func (r *RoutineRepository) GetRoutineByID(
 	return &routine, nil
 }
 
+func (r *RoutineRepository) CountRoutinesByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	if err := r.DB.QueryRow(ctx, countRoutinesByUserIDQuery, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count routines: %w", err)
+	}
+	return count, nil
+}
+
 func (r *RoutineRepository) GetRoutinesByUserID(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -162,3 +171,164 @@ func (r *RoutineRepository) DeleteRoutine(ctx context.Context, id uuid.UUID, use
 
 	return nil
 }
+
+// StartWorkoutFromRoutine creates a new workout from a routine owned by the user,
+// copying its exercises and planned sets. When the user has enabled
+// auto_fill_previous_values, set weights are pre-filled from the last time each
+// exercise was performed.
+func (r *RoutineRepository) StartWorkoutFromRoutine(
+	ctx context.Context,
+	routineID uuid.UUID,
+	userID uuid.UUID,
+	startedAt time.Time,
+) (*models.StartedWorkout, error) {
+	// Start a transaction
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var autoFill bool
+	if err := tx.QueryRow(ctx, getAutoFillPreviousValuesQuery, userID).Scan(&autoFill); err != nil {
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+
+	// Create the workout header
+	started := models.StartedWorkout{Exercises: []*models.StartedWorkoutExercise{}}
+	w := &started.Workout
+	err = tx.QueryRow(ctx, insertWorkoutFromRoutineQuery, routineID, userID, startedAt).Scan(
+		&w.ID,
+		&w.UserID,
+		&w.RoutineID,
+		&w.Name,
+		&w.Comment,
+		&w.StartedAt,
+		&w.EndedAt,
+		&w.DurationSeconds,
+		&w.TotalWeight,
+		&w.LikesCount,
+		&w.CommentsCount,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRoutineNotFound
+		}
+		return nil, fmt.Errorf("failed to create workout from routine: %w", err)
+	}
+
+	// Load the routine exercises before issuing further queries on the tx
+	rows, err := tx.Query(ctx, getRoutineExercisesByRoutineIDQuery, routineID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routine exercises: %w", err)
+	}
+	var routineExercises []models.RoutineExercise
+	for rows.Next() {
+		var re models.RoutineExercise
+		err := rows.Scan(
+			&re.ID,
+			&re.RoutineID,
+			&re.ExerciseID,
+			&re.OrderIndex,
+			&re.RestTimerSeconds,
+			&re.Memo,
+			&re.CreatedAt,
+			&re.UpdatedAt,
+		)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan routine exercise: %w", err)
+		}
+		routineExercises = append(routineExercises, re)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get routine exercises: %w", err)
+	}
+
+	// Copy each exercise and its planned sets
+	for _, re := range routineExercises {
+		exercise := &models.StartedWorkoutExercise{Sets: []*models.WorkoutSet{}}
+		we := &exercise.WorkoutExercise
+		err := tx.QueryRow(
+			ctx,
+			insertWorkoutExerciseQuery,
+			w.ID,
+			re.ExerciseID,
+			re.OrderIndex,
+			re.Memo,
+			re.RestTimerSeconds,
+			userID,
+		).Scan(
+			&we.ID,
+			&we.WorkoutID,
+			&we.ExerciseID,
+			&we.OrderIndex,
+			&we.Memo,
+			&we.RestTimerSeconds,
+			&we.CreatedAt,
+			&we.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy routine exercise: %w", err)
+		}
+
+		setRows, err := tx.Query(
+			ctx,
+			insertWorkoutSetsFromRoutineQuery,
+			we.ID,
+			re.ID,
+			userID,
+			re.ExerciseID,
+			w.ID,
+			autoFill,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy routine sets: %w", err)
+		}
+		for setRows.Next() {
+			var set models.WorkoutSet
+			err := setRows.Scan(
+				&set.ID,
+				&set.WorkoutExerciseID,
+				&set.Weight,
+				&set.Reps,
+				&set.OrderIndex,
+				&set.CreatedAt,
+				&set.UpdatedAt,
+			)
+			if err != nil {
+				setRows.Close()
+				return nil, fmt.Errorf("failed to scan workout set: %w", err)
+			}
+			exercise.Sets = append(exercise.Sets, &set)
+		}
+		setRows.Close()
+		if err := setRows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to copy routine sets: %w", err)
+		}
+
+		if err := recomputePersonalRecords(ctx, tx, we.ID); err != nil {
+			return nil, err
+		}
+
+		started.Exercises = append(started.Exercises, exercise)
+	}
+
+	// The set weight trigger updated total_weight; reflect it in the response
+	if err := tx.QueryRow(ctx, getWorkoutTotalWeightQuery, w.ID).Scan(&w.TotalWeight); err != nil {
+		return nil, fmt.Errorf("failed to get workout total weight: %w", err)
+	}
+
+	if err := recomputeStreak(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &started, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/models"
@@ -42,6 +43,39 @@ func TestCreateRoutine(t *testing.T) {
 	}
 }
 
+func TestCountRoutinesByUserID(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewRoutineRepository(db)
+	ctx := context.Background()
+
+	userID, _, err := testutil.InsertProfile(ctx, db, "testuser")
+	if err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+	otherID, _, err := testutil.InsertProfile(ctx, db, "otheruser")
+	if err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+
+	for _, name := range []string{"Push Day", "Pull Day"} {
+		if _, err := repo.CreateRoutine(ctx, userID, name); err != nil {
+			t.Fatalf("Failed to create routine: %v", err)
+		}
+	}
+	if _, err := repo.CreateRoutine(ctx, otherID, "Leg Day"); err != nil {
+		t.Fatalf("Failed to create routine: %v", err)
+	}
+
+	count, err := repo.CountRoutinesByUserID(ctx, userID)
+	if err != nil {
+		t.Fatalf("Failed to count routines: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Count mismatch: got %d, want 2", count)
+	}
+}
+
 func TestGetRoutineByID(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
@@ -307,3 +341,121 @@ func TestDeleteRoutineNotFound(t *testing.T) {
 		t.Errorf("Expected ErrRoutineNotFound, but got %v", err)
 	}
 }
+
+func TestStartWorkoutFromRoutine(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewRoutineRepository(db)
+	reRepo := NewRoutineExerciseRepository(db)
+	rsRepo := NewRoutineSetRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	routine, _ := repo.CreateRoutine(ctx, userID, "Push Day")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Bench Press", nil, nil, userID)
+	re, _ := reRepo.CreateRoutineExercise(ctx, routine.ID, exercise.ID, 0, nil, nil, userID)
+
+	weight := 60.0
+	reps := 10
+	rsRepo.CreateRoutineSet(ctx, re.ID, &weight, &reps, 0, userID)
+	rsRepo.CreateRoutineSet(ctx, re.ID, &weight, &reps, 1, userID)
+
+	startedAt := time.Now().UTC().Truncate(time.Second)
+	started, err := repo.StartWorkoutFromRoutine(ctx, routine.ID, userID, startedAt)
+	if err != nil {
+		t.Fatalf("Failed to start workout from routine: %v", err)
+	}
+
+	if started.RoutineID == nil || *started.RoutineID != routine.ID {
+		t.Errorf("RoutineID mismatch: got %v, want %v", started.RoutineID, routine.ID)
+	}
+	if started.Name == nil || *started.Name != routine.Name {
+		t.Errorf("Name mismatch: got %v, want %v", started.Name, routine.Name)
+	}
+	if !started.StartedAt.Equal(startedAt) {
+		t.Errorf("StartedAt mismatch: got %v, want %v", started.StartedAt, startedAt)
+	}
+	if len(started.Exercises) != 1 {
+		t.Fatalf("Expected 1 exercise, got %d", len(started.Exercises))
+	}
+	if started.Exercises[0].ExerciseID != exercise.ID {
+		t.Errorf("ExerciseID mismatch: got %v, want %v", started.Exercises[0].ExerciseID, exercise.ID)
+	}
+	if len(started.Exercises[0].Sets) != 2 {
+		t.Fatalf("Expected 2 sets, got %d", len(started.Exercises[0].Sets))
+	}
+	if *started.Exercises[0].Sets[0].Weight != weight {
+		t.Errorf("Weight mismatch: got %v, want %v", *started.Exercises[0].Sets[0].Weight, weight)
+	}
+	if started.TotalWeight != 2*weight*float64(reps) {
+		t.Errorf("TotalWeight mismatch: got %v, want %v", started.TotalWeight, 2*weight*float64(reps))
+	}
+}
+
+func TestStartWorkoutFromRoutineAutoFill(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewRoutineRepository(db)
+	reRepo := NewRoutineExerciseRepository(db)
+	rsRepo := NewRoutineSetRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	_, err := db.Exec(ctx, "INSERT INTO user_settings (user_id, auto_fill_previous_values) VALUES ($1, true)", userID)
+	if err != nil {
+		t.Fatalf("Failed to insert user settings: %v", err)
+	}
+
+	routine, _ := repo.CreateRoutine(ctx, userID, "Push Day")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Bench Press", nil, nil, userID)
+	re, _ := reRepo.CreateRoutineExercise(ctx, routine.ID, exercise.ID, 0, nil, nil, userID)
+
+	planned := 60.0
+	reps := 10
+	rsRepo.CreateRoutineSet(ctx, re.ID, &planned, &reps, 0, userID)
+	rsRepo.CreateRoutineSet(ctx, re.ID, &planned, &reps, 1, userID)
+
+	// Previous session: only one set was logged
+	previous := 70.0
+	now := time.Now()
+	w, _ := workoutRepo.Create(ctx, userID, nil, nil, now.Add(-24*time.Hour), now.Add(-23*time.Hour), 3600)
+	we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+	wsRepo.CreateWorkoutSet(ctx, we.ID, &previous, &reps, 0, userID)
+
+	started, err := repo.StartWorkoutFromRoutine(ctx, routine.ID, userID, now)
+	if err != nil {
+		t.Fatalf("Failed to start workout from routine: %v", err)
+	}
+
+	sets := started.Exercises[0].Sets
+	if len(sets) != 2 {
+		t.Fatalf("Expected 2 sets, got %d", len(sets))
+	}
+	if *sets[0].Weight != previous {
+		t.Errorf("First set should be auto-filled: got %v, want %v", *sets[0].Weight, previous)
+	}
+	if *sets[1].Weight != planned {
+		t.Errorf("Second set should fall back to plan: got %v, want %v", *sets[1].Weight, planned)
+	}
+}
+
+func TestStartWorkoutFromRoutineNotOwner(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewRoutineRepository(db)
+	ctx := context.Background()
+
+	ownerID, _, _ := testutil.InsertProfile(ctx, db, "owner")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
+	routine, _ := repo.CreateRoutine(ctx, ownerID, "Push Day")
+
+	_, err := repo.StartWorkoutFromRoutine(ctx, routine.ID, otherID, time.Now())
+	if !errors.Is(err, ErrRoutineNotFound) {
+		t.Errorf("Expected ErrRoutineNotFound, got %v", err)
+	}
+}
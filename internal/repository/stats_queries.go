@@ -0,0 +1,78 @@
+package repository
+
+const getStatsSettingsQuery = `
+  SELECT
+    COALESCE((SELECT unit_weight FROM public.user_settings WHERE user_id = $1), 'kg'),
+    COALESCE((SELECT timezone FROM public.user_settings WHERE user_id = $1), 'UTC')
+`
+
+// Buckets are generated for the whole range so empty periods are returned as zeros.
+// $4 is the date_trunc field: 'day', 'week' or 'month'. Buckets are cut at
+// midnight in the user's timezone ($5), so a session counts on its local day.
+const getStatsBucketsQuery = `
+  WITH series AS (
+    SELECT generate_series(
+      date_trunc($4::text, $2::timestamptz AT TIME ZONE $5::text),
+      date_trunc($4::text, ($3::timestamptz - interval '1 microsecond') AT TIME ZONE $5::text),
+      ('1 ' || $4::text)::interval
+    ) AS bucket_start
+  ),
+  agg AS (
+    SELECT
+      date_trunc($4::text, w.started_at AT TIME ZONE $5::text) AS bucket_start,
+      SUM(w.total_weight) AS volume,
+      COUNT(*) AS workout_count,
+      SUM(w.duration_seconds) AS duration_seconds
+    FROM public.workouts w
+    WHERE w.user_id = $1
+      AND w.started_at >= $2
+      AND w.started_at < $3
+    GROUP BY 1
+  )
+  SELECT
+    s.bucket_start AT TIME ZONE $5::text,
+    COALESCE(a.volume, 0),
+    COALESCE(a.workout_count, 0),
+    COALESCE(a.duration_seconds, 0)
+  FROM series s
+  LEFT JOIN agg a ON a.bucket_start = s.bucket_start
+  ORDER BY s.bucket_start ASC
+`
+
+// A set counts once for every muscle its exercise targets.
+const getStatsMuscleSetsQuery = `
+  SELECT
+    date_trunc($4::text, w.started_at AT TIME ZONE $5::text) AT TIME ZONE $5::text AS bucket_start,
+    m.id,
+    m.name,
+    COUNT(ws.id) AS sets
+  FROM public.workouts w
+  JOIN public.workout_exercises we ON we.workout_id = w.id
+  JOIN public.workout_sets ws ON ws.workout_exercise_id = we.id
+  JOIN public.exercise_target_muscles etm ON etm.exercise_id = we.exercise_id
+  JOIN public.muscles m ON m.id = etm.muscle_id
+  WHERE w.user_id = $1
+    AND w.started_at >= $2
+    AND w.started_at < $3
+  GROUP BY 1, m.id, m.name
+  ORDER BY 1 ASC, sets DESC, m.name ASC
+`
+
+const getStatsTopExercisesQuery = `
+  SELECT
+    e.id,
+    e.name,
+    COUNT(ws.id) AS sets,
+    COALESCE(SUM(ws.weight * ws.reps), 0) AS volume,
+    COUNT(DISTINCT w.id) AS workout_count
+  FROM public.workouts w
+  JOIN public.workout_exercises we ON we.workout_id = w.id
+  JOIN public.workout_sets ws ON ws.workout_exercise_id = we.id
+  JOIN public.exercises e ON e.id = we.exercise_id
+  WHERE w.user_id = $1
+    AND w.started_at >= $2
+    AND w.started_at < $3
+  GROUP BY e.id, e.name
+  ORDER BY sets DESC, volume DESC, e.name ASC
+  LIMIT $4
+`
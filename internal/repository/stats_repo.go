@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+// Weights are stored in kilograms and converted on the way out.
+const kgToLbs = 2.20462262
+
+type StatsRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewStatsRepository(db *pgxpool.Pool) *StatsRepository {
+	return &StatsRepository{
+		DB: db,
+	}
+}
+
+// GetTrainingStats aggregates the user's workouts started in [from, to) into
+// buckets of the given size ("day", "week" or "month"), cut in the user's
+// timezone.
+func (r *StatsRepository) GetTrainingStats(
+	ctx context.Context,
+	userID uuid.UUID,
+	from time.Time,
+	to time.Time,
+	bucket string,
+	topN int,
+) (*models.TrainingStats, error) {
+	stats := &models.TrainingStats{
+		Bucket:       bucket,
+		From:         from,
+		To:           to,
+		Buckets:      []*models.StatsBucket{},
+		MuscleSets:   []*models.MuscleSetCount{},
+		TopExercises: []*models.ExerciseStat{},
+	}
+
+	// 1. Unit and timezone
+	var timezone string
+	if err := r.DB.QueryRow(ctx, getStatsSettingsQuery, userID).Scan(&stats.Unit, &timezone); err != nil {
+		return nil, fmt.Errorf("failed to get stats settings: %w", err)
+	}
+	factor := 1.0
+	if stats.Unit == "lb" || stats.Unit == "lbs" {
+		factor = kgToLbs
+	}
+
+	// 2. Volume, frequency and duration per bucket
+	rows, err := r.DB.Query(ctx, getStatsBucketsQuery, userID, from, to, bucket, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats buckets: %w", err)
+	}
+	byStart := map[time.Time]*models.StatsBucket{}
+	for rows.Next() {
+		b := &models.StatsBucket{MuscleSets: []*models.MuscleSetCount{}}
+		if err := rows.Scan(&b.Start, &b.Volume, &b.WorkoutCount, &b.DurationSeconds); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stats bucket: %w", err)
+		}
+		b.Start = b.Start.UTC()
+		b.Volume *= factor
+		stats.Buckets = append(stats.Buckets, b)
+		byStart[b.Start] = b
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get stats buckets: %w", err)
+	}
+
+	// 3. Sets per muscle group, per bucket and for the whole range
+	rows, err = r.DB.Query(ctx, getStatsMuscleSetsQuery, userID, from, to, bucket, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get muscle sets: %w", err)
+	}
+	totals := map[uuid.UUID]*models.MuscleSetCount{}
+	for rows.Next() {
+		var start time.Time
+		var mc models.MuscleSetCount
+		if err := rows.Scan(&start, &mc.MuscleID, &mc.Name, &mc.Sets); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan muscle sets: %w", err)
+		}
+		if b, ok := byStart[start.UTC()]; ok {
+			b.MuscleSets = append(b.MuscleSets, &mc)
+		}
+		if total, ok := totals[mc.MuscleID]; ok {
+			total.Sets += mc.Sets
+		} else {
+			totals[mc.MuscleID] = &models.MuscleSetCount{MuscleID: mc.MuscleID, Name: mc.Name, Sets: mc.Sets}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get muscle sets: %w", err)
+	}
+	for _, total := range totals {
+		stats.MuscleSets = append(stats.MuscleSets, total)
+	}
+	sort.Slice(stats.MuscleSets, func(i, j int) bool {
+		if stats.MuscleSets[i].Sets != stats.MuscleSets[j].Sets {
+			return stats.MuscleSets[i].Sets > stats.MuscleSets[j].Sets
+		}
+		return stats.MuscleSets[i].Name < stats.MuscleSets[j].Name
+	})
+
+	// 4. Top exercises
+	rows, err = r.DB.Query(ctx, getStatsTopExercisesQuery, userID, from, to, topN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top exercises: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var es models.ExerciseStat
+		if err := rows.Scan(&es.ExerciseID, &es.Name, &es.Sets, &es.Volume, &es.WorkoutCount); err != nil {
+			return nil, fmt.Errorf("failed to scan top exercise: %w", err)
+		}
+		es.Volume *= factor
+		stats.TopExercises = append(stats.TopExercises, &es)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get top exercises: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestGetTrainingStats(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewStatsRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	etmRepo := NewExerciseTargetMuscleRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	chest, _ := testutil.InsertMuscle(ctx, db, "stats_chest")
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &userID, "Bench Press", nil, nil, userID)
+	etmRepo.AddTargetMuscle(ctx, exercise.ID, chest.ID, userID)
+
+	// Two workouts on consecutive days, none on the third
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(3 * 24 * time.Hour)
+	weight := 100.0
+	reps := 5
+	for day := 0; day < 2; day++ {
+		startedAt := from.Add(time.Duration(day)*24*time.Hour + 10*time.Hour)
+		w, _ := workoutRepo.Create(ctx, userID, nil, nil, startedAt, startedAt.Add(time.Hour), 3600)
+		we, _ := weRepo.CreateWorkoutExercise(ctx, w.ID, exercise.ID, 0, nil, nil, userID)
+		wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, userID)
+		wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 1, userID)
+	}
+
+	stats, err := repo.GetTrainingStats(ctx, userID, from, to, models.StatsBucketDay, 5)
+	if err != nil {
+		t.Fatalf("Failed to get training stats: %v", err)
+	}
+
+	if stats.Unit != "kg" {
+		t.Errorf("Unit mismatch: got %v, want kg", stats.Unit)
+	}
+	if len(stats.Buckets) != 3 {
+		t.Fatalf("Expected 3 buckets, got %d", len(stats.Buckets))
+	}
+	if stats.Buckets[0].WorkoutCount != 1 || stats.Buckets[0].Volume != 1000 || stats.Buckets[0].DurationSeconds != 3600 {
+		t.Errorf("Unexpected first bucket: %+v", stats.Buckets[0])
+	}
+	if stats.Buckets[2].WorkoutCount != 0 {
+		t.Errorf("Expected empty third bucket, got %+v", stats.Buckets[2])
+	}
+	if len(stats.Buckets[0].MuscleSets) != 1 || stats.Buckets[0].MuscleSets[0].Sets != 2 {
+		t.Errorf("Unexpected muscle sets in first bucket: %+v", stats.Buckets[0].MuscleSets)
+	}
+	if len(stats.MuscleSets) != 1 || stats.MuscleSets[0].Sets != 4 {
+		t.Errorf("Unexpected muscle set totals: %+v", stats.MuscleSets)
+	}
+	if len(stats.TopExercises) != 1 || stats.TopExercises[0].WorkoutCount != 2 || stats.TopExercises[0].Volume != 2000 {
+		t.Errorf("Unexpected top exercises: %+v", stats.TopExercises)
+	}
+
+	// Switching to pounds converts weights
+	db.Exec(ctx, "INSERT INTO user_settings (user_id, unit_weight) VALUES ($1, 'lbs')", userID)
+	stats, err = repo.GetTrainingStats(ctx, userID, from, to, models.StatsBucketWeek, 5)
+	if err != nil {
+		t.Fatalf("Failed to get training stats: %v", err)
+	}
+	if stats.Unit != "lbs" {
+		t.Errorf("Unit mismatch: got %v, want lbs", stats.Unit)
+	}
+	var total float64
+	for _, b := range stats.Buckets {
+		total += b.Volume
+	}
+	if math.Abs(total-2000*kgToLbs) > 0.01 {
+		t.Errorf("Volume not converted: got %v, want %v", total, 2000*kgToLbs)
+	}
+}
+
+func TestGetTrainingStatsInUserTimezone(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewStatsRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	if _, err := db.Exec(ctx, "INSERT INTO user_settings (user_id, timezone) VALUES ($1, 'Asia/Tokyo')", userID); err != nil {
+		t.Fatalf("Failed to set timezone: %v", err)
+	}
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	// 23:30 on March 1 and 07:00 on March 2, local time; both are March 1 in UTC
+	lateNight := time.Date(2026, 3, 1, 23, 30, 0, 0, tokyo)
+	morning := time.Date(2026, 3, 2, 7, 0, 0, 0, tokyo)
+	for _, startedAt := range []time.Time{lateNight, morning} {
+		workoutRepo.Create(ctx, userID, nil, nil, startedAt, startedAt.Add(time.Hour), 3600)
+	}
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, tokyo)
+	to := time.Date(2026, 3, 3, 0, 0, 0, 0, tokyo)
+	stats, err := repo.GetTrainingStats(ctx, userID, from, to, models.StatsBucketDay, 5)
+	if err != nil {
+		t.Fatalf("Failed to get training stats: %v", err)
+	}
+
+	if len(stats.Buckets) != 2 {
+		t.Fatalf("Expected 2 buckets, got %d", len(stats.Buckets))
+	}
+	for i, b := range stats.Buckets {
+		wantStart := from.AddDate(0, 0, i)
+		if !b.Start.Equal(wantStart) {
+			t.Errorf("Bucket %d starts at %v, want local midnight %v", i, b.Start, wantStart)
+		}
+		if b.WorkoutCount != 1 {
+			t.Errorf("Expected one workout on local day %d, got %d", i, b.WorkoutCount)
+		}
+	}
+}
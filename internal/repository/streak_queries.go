@@ -0,0 +1,22 @@
+package repository
+
+const getStreakSettingsQuery = `
+  SELECT
+    COALESCE((SELECT timezone FROM public.user_settings WHERE user_id = $1), 'UTC'),
+    COALESCE((SELECT streak_rule FROM public.user_settings WHERE user_id = $1), 'daily'),
+    COALESCE((SELECT streak_weekly_target FROM public.user_settings WHERE user_id = $1), 1)
+`
+
+const getWorkoutStartTimesByUserIDQuery = `
+  SELECT started_at FROM public.workouts WHERE user_id = $1
+`
+
+const updateProfileStreakQuery = `
+  UPDATE public.profiles
+  SET
+    current_streak = $2,
+    longest_streak = $3,
+    streak_expires_at = $4,
+    last_worked_out_at = (SELECT MAX(started_at) FROM public.workouts WHERE user_id = $1)
+  WHERE id = $1
+`
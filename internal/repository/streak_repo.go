@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rotsu1/jimu-backend/internal/streak"
+)
+
+// recomputeStreak recalculates the user's current and longest streak from all of
+// their workouts, in their own timezone and according to their streak rule. It must
+// run in the same transaction as the workout change that triggered it.
+func recomputeStreak(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	var tz string
+	var rule streak.Rule
+	err := tx.QueryRow(ctx, getStreakSettingsQuery, userID).Scan(&tz, &rule.Kind, &rule.PerWeek)
+	if err != nil {
+		return fmt.Errorf("failed to get streak settings: %w", err)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	rows, err := tx.Query(ctx, getWorkoutStartTimesByUserIDQuery, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get workout times: %w", err)
+	}
+	startedAt, err := pgx.CollectRows(rows, pgx.RowTo[time.Time])
+	if err != nil {
+		return fmt.Errorf("failed to scan workout times: %w", err)
+	}
+
+	res := streak.Compute(startedAt, time.Now(), loc, rule)
+
+	_, err = tx.Exec(ctx, updateProfileStreakQuery, userID, res.Current, res.Longest, res.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestStreakRecompute(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	workoutRepo := NewWorkoutRepository(db)
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+
+	// Three consecutive days ending today
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for day := 2; day >= 0; day-- {
+		startedAt := today.Add(-time.Duration(day) * 24 * time.Hour)
+		if _, err := workoutRepo.Create(ctx, userID, nil, nil, startedAt, startedAt.Add(time.Minute), 60); err != nil {
+			t.Fatalf("Failed to create workout: %v", err)
+		}
+	}
+
+	profile, err := userRepo.GetProfileByID(ctx, userID, userID)
+	if err != nil {
+		t.Fatalf("Failed to get profile: %v", err)
+	}
+	if profile.CurrentStreak != 3 || profile.LongestStreak != 3 {
+		t.Errorf("Streak mismatch: got current %d longest %d, want 3 and 3", profile.CurrentStreak, profile.LongestStreak)
+	}
+
+	var expiresAt *time.Time
+	db.QueryRow(ctx, "SELECT streak_expires_at FROM public.profiles WHERE id = $1", userID).Scan(&expiresAt)
+	if expiresAt == nil {
+		t.Fatal("Expected streak_expires_at to be set")
+	}
+
+	// Deleting the middle day breaks the current streak
+	middle, _ := workoutRepo.GetWorkoutsByUserID(ctx, userID, userID, nil, 10)
+	for _, w := range middle {
+		if w.StartedAt.Equal(today.Add(-24 * time.Hour)) {
+			if err := workoutRepo.DeleteWorkout(ctx, w.ID, userID); err != nil {
+				t.Fatalf("Failed to delete workout: %v", err)
+			}
+		}
+	}
+
+	profile, _ = userRepo.GetProfileByID(ctx, userID, userID)
+	if profile.CurrentStreak != 1 || profile.LongestStreak != 1 {
+		t.Errorf("Streak mismatch after delete: got current %d longest %d, want 1 and 1", profile.CurrentStreak, profile.LongestStreak)
+	}
+	if profile.TotalWorkouts != 2 {
+		t.Errorf("TotalWorkouts mismatch: got %d, want 2", profile.TotalWorkouts)
+	}
+	if profile.LastWorkedOutAt == nil || !profile.LastWorkedOutAt.Equal(today) {
+		t.Errorf("LastWorkedOutAt mismatch: got %v, want %v", profile.LastWorkedOutAt, today)
+	}
+}
+
+func TestStreakWeeklyRule(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	workoutRepo := NewWorkoutRepository(db)
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	_, err := db.Exec(ctx, "INSERT INTO public.user_settings (user_id) VALUES ($1)", userID)
+	if err != nil {
+		t.Fatalf("Failed to insert user settings: %v", err)
+	}
+
+	// One workout in each of the last two completed weeks
+	now := time.Now().UTC()
+	for week := 1; week <= 2; week++ {
+		startedAt := now.Add(-time.Duration(week) * 7 * 24 * time.Hour)
+		if _, err := workoutRepo.Create(ctx, userID, nil, nil, startedAt, startedAt.Add(time.Minute), 60); err != nil {
+			t.Fatalf("Failed to create workout: %v", err)
+		}
+	}
+
+	rule := "weekly"
+	target := 1
+	err = userRepo.UpdateUserSettings(ctx, userID.String(), models.UpdateUserSettingsRequest{
+		StreakRule:         &rule,
+		StreakWeeklyTarget: &target,
+	})
+	if err != nil {
+		t.Fatalf("Failed to update user settings: %v", err)
+	}
+
+	profile, _ := userRepo.GetProfileByID(ctx, userID, userID)
+	if profile.CurrentStreak != 2 {
+		t.Errorf("Weekly streak mismatch: got %d, want 2", profile.CurrentStreak)
+	}
+
+	settings, _ := userRepo.GetUserSettingsByID(ctx, userID)
+	if settings.StreakRule != "weekly" || settings.StreakWeeklyTarget != 1 || settings.Timezone != "UTC" {
+		t.Errorf("Settings mismatch: %+v", settings)
+	}
+}
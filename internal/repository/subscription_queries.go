@@ -1,7 +1,7 @@
 package repository
 
 const getSubscriptionByUserIDQuery = `
-  SELECT id, user_id, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
+  SELECT id, user_id, store, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
   FROM public.subscriptions
   WHERE user_id = $1
     AND (
@@ -11,7 +11,7 @@ const getSubscriptionByUserIDQuery = `
 `
 
 const getSubscriptionByTransactionIDQuery = `
-  SELECT id, user_id, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
+  SELECT id, user_id, store, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
   FROM public.subscriptions
   WHERE original_transaction_id = $1
     AND (
@@ -22,20 +22,99 @@ const getSubscriptionByTransactionIDQuery = `
     )
 `
 
-const upsertSubscriptionQuery = `
-	INSERT INTO public.subscriptions (user_id, original_transaction_id, product_id, status, expires_at, environment)
-	VALUES ($1, $2, $3, $4, $5, $6)
-	ON CONFLICT (user_id) 
-	DO UPDATE SET 
-		original_transaction_id = EXCLUDED.original_transaction_id,
-		product_id = EXCLUDED.product_id,
-		status = EXCLUDED.status,
-		expires_at = EXCLUDED.expires_at,
-		environment = EXCLUDED.environment
-	RETURNING id, user_id, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
-`
-
 const deleteSubscriptionByUserIDQuery = `
 	DELETE FROM public.subscriptions
 	WHERE user_id = $1
 `
+
+// saveVerifiedSubscriptionQuery upserts the user's subscription from a
+// verified transaction. A transaction of the same purchase signed before the
+// stored one is stale and leaves the row untouched, in which case the stored
+// row is returned instead.
+const saveVerifiedSubscriptionQuery = `
+	WITH saved AS (
+		INSERT INTO public.subscriptions (
+			user_id, original_transaction_id, latest_transaction_id, product_id,
+			status, expires_at, environment, signed_at, verified_payload, store
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			store = EXCLUDED.store,
+			original_transaction_id = EXCLUDED.original_transaction_id,
+			latest_transaction_id = EXCLUDED.latest_transaction_id,
+			product_id = EXCLUDED.product_id,
+			status = EXCLUDED.status,
+			expires_at = EXCLUDED.expires_at,
+			environment = EXCLUDED.environment,
+			signed_at = EXCLUDED.signed_at,
+			verified_payload = EXCLUDED.verified_payload
+		WHERE public.subscriptions.signed_at IS NULL
+			OR public.subscriptions.store <> EXCLUDED.store
+			OR public.subscriptions.original_transaction_id <> EXCLUDED.original_transaction_id
+			OR public.subscriptions.signed_at <= EXCLUDED.signed_at
+		RETURNING id, user_id, store, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
+	)
+	SELECT id, user_id, store, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
+	FROM saved
+	UNION ALL
+	SELECT id, user_id, store, original_transaction_id, product_id, status, expires_at, environment, created_at, updated_at
+	FROM public.subscriptions
+	WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM saved)
+`
+
+const insertSubscriptionEventQuery = `
+	INSERT INTO public.subscription_events (
+		store, notification_id, notification_type, subtype, original_transaction_id,
+		user_id, status, signed_at, payload
+	)
+	VALUES (
+		$8, $1, $2, NULLIF($3, ''), NULLIF($4, ''),
+		(SELECT user_id FROM public.subscriptions WHERE store = $8 AND original_transaction_id = $4),
+		NULLIF($5, ''), $6, $7
+	)
+	ON CONFLICT (store, notification_id) DO NOTHING
+	RETURNING id
+`
+
+// lockNotifiedSubscriptionQuery locks the subscription a store notification
+// applies to: the one stored under its purchase ($2), or else the one under
+// the purchase it replaces ($3).
+const lockNotifiedSubscriptionQuery = `
+	SELECT user_id
+	FROM public.subscriptions
+	WHERE store = $1
+		AND original_transaction_id IN ($2, NULLIF($3, ''))
+	ORDER BY original_transaction_id = $2 DESC
+	LIMIT 1
+	FOR UPDATE
+`
+
+// applySubscriptionNotificationQuery updates a subscription from a store
+// notification, skipping notifications older than the stored state. A
+// subscription stored under the purchase this one replaces ($10) moves over
+// to it, unless the new purchase is already stored.
+const applySubscriptionNotificationQuery = `
+	UPDATE public.subscriptions s
+	SET
+		original_transaction_id = $1,
+		latest_transaction_id = $2,
+		product_id = $3,
+		status = $4,
+		expires_at = $5,
+		environment = $6,
+		signed_at = $7,
+		verified_payload = $8
+	WHERE s.store = $9
+		AND (
+			s.original_transaction_id = $1
+			OR (
+				s.original_transaction_id = NULLIF($10, '')
+				AND NOT EXISTS (
+					SELECT 1 FROM public.subscriptions o
+					WHERE o.store = $9 AND o.original_transaction_id = $1
+				)
+			)
+		)
+		AND (s.signed_at IS NULL OR s.signed_at <= $7)
+`
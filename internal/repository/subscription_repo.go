@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,21 +22,33 @@ func NewSubscriptionRepository(db *pgxpool.Pool) *SubscriptionRepository {
 	}
 }
 
-// Upsert creates or updates a subscription for a user.
-func (r *SubscriptionRepository) UpsertSubscription(
+// SaveVerifiedSubscription stores the user's subscription as derived from a
+// verified store transaction. A purchase already linked to another user
+// returns ErrSubscriptionClaimed.
+func (r *SubscriptionRepository) SaveVerifiedSubscription(
 	ctx context.Context,
 	userID uuid.UUID,
-	originalTransactionID string,
-	productID string,
-	status string,
-	expiresAt time.Time,
-	environment string,
+	v models.VerifiedSubscription,
 ) (*models.Subscription, error) {
 	var sub models.Subscription
 
-	err := r.DB.QueryRow(ctx, upsertSubscriptionQuery, userID, originalTransactionID, productID, status, expiresAt, environment).Scan(
+	err := r.DB.QueryRow(
+		ctx,
+		saveVerifiedSubscriptionQuery,
+		userID,
+		v.OriginalTransactionID,
+		v.TransactionID,
+		v.ProductID,
+		v.Status,
+		v.ExpiresAt,
+		v.Environment,
+		v.SignedAt,
+		v.Payload,
+		v.Store,
+	).Scan(
 		&sub.ID,
 		&sub.UserID,
+		&sub.Store,
 		&sub.OriginalTransactionID,
 		&sub.ProductID,
 		&sub.Status,
@@ -52,14 +63,95 @@ func (r *SubscriptionRepository) UpsertSubscription(
 			switch pgErr.Code {
 			case "23503":
 				return nil, ErrReferenceViolation
+			case "23505":
+				return nil, ErrSubscriptionClaimed
 			}
 		}
-		return nil, fmt.Errorf("failed to upsert subscription: %w", err)
+		return nil, fmt.Errorf("failed to save verified subscription: %w", err)
 	}
 
 	return &sub, nil
 }
 
+// ApplySubscriptionEvent records a store notification and, when update is
+// set, applies it to the subscription with the same store and original
+// transaction, or the one it links to.
+// It returns the user whose subscription the update applied to, and false
+// without changing anything if the notification was already processed, or ErrSubscriptionNotFound without recording it if the
+// update matches no subscription, so a redelivery after the app reports the
+// purchase still applies it.
+func (r *SubscriptionRepository) ApplySubscriptionEvent(
+	ctx context.Context,
+	event models.SubscriptionEvent,
+	update *models.VerifiedSubscription,
+) (uuid.UUID, bool, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var eventID uuid.UUID
+	err = tx.QueryRow(
+		ctx,
+		insertSubscriptionEventQuery,
+		event.NotificationID,
+		event.NotificationType,
+		event.Subtype,
+		event.OriginalTransactionID,
+		event.Status,
+		event.SignedAt,
+		event.Payload,
+		event.Store,
+	).Scan(&eventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, fmt.Errorf("failed to record subscription event: %w", err)
+	}
+
+	var userID uuid.UUID
+	if update != nil {
+		err = tx.QueryRow(
+			ctx,
+			lockNotifiedSubscriptionQuery,
+			update.Store,
+			update.OriginalTransactionID,
+			update.LinkedTransactionID,
+		).Scan(&userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return uuid.Nil, false, ErrSubscriptionNotFound
+			}
+			return uuid.Nil, false, fmt.Errorf("failed to lock subscription: %w", err)
+		}
+
+		_, err = tx.Exec(
+			ctx,
+			applySubscriptionNotificationQuery,
+			update.OriginalTransactionID,
+			update.TransactionID,
+			update.ProductID,
+			update.Status,
+			update.ExpiresAt,
+			update.Environment,
+			update.SignedAt,
+			update.Payload,
+			update.Store,
+			update.LinkedTransactionID,
+		)
+		if err != nil {
+			return uuid.Nil, false, fmt.Errorf("failed to apply subscription event: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return userID, true, nil
+}
+
 // GetByUserID gets a subscription by user ID.
 func (r *SubscriptionRepository) GetSubscriptionByUserID(
 	ctx context.Context,
@@ -71,6 +163,7 @@ func (r *SubscriptionRepository) GetSubscriptionByUserID(
 	err := r.DB.QueryRow(ctx, getSubscriptionByUserIDQuery, userID, viewerID).Scan(
 		&sub.ID,
 		&sub.UserID,
+		&sub.Store,
 		&sub.OriginalTransactionID,
 		&sub.ProductID,
 		&sub.Status,
@@ -100,6 +193,7 @@ func (r *SubscriptionRepository) GetSubscriptionByTransactionID(
 	err := r.DB.QueryRow(ctx, getSubscriptionByTransactionIDQuery, transactionID, userID).Scan(
 		&sub.ID,
 		&sub.UserID,
+		&sub.Store,
 		&sub.OriginalTransactionID,
 		&sub.ProductID,
 		&sub.Status,
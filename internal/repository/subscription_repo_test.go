@@ -7,10 +7,30 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
 )
 
-func TestUpsertSubscription(t *testing.T) {
+// saveTestSubscription stores an active App Store subscription for userID.
+func saveTestSubscription(t *testing.T, repo *SubscriptionRepository, userID uuid.UUID, transactionID string) {
+	t.Helper()
+	_, err := repo.SaveVerifiedSubscription(context.Background(), userID, models.VerifiedSubscription{
+		Store:                 models.StoreAppStore,
+		OriginalTransactionID: transactionID,
+		TransactionID:         transactionID,
+		ProductID:             "premium_monthly",
+		Status:                models.SubscriptionActive,
+		ExpiresAt:             time.Now().Add(30 * 24 * time.Hour),
+		Environment:           models.EnvironmentProduction,
+		SignedAt:              time.Now(),
+		Payload:               []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to save subscription: %v", err)
+	}
+}
+
+func TestGetSubscriptionByUserID(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
@@ -18,37 +38,32 @@ func TestUpsertSubscription(t *testing.T) {
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 
-	transactionID := "txn_123"
-	productID := "premium_monthly"
-	status := "active"
-	expiresAt := time.Now().Add(30 * 24 * time.Hour)
-	environment := "production"
-
-	sub, err := repo.UpsertSubscription(
-		ctx,
-		userID,
-		transactionID,
-		productID,
-		status,
-		expiresAt,
-		environment,
-	)
+	transactionID := "txn_456"
+	saveTestSubscription(t, repo, userID, transactionID)
+
+	sub, err := repo.GetSubscriptionByUserID(ctx, userID, userID)
 	if err != nil {
-		t.Fatalf("Failed to upsert subscription: %v", err)
+		t.Fatalf("Failed to get subscription: %v", err)
 	}
 
-	if sub.ID == uuid.Nil {
-		t.Error("Subscription ID should not be nil")
-	}
 	if sub.UserID != userID {
 		t.Errorf("UserID mismatch: got %v, want %v", sub.UserID, userID)
 	}
-	if sub.Status != status {
-		t.Errorf("Status mismatch: got %v, want %v", sub.Status, status)
+}
+
+func TestGetSubscriptionByUserIDNotFound(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewSubscriptionRepository(db)
+	ctx := context.Background()
+
+	_, err := repo.GetSubscriptionByUserID(ctx, uuid.New(), uuid.New())
+	if !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected ErrSubscriptionNotFound, but got %v", err)
 	}
 }
 
-func TestUpsertSubscriptionIdempotent(t *testing.T) {
+func TestGetSubscriptionByTransactionID(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
@@ -56,49 +71,20 @@ func TestUpsertSubscriptionIdempotent(t *testing.T) {
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 
-	transactionID := "txn_123"
-	productID := "premium_monthly"
-	status := "active"
-	expiresAt := time.Now().Add(30 * 24 * time.Hour)
-	environment := "production"
-
-	// First upsert
-	sub1, _ := repo.UpsertSubscription(
-		ctx,
-		userID,
-		transactionID,
-		productID,
-		status,
-		expiresAt,
-		environment,
-	)
-
-	// Second upsert with different status
-	newStatus := "expired"
-	sub2, err := repo.UpsertSubscription(
-		ctx,
-		userID,
-		transactionID,
-		productID,
-		newStatus,
-		expiresAt,
-		environment,
-	)
+	transactionID := "txn_789"
+	saveTestSubscription(t, repo, userID, transactionID)
+
+	sub, err := repo.GetSubscriptionByTransactionID(ctx, transactionID, userID)
 	if err != nil {
-		t.Fatalf("Failed to upsert subscription: %v", err)
+		t.Fatalf("Failed to get subscription: %v", err)
 	}
 
-	// Should be same ID
-	if sub1.ID != sub2.ID {
-		t.Errorf("ID should be the same: got %v, want %v", sub2.ID, sub1.ID)
-	}
-	// Status should be updated
-	if sub2.Status != newStatus {
-		t.Errorf("Status should be updated: got %v, want %v", sub2.Status, newStatus)
+	if sub.OriginalTransactionID != transactionID {
+		t.Errorf("TransactionID mismatch: got %v, want %v", sub.OriginalTransactionID, transactionID)
 	}
 }
 
-func TestGetSubscriptionByUserID(t *testing.T) {
+func TestDeleteSubscription(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
@@ -106,83 +92,173 @@ func TestGetSubscriptionByUserID(t *testing.T) {
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 
-	transactionID := "txn_456"
-	status := "active"
-	productID := "premium_monthly"
-	expiresAt := time.Now().Add(30 * 24 * time.Hour)
-	environment := "production"
-	_, err := repo.UpsertSubscription(
-		ctx,
-		userID,
-		transactionID,
-		productID,
-		status,
-		expiresAt,
-		environment,
-	)
-	if err != nil {
-		t.Fatalf("Failed to upsert subscription: %v", err)
-	}
+	saveTestSubscription(t, repo, userID, "txn_delete")
 
-	sub, err := repo.GetSubscriptionByUserID(ctx, userID, userID)
+	err := repo.DeleteSubscription(ctx, userID)
 	if err != nil {
-		t.Fatalf("Failed to get subscription: %v", err)
+		t.Fatalf("Failed to delete subscription: %v", err)
 	}
 
-	if sub.UserID != userID {
-		t.Errorf("UserID mismatch: got %v, want %v", sub.UserID, userID)
+	_, err = repo.GetSubscriptionByUserID(ctx, userID, uuid.New())
+	if !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Errorf("Expected ErrSubscriptionNotFound, but got %v", err)
 	}
 }
 
-func TestGetSubscriptionByUserIDNotFound(t *testing.T) {
+func TestDeleteSubscriptionNotFound(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
 	ctx := context.Background()
 
-	_, err := repo.GetSubscriptionByUserID(ctx, uuid.New(), uuid.New())
+	err := repo.DeleteSubscription(ctx, uuid.New())
 	if !errors.Is(err, ErrSubscriptionNotFound) {
 		t.Errorf("Expected ErrSubscriptionNotFound, but got %v", err)
 	}
 }
 
-func TestGetSubscriptionByTransactionID(t *testing.T) {
+func TestSaveVerifiedSubscription(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
 	ctx := context.Background()
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "otheruser")
+
+	signedAt := time.Now().Add(-time.Hour)
+	renewal := models.VerifiedSubscription{
+		Store:                 models.StoreAppStore,
+		OriginalTransactionID: "txn_1",
+		TransactionID:         "txn_2",
+		ProductID:             "premium_monthly",
+		Status:                models.SubscriptionActive,
+		ExpiresAt:             time.Now().Add(30 * 24 * time.Hour),
+		Environment:           "sandbox",
+		SignedAt:              signedAt,
+		Payload:               []byte(`{"transactionId":"txn_2"}`),
+	}
+	sub, err := repo.SaveVerifiedSubscription(ctx, userID, renewal)
+	if err != nil {
+		t.Fatalf("Failed to save subscription: %v", err)
+	}
+	if sub.Status != models.SubscriptionActive || sub.OriginalTransactionID != "txn_1" {
+		t.Errorf("Unexpected subscription %+v", sub)
+	}
 
-	transactionID := "txn_789"
-	status := "active"
-	productID := "premium_monthly"
-	expiresAt := time.Now().Add(30 * 24 * time.Hour)
-	environment := "production"
-	_, err := repo.UpsertSubscription(
-		ctx,
-		userID,
-		transactionID,
-		productID,
-		status,
-		expiresAt,
-		environment,
-	)
+	// A replayed transaction signed earlier does not roll the subscription back
+	stale := renewal
+	stale.TransactionID = "txn_0"
+	stale.Status = models.SubscriptionExpired
+	stale.SignedAt = signedAt.Add(-24 * time.Hour)
+	sub, err = repo.SaveVerifiedSubscription(ctx, userID, stale)
 	if err != nil {
-		t.Fatalf("Failed to upsert subscription: %v", err)
+		t.Fatalf("Failed to save stale subscription: %v", err)
+	}
+	if sub.Status != models.SubscriptionActive {
+		t.Errorf("Expected the stale transaction to be ignored, got status %q", sub.Status)
 	}
 
-	sub, err := repo.GetSubscriptionByTransactionID(ctx, transactionID, userID)
+	// The same purchase cannot be claimed by another user
+	_, err = repo.SaveVerifiedSubscription(ctx, otherID, renewal)
+	if !errors.Is(err, ErrSubscriptionClaimed) {
+		t.Errorf("Expected ErrSubscriptionClaimed, got %v", err)
+	}
+}
+
+func TestApplySubscriptionEvent(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewSubscriptionRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+
+	signedAt := time.Now().Add(-time.Hour)
+	purchase := models.VerifiedSubscription{
+		Store:                 models.StoreAppStore,
+		OriginalTransactionID: "txn_1",
+		TransactionID:         "txn_1",
+		ProductID:             "premium_monthly",
+		Status:                models.SubscriptionActive,
+		ExpiresAt:             time.Now().Add(30 * 24 * time.Hour),
+		Environment:           "sandbox",
+		SignedAt:              signedAt,
+		Payload:               []byte(`{}`),
+	}
+	if _, err := repo.SaveVerifiedSubscription(ctx, userID, purchase); err != nil {
+		t.Fatalf("Failed to save subscription: %v", err)
+	}
+
+	refund := purchase
+	refund.Status = models.SubscriptionRevoked
+	refund.SignedAt = signedAt.Add(time.Minute)
+	event := models.SubscriptionEvent{
+		Store:                 models.StoreAppStore,
+		NotificationID:        "notification-1",
+		NotificationType:      "REFUND",
+		OriginalTransactionID: "txn_1",
+		Status:                models.SubscriptionRevoked,
+		SignedAt:              refund.SignedAt,
+		Payload:               []byte(`{"notificationType":"REFUND"}`),
+	}
+
+	eventUser, applied, err := repo.ApplySubscriptionEvent(ctx, event, &refund)
 	if err != nil {
-		t.Fatalf("Failed to get subscription: %v", err)
+		t.Fatalf("Failed to apply event: %v", err)
+	}
+	if !applied || eventUser != userID {
+		t.Errorf("Expected the first delivery to be applied to the user, got %v %v", applied, eventUser)
 	}
 
-	if sub.OriginalTransactionID != transactionID {
-		t.Errorf("TransactionID mismatch: got %v, want %v", sub.OriginalTransactionID, transactionID)
+	sub, _ := repo.GetSubscriptionByUserID(ctx, userID, userID)
+	if sub.Status != models.SubscriptionRevoked {
+		t.Errorf("Expected the subscription to be revoked, got %q", sub.Status)
+	}
+
+	// Redelivery is a no-op, even if the state changed in between
+	if _, err := repo.SaveVerifiedSubscription(ctx, userID, models.VerifiedSubscription{
+		Store:                 models.StoreAppStore,
+		OriginalTransactionID: "txn_1",
+		TransactionID:         "txn_3",
+		ProductID:             "premium_monthly",
+		Status:                models.SubscriptionActive,
+		ExpiresAt:             time.Now().Add(60 * 24 * time.Hour),
+		Environment:           "sandbox",
+		SignedAt:              signedAt.Add(time.Hour),
+		Payload:               []byte(`{}`),
+	}); err != nil {
+		t.Fatalf("Failed to save renewal: %v", err)
+	}
+	_, applied, err = repo.ApplySubscriptionEvent(ctx, event, &refund)
+	if err != nil {
+		t.Fatalf("Failed to reapply event: %v", err)
+	}
+	if applied {
+		t.Error("Expected the redelivered notification to be skipped")
+	}
+	sub, _ = repo.GetSubscriptionByUserID(ctx, userID, userID)
+	if sub.Status != models.SubscriptionActive {
+		t.Errorf("Expected the renewal to stand, got %q", sub.Status)
+	}
+
+	var count int
+	var eventUserID uuid.UUID
+	err = db.QueryRow(ctx, "SELECT count(*), max(user_id::text)::uuid FROM public.subscription_events").Scan(&count, &eventUserID)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	if count != 1 || eventUserID != userID {
+		t.Errorf("Expected 1 event for the user, got %d for %v", count, eventUserID)
+	}
+
+	// History is append-only
+	if _, err := db.Exec(ctx, "DELETE FROM public.subscription_events"); err == nil {
+		t.Error("Expected deleting subscription events to fail")
 	}
 }
 
-func TestDeleteSubscription(t *testing.T) {
+func TestApplySubscriptionEventLinkedPurchase(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
@@ -190,32 +266,125 @@ func TestDeleteSubscription(t *testing.T) {
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 
-	transactionID := "txn_delete"
-	status := "active"
-	productID := "premium_monthly"
-	expiresAt := time.Now().Add(30 * 24 * time.Hour)
-	environment := "production"
-	repo.UpsertSubscription(ctx, userID, transactionID, productID, status, expiresAt, environment)
+	signedAt := time.Now().Add(-time.Hour)
+	monthly := models.VerifiedSubscription{
+		Store:                 models.StoreGooglePlay,
+		OriginalTransactionID: "token-monthly",
+		TransactionID:         "GPA.1",
+		ProductID:             "premium_monthly",
+		Status:                models.SubscriptionActive,
+		ExpiresAt:             time.Now().Add(30 * 24 * time.Hour),
+		Environment:           "production",
+		SignedAt:              signedAt,
+		Payload:               []byte(`{}`),
+	}
+	if _, err := repo.SaveVerifiedSubscription(ctx, userID, monthly); err != nil {
+		t.Fatalf("Failed to save subscription: %v", err)
+	}
 
-	err := repo.DeleteSubscription(ctx, userID)
+	// The same identifier from another store is a different purchase
+	other, _, _ := testutil.InsertProfile(ctx, db, "otheruser")
+	apple := monthly
+	apple.Store = models.StoreAppStore
+	if _, err := repo.SaveVerifiedSubscription(ctx, other, apple); err != nil {
+		t.Fatalf("Expected identifiers to be unique per store, got %v", err)
+	}
+
+	// An upgrade replaces the purchase token
+	yearly := monthly
+	yearly.OriginalTransactionID = "token-yearly"
+	yearly.LinkedTransactionID = "token-monthly"
+	yearly.TransactionID = "GPA.2"
+	yearly.ProductID = "premium_yearly"
+	yearly.SignedAt = signedAt.Add(time.Minute)
+	event := models.SubscriptionEvent{
+		Store:                 models.StoreGooglePlay,
+		NotificationID:        "message-1",
+		NotificationType:      "SUBSCRIPTION_PURCHASED",
+		OriginalTransactionID: "token-yearly",
+		Status:                models.SubscriptionActive,
+		SignedAt:              yearly.SignedAt,
+		Payload:               []byte(`{}`),
+	}
+	if _, _, err := repo.ApplySubscriptionEvent(ctx, event, &yearly); err != nil {
+		t.Fatalf("Failed to apply event: %v", err)
+	}
+
+	sub, err := repo.GetSubscriptionByUserID(ctx, userID, userID)
 	if err != nil {
-		t.Fatalf("Failed to delete subscription: %v", err)
+		t.Fatalf("Failed to get subscription: %v", err)
+	}
+	if sub.Store != models.StoreGooglePlay || sub.OriginalTransactionID != "token-yearly" || sub.ProductID != "premium_yearly" {
+		t.Errorf("Expected the subscription to move to the new purchase, got %+v", sub)
 	}
 
-	_, err = repo.GetSubscriptionByUserID(ctx, userID, uuid.New())
-	if !errors.Is(err, ErrSubscriptionNotFound) {
-		t.Errorf("Expected ErrSubscriptionNotFound, but got %v", err)
+	// Notification IDs are scoped to their store
+	event.Store = models.StoreAppStore
+	_, applied, err := repo.ApplySubscriptionEvent(ctx, event, nil)
+	if err != nil {
+		t.Fatalf("Failed to apply event: %v", err)
+	}
+	if !applied {
+		t.Error("Expected the same notification ID from another store to be recorded")
 	}
 }
 
-func TestDeleteSubscriptionNotFound(t *testing.T) {
+func TestApplySubscriptionEventUnknownSubscription(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewSubscriptionRepository(db)
 	ctx := context.Background()
 
-	err := repo.DeleteSubscription(ctx, uuid.New())
-	if !errors.Is(err, ErrSubscriptionNotFound) {
-		t.Errorf("Expected ErrSubscriptionNotFound, but got %v", err)
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+
+	signedAt := time.Now().Add(-time.Hour)
+	purchase := models.VerifiedSubscription{
+		Store:                 models.StoreAppStore,
+		OriginalTransactionID: "txn_1",
+		TransactionID:         "txn_1",
+		ProductID:             "premium_monthly",
+		Status:                models.SubscriptionActive,
+		ExpiresAt:             time.Now().Add(30 * 24 * time.Hour),
+		Environment:           models.EnvironmentProduction,
+		SignedAt:              signedAt,
+		Payload:               []byte(`{}`),
+	}
+	refund := purchase
+	refund.Status = models.SubscriptionRevoked
+	refund.SignedAt = signedAt.Add(time.Minute)
+	event := models.SubscriptionEvent{
+		Store:                 models.StoreAppStore,
+		NotificationID:        "notification-1",
+		NotificationType:      "REFUND",
+		OriginalTransactionID: "txn_1",
+		Status:                models.SubscriptionRevoked,
+		SignedAt:              refund.SignedAt,
+		Payload:               []byte(`{}`),
+	}
+
+	// A notification for a purchase the app hasn't reported is not recorded
+	if _, _, err := repo.ApplySubscriptionEvent(ctx, event, &refund); !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Fatalf("Expected ErrSubscriptionNotFound, got %v", err)
+	}
+	var count int
+	db.QueryRow(ctx, "SELECT count(*) FROM public.subscription_events").Scan(&count)
+	if count != 0 {
+		t.Errorf("Expected no recorded events, got %d", count)
+	}
+
+	// So its redelivery applies once the purchase is stored
+	if _, err := repo.SaveVerifiedSubscription(ctx, userID, purchase); err != nil {
+		t.Fatalf("Failed to save subscription: %v", err)
+	}
+	_, applied, err := repo.ApplySubscriptionEvent(ctx, event, &refund)
+	if err != nil {
+		t.Fatalf("Failed to apply event: %v", err)
+	}
+	if !applied {
+		t.Error("Expected the redelivered notification to be applied")
+	}
+	sub, _ := repo.GetSubscriptionByUserID(ctx, userID, userID)
+	if sub.Status != models.SubscriptionRevoked {
+		t.Errorf("Expected the subscription to be revoked, got %q", sub.Status)
 	}
 }
@@ -56,7 +56,10 @@ func cleanupTables(t *testing.T, db *pgxpool.Pool) {
       public.workout_sets,
       public.workout_images,
       public.comment_likes,
-      public.comments
+      public.comments,
+      public.blob_deletions,
+      public.admin_audit_log,
+      public.reports
     RESTART IDENTITY CASCADE`
 
 	_, err := db.Exec(context.Background(), query)
@@ -0,0 +1,35 @@
+package repository
+
+const insertUploadQuery = `
+	INSERT INTO public.uploads (user_id, object_key, content_type, size_bytes, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, user_id, object_key, content_type, size_bytes, status, created_at, expires_at
+`
+
+const getPendingUploadQuery = `
+	SELECT id, user_id, object_key, content_type, size_bytes, status, created_at, expires_at
+	FROM public.uploads
+	WHERE id = $1
+	AND user_id = $2
+	AND status = 'pending'
+	AND expires_at > now()
+`
+
+// commitUploadQuery claims a pending upload, so each is used at most once.
+const commitUploadQuery = `
+	UPDATE public.uploads
+	SET status = 'committed'
+	WHERE id = $1
+	AND user_id = $2
+	AND status = 'pending'
+	AND expires_at > now()
+	RETURNING id
+`
+
+// deleteExpiredUploadsQuery removes pending uploads that can no longer be
+// committed. Their objects are queued for deletion by a trigger.
+const deleteExpiredUploadsQuery = `
+	DELETE FROM public.uploads
+	WHERE status = 'pending'
+	AND expires_at <= now()
+`
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
+)
+
+type UploadRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewUploadRepository(db *pgxpool.Pool) *UploadRepository {
+	return &UploadRepository{
+		DB: db,
+	}
+}
+
+func (r *UploadRepository) CreateUpload(
+	ctx context.Context,
+	userID uuid.UUID,
+	objectKey string,
+	contentType string,
+	sizeBytes int64,
+	expiresAt time.Time,
+) (*models.Upload, error) {
+	var u models.Upload
+
+	err := r.DB.QueryRow(ctx, insertUploadQuery, userID, objectKey, contentType, sizeBytes, expiresAt).Scan(
+		&u.ID,
+		&u.UserID,
+		&u.ObjectKey,
+		&u.ContentType,
+		&u.SizeBytes,
+		&u.Status,
+		&u.CreatedAt,
+		&u.ExpiresAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23505":
+				return nil, ErrAlreadyExists
+			case "23503":
+				return nil, ErrReferenceViolation
+			}
+		}
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	return &u, nil
+}
+
+// GetPendingUpload returns one of the user's uploads that can still be
+// committed.
+func (r *UploadRepository) GetPendingUpload(
+	ctx context.Context,
+	id uuid.UUID,
+	userID uuid.UUID,
+) (*models.Upload, error) {
+	var u models.Upload
+
+	err := r.DB.QueryRow(ctx, getPendingUploadQuery, id, userID).Scan(
+		&u.ID,
+		&u.UserID,
+		&u.ObjectKey,
+		&u.ContentType,
+		&u.SizeBytes,
+		&u.Status,
+		&u.CreatedAt,
+		&u.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to get upload: %w", err)
+	}
+
+	return &u, nil
+}
+
+// DeleteExpiredUploads removes pending uploads past their commit window and
+// queues their objects for the blob sweeper. It runs as a background job.
+func (r *UploadRepository) DeleteExpiredUploads(ctx context.Context) error {
+	if _, err := r.DB.Exec(ctx, deleteExpiredUploadsQuery); err != nil {
+		return fmt.Errorf("failed to delete expired uploads: %w", err)
+	}
+	return nil
+}
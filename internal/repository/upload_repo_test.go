@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+func TestCreateAndGetPendingUpload(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewUploadRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "uploader")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
+
+	u, err := repo.CreateUpload(ctx, userID, "uploads/a", "image/jpeg", 1024, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create upload: %v", err)
+	}
+	if u.Status != models.UploadPending {
+		t.Errorf("Expected status %q, got %q", models.UploadPending, u.Status)
+	}
+
+	got, err := repo.GetPendingUpload(ctx, u.ID, userID)
+	if err != nil {
+		t.Fatalf("Failed to get upload: %v", err)
+	}
+	if got.ObjectKey != "uploads/a" || got.SizeBytes != 1024 {
+		t.Errorf("Unexpected upload %+v", got)
+	}
+
+	if _, err := repo.GetPendingUpload(ctx, u.ID, otherID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("Expected ErrUploadNotFound for another user, got %v", err)
+	}
+
+	if _, err := repo.CreateUpload(ctx, userID, "uploads/a", "image/jpeg", 1, time.Now().Add(time.Hour)); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists for a reused key, got %v", err)
+	}
+}
+
+func TestGetPendingUploadExpired(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewUploadRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "uploader")
+	u, _ := repo.CreateUpload(ctx, userID, "uploads/old", "image/png", 10, time.Now().Add(-time.Minute))
+
+	if _, err := repo.GetPendingUpload(ctx, u.ID, userID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("Expected ErrUploadNotFound, got %v", err)
+	}
+	if _, err := repo.GetPendingUpload(ctx, uuid.New(), userID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("Expected ErrUploadNotFound, got %v", err)
+	}
+}
+
+func TestDeleteExpiredUploads(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewUploadRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "uploader")
+	expired, _ := repo.CreateUpload(ctx, userID, "uploads/expired", "image/jpeg", 10, time.Now().Add(-time.Minute))
+	pending, _ := repo.CreateUpload(ctx, userID, "uploads/pending", "image/jpeg", 10, time.Now().Add(time.Hour))
+
+	if err := repo.DeleteExpiredUploads(ctx); err != nil {
+		t.Fatalf("Failed to delete expired uploads: %v", err)
+	}
+
+	var count int
+	db.QueryRow(ctx, "SELECT count(*) FROM uploads WHERE id = $1", expired.ID).Scan(&count)
+	if count != 0 {
+		t.Error("Expected the expired upload to be deleted")
+	}
+	if _, err := repo.GetPendingUpload(ctx, pending.ID, userID); err != nil {
+		t.Errorf("Expected the pending upload to remain, got %v", err)
+	}
+
+	var keys []string
+	db.QueryRow(ctx, "SELECT array_agg(object_key ORDER BY object_key) FROM blob_deletions").Scan(&keys)
+	if len(keys) != 1 || keys[0] != "uploads/expired" {
+		t.Errorf("Expected only the expired object to be queued, got %v", keys)
+	}
+
+	// Deleting the profile queues the rest of its raw uploads
+	if _, err := db.Exec(ctx, "DELETE FROM profiles WHERE id = $1", userID); err != nil {
+		t.Fatalf("Failed to delete profile: %v", err)
+	}
+	db.QueryRow(ctx, "SELECT array_agg(object_key ORDER BY object_key) FROM blob_deletions").Scan(&keys)
+	if len(keys) != 2 || keys[1] != "uploads/pending" {
+		t.Errorf("Expected the pending object to be queued, got %v", keys)
+	}
+}
@@ -25,10 +25,19 @@ const insertUserIdentityQuery = `
 			RETURNING user_id;
 `
 
+// updateUserIdentityQuery also returns the account's status, which decides
+// whether the sign-in may proceed.
 const updateUserIdentityQuery = `
-			UPDATE user_identities 
+			UPDATE user_identities ui
 			SET last_sign_in_at = now(), provider_email = $2
-			WHERE provider_name = 'google' AND provider_user_id = $1
+			FROM public.profiles p
+			WHERE ui.provider_name = 'google' AND ui.provider_user_id = $1
+			AND p.id = ui.user_id
+			RETURNING p.account_status, p.suspended_until
+`
+
+const isAccountActiveQuery = `
+  SELECT public.is_account_active($1)
 `
 
 const getProfileByIDQuery = `
@@ -39,41 +48,51 @@ const getProfileByIDQuery = `
 			p.bio,
 
 			CASE 
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
+					WHEN v.can_view 
 					THEN p.location 
 					ELSE '' 
 			END AS location,
 
 			CASE 
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
+					WHEN v.can_view 
 					THEN p.birth_date 
 					ELSE '0001-01-01' -- Go's time.Time zero value equivalent
 			END AS birth_date,
 
 			p.avatar_url,
-			p.subscription_plan,
+			public.subscription_plan(p.id) AS subscription_plan,
 			p.is_private_account,
 
 			CASE 
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
+					WHEN v.can_view 
 					THEN p.last_worked_out_at 
 					ELSE '0001-01-01' -- Go's time.Time zero value equivalent
 			END AS last_worked_out_at,
 
 			CASE 
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
+					WHEN v.can_view 
 					THEN p.total_workouts 
 					ELSE 0 
 			END AS total_workouts,
 
 			CASE 
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
-					THEN p.current_streak 
+					WHEN v.can_view 
+					THEN CASE
+							-- A streak nobody extended in time has lapsed even without a recompute
+							WHEN p.streak_expires_at IS NOT NULL AND p.streak_expires_at <= now() THEN 0
+							ELSE p.current_streak
+					END
 					ELSE 0 
 			END AS current_streak,
 
 			CASE 
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
+					WHEN v.can_view 
+					THEN p.longest_streak 
+					ELSE 0 
+			END AS longest_streak,
+
+			CASE 
+					WHEN v.can_view 
 					THEN p.total_weight 
 					ELSE 0 
 			END AS total_weight,
@@ -83,23 +102,18 @@ const getProfileByIDQuery = `
 			p.created_at,
 
 			CASE
-					WHEN p.id = $1 OR p.is_private_account = false OR f.status = 'accepted' 
+					WHEN v.can_view 
 					THEN p.updated_at 
 					ELSE '0001-01-01' -- Go's time.Time zero value equivalent
 			END AS updated_at
 
 			FROM public.profiles p
-			-- Check if the viewer is following the target
-			LEFT JOIN public.follows f
-			ON f.following_id = p.id AND f.follower_id = $1
-			-- Check if the target is not blocked (either the target blocked the viewer or the viewer blocked the target)
-			LEFT JOIN public.blocked_users b 
-			ON (b.blocker_id = p.id AND b.blocked_id = $1) 
-			OR (b.blocker_id = $1 AND b.blocked_id = p.id)
+			-- Visibility Policy: whether the viewer may see the target's details (see can_view_user)
+			CROSS JOIN LATERAL (SELECT public.can_view_user($1, p.id) AS can_view) v
 
 			WHERE p.id = $2
 			-- If there is any block relationship, return no rows (treat it as non-existent)
-			AND b.blocker_id IS NULL;
+			AND NOT public.is_blocked_between($1, p.id);
 `
 
 const deleteProfileByIDQuery = `
@@ -108,6 +122,12 @@ const deleteProfileByIDQuery = `
 			WHERE id = $1
 `
 
+const setAvatarQuery = `
+			UPDATE public.profiles
+			SET avatar_url = $2, avatar_keys = $3
+			WHERE id = $1
+`
+
 const getUserSettingsByIDQuery = `
 			SELECT 
 			user_id,
@@ -121,6 +141,11 @@ const getUserSettingsByIDQuery = `
 			unit_weight,
 			unit_distance,
 			unit_length,
+			timezone,
+			streak_rule,
+			streak_weekly_target,
+			quiet_hours_start,
+			quiet_hours_end,
 			created_at,
 			updated_at
 			FROM public.user_settings
@@ -42,16 +42,17 @@ func (r *UserRepository) UpsertGoogleUser(
 		return &models.Profile{ID: userID}, nil
 	} else if err != nil {
 		return nil, err
-	} else {
-		// If the user is existing, update the last sign in time
-		_, err = r.DB.Exec(ctx, updateUserIdentityQuery, googleID, email)
-		if err != nil {
-			return nil, fmt.Errorf("failed to update login time: %w", err)
-		}
+	}
+
+	// If the user is existing, update the last sign in time
+	user := &models.Profile{ID: identity.UserID}
+	err = r.DB.QueryRow(ctx, updateUserIdentityQuery, googleID, email).Scan(&user.AccountStatus, &user.SuspendedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update login time: %w", err)
 	}
 
 	// Return the Profile with the userID
-	return &models.Profile{ID: identity.UserID}, nil
+	return user, nil
 }
 
 func (r *UserRepository) GetIdentityByProvider(
@@ -79,6 +80,16 @@ func (r *UserRepository) GetIdentityByProvider(
 	return &userIdentity, nil
 }
 
+// IsAccountActive reports whether userID is neither banned nor serving a
+// suspension. Unknown users count as active; their tokens fail elsewhere.
+func (r *UserRepository) IsAccountActive(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var active bool
+	if err := r.DB.QueryRow(ctx, isAccountActiveQuery, userID).Scan(&active); err != nil {
+		return false, fmt.Errorf("failed to check account status: %w", err)
+	}
+	return active, nil
+}
+
 func (r *UserRepository) GetProfileByID(
 	ctx context.Context,
 	viewerID uuid.UUID,
@@ -91,14 +102,15 @@ func (r *UserRepository) GetProfileByID(
 		&profile.Username,
 		&profile.DisplayName,
 		&profile.Bio,
-		&profile.AvatarURL,
 		&profile.Location,
 		&profile.BirthDate,
+		&profile.AvatarURL,
 		&profile.SubscriptionPlan,
 		&profile.IsPrivateAccount,
 		&profile.LastWorkedOutAt,
 		&profile.TotalWorkouts,
 		&profile.CurrentStreak,
+		&profile.LongestStreak,
 		&profile.TotalWeight,
 		&profile.FollowersCount,
 		&profile.FollowingCount,
@@ -168,24 +180,6 @@ func (r *UserRepository) UpdateProfile(
 		}
 		i++
 	}
-	if updates.AvatarURL != nil {
-		sets = append(sets, fmt.Sprintf("avatar_url = $%d", i))
-		if *updates.AvatarURL == "" {
-			args = append(args, nil)
-		} else {
-			args = append(args, *updates.AvatarURL)
-		}
-		i++
-	}
-	if updates.SubscriptionPlan != nil {
-		sets = append(sets, fmt.Sprintf("subscription_plan = $%d", i))
-		if *updates.SubscriptionPlan == "" {
-			args = append(args, nil)
-		} else {
-			args = append(args, *updates.SubscriptionPlan)
-		}
-		i++
-	}
 	if updates.IsPrivateAccount != nil {
 		sets = append(sets, fmt.Sprintf("is_private_account = $%d", i))
 		args = append(args, *updates.IsPrivateAccount)
@@ -203,7 +197,13 @@ func (r *UserRepository) UpdateProfile(
 	)
 	args = append(args, id)
 
-	res, err := r.DB.Exec(ctx, query, args...)
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx, query, args...)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -216,6 +216,17 @@ func (r *UserRepository) UpdateProfile(
 		return ErrProfileNotFound
 	}
 
+	// A public account has nobody left to approve its pending requests
+	if updates.IsPrivateAccount != nil && !*updates.IsPrivateAccount {
+		if _, err := tx.Exec(ctx, acceptAllPendingFollowsQuery, id); err != nil {
+			return fmt.Errorf("failed to accept pending follows: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -232,6 +243,57 @@ func (r *UserRepository) DeleteProfile(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// SetAvatarFromUpload commits an upload as the user's avatar. The previous
+// avatar's objects are queued for deletion by the profiles trigger.
+func (r *UserRepository) SetAvatarFromUpload(
+	ctx context.Context,
+	userID uuid.UUID,
+	uploadID uuid.UUID,
+	avatarURL string,
+	keys []string,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var committedID uuid.UUID
+	err = tx.QueryRow(ctx, commitUploadQuery, uploadID, userID).Scan(&committedID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUploadNotFound
+		}
+		return fmt.Errorf("failed to commit upload: %w", err)
+	}
+
+	res, err := tx.Exec(ctx, setAvatarQuery, userID, avatarURL, keys)
+	if err != nil {
+		return fmt.Errorf("failed to set avatar: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrProfileNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ClearAvatar removes the user's avatar, queueing its objects for deletion.
+func (r *UserRepository) ClearAvatar(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.DB.Exec(ctx, setAvatarQuery, userID, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to clear avatar: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrProfileNotFound
+	}
+	return nil
+}
+
 func (r *UserRepository) GetUserSettingsByID(
 	ctx context.Context,
 	id uuid.UUID,
@@ -251,6 +313,11 @@ func (r *UserRepository) GetUserSettingsByID(
 		&userSetting.UnitWeight,
 		&userSetting.UnitDistance,
 		&userSetting.UnitLength,
+		&userSetting.Timezone,
+		&userSetting.StreakRule,
+		&userSetting.StreakWeeklyTarget,
+		&userSetting.QuietHoursStart,
+		&userSetting.QuietHoursEnd,
 		&userSetting.CreatedAt,
 		&userSetting.UpdatedAt,
 	)
@@ -321,25 +388,67 @@ func (r *UserRepository) UpdateUserSettings(
 		args = append(args, *updates.UnitLength)
 		i++
 	}
+	if updates.Timezone != nil {
+		sets = append(sets, fmt.Sprintf("timezone = $%d", i))
+		args = append(args, *updates.Timezone)
+		i++
+	}
+	if updates.StreakRule != nil {
+		sets = append(sets, fmt.Sprintf("streak_rule = $%d", i))
+		args = append(args, *updates.StreakRule)
+		i++
+	}
+	if updates.StreakWeeklyTarget != nil {
+		sets = append(sets, fmt.Sprintf("streak_weekly_target = $%d", i))
+		args = append(args, *updates.StreakWeeklyTarget)
+		i++
+	}
+	if updates.QuietHoursStart != nil {
+		sets = append(sets, fmt.Sprintf("quiet_hours_start = NULLIF($%d, '')", i))
+		args = append(args, *updates.QuietHoursStart)
+		i++
+	}
+	if updates.QuietHoursEnd != nil {
+		sets = append(sets, fmt.Sprintf("quiet_hours_end = NULLIF($%d, '')", i))
+		args = append(args, *updates.QuietHoursEnd)
+		i++
+	}
 
 	if len(sets) == 0 {
 		return nil
 	}
 
 	query := fmt.Sprintf(
-		"UPDATE user_settings SET %s WHERE user_id = $%d",
+		"UPDATE user_settings SET %s WHERE user_id = $%d RETURNING user_id",
 		strings.Join(sets, ", "),
 		i,
 	)
 	args = append(args, id)
 
-	res, err := r.DB.Exec(ctx, query, args...)
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID uuid.UUID
+	err = tx.QueryRow(ctx, query, args...).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
 		return fmt.Errorf("failed to update user settings: %w", err)
 	}
 
-	if res.RowsAffected() == 0 {
-		return ErrNotFound
+	// Changing the timezone or rule changes how existing workouts count
+	if updates.Timezone != nil || updates.StreakRule != nil || updates.StreakWeeklyTarget != nil {
+		if err := recomputeStreak(ctx, tx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
 )
 
@@ -268,8 +270,8 @@ func TestNullFieldsGetProfileByID(t *testing.T) {
 	if profile.AvatarURL != nil {
 		t.Errorf("AvatarURL is not nil: got %v, want nil", profile.AvatarURL)
 	}
-	if profile.SubscriptionPlan != nil {
-		t.Errorf("SubscriptionPlan is not nil: got %v, want nil", profile.SubscriptionPlan)
+	if profile.SubscriptionPlan == nil || *profile.SubscriptionPlan != "free" {
+		t.Errorf("SubscriptionPlan is not free: got %v, want free", profile.SubscriptionPlan)
 	}
 	if profile.IsPrivateAccount {
 		t.Errorf("IsPrivateAccount is not false: got %v, want false", profile.IsPrivateAccount)
@@ -521,6 +523,99 @@ func TestNotFoundDeleteProfile(t *testing.T) {
 	}
 }
 
+func TestSetAvatarFromUpload(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewUserRepository(db)
+	uploadRepo := NewUploadRepository(db)
+	ctx := context.Background()
+
+	id, _, err := testutil.InsertProfile(ctx, db, "testuser")
+	if err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+
+	first, _ := uploadRepo.CreateUpload(ctx, id, "uploads/first", "image/jpeg", 10, time.Now().Add(time.Hour))
+	err = repo.SetAvatarFromUpload(ctx, id, first.ID, "https://api.example/avatars/1", []string{"avatars/1/512.jpg", "avatars/1/128.jpg"})
+	if err != nil {
+		t.Fatalf("Failed to set avatar: %v", err)
+	}
+
+	profile, err := repo.GetProfileByID(ctx, id, id)
+	if err != nil {
+		t.Fatalf("Profile not found: %v", err)
+	}
+	if profile.AvatarURL == nil || *profile.AvatarURL != "https://api.example/avatars/1" {
+		t.Errorf("Expected the avatar URL to be set, got %v", profile.AvatarURL)
+	}
+
+	// An upload is only used once
+	err = repo.SetAvatarFromUpload(ctx, id, first.ID, "https://api.example/avatars/x", []string{"avatars/x/512.jpg"})
+	if !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("Expected ErrUploadNotFound for a committed upload, got %v", err)
+	}
+
+	// Replacing the avatar queues the old objects
+	second, _ := uploadRepo.CreateUpload(ctx, id, "uploads/second", "image/jpeg", 10, time.Now().Add(time.Hour))
+	err = repo.SetAvatarFromUpload(ctx, id, second.ID, "https://api.example/avatars/2", []string{"avatars/2/512.jpg", "avatars/2/128.jpg"})
+	if err != nil {
+		t.Fatalf("Failed to replace avatar: %v", err)
+	}
+	assertQueuedBlobs(t, db, "avatars/1/128.jpg", "avatars/1/512.jpg")
+
+	// So does deleting the profile, along with its raw uploads
+	if err := repo.DeleteProfile(ctx, id); err != nil {
+		t.Fatalf("Failed to delete profile: %v", err)
+	}
+	assertQueuedBlobs(t, db,
+		"avatars/1/128.jpg", "avatars/1/512.jpg", "avatars/2/128.jpg", "avatars/2/512.jpg",
+		"uploads/first", "uploads/second",
+	)
+}
+
+func TestClearAvatar(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewUserRepository(db)
+	uploadRepo := NewUploadRepository(db)
+	ctx := context.Background()
+
+	id, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	u, _ := uploadRepo.CreateUpload(ctx, id, "uploads/a", "image/jpeg", 10, time.Now().Add(time.Hour))
+	repo.SetAvatarFromUpload(ctx, id, u.ID, "https://api.example/avatars/1", []string{"avatars/1/512.jpg"})
+
+	if err := repo.ClearAvatar(ctx, id); err != nil {
+		t.Fatalf("Failed to clear avatar: %v", err)
+	}
+	profile, _ := repo.GetProfileByID(ctx, id, id)
+	if profile.AvatarURL != nil {
+		t.Errorf("Expected no avatar URL, got %v", *profile.AvatarURL)
+	}
+	assertQueuedBlobs(t, db, "avatars/1/512.jpg")
+
+	if err := repo.ClearAvatar(ctx, uuid.New()); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Expected ErrProfileNotFound, got %v", err)
+	}
+}
+
+// assertQueuedBlobs checks which object keys are queued for deletion, in
+// any order.
+func assertQueuedBlobs(t *testing.T, db *pgxpool.Pool, want ...string) {
+	t.Helper()
+	var got []string
+	err := db.QueryRow(context.Background(),
+		"SELECT COALESCE(array_agg(object_key), '{}') FROM blob_deletions",
+	).Scan(&got)
+	if err != nil {
+		t.Fatalf("Failed to read blob deletions: %v", err)
+	}
+	slices.Sort(got)
+	want = slices.Sorted(slices.Values(want))
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected queued blobs %v, got %v", want, got)
+	}
+}
+
 // Test GetIdentitiesByUserID Functionality
 func TestGetIdentitiesByUserID(t *testing.T) {
 	db := testutil.SetupTestDB(t)
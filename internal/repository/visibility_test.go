@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
+)
+
+// TestVisibilityPolicy checks every viewer-scoped read path against the
+// viewers that must not see a private account's content. An accepted
+// follower is included as the control that can see everything.
+func TestVisibilityPolicy(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	userRepo := NewUserRepository(db)
+	followRepo := NewFollowRepository(db)
+	blockRepo := NewBlockedUserRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	exerciseRepo := NewExerciseRepository(db)
+	weRepo := NewWorkoutExerciseRepository(db)
+	wsRepo := NewWorkoutSetRepository(db)
+	wiRepo := NewWorkoutImageRepository(db)
+	commentRepo := NewCommentRepository(db)
+	likeRepo := NewWorkoutLikeRepository(db)
+	routineRepo := NewRoutineRepository(db)
+	prRepo := NewPersonalRecordRepository(db)
+	forYouRepo := NewForYouRepository(db)
+
+	ownerID, _, _ := testutil.InsertProfile(ctx, db, "owner")
+	blockedID, _, _ := testutil.InsertProfile(ctx, db, "blocked")
+	strangerID, _, _ := testutil.InsertProfile(ctx, db, "stranger")
+	pendingID, _, _ := testutil.InsertProfile(ctx, db, "pending")
+	followerID, _, _ := testutil.InsertProfile(ctx, db, "follower")
+
+	// The follower is accepted while the account is still public
+	followRepo.Follow(ctx, followerID, ownerID)
+	_, err := db.Exec(ctx, "UPDATE public.profiles SET is_private_account = true WHERE id = $1", ownerID)
+	if err != nil {
+		t.Fatalf("Failed to make profile private: %v", err)
+	}
+	followRepo.Follow(ctx, pendingID, ownerID)
+	followRepo.Follow(ctx, blockedID, ownerID)
+	if _, err := blockRepo.Block(ctx, ownerID, blockedID); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+
+	// The owner's content
+	startedAt := time.Now().Add(-time.Hour)
+	workout, err := workoutRepo.Create(ctx, ownerID, nil, nil, startedAt, startedAt.Add(time.Minute), 60)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	exercise, _ := exerciseRepo.CreateExercise(ctx, &ownerID, "Secret Lift", nil, nil, ownerID)
+	we, _ := weRepo.CreateWorkoutExercise(ctx, workout.ID, exercise.ID, 0, nil, nil, ownerID)
+	weight := 100.0
+	reps := 5
+	ws, _ := wsRepo.CreateWorkoutSet(ctx, we.ID, &weight, &reps, 0, ownerID)
+	wi, _ := addUploadedImage(t, db, workout.ID, ownerID, nil)
+	comment, _ := commentRepo.CreateComment(ctx, ownerID, workout.ID, nil, "private thoughts")
+	likeRepo.LikeWorkout(ctx, ownerID, workout.ID)
+	routine, _ := routineRepo.CreateRoutine(ctx, ownerID, "Secret Routine")
+	if err := forYouRepo.Refresh(ctx); err != nil {
+		t.Fatalf("Failed to refresh for-you candidates: %v", err)
+	}
+
+	// Each read reports how many of the owner's items the viewer can see
+	reads := []struct {
+		name string
+		read func(viewerID uuid.UUID) int
+	}{
+		{"profile stats", func(v uuid.UUID) int {
+			p, err := userRepo.GetProfileByID(ctx, v, ownerID)
+			if err != nil {
+				return 0
+			}
+			return p.TotalWorkouts
+		}},
+		{"workout by id", func(v uuid.UUID) int {
+			w, _ := workoutRepo.GetWorkoutByID(ctx, workout.ID, v)
+			return boolCount(w != nil)
+		}},
+		{"workouts by user", func(v uuid.UUID) int {
+			ws, _ := workoutRepo.GetWorkoutsByUserID(ctx, ownerID, v, nil, 10)
+			return len(ws)
+		}},
+		{"user timeline", func(v uuid.UUID) int {
+			ws, _ := workoutRepo.GetTimelineWorkouts(ctx, v, ownerID, models.TimelineIncludeAll, nil, 10)
+			return len(ws)
+		}},
+		{"for-you timeline", func(v uuid.UUID) int {
+			ws, _ := forYouRepo.GetForYouTimelineWorkouts(ctx, v, models.TimelineIncludeAll, nil, 10)
+			return len(ws)
+		}},
+		{"following timeline", func(v uuid.UUID) int {
+			ws, _ := workoutRepo.GetFollowingTimelineWorkouts(ctx, v, models.TimelineIncludeAll, nil, 10)
+			return len(ws)
+		}},
+		{"workout exercise by id", func(v uuid.UUID) int {
+			e, _ := weRepo.GetWorkoutExerciseByID(ctx, we.ID, v)
+			return boolCount(e != nil)
+		}},
+		{"workout exercises", func(v uuid.UUID) int {
+			es, _ := weRepo.GetWorkoutExercisesByWorkoutID(ctx, workout.ID, v)
+			return len(es)
+		}},
+		{"workout set by id", func(v uuid.UUID) int {
+			s, _ := wsRepo.GetWorkoutSetByID(ctx, ws.ID, v)
+			return boolCount(s != nil)
+		}},
+		{"workout sets", func(v uuid.UUID) int {
+			ss, _ := wsRepo.GetWorkoutSetsByWorkoutExerciseID(ctx, we.ID, v)
+			return len(ss)
+		}},
+		{"workout image by id", func(v uuid.UUID) int {
+			i, _ := wiRepo.GetWorkoutImageByID(ctx, wi.ID, v)
+			return boolCount(i != nil)
+		}},
+		{"workout images", func(v uuid.UUID) int {
+			is, _ := wiRepo.GetWorkoutImagesByWorkoutID(ctx, workout.ID, v)
+			return len(is)
+		}},
+		{"comment by id", func(v uuid.UUID) int {
+			c, _ := commentRepo.GetCommentByUserID(ctx, comment.ID, v)
+			return boolCount(c != nil)
+		}},
+		{"comments", func(v uuid.UUID) int {
+			cs, _ := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, v, nil, 10)
+			return len(cs)
+		}},
+		{"workout likes", func(v uuid.UUID) int {
+			ls, _ := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, v, nil, 10)
+			return len(ls)
+		}},
+		{"custom exercise", func(v uuid.UUID) int {
+			e, _ := exerciseRepo.GetExerciseByID(ctx, exercise.ID, v)
+			return boolCount(e != nil)
+		}},
+		{"routine by id", func(v uuid.UUID) int {
+			r, _ := routineRepo.GetRoutineByID(ctx, routine.ID, v)
+			return boolCount(r != nil)
+		}},
+		{"personal records", func(v uuid.UUID) int {
+			prs, _ := prRepo.GetPersonalRecords(ctx, ownerID, v, nil)
+			return len(prs)
+		}},
+		{"followers", func(v uuid.UUID) int {
+			fs, _ := followRepo.GetFollowers(ctx, ownerID, v, nil, 10)
+			return len(fs)
+		}},
+	}
+
+	viewers := []struct {
+		name    string
+		id      uuid.UUID
+		canView bool
+	}{
+		{"blocked", blockedID, false},
+		{"private non-follower", strangerID, false},
+		{"pending follower", pendingID, false},
+		{"accepted follower", followerID, true},
+	}
+
+	for _, viewer := range viewers {
+		for _, read := range reads {
+			t.Run(viewer.name+"/"+read.name, func(t *testing.T) {
+				got := read.read(viewer.id)
+				if viewer.canView && got == 0 {
+					t.Errorf("%s should see %s", viewer.name, read.name)
+				}
+				if !viewer.canView && got != 0 {
+					t.Errorf("%s should not see %s, got %d item(s)", viewer.name, read.name, got)
+				}
+			})
+		}
+	}
+
+	// Comments by a banned user or one the viewer blocked stay hidden, even
+	// on a workout the viewer can see
+	bannedID, _, _ := testutil.InsertProfile(ctx, db, "banned")
+	rudeID, _, _ := testutil.InsertProfile(ctx, db, "rude")
+	insertComment := func(userID uuid.UUID) uuid.UUID {
+		t.Helper()
+		var id uuid.UUID
+		err := db.QueryRow(ctx,
+			"INSERT INTO public.comments (user_id, workout_id, content) VALUES ($1, $2, 'hello') RETURNING id",
+			userID, workout.ID,
+		).Scan(&id)
+		if err != nil {
+			t.Fatalf("Failed to insert comment: %v", err)
+		}
+		return id
+	}
+	hiddenComments := map[string]uuid.UUID{
+		"banned commenter":  insertComment(bannedID),
+		"blocked commenter": insertComment(rudeID),
+	}
+	if _, err := db.Exec(ctx, "UPDATE public.profiles SET account_status = 'banned' WHERE id = $1", bannedID); err != nil {
+		t.Fatalf("Failed to ban user: %v", err)
+	}
+	if _, err := blockRepo.Block(ctx, followerID, rudeID); err != nil {
+		t.Fatalf("Failed to block: %v", err)
+	}
+
+	for name, commentID := range hiddenComments {
+		t.Run(name, func(t *testing.T) {
+			if _, err := commentRepo.GetCommentByUserID(ctx, commentID, followerID); !errors.Is(err, ErrCommentNotFound) {
+				t.Errorf("Expected ErrCommentNotFound, got %v", err)
+			}
+		})
+	}
+	if cs, _ := commentRepo.GetCommentsByWorkoutID(ctx, workout.ID, followerID, nil, 10); len(cs) != 1 {
+		t.Errorf("Expected only the owner's comment, got %d", len(cs))
+	}
+}
+
+func boolCount(ok bool) int {
+	if ok {
+		return 1
+	}
+	return 0
+}
@@ -1,16 +1,22 @@
 package repository
 
 const getWorkoutExerciseByIDQuery = `
-	SELECT id, workout_id, exercise_id, order_index, memo, rest_timer_seconds, created_at, updated_at
-	FROM public.workout_exercises
-	WHERE id = $1
+	SELECT we.id, we.workout_id, we.exercise_id, we.order_index, we.memo, we.rest_timer_seconds, we.created_at, we.updated_at
+	FROM public.workout_exercises we
+	JOIN public.workouts w ON we.workout_id = w.id
+	WHERE we.id = $1
+	-- Visibility Policy: viewer may see the owner's content (see can_view_content)
+	AND public.can_view_content($2, w.user_id, w.hidden_at)
 `
 
 const getWorkoutExercisesByWorkoutIDQuery = `
-	SELECT id, workout_id, exercise_id, order_index, memo, rest_timer_seconds, created_at, updated_at
-	FROM public.workout_exercises
-	WHERE workout_id = $1
-	ORDER BY order_index ASC NULLS LAST, created_at ASC
+	SELECT we.id, we.workout_id, we.exercise_id, we.order_index, we.memo, we.rest_timer_seconds, we.created_at, we.updated_at
+	FROM public.workout_exercises we
+	JOIN public.workouts w ON we.workout_id = w.id
+	WHERE we.workout_id = $1
+	-- Visibility Policy: viewer may see the owner's content (see can_view_content)
+	AND public.can_view_content($2, w.user_id, w.hidden_at)
+	ORDER BY we.order_index ASC NULLS LAST, we.created_at ASC
 `
 
 const insertWorkoutExerciseQuery = `
@@ -26,11 +32,11 @@ const insertWorkoutExerciseQuery = `
 `
 
 const deleteWorkoutExerciseByIDQuery = `
-  DELETE FROM public.workout_exercises
-  WHERE id = $1
-  AND workout_id IN (
-      -- Guard: Only allow deletion if the workout belongs to the requester
-      SELECT id FROM public.workouts 
-      WHERE (user_id = $2 OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2))
-  )
+  DELETE FROM public.workout_exercises we
+  USING public.workouts w
+  WHERE we.id = $1
+    AND w.id = we.workout_id
+    -- Guard: Only allow deletion if the workout belongs to the requester
+    AND (w.user_id = $2 OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2))
+  RETURNING w.user_id, we.exercise_id
 `
@@ -76,10 +76,11 @@ func (r *WorkoutExerciseRepository) CreateWorkoutExercise(
 func (r *WorkoutExerciseRepository) GetWorkoutExerciseByID(
 	ctx context.Context,
 	id uuid.UUID,
+	viewerID uuid.UUID,
 ) (*models.WorkoutExercise, error) {
 	var we models.WorkoutExercise
 
-	err := r.DB.QueryRow(ctx, getWorkoutExerciseByIDQuery, id).Scan(
+	err := r.DB.QueryRow(ctx, getWorkoutExerciseByIDQuery, id, viewerID).Scan(
 		&we.ID,
 		&we.WorkoutID,
 		&we.ExerciseID,
@@ -102,8 +103,9 @@ func (r *WorkoutExerciseRepository) GetWorkoutExerciseByID(
 func (r *WorkoutExerciseRepository) GetWorkoutExercisesByWorkoutID(
 	ctx context.Context,
 	workoutID uuid.UUID,
+	viewerID uuid.UUID,
 ) ([]*models.WorkoutExercise, error) {
-	rows, err := r.DB.Query(ctx, getWorkoutExercisesByWorkoutIDQuery, workoutID)
+	rows, err := r.DB.Query(ctx, getWorkoutExercisesByWorkoutIDQuery, workoutID, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workout exercises: %w", err)
 	}
@@ -203,13 +205,29 @@ func (r *WorkoutExerciseRepository) DeleteWorkoutExercise(
 	workoutExerciseID uuid.UUID,
 	userID uuid.UUID,
 ) error {
-	commandTag, err := r.DB.Exec(ctx, deleteWorkoutExerciseByIDQuery, workoutExerciseID, userID)
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var ownerID, exerciseID uuid.UUID
+	err = tx.QueryRow(ctx, deleteWorkoutExerciseByIDQuery, workoutExerciseID, userID).Scan(&ownerID, &exerciseID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkoutExerciseNotFound
+		}
 		return fmt.Errorf("failed to delete workout exercise: %w", err)
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return ErrWorkoutExerciseNotFound
+	// The workout's volume record goes with its sets, and later sessions may
+	// now hold the records they held
+	if err := recomputeExercisePersonalRecords(ctx, tx, ownerID, exerciseID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
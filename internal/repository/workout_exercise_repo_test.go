@@ -90,7 +90,7 @@ func TestGetWorkoutExerciseByID(t *testing.T) {
 		t.Fatalf("Failed to create workout exercise: %v", err)
 	}
 
-	we, err := weRepo.GetWorkoutExerciseByID(ctx, created.ID)
+	we, err := weRepo.GetWorkoutExerciseByID(ctx, created.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to get workout exercise: %v", err)
 	}
@@ -106,7 +106,7 @@ func TestGetWorkoutExerciseByIDNotFound(t *testing.T) {
 	weRepo := NewWorkoutExerciseRepository(db)
 	ctx := context.Background()
 
-	_, err := weRepo.GetWorkoutExerciseByID(ctx, uuid.New())
+	_, err := weRepo.GetWorkoutExerciseByID(ctx, uuid.New(), uuid.New())
 	if !errors.Is(err, ErrWorkoutExerciseNotFound) {
 		t.Errorf("Expected ErrWorkoutExerciseNotFound, but got %v", err)
 	}
@@ -134,7 +134,7 @@ func TestGetWorkoutExercisesByWorkoutID(t *testing.T) {
 	weRepo.CreateWorkoutExercise(ctx, workout.ID, exercise1.ID, order1, nil, nil, userID)
 	weRepo.CreateWorkoutExercise(ctx, workout.ID, exercise2.ID, order2, nil, nil, userID)
 
-	exercises, err := weRepo.GetWorkoutExercisesByWorkoutID(ctx, workout.ID)
+	exercises, err := weRepo.GetWorkoutExercisesByWorkoutID(ctx, workout.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to get workout exercises: %v", err)
 	}
@@ -173,7 +173,7 @@ func TestUpdateWorkoutExercise(t *testing.T) {
 		t.Fatalf("Failed to update workout exercise: %v", err)
 	}
 
-	updated, _ := weRepo.GetWorkoutExerciseByID(ctx, we.ID)
+	updated, _ := weRepo.GetWorkoutExerciseByID(ctx, we.ID, userID)
 	if *updated.Memo != newMemo {
 		t.Errorf("Memo was not updated: got %v, want %v", *updated.Memo, newMemo)
 	}
@@ -217,7 +217,7 @@ func TestDeleteWorkoutExercise(t *testing.T) {
 		t.Fatalf("Failed to delete workout exercise: %v", err)
 	}
 
-	_, err = weRepo.GetWorkoutExerciseByID(ctx, weID)
+	_, err = weRepo.GetWorkoutExerciseByID(ctx, weID, userID)
 	if !errors.Is(err, ErrWorkoutExerciseNotFound) {
 		t.Errorf("Expected ErrWorkoutExerciseNotFound, but got %v", err)
 	}
@@ -255,7 +255,7 @@ func TestDeleteWorkoutExerciseOnDeleteWorkout(t *testing.T) {
 		t.Fatalf("Failed to delete workout: %v", err)
 	}
 
-	_, err = weRepo.GetWorkoutExerciseByID(ctx, we.ID)
+	_, err = weRepo.GetWorkoutExerciseByID(ctx, we.ID, userID)
 	if !errors.Is(err, ErrWorkoutExerciseNotFound) {
 		t.Errorf("Expected ErrWorkoutExerciseNotFound, but got %v", err)
 	}
@@ -1,28 +1,26 @@
 package repository
 
 const getWorkoutImageByIDQuery = `
-	SELECT id, workout_id, storage_path, display_order, created_at, updated_at
-	FROM public.workout_images
-	WHERE id = $1
+	SELECT wi.id, wi.workout_id, wi.storage_path, wi.thumbnail_path, wi.display_order, wi.created_at, wi.updated_at
+	FROM public.workout_images wi
+	JOIN public.workouts w ON wi.workout_id = w.id
+	WHERE wi.id = $1
+	-- Visibility Policy: viewer may see the owner's content (see can_view_content)
+	AND public.can_view_content($2, w.user_id, w.hidden_at)
+	-- Moderation Filter: Hide images taken down by a moderator
+	AND NOT public.is_hidden_from($2, w.user_id, wi.hidden_at)
 `
 
 const getWorkoutImagesByWorkoutIDQuery = `
-	SELECT id, workout_id, storage_path, display_order, created_at, updated_at
-	FROM public.workout_images
-	WHERE workout_id = $1
-	ORDER BY display_order ASC NULLS LAST, created_at ASC
-`
-
-const insertWorkoutImageQuery = `
-  INSERT INTO public.workout_images (workout_id, storage_path, display_order)
-  SELECT $1, $2, $3
-  WHERE EXISTS (
-      -- Guard: Ensure the workout belongs to the user or is handled by an admin
-      SELECT 1 FROM public.workouts 
-      WHERE id = $1 
-      AND (user_id = $4 OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $4))
-  )
-  RETURNING id, workout_id, storage_path, display_order, created_at, updated_at
+	SELECT wi.id, wi.workout_id, wi.storage_path, wi.thumbnail_path, wi.display_order, wi.created_at, wi.updated_at
+	FROM public.workout_images wi
+	JOIN public.workouts w ON wi.workout_id = w.id
+	WHERE wi.workout_id = $1
+	-- Visibility Policy: viewer may see the owner's content (see can_view_content)
+	AND public.can_view_content($2, w.user_id, w.hidden_at)
+	-- Moderation Filter: Hide images taken down by a moderator
+	AND NOT public.is_hidden_from($2, w.user_id, wi.hidden_at)
+	ORDER BY wi.display_order ASC NULLS LAST, wi.created_at ASC
 `
 
 const deleteWorkoutImageByIDQuery = `
@@ -34,3 +32,40 @@ const deleteWorkoutImageByIDQuery = `
       WHERE (user_id = $2 OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2))
   )
 `
+
+// lockWorkoutForImagesQuery locks a workout the user may edit, so changes to
+// its images are serialized.
+const lockWorkoutForImagesQuery = `
+  SELECT id FROM public.workouts
+  WHERE id = $1
+  AND (user_id = $2 OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2))
+  FOR UPDATE
+`
+
+const countWorkoutImagesQuery = `
+  SELECT count(*) FROM public.workout_images
+  WHERE workout_id = $1
+`
+
+// insertWorkoutImageFromUploadQuery runs after the upload was claimed and the
+// workout locked in the same transaction. Without a display order the image
+// goes last.
+const insertWorkoutImageFromUploadQuery = `
+  INSERT INTO public.workout_images (workout_id, storage_path, thumbnail_path, display_order)
+  VALUES ($1, $2, $3, COALESCE(
+      $4,
+      (SELECT max(display_order) + 1 FROM public.workout_images WHERE workout_id = $1),
+      0
+  ))
+  RETURNING id, workout_id, storage_path, thumbnail_path, display_order, created_at, updated_at
+`
+
+// reorderWorkoutImagesQuery numbers the images in the order of $2. The
+// unique order constraint is checked once the whole statement is done.
+const reorderWorkoutImagesQuery = `
+  UPDATE public.workout_images wi
+  SET display_order = o.position - 1
+  FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+  WHERE wi.id = o.id
+  AND wi.workout_id = $1
+`
@@ -22,54 +22,135 @@ func NewWorkoutImageRepository(db *pgxpool.Pool) *WorkoutImageRepository {
 	}
 }
 
-func (r *WorkoutImageRepository) CreateWorkoutImage(
+// CreateWorkoutImageFromUpload adds an image processed from one of the
+// user's pending uploads, committing the upload in the same transaction.
+// A nil displayOrder puts the image last.
+func (r *WorkoutImageRepository) CreateWorkoutImageFromUpload(
 	ctx context.Context,
+	uploadID uuid.UUID,
 	workoutID uuid.UUID,
 	storagePath string,
-	displayOrder int,
+	thumbnailPath string,
+	displayOrder *int,
 	userID uuid.UUID,
 ) (*models.WorkoutImage, error) {
-	var wi models.WorkoutImage
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var committed uuid.UUID
+	if err := tx.QueryRow(ctx, commitUploadQuery, uploadID, userID).Scan(&committed); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to commit upload: %w", err)
+	}
+
+	var locked uuid.UUID
+	if err := tx.QueryRow(ctx, lockWorkoutForImagesQuery, workoutID, userID).Scan(&locked); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReferenceViolation
+		}
+		return nil, fmt.Errorf("failed to lock workout: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, countWorkoutImagesQuery, workoutID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count workout images: %w", err)
+	}
+	if count >= models.MaxWorkoutImages {
+		return nil, ErrWorkoutImageLimit
+	}
 
-	err := r.DB.QueryRow(ctx, insertWorkoutImageQuery, workoutID, storagePath, displayOrder, userID).Scan(
+	var wi models.WorkoutImage
+	err = tx.QueryRow(ctx, insertWorkoutImageFromUploadQuery, workoutID, storagePath, thumbnailPath, displayOrder).Scan(
 		&wi.ID,
 		&wi.WorkoutID,
 		&wi.StoragePath,
+		&wi.ThumbnailPath,
 		&wi.DisplayOrder,
 		&wi.CreatedAt,
 		&wi.UpdatedAt,
 	)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create workout image: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &wi, nil
+}
+
+// ReorderWorkoutImages sets the order of a workout's images. imageIDs must
+// list every image of the workout exactly once.
+func (r *WorkoutImageRepository) ReorderWorkoutImages(
+	ctx context.Context,
+	workoutID uuid.UUID,
+	imageIDs []uuid.UUID,
+	userID uuid.UUID,
+) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var locked uuid.UUID
+	if err := tx.QueryRow(ctx, lockWorkoutForImagesQuery, workoutID, userID).Scan(&locked); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ErrReferenceViolation
+			return ErrWorkoutNotFound
 		}
+		return fmt.Errorf("failed to lock workout: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, countWorkoutImagesQuery, workoutID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count workout images: %w", err)
+	}
+	if count != len(imageIDs) {
+		return ErrInvalidImageOrder
+	}
 
+	// A repeated or foreign ID leaves some image unnumbered
+	res, err := tx.Exec(ctx, reorderWorkoutImagesQuery, workoutID, imageIDs)
+	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			switch pgErr.Code {
-			case "23505": // Unique Violation
-				return nil, ErrAlreadyExists
-			case "23503": // Foreign Key Violation
-				return nil, ErrReferenceViolation
-			}
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrInvalidImageOrder
 		}
+		return fmt.Errorf("failed to reorder workout images: %w", err)
+	}
+	if res.RowsAffected() != int64(count) {
+		return ErrInvalidImageOrder
+	}
 
-		return nil, fmt.Errorf("failed to create workout image: %w", err)
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &wi, nil
+	return nil
 }
 
 func (r *WorkoutImageRepository) GetWorkoutImageByID(
 	ctx context.Context,
 	id uuid.UUID,
+	viewerID uuid.UUID,
 ) (*models.WorkoutImage, error) {
 	var wi models.WorkoutImage
 
-	err := r.DB.QueryRow(ctx, getWorkoutImageByIDQuery, id).Scan(
+	err := r.DB.QueryRow(ctx, getWorkoutImageByIDQuery, id, viewerID).Scan(
 		&wi.ID,
 		&wi.WorkoutID,
 		&wi.StoragePath,
+		&wi.ThumbnailPath,
 		&wi.DisplayOrder,
 		&wi.CreatedAt,
 		&wi.UpdatedAt,
@@ -87,8 +168,9 @@ func (r *WorkoutImageRepository) GetWorkoutImageByID(
 func (r *WorkoutImageRepository) GetWorkoutImagesByWorkoutID(
 	ctx context.Context,
 	workoutID uuid.UUID,
+	viewerID uuid.UUID,
 ) ([]*models.WorkoutImage, error) {
-	rows, err := r.DB.Query(ctx, getWorkoutImagesByWorkoutIDQuery, workoutID)
+	rows, err := r.DB.Query(ctx, getWorkoutImagesByWorkoutIDQuery, workoutID, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workout images: %w", err)
 	}
@@ -101,6 +183,7 @@ func (r *WorkoutImageRepository) GetWorkoutImagesByWorkoutID(
 			&wi.ID,
 			&wi.WorkoutID,
 			&wi.StoragePath,
+			&wi.ThumbnailPath,
 			&wi.DisplayOrder,
 			&wi.CreatedAt,
 			&wi.UpdatedAt,
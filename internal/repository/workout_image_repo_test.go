@@ -7,48 +7,46 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
 )
 
-func TestCreateWorkoutImage(t *testing.T) {
+func TestCreateWorkoutImageFromUpload(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	wiRepo := NewWorkoutImageRepository(db)
+	uploadRepo := NewUploadRepository(db)
 	workoutRepo := NewWorkoutRepository(db)
 	ctx := context.Background()
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
 	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+	upload, _ := uploadRepo.CreateUpload(ctx, userID, "uploads/u/1", "image/jpeg", 100, time.Now().Add(time.Hour))
 
-	storagePath := "/images/workout123.jpg"
-	displayOrder := 1
+	// Someone else's workout: nothing is committed
+	otherWorkout, _ := workoutRepo.Create(ctx, otherID, nil, nil, time.Now(), time.Now(), 0)
+	_, err := wiRepo.CreateWorkoutImageFromUpload(ctx, upload.ID, otherWorkout.ID, "images/a.jpg", "images/a_thumb.jpg", nil, userID)
+	if !errors.Is(err, ErrReferenceViolation) {
+		t.Fatalf("Expected ErrReferenceViolation, got %v", err)
+	}
 
-	wi, err := wiRepo.CreateWorkoutImage(ctx, workout.ID, storagePath, displayOrder, userID)
+	wi, err := wiRepo.CreateWorkoutImageFromUpload(ctx, upload.ID, workout.ID, "images/a.jpg", "images/a_thumb.jpg", nil, userID)
 	if err != nil {
 		t.Fatalf("Failed to create workout image: %v", err)
 	}
-
-	if wi.ID == uuid.Nil {
-		t.Error("WorkoutImage ID should not be nil")
-	}
-	if wi.StoragePath != storagePath {
-		t.Errorf("StoragePath mismatch: got %v, want %v", wi.StoragePath, storagePath)
-	}
-	if wi.DisplayOrder != displayOrder {
-		t.Errorf("DisplayOrder mismatch: got %v, want %v", wi.DisplayOrder, displayOrder)
+	if wi.StoragePath != "images/a.jpg" || wi.ThumbnailPath == nil || *wi.ThumbnailPath != "images/a_thumb.jpg" {
+		t.Errorf("Unexpected paths %q, %v", wi.StoragePath, wi.ThumbnailPath)
 	}
-}
 
-func TestCreateWorkoutImageNotFoundWorkout(t *testing.T) {
-	db := testutil.SetupTestDB(t)
-	defer db.Close()
-	wiRepo := NewWorkoutImageRepository(db)
-	ctx := context.Background()
-
-	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
-	_, err := wiRepo.CreateWorkoutImage(ctx, uuid.New(), "/path/to/image.jpg", 0, userID)
-	if !errors.Is(err, ErrReferenceViolation) {
-		t.Errorf("Expected ErrReferenceViolation, but got %v", err)
+	// An upload is committed once
+	_, err = wiRepo.CreateWorkoutImageFromUpload(ctx, upload.ID, workout.ID, "images/b.jpg", "images/b_thumb.jpg", nil, userID)
+	if !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("Expected ErrUploadNotFound, got %v", err)
+	}
+	if _, err := uploadRepo.GetPendingUpload(ctx, upload.ID, userID); !errors.Is(err, ErrUploadNotFound) {
+		t.Errorf("Expected the upload to be committed, got %v", err)
 	}
 }
 
@@ -62,9 +60,9 @@ func TestGetWorkoutImageByID(t *testing.T) {
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
 
-	created, _ := wiRepo.CreateWorkoutImage(ctx, workout.ID, "/path/to/image.jpg", 0, userID)
+	created, _ := addUploadedImage(t, db, workout.ID, userID, nil)
 
-	wi, err := wiRepo.GetWorkoutImageByID(ctx, created.ID)
+	wi, err := wiRepo.GetWorkoutImageByID(ctx, created.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to get workout image: %v", err)
 	}
@@ -80,7 +78,7 @@ func TestGetWorkoutImageByIDNotFound(t *testing.T) {
 	wiRepo := NewWorkoutImageRepository(db)
 	ctx := context.Background()
 
-	_, err := wiRepo.GetWorkoutImageByID(ctx, uuid.New())
+	_, err := wiRepo.GetWorkoutImageByID(ctx, uuid.New(), uuid.New())
 	if !errors.Is(err, ErrWorkoutImageNotFound) {
 		t.Errorf("Expected ErrWorkoutImageNotFound, but got %v", err)
 	}
@@ -98,10 +96,10 @@ func TestGetWorkoutImagesByWorkoutID(t *testing.T) {
 
 	order1 := 2
 	order2 := 1
-	wiRepo.CreateWorkoutImage(ctx, workout.ID, "/path/to/image1.jpg", order1, userID)
-	wiRepo.CreateWorkoutImage(ctx, workout.ID, "/path/to/image2.jpg", order2, userID)
+	addUploadedImage(t, db, workout.ID, userID, &order1)
+	addUploadedImage(t, db, workout.ID, userID, &order2)
 
-	images, err := wiRepo.GetWorkoutImagesByWorkoutID(ctx, workout.ID)
+	images, err := wiRepo.GetWorkoutImagesByWorkoutID(ctx, workout.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to get workout images: %v", err)
 	}
@@ -122,7 +120,7 @@ func TestGetWorkoutImagesByWorkoutIDNotFoundWorkout(t *testing.T) {
 	wiRepo := NewWorkoutImageRepository(db)
 	ctx := context.Background()
 
-	rows, err := wiRepo.GetWorkoutImagesByWorkoutID(ctx, uuid.New())
+	rows, err := wiRepo.GetWorkoutImagesByWorkoutID(ctx, uuid.New(), uuid.New())
 	if err != nil {
 		t.Fatalf("Failed to get workout images: %v", err)
 	}
@@ -141,7 +139,7 @@ func TestDeleteWorkoutImage(t *testing.T) {
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
 
-	wi, _ := wiRepo.CreateWorkoutImage(ctx, workout.ID, "/path/to/delete.jpg", 0, userID)
+	wi, _ := addUploadedImage(t, db, workout.ID, userID, nil)
 	wiID := wi.ID
 
 	err := wiRepo.DeleteWorkoutImage(ctx, wiID, userID)
@@ -149,7 +147,7 @@ func TestDeleteWorkoutImage(t *testing.T) {
 		t.Fatalf("Failed to delete workout image: %v", err)
 	}
 
-	_, err = wiRepo.GetWorkoutImageByID(ctx, wiID)
+	_, err = wiRepo.GetWorkoutImageByID(ctx, wiID, userID)
 	if !errors.Is(err, ErrWorkoutImageNotFound) {
 		t.Errorf("Expected ErrWorkoutImageNotFound, but got %v", err)
 	}
@@ -191,14 +189,149 @@ func TestDeleteWorkoutImageOnDeleteWorkout(t *testing.T) {
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
-	wi, _ := wiRepo.CreateWorkoutImage(ctx, workout.ID, "/path/to/delete.jpg", 0, userID)
+	wi, _ := addUploadedImage(t, db, workout.ID, userID, nil)
 	err := workoutRepo.DeleteWorkout(ctx, workout.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to delete workout: %v", err)
 	}
 
-	_, err = wiRepo.GetWorkoutImageByID(ctx, wi.ID)
+	_, err = wiRepo.GetWorkoutImageByID(ctx, wi.ID, userID)
 	if !errors.Is(err, ErrWorkoutImageNotFound) {
 		t.Errorf("Expected ErrWorkoutImageNotFound, but got %v", err)
 	}
 }
+
+// addUploadedImage adds an image to a workout the way the handler does.
+func addUploadedImage(t *testing.T, db *pgxpool.Pool, workoutID uuid.UUID, userID uuid.UUID, displayOrder *int) (*models.WorkoutImage, error) {
+	t.Helper()
+	ctx := context.Background()
+	key := uuid.New().String()
+	upload, err := NewUploadRepository(db).CreateUpload(ctx, userID, "uploads/"+key, "image/jpeg", 100, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create upload: %v", err)
+	}
+	base := "images/workouts/" + workoutID.String() + "/" + key
+	return NewWorkoutImageRepository(db).CreateWorkoutImageFromUpload(ctx, upload.ID, workoutID, base+".jpg", base+"_thumb.jpg", displayOrder, userID)
+}
+
+// insertLegacyImage adds an image with a path set by a client, as stored
+// before uploads were processed.
+func insertLegacyImage(t *testing.T, db *pgxpool.Pool, workoutID uuid.UUID, storagePath string, displayOrder int) {
+	t.Helper()
+	_, err := db.Exec(context.Background(),
+		"INSERT INTO workout_images (workout_id, storage_path, display_order) VALUES ($1, $2, $3)",
+		workoutID, storagePath, displayOrder,
+	)
+	if err != nil {
+		t.Fatalf("Failed to insert image: %v", err)
+	}
+}
+
+func TestCreateWorkoutImageFromUpload_OrderAndLimit(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+
+	// Without an order, images go last
+	for i := 0; i < 2; i++ {
+		wi, err := addUploadedImage(t, db, workout.ID, userID, nil)
+		if err != nil {
+			t.Fatalf("Failed to add image: %v", err)
+		}
+		if wi.DisplayOrder != i {
+			t.Errorf("Expected display_order %d, got %d", i, wi.DisplayOrder)
+		}
+	}
+
+	taken := 1
+	if _, err := addUploadedImage(t, db, workout.ID, userID, &taken); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists for a taken order, got %v", err)
+	}
+
+	for i := 2; i < models.MaxWorkoutImages; i++ {
+		if _, err := addUploadedImage(t, db, workout.ID, userID, nil); err != nil {
+			t.Fatalf("Failed to add image %d: %v", i, err)
+		}
+	}
+	if _, err := addUploadedImage(t, db, workout.ID, userID, nil); !errors.Is(err, ErrWorkoutImageLimit) {
+		t.Errorf("Expected ErrWorkoutImageLimit, got %v", err)
+	}
+}
+
+func TestReorderWorkoutImages(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	wiRepo := NewWorkoutImageRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	otherID, _, _ := testutil.InsertProfile(ctx, db, "other")
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		wi, err := addUploadedImage(t, db, workout.ID, userID, nil)
+		if err != nil {
+			t.Fatalf("Failed to add image: %v", err)
+		}
+		ids = append(ids, wi.ID)
+	}
+
+	reversed := []uuid.UUID{ids[2], ids[1], ids[0]}
+	if err := wiRepo.ReorderWorkoutImages(ctx, workout.ID, reversed, userID); err != nil {
+		t.Fatalf("Failed to reorder images: %v", err)
+	}
+	images, _ := wiRepo.GetWorkoutImagesByWorkoutID(ctx, workout.ID, userID)
+	for i, wi := range images {
+		if wi.ID != reversed[i] || wi.DisplayOrder != i {
+			t.Errorf("Expected %s at position %d, got %s with display_order %d", reversed[i], i, wi.ID, wi.DisplayOrder)
+		}
+	}
+
+	invalid := [][]uuid.UUID{
+		{ids[0], ids[1]},
+		{ids[0], ids[0], ids[1]},
+		{ids[0], ids[1], uuid.New()},
+	}
+	for _, order := range invalid {
+		if err := wiRepo.ReorderWorkoutImages(ctx, workout.ID, order, userID); !errors.Is(err, ErrInvalidImageOrder) {
+			t.Errorf("Expected ErrInvalidImageOrder for %v, got %v", order, err)
+		}
+	}
+
+	if err := wiRepo.ReorderWorkoutImages(ctx, workout.ID, ids, otherID); !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected ErrWorkoutNotFound for another user, got %v", err)
+	}
+}
+
+func TestDeleteWorkoutImage_QueuesFiles(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	wiRepo := NewWorkoutImageRepository(db)
+	workoutRepo := NewWorkoutRepository(db)
+	ctx := context.Background()
+
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
+	workout, _ := workoutRepo.Create(ctx, userID, nil, nil, time.Now(), time.Now(), 0)
+
+	first, _ := addUploadedImage(t, db, workout.ID, userID, nil)
+	second, _ := addUploadedImage(t, db, workout.ID, userID, nil)
+	// A path set by a client before uploads were processed is not queued
+	insertLegacyImage(t, db, workout.ID, "images/workouts/someone-else/a.jpg", 5)
+
+	if err := wiRepo.DeleteWorkoutImage(ctx, first.ID, userID); err != nil {
+		t.Fatalf("Failed to delete workout image: %v", err)
+	}
+	assertQueuedBlobs(t, db, first.StoragePath, *first.ThumbnailPath)
+
+	// Deleting the workout queues the rest
+	if err := workoutRepo.DeleteWorkout(ctx, workout.ID, userID); err != nil {
+		t.Fatalf("Failed to delete workout: %v", err)
+	}
+	assertQueuedBlobs(t, db, first.StoragePath, *first.ThumbnailPath, second.StoragePath, *second.ThumbnailPath)
+}
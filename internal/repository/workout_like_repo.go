@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
 type WorkoutLikeRepository struct {
@@ -102,11 +103,11 @@ func (r *WorkoutLikeRepository) GetWorkoutLikesByWorkoutID(
 	ctx context.Context,
 	workoutID uuid.UUID,
 	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.WorkoutLikeDetail, error) {
 	// We use Query because we expect 0 or more rows
-	rows, err := r.DB.Query(ctx, getLikesByWorkoutIDQuery, workoutID, viewerID, limit, offset)
+	rows, err := r.DB.Query(ctx, getLikesByWorkoutIDQuery, workoutID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch workout likes: %w", err)
 	}
@@ -224,7 +224,7 @@ func TestGetLikesByWorkoutID(t *testing.T) {
 
 	likeRepo.LikeWorkout(ctx, userID, workout.ID)
 
-	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, userID, 10, 0)
+	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
@@ -240,7 +240,7 @@ func TestGetLikesByWorkoutIDWorkoutNotFound(t *testing.T) {
 	ctx := context.Background()
 
 	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
-	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, uuid.New(), userID, 10, 0)
+	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, uuid.New(), userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
@@ -272,7 +272,7 @@ func TestGetLikesByWorkoutIDBlockedUser(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to insert blocked user: %v", err)
 	}
-	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, blockedUserID, 10, 0)
+	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, blockedUserID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
@@ -301,7 +301,7 @@ func TestLikeDeleteOnCascadeWorkout(t *testing.T) {
 		t.Fatalf("Failed to delete workout: %v", err)
 	}
 
-	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, userID, 10, 0)
+	likes, err := likeRepo.GetWorkoutLikesByWorkoutID(ctx, workout.ID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get likes: %v", err)
 	}
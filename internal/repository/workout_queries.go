@@ -2,64 +2,34 @@ package repository
 
 const getWorkoutByIDQuery = `
   SELECT 
-    w.id, w.user_id, w.name, w.comment, w.started_at, w.ended_at, 
+    w.id, w.user_id, w.routine_id, w.name, w.comment, w.started_at, w.ended_at, 
     w.duration_seconds, w.total_weight, w.likes_count, w.comments_count, 
     w.created_at, w.updated_at
   FROM public.workouts w
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE w.id = $1
-    -- 1. Block Guard: Neither user has blocked the other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Account is public OR (User is following AND accepted) OR User is viewing their own workout
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($2, w.user_id, w.hidden_at)
 `
 
 const getWorkoutsByUserIDQuery = `
   SELECT 
-    w.id, w.user_id, w.name, w.comment, w.started_at, w.ended_at, 
+    w.id, w.user_id, w.routine_id, w.name, w.comment, w.started_at, w.ended_at, 
     w.duration_seconds, w.total_weight, w.likes_count, w.comments_count, 
     w.created_at, w.updated_at
   FROM public.workouts w
-  JOIN public.profiles p ON w.user_id = p.id
   WHERE w.user_id = $1
-    -- 1. Block Guard: Neither user has blocked the other
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-    )
-    -- 2. Privacy Guard: Account is public OR (User is following AND accepted) OR User is viewing their own feed
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-  ORDER BY w.started_at DESC
-  LIMIT $3 OFFSET $4
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($2, w.user_id, w.hidden_at)
+    -- Keyset Cursor: resume after the last workout of the previous page
+    AND ($3::timestamptz IS NULL OR (w.started_at, w.id) < ($3::timestamptz, $4::uuid))
+  ORDER BY w.started_at DESC, w.id DESC
+  LIMIT $5
 `
 
 const insertWorkoutQuery = `
 	INSERT INTO public.workouts (user_id, name, comment, started_at, ended_at, duration_seconds)
 	VALUES ($1, $2, $3, $4, $5, $6)
-	RETURNING id, user_id, name, comment, started_at, ended_at, duration_seconds, total_weight, likes_count, comments_count, created_at, updated_at
+	RETURNING id, user_id, routine_id, name, comment, started_at, ended_at, duration_seconds, total_weight, likes_count, comments_count, created_at, updated_at
 `
 
 const deleteWorkoutByIDQuery = `
@@ -69,13 +39,18 @@ const deleteWorkoutByIDQuery = `
       user_id = $2 
       OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2)
   )
+  RETURNING user_id
 `
 
-const getTimelineWorkoutsQuery = `
+// Timeline queries share one SELECT list, assembled per request by
+// timelineWorkoutSelect so clients can leave out sections they do not need.
+// In every timeline query $1 is the viewer.
+const timelineWorkoutColumns = `
   SELECT 
     w.id, 
     w.user_id, 
     p.username, 
+    p.display_name, 
     p.avatar_url, 
     w.name, 
     w.comment, 
@@ -85,8 +60,13 @@ const getTimelineWorkoutsQuery = `
     w.likes_count, 
     w.comments_count, 
     w.updated_at,
-    
-    -- 1. EXERCISES (Nested with Sets)
+    EXISTS (
+      SELECT 1 FROM public.workout_likes wl
+      WHERE wl.workout_id = w.id AND wl.user_id = $1
+    ) AS liked_by_me`
+
+// timelineWorkoutExercisesColumn nests each exercise with its sets.
+const timelineWorkoutExercisesColumn = `,
     COALESCE(
       (
         SELECT json_agg(
@@ -102,7 +82,8 @@ const getTimelineWorkoutsQuery = `
                     'id', ws.id,
                     'weight', ws.weight,
                     'reps', ws.reps,
-                    'order_index', ws.order_index
+                    'order_index', ws.order_index,
+                    'is_pr', EXISTS (SELECT 1 FROM public.personal_records pr WHERE pr.workout_set_id = ws.id)
                   ) ORDER BY ws.order_index ASC
                 )
                 FROM public.workout_sets ws
@@ -115,263 +96,115 @@ const getTimelineWorkoutsQuery = `
         JOIN public.exercises e ON we.exercise_id = e.id
         WHERE we.workout_id = w.id
       ), '[]'::json
-    ) AS exercises,
+    ) AS exercises`
 
-    -- 2. COMMENTS (Joined with Profile)
+// timelineWorkoutCommentsColumn previews the latest 3 top-level comments,
+// oldest first. The rest are paged from the workout's comments endpoint.
+const timelineWorkoutCommentsColumn = `,
     COALESCE(
       (
         SELECT json_agg(
           json_build_object(
-            'id', wc.id,
-            'user_id', wc.user_id,
-            'content', wc.content,
-            'likes_count', wc.likes_count,
-            'created_at', wc.created_at,
-            'username', cp.username,      -- Commenter's username
-            'avatar_url', cp.avatar_url,  -- Commenter's avatar
+            'id', lc.id,
+            'user_id', lc.user_id,
+            'content', lc.content,
+            'likes_count', lc.likes_count,
+            'created_at', lc.created_at,
+            'username', lc.username,      -- Commenter's username
+            'avatar_url', lc.avatar_url,  -- Commenter's avatar
             'comments', '[]'::json        -- Empty array for child comments (Lazy Load these!)
-          ) ORDER BY wc.created_at ASC
+          ) ORDER BY lc.created_at ASC, lc.id ASC
         )
-        FROM public.comments wc
-        JOIN public.profiles cp ON wc.user_id = cp.id
-        WHERE wc.workout_id = w.id
+        FROM (
+          SELECT wc.id, wc.user_id, wc.content, wc.likes_count, wc.created_at, cp.username, cp.avatar_url
+          FROM public.comments wc
+          JOIN public.profiles cp ON wc.user_id = cp.id
+          WHERE wc.workout_id = w.id
+            AND wc.parent_id IS NULL
+            AND wc.deleted_at IS NULL
+            AND NOT public.is_blocked_between(wc.user_id, $1) -- Ghost Filter
+            AND public.is_account_active(wc.user_id) -- Account Filter
+            AND NOT public.is_hidden_from($1, wc.user_id, wc.hidden_at) -- Moderation Filter
+          ORDER BY wc.created_at DESC, wc.id DESC
+          LIMIT 3
+        ) lc
       ), '[]'::json
-    ) AS comments,
+    ) AS comments`
 
-    -- 3. IMAGES
+const timelineWorkoutImagesColumn = `,
     COALESCE(
       (
         SELECT json_agg(
           json_build_object(
             'id', wi.id,
             'storage_path', wi.storage_path,
+            'thumbnail_path', wi.thumbnail_path,
             'display_order', wi.display_order
           ) ORDER BY wi.display_order ASC
         )
         FROM public.workout_images wi
         WHERE wi.workout_id = w.id
+          AND NOT public.is_hidden_from($1, w.user_id, wi.hidden_at) -- Moderation Filter
       ), '[]'::json
-    ) AS images
+    ) AS images`
 
-  FROM public.workouts w
-  JOIN public.profiles p ON w.user_id = p.id
-  WHERE w.user_id = $1
-    -- Block & Privacy Logic
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $2)
-           OR (b.blocker_id = $2 AND b.blocked_id = w.user_id)
-    )
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $2
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $2 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-  ORDER BY w.started_at DESC
-  LIMIT $3 OFFSET $4
-`
+// Sections a client did not ask for come back as JSON null.
+const (
+	timelineWorkoutExercisesOmitted = `,
+    NULL::json AS exercises`
+	timelineWorkoutCommentsOmitted = `,
+    NULL::json AS comments`
+	timelineWorkoutImagesOmitted = `,
+    NULL::json AS images`
+)
 
-// getFollowingTimelineWorkoutsQuery returns timeline workouts from the viewer and users they follow.
-// $1 = viewerID, $2 = limit, $3 = offset
-const getFollowingTimelineWorkoutsQuery = `
-  SELECT 
-    w.id, 
-    w.user_id, 
-    p.username, 
-    p.avatar_url, 
-    w.name, 
-    w.comment, 
-    w.started_at, 
-    w.ended_at, 
-    w.total_weight, 
-    w.likes_count, 
-    w.comments_count, 
-    w.updated_at,
-    COALESCE(
-      (
-        SELECT json_agg(
-          json_build_object(
-            'id', we.id,
-            'exercise_id', we.exercise_id,
-            'name', e.name, 
-            'order_index', we.order_index,
-            'sets', COALESCE(
-              (
-                SELECT json_agg(
-                  json_build_object(
-                    'id', ws.id,
-                    'weight', ws.weight,
-                    'reps', ws.reps,
-                    'order_index', ws.order_index
-                  ) ORDER BY ws.order_index ASC
-                )
-                FROM public.workout_sets ws
-                WHERE ws.workout_exercise_id = we.id
-              ), '[]'::json
-            )
-          ) ORDER BY we.order_index ASC
-        )
-        FROM public.workout_exercises we
-        JOIN public.exercises e ON we.exercise_id = e.id
-        WHERE we.workout_id = w.id
-      ), '[]'::json
-    ) AS exercises,
-    COALESCE(
-      (
-        SELECT json_agg(
-          json_build_object(
-            'id', wc.id,
-            'user_id', wc.user_id,
-            'content', wc.content,
-            'likes_count', wc.likes_count,
-            'created_at', wc.created_at,
-            'username', cp.username,
-            'avatar_url', cp.avatar_url,
-            'comments', '[]'::json
-          ) ORDER BY wc.created_at ASC
-        )
-        FROM public.comments wc
-        JOIN public.profiles cp ON wc.user_id = cp.id
-        WHERE wc.workout_id = w.id
-      ), '[]'::json
-    ) AS comments,
-    COALESCE(
-      (
-        SELECT json_agg(
-          json_build_object(
-            'id', wi.id,
-            'storage_path', wi.storage_path,
-            'display_order', wi.display_order
-          ) ORDER BY wi.display_order ASC
-        )
-        FROM public.workout_images wi
-        WHERE wi.workout_id = w.id
-      ), '[]'::json
-    ) AS images
+// getTimelineWorkoutsFrom selects one user's timeline workouts.
+// $1 = viewerID, $2 = targetID, $3 = cursor started_at, $4 = cursor id, $5 = limit
+const getTimelineWorkoutsFrom = `
   FROM public.workouts w
   JOIN public.profiles p ON w.user_id = p.id
-  WHERE (w.user_id = $1 OR w.user_id IN (
-      SELECT f.following_id FROM public.follows f
-      WHERE f.follower_id = $1 AND f.status = 'accepted'
-    ))
-    AND NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $1)
-           OR (b.blocker_id = $1 AND b.blocked_id = w.user_id)
-    )
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $1
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $1 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-  ORDER BY w.started_at DESC
-  LIMIT $2 OFFSET $3
+  WHERE w.user_id = $2
+    -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+    AND public.can_view_content($1, w.user_id, w.hidden_at)
+    -- Keyset Cursor: resume after the last workout of the previous page
+    AND ($3::timestamptz IS NULL OR (w.started_at, w.id) < ($3::timestamptz, $4::uuid))
+  ORDER BY w.started_at DESC, w.id DESC
+  LIMIT $5
 `
 
-// getForYouTimelineWorkoutsQuery returns timeline workouts from any visible user, ordered by engagement (likes + comments) then recency.
-// $1 = viewerID, $2 = limit, $3 = offset
-const getForYouTimelineWorkoutsQuery = `
-  SELECT 
-    w.id, 
-    w.user_id, 
-    p.username, 
-    p.avatar_url, 
-    w.name, 
-    w.comment, 
-    w.started_at, 
-    w.ended_at, 
-    w.total_weight, 
-    w.likes_count, 
-    w.comments_count, 
-    w.updated_at,
-    COALESCE(
-      (
-        SELECT json_agg(
-          json_build_object(
-            'id', we.id,
-            'exercise_id', we.exercise_id,
-            'name', e.name, 
-            'order_index', we.order_index,
-            'sets', COALESCE(
-              (
-                SELECT json_agg(
-                  json_build_object(
-                    'id', ws.id,
-                    'weight', ws.weight,
-                    'reps', ws.reps,
-                    'order_index', ws.order_index
-                  ) ORDER BY ws.order_index ASC
-                )
-                FROM public.workout_sets ws
-                WHERE ws.workout_exercise_id = we.id
-              ), '[]'::json
-            )
-          ) ORDER BY we.order_index ASC
-        )
-        FROM public.workout_exercises we
-        JOIN public.exercises e ON we.exercise_id = e.id
-        WHERE we.workout_id = w.id
-      ), '[]'::json
-    ) AS exercises,
-    COALESCE(
-      (
-        SELECT json_agg(
-          json_build_object(
-            'id', wc.id,
-            'user_id', wc.user_id,
-            'content', wc.content,
-            'likes_count', wc.likes_count,
-            'created_at', wc.created_at,
-            'username', cp.username,
-            'avatar_url', cp.avatar_url,
-            'comments', '[]'::json
-          ) ORDER BY wc.created_at ASC
-        )
-        FROM public.comments wc
-        JOIN public.profiles cp ON wc.user_id = cp.id
-        WHERE wc.workout_id = w.id
-      ), '[]'::json
-    ) AS comments,
-    COALESCE(
-      (
-        SELECT json_agg(
-          json_build_object(
-            'id', wi.id,
-            'storage_path', wi.storage_path,
-            'display_order', wi.display_order
-          ) ORDER BY wi.display_order ASC
-        )
-        FROM public.workout_images wi
-        WHERE wi.workout_id = w.id
-      ), '[]'::json
-    ) AS images
-  FROM public.workouts w
+// getFollowingTimelineWorkoutsFrom selects the viewer's home feed: their materialized
+// feed items plus followed authors' workouts that were not fanned out when posted.
+// $1 = viewerID, $2 = cursor started_at, $3 = cursor id, $4 = limit
+const getFollowingTimelineWorkoutsFrom = `
+  FROM (
+    -- Fanned-out workouts materialized in the viewer's feed
+    SELECT fi.workout_id, fi.started_at
+    FROM public.feed_items fi
+    JOIN public.workouts fw ON fw.id = fi.workout_id
+    WHERE fi.user_id = $1
+      -- Visibility Policy: viewer may see the owner's content (see can_view_content)
+      AND public.can_view_content($1, fi.author_id, fw.hidden_at)
+      -- Keyset Cursor: resume after the last workout of the previous page
+      AND ($2::timestamptz IS NULL OR (fi.started_at, fi.workout_id) < ($2::timestamptz, $3::uuid))
+
+    UNION
+
+    -- Workouts posted while their author was too popular to fan out, pulled at read time
+    SELECT w.id, w.started_at
+    FROM public.follows f
+    JOIN public.workouts w ON w.user_id = f.following_id
+    WHERE f.follower_id = $1
+      AND f.status = 'accepted'
+      AND NOT w.fanned_out
+      AND NOT public.is_blocked_between($1, w.user_id)
+      AND public.is_account_active(w.user_id)
+      AND NOT public.is_hidden_from($1, w.user_id, w.hidden_at)
+      AND ($2::timestamptz IS NULL OR (w.started_at, w.id) < ($2::timestamptz, $3::uuid))
+
+    ORDER BY started_at DESC, workout_id DESC
+    LIMIT $4
+  ) page
+  JOIN public.workouts w ON w.id = page.workout_id
   JOIN public.profiles p ON w.user_id = p.id
-  WHERE NOT EXISTS (
-        SELECT 1 FROM public.blocked_users b
-        WHERE (b.blocker_id = w.user_id AND b.blocked_id = $1)
-           OR (b.blocker_id = $1 AND b.blocked_id = w.user_id)
-    )
-    AND (
-        p.is_private_account = false 
-        OR w.user_id = $1
-        OR EXISTS (
-            SELECT 1 FROM public.follows f
-            WHERE f.follower_id = $1 
-              AND f.following_id = w.user_id 
-              AND f.status = 'accepted'
-        )
-    )
-  ORDER BY (w.likes_count + w.comments_count) DESC, w.started_at DESC
-  LIMIT $2 OFFSET $3
+  ORDER BY w.started_at DESC, w.id DESC
 `
@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 )
 
 type WorkoutRepository struct {
@@ -34,9 +35,15 @@ func (r *WorkoutRepository) Create(
 	endedAt time.Time,
 	durationSeconds int,
 ) (*models.Workout, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	var workout models.Workout
 
-	err := r.DB.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		insertWorkoutQuery,
 		userID,
@@ -48,6 +55,7 @@ func (r *WorkoutRepository) Create(
 	).Scan(
 		&workout.ID,
 		&workout.UserID,
+		&workout.RoutineID,
 		&workout.Name,
 		&workout.Comment,
 		&workout.StartedAt,
@@ -70,6 +78,18 @@ func (r *WorkoutRepository) Create(
 		return nil, fmt.Errorf("failed to create workout: %w", err)
 	}
 
+	if err := syncWorkoutEntities(ctx, tx, workout.ID, userID, workout.Comment); err != nil {
+		return nil, err
+	}
+
+	if err := recomputeStreak(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &workout, nil
 }
 
@@ -83,6 +103,7 @@ func (r *WorkoutRepository) GetWorkoutByID(
 	err := r.DB.QueryRow(ctx, getWorkoutByIDQuery, workoutID, viewerID).Scan(
 		&workout.ID,
 		&workout.UserID,
+		&workout.RoutineID,
 		&workout.Name,
 		&workout.Comment,
 		&workout.StartedAt,
@@ -108,10 +129,10 @@ func (r *WorkoutRepository) GetWorkoutsByUserID(
 	ctx context.Context,
 	targetID uuid.UUID,
 	viewerID uuid.UUID,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.Workout, error) {
-	rows, err := r.DB.Query(ctx, getWorkoutsByUserIDQuery, targetID, viewerID, limit, offset)
+	rows, err := r.DB.Query(ctx, getWorkoutsByUserIDQuery, targetID, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workouts: %w", err)
 	}
@@ -123,6 +144,7 @@ func (r *WorkoutRepository) GetWorkoutsByUserID(
 		err := rows.Scan(
 			&workout.ID,
 			&workout.UserID,
+			&workout.RoutineID,
 			&workout.Name,
 			&workout.Comment,
 			&workout.StartedAt,
@@ -171,6 +193,11 @@ func (r *WorkoutRepository) UpdateWorkout(
 		}
 		i++
 	}
+	if updates.StartedAt != nil {
+		sets = append(sets, fmt.Sprintf("started_at = $%d", i))
+		args = append(args, *updates.StartedAt)
+		i++
+	}
 	if updates.EndedAt != nil {
 		sets = append(sets, fmt.Sprintf("ended_at = $%d", i))
 		args = append(args, *updates.EndedAt)
@@ -187,7 +214,7 @@ func (r *WorkoutRepository) UpdateWorkout(
 	}
 
 	query := fmt.Sprintf(
-		"UPDATE workouts SET %s WHERE id = $%d AND (user_id = $%d OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $%d))",
+		"UPDATE workouts SET %s WHERE id = $%d AND (user_id = $%d OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $%d)) RETURNING user_id",
 		strings.Join(sets, ", "),
 		i,
 		i+1,
@@ -195,13 +222,51 @@ func (r *WorkoutRepository) UpdateWorkout(
 	)
 	args = append(args, id, userID, userID)
 
-	res, err := r.DB.Exec(ctx, query, args...)
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var ownerID uuid.UUID
+	err = tx.QueryRow(ctx, query, args...).Scan(&ownerID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkoutNotFound
+		}
 		return fmt.Errorf("failed to update workout: %w", err)
 	}
 
-	if res.RowsAffected() == 0 {
-		return ErrWorkoutNotFound
+	if updates.Comment != nil {
+		caption := updates.Comment
+		if *caption == "" {
+			caption = nil
+		}
+		if err := syncWorkoutEntities(ctx, tx, id, ownerID, caption); err != nil {
+			return err
+		}
+	}
+
+	// Moving a workout to another day can make or break a streak
+	if updates.StartedAt != nil {
+		if err := recomputeStreak(ctx, tx, ownerID); err != nil {
+			return err
+		}
+
+		// PRs are awarded in session order, which the move may have changed
+		exerciseIDs, err := getWorkoutExerciseIDs(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		for _, exerciseID := range exerciseIDs {
+			if err := recomputeExercisePersonalRecords(ctx, tx, ownerID, exerciseID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -212,13 +277,39 @@ func (r *WorkoutRepository) DeleteWorkout(
 	id uuid.UUID,
 	userID uuid.UUID,
 ) error {
-	commandTag, err := r.DB.Exec(ctx, deleteWorkoutByIDQuery, id, userID)
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exerciseIDs, err := getWorkoutExerciseIDs(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	var ownerID uuid.UUID
+	err = tx.QueryRow(ctx, deleteWorkoutByIDQuery, id, userID).Scan(&ownerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkoutNotFound
+		}
 		return fmt.Errorf("failed to delete workout: %w", err)
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return ErrWorkoutNotFound
+	if err := recomputeStreak(ctx, tx, ownerID); err != nil {
+		return err
+	}
+
+	// Sessions after the deleted one may now hold the records it held
+	for _, exerciseID := range exerciseIDs {
+		if err := recomputeExercisePersonalRecords(ctx, tx, ownerID, exerciseID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -226,53 +317,64 @@ func (r *WorkoutRepository) DeleteWorkout(
 
 func (r *WorkoutRepository) GetTimelineWorkouts(
 	ctx context.Context,
-	viewerID uuid.UUID, // $2: The person viewing the feed
-	targetID uuid.UUID, // $1: The person whose profile we are looking at
-	limit int, // $3
-	offset int, // $4
+	viewerID uuid.UUID, // $1: The person viewing the feed
+	targetID uuid.UUID, // $2: The person whose profile we are looking at
+	include models.TimelineInclude,
+	cursor *pagination.Cursor, // $3, $4
+	limit int, // $5
 ) ([]*models.TimelineWorkout, error) {
-
-	// 1. Query with correct Argument Order
-	rows, err := r.DB.Query(ctx, getTimelineWorkoutsQuery, targetID, viewerID, limit, offset)
+	query := timelineWorkoutSelect(include) + getTimelineWorkoutsFrom
+	rows, err := r.DB.Query(ctx, query, viewerID, targetID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get timeline workouts: %w", err)
 	}
 	defer rows.Close()
-	return r.scanTimelineWorkoutRows(rows)
+	return scanTimelineWorkoutRows(rows)
 }
 
-// GetFollowingTimelineWorkouts returns timeline workouts from the viewer and users they follow (accepted).
+// GetFollowingTimelineWorkouts returns the viewer's home feed from the materialized feed_items table,
+// merged with workouts pulled from followed authors too popular to fan out.
 func (r *WorkoutRepository) GetFollowingTimelineWorkouts(
 	ctx context.Context,
 	viewerID uuid.UUID,
+	include models.TimelineInclude,
+	cursor *pagination.Cursor,
 	limit int,
-	offset int,
 ) ([]*models.TimelineWorkout, error) {
-	rows, err := r.DB.Query(ctx, getFollowingTimelineWorkoutsQuery, viewerID, limit, offset)
+	query := timelineWorkoutSelect(include) + getFollowingTimelineWorkoutsFrom
+	rows, err := r.DB.Query(ctx, query, viewerID, cursor.TimeKey(), cursor.IDKey(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get following timeline workouts: %w", err)
 	}
 	defer rows.Close()
-	return r.scanTimelineWorkoutRows(rows)
+	return scanTimelineWorkoutRows(rows)
 }
 
-// GetForYouTimelineWorkouts returns timeline workouts from any visible user, ordered by engagement (likes + comments) then recency.
-func (r *WorkoutRepository) GetForYouTimelineWorkouts(
-	ctx context.Context,
-	viewerID uuid.UUID,
-	limit int,
-	offset int,
-) ([]*models.TimelineWorkout, error) {
-	rows, err := r.DB.Query(ctx, getForYouTimelineWorkoutsQuery, viewerID, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get for-you timeline workouts: %w", err)
+// timelineWorkoutSelect builds the SELECT list of a timeline query with only
+// the requested sections, so omitted ones cost nothing to compute.
+func timelineWorkoutSelect(include models.TimelineInclude) string {
+	var b strings.Builder
+	b.WriteString(timelineWorkoutColumns)
+	if include.Exercises {
+		b.WriteString(timelineWorkoutExercisesColumn)
+	} else {
+		b.WriteString(timelineWorkoutExercisesOmitted)
 	}
-	defer rows.Close()
-	return r.scanTimelineWorkoutRows(rows)
+	if include.Comments {
+		b.WriteString(timelineWorkoutCommentsColumn)
+	} else {
+		b.WriteString(timelineWorkoutCommentsOmitted)
+	}
+	if include.Images {
+		b.WriteString(timelineWorkoutImagesColumn)
+	} else {
+		b.WriteString(timelineWorkoutImagesOmitted)
+	}
+	return b.String()
 }
 
 // scanTimelineWorkoutRows scans pgx rows from a timeline workout query into []*models.TimelineWorkout.
-func (r *WorkoutRepository) scanTimelineWorkoutRows(rows pgx.Rows) ([]*models.TimelineWorkout, error) {
+func scanTimelineWorkoutRows(rows pgx.Rows) ([]*models.TimelineWorkout, error) {
 	workouts := []*models.TimelineWorkout{}
 	for rows.Next() {
 		var workout models.TimelineWorkout
@@ -283,6 +385,7 @@ func (r *WorkoutRepository) scanTimelineWorkoutRows(rows pgx.Rows) ([]*models.Ti
 			&workout.ID,
 			&workout.UserID,
 			&workout.Username,
+			&workout.DisplayName,
 			&workout.AvatarURL,
 			&workout.Name,
 			&workout.Comment,
@@ -292,6 +395,7 @@ func (r *WorkoutRepository) scanTimelineWorkoutRows(rows pgx.Rows) ([]*models.Ti
 			&workout.LikesCount,
 			&workout.CommentsCount,
 			&workout.UpdatedAt,
+			&workout.LikedByMe,
 			&exercisesJSON,
 			&commentsJSON,
 			&imagesJSON,
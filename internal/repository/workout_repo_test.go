@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
 	"github.com/rotsu1/jimu-backend/internal/repository/testutil"
 )
 
@@ -219,7 +220,7 @@ func TestGetWorkoutsByUserID(t *testing.T) {
 		t.Fatalf("Failed to create workout: %v", err)
 	}
 
-	workouts, err := repo.GetWorkoutsByUserID(ctx, userID, userID, 10, 0)
+	workouts, err := repo.GetWorkoutsByUserID(ctx, userID, userID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get workouts: %v", err)
 	}
@@ -253,7 +254,7 @@ func TestGetWorkoutsByUserIDWithPagination(t *testing.T) {
 		}
 	}
 
-	workouts, err := repo.GetWorkoutsByUserID(ctx, userID, userID, 2, 0)
+	workouts, err := repo.GetWorkoutsByUserID(ctx, userID, userID, nil, 2)
 	if err != nil {
 		t.Fatalf("Failed to get workouts: %v", err)
 	}
@@ -262,13 +263,13 @@ func TestGetWorkoutsByUserIDWithPagination(t *testing.T) {
 		t.Errorf("Expected 2 workouts with limit, got %d", len(workouts))
 	}
 
-	workouts, err = repo.GetWorkoutsByUserID(ctx, userID, userID, 2, 2)
+	workouts, err = repo.GetWorkoutsByUserID(ctx, userID, userID, &pagination.Cursor{Time: &workouts[1].StartedAt, ID: workouts[1].ID}, 2)
 	if err != nil {
 		t.Fatalf("Failed to get workouts: %v", err)
 	}
 
 	if len(workouts) != 2 {
-		t.Errorf("Expected 2 workouts with offset, got %d", len(workouts))
+		t.Errorf("Expected 2 workouts on the next page, got %d", len(workouts))
 	}
 }
 
@@ -303,7 +304,7 @@ func TestGetWorkoutsByUserIDBlockedUser(t *testing.T) {
 		t.Fatalf("Failed to insert blocked user: %v", err)
 	}
 
-	workouts, err := repo.GetWorkoutsByUserID(ctx, userID, blockedUserID, 10, 0)
+	workouts, err := repo.GetWorkoutsByUserID(ctx, userID, blockedUserID, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get workouts: %v", err)
 	}
@@ -573,7 +574,7 @@ func TestGetTimelineWorkouts(t *testing.T) {
 		t.Fatalf("Failed to create workout: %v", err)
 	}
 
-	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, 20, 0)
+	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, models.TimelineIncludeAll, nil, 20)
 	if err != nil {
 		t.Fatalf("Failed to get timeline workouts: %v", err)
 	}
@@ -621,6 +622,79 @@ func TestGetTimelineWorkouts(t *testing.T) {
 	}
 }
 
+func TestGetTimelineWorkouts_CommentPreviewAndLikedByMe(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer db.Close()
+	repo := NewWorkoutRepository(db)
+	commentRepo := NewCommentRepository(db)
+	likeRepo := NewWorkoutLikeRepository(db)
+	ctx := context.Background()
+
+	userID, _, err := testutil.InsertProfile(ctx, db, "testuser")
+	if err != nil {
+		t.Fatalf("Failed to insert profile: %v", err)
+	}
+	_, err = db.Exec(ctx, "UPDATE public.profiles SET display_name = 'Test User' WHERE id = $1", userID)
+	if err != nil {
+		t.Fatalf("Failed to set display name: %v", err)
+	}
+
+	workout, err := repo.Create(ctx, userID, nil, nil, time.Now().Add(-time.Hour), time.Now(), 0)
+	if err != nil {
+		t.Fatalf("Failed to create workout: %v", err)
+	}
+	var latest *models.Comment
+	for _, content := range []string{"one", "two", "three", "four", "five"} {
+		latest, err = commentRepo.CreateComment(ctx, userID, workout.ID, nil, content)
+		if err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+	}
+	// Replies never appear in the preview
+	if _, err := commentRepo.CreateComment(ctx, userID, workout.ID, &latest.ID, "reply"); err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	if _, err := likeRepo.LikeWorkout(ctx, userID, workout.ID); err != nil {
+		t.Fatalf("Failed to like workout: %v", err)
+	}
+
+	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, models.TimelineIncludeAll, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get timeline workouts: %v", err)
+	}
+	if len(workouts) != 1 {
+		t.Fatalf("Expected 1 timeline workout, got %d", len(workouts))
+	}
+	tw := workouts[0]
+	if len(tw.Comments) != 3 {
+		t.Fatalf("Expected a preview of 3 comments, got %d", len(tw.Comments))
+	}
+	if tw.Comments[0].Content != "three" || tw.Comments[2].Content != "five" {
+		t.Errorf("Expected the latest comments oldest first, got %q..%q", tw.Comments[0].Content, tw.Comments[2].Content)
+	}
+	if tw.CommentsCount < 5 {
+		t.Errorf("Expected comments_count to cover all comments, got %d", tw.CommentsCount)
+	}
+	if !tw.LikedByMe {
+		t.Error("Expected liked_by_me to be true")
+	}
+	if tw.DisplayName == nil || *tw.DisplayName != "Test User" {
+		t.Errorf("Expected display name %q, got %v", "Test User", tw.DisplayName)
+	}
+
+	// Sections left out come back as nil
+	workouts, err = repo.GetTimelineWorkouts(ctx, userID, userID, models.TimelineInclude{Images: true}, nil, 20)
+	if err != nil {
+		t.Fatalf("Failed to get timeline workouts: %v", err)
+	}
+	if workouts[0].Exercises != nil || workouts[0].Comments != nil {
+		t.Error("Expected exercises and comments to be omitted")
+	}
+	if workouts[0].Images == nil {
+		t.Error("Expected images to be included")
+	}
+}
+
 func TestGetTimelineWorkouts_Empty(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
@@ -632,7 +706,7 @@ func TestGetTimelineWorkouts_Empty(t *testing.T) {
 		t.Fatalf("Failed to insert profile: %v", err)
 	}
 
-	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, 20, 0)
+	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, models.TimelineIncludeAll, nil, 20)
 	if err != nil {
 		t.Fatalf("Failed to get timeline workouts: %v", err)
 	}
@@ -664,7 +738,7 @@ func TestGetTimelineWorkouts_Pagination(t *testing.T) {
 		}
 	}
 
-	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, 2, 0)
+	workouts, err := repo.GetTimelineWorkouts(ctx, userID, userID, models.TimelineIncludeAll, nil, 2)
 	if err != nil {
 		t.Fatalf("Failed to get timeline workouts: %v", err)
 	}
@@ -672,12 +746,12 @@ func TestGetTimelineWorkouts_Pagination(t *testing.T) {
 		t.Errorf("Expected 2 with limit 2, got %d", len(workouts))
 	}
 
-	workouts, err = repo.GetTimelineWorkouts(ctx, userID, userID, 2, 2)
+	workouts, err = repo.GetTimelineWorkouts(ctx, userID, userID, models.TimelineIncludeAll, &pagination.Cursor{Time: &workouts[1].StartedAt, ID: workouts[1].ID}, 2)
 	if err != nil {
 		t.Fatalf("Failed to get timeline workouts: %v", err)
 	}
 	if len(workouts) != 2 {
-		t.Errorf("Expected 2 with offset 2, got %d", len(workouts))
+		t.Errorf("Expected 2 on the next page, got %d", len(workouts))
 	}
 }
 
@@ -712,7 +786,7 @@ func TestGetTimelineWorkouts_BlockedUser(t *testing.T) {
 		t.Fatalf("Failed to insert blocked user: %v", err)
 	}
 
-	workouts, err := repo.GetTimelineWorkouts(ctx, blockedUserID, userID, 10, 0)
+	workouts, err := repo.GetTimelineWorkouts(ctx, blockedUserID, userID, models.TimelineIncludeAll, nil, 10)
 	if err != nil {
 		t.Fatalf("Failed to get timeline workouts: %v", err)
 	}
@@ -760,7 +834,7 @@ func TestGetFollowingTimelineWorkouts(t *testing.T) {
 		t.Fatalf("Failed to create workout: %v", err)
 	}
 
-	workouts, err := repo.GetFollowingTimelineWorkouts(ctx, viewerID, 20, 0)
+	workouts, err := repo.GetFollowingTimelineWorkouts(ctx, viewerID, models.TimelineIncludeAll, nil, 20)
 	if err != nil {
 		t.Fatalf("Failed to get following timeline workouts: %v", err)
 	}
@@ -788,7 +862,7 @@ func TestGetFollowingTimelineWorkouts_SelfOnly(t *testing.T) {
 		t.Fatalf("Failed to create workout: %v", err)
 	}
 
-	workouts, err := repo.GetFollowingTimelineWorkouts(ctx, userID, 20, 0)
+	workouts, err := repo.GetFollowingTimelineWorkouts(ctx, userID, models.TimelineIncludeAll, nil, 20)
 	if err != nil {
 		t.Fatalf("Failed to get following timeline workouts: %v", err)
 	}
@@ -801,104 +875,43 @@ func TestGetFollowingTimelineWorkouts_SelfOnly(t *testing.T) {
 	}
 }
 
-func TestGetForYouTimelineWorkouts(t *testing.T) {
+func TestGetWorkoutsByUserIDCursorTies(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer db.Close()
 	repo := NewWorkoutRepository(db)
 	ctx := context.Background()
 
-	viewerID, _, err := testutil.InsertProfile(ctx, db, "viewer")
-	if err != nil {
-		t.Fatalf("Failed to insert profile: %v", err)
-	}
-
-	otherID, _, err := testutil.InsertProfile(ctx, db, "other")
-	if err != nil {
-		t.Fatalf("Failed to insert profile: %v", err)
-	}
+	userID, _, _ := testutil.InsertProfile(ctx, db, "testuser")
 
-	nameOther := "Other Workout"
-	created, err := repo.Create(ctx, otherID, &nameOther, nil, time.Now(), time.Now(), 0)
-	if err != nil {
-		t.Fatalf("Failed to create workout: %v", err)
-	}
-	// Boost engagement so it appears in "for you" (public account by default)
-	_, err = db.Exec(ctx, "UPDATE public.workouts SET likes_count = 5, comments_count = 1 WHERE id = $1", created.ID)
-	if err != nil {
-		t.Fatalf("Failed to update workout: %v", err)
-	}
-
-	workouts, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, 20, 0)
-	if err != nil {
-		t.Fatalf("Failed to get for-you timeline workouts: %v", err)
+	// Workouts sharing a start time must still page without duplicates or gaps
+	startedAt := time.Now().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		if _, err := repo.Create(ctx, userID, nil, nil, startedAt, startedAt, 0); err != nil {
+			t.Fatalf("Failed to create workout: %v", err)
+		}
 	}
 
-	// Should see at least the other user's workout (visible because public)
-	if len(workouts) < 1 {
-		t.Fatalf("Expected at least 1 for-you workout, got %d", len(workouts))
-	}
-	found := false
-	for _, w := range workouts {
-		if w.ID == created.ID {
-			found = true
+	seen := map[uuid.UUID]bool{}
+	var cursor *pagination.Cursor
+	for page := 0; page < 5; page++ {
+		workouts, err := repo.GetWorkoutsByUserID(ctx, userID, userID, cursor, 2)
+		if err != nil {
+			t.Fatalf("Failed to get workouts: %v", err)
+		}
+		if len(workouts) == 0 {
 			break
 		}
-	}
-	if !found {
-		t.Error("Expected to find other user's workout in for-you feed")
-	}
-}
-
-func TestGetForYouTimelineWorkouts_OrderedByEngagement(t *testing.T) {
-	db := testutil.SetupTestDB(t)
-	defer db.Close()
-	repo := NewWorkoutRepository(db)
-	ctx := context.Background()
-
-	viewerID, _, err := testutil.InsertProfile(ctx, db, "viewer")
-	if err != nil {
-		t.Fatalf("Failed to insert profile: %v", err)
-	}
-
-	userA, _, err := testutil.InsertProfile(ctx, db, "usera")
-	if err != nil {
-		t.Fatalf("Failed to insert profile: %v", err)
-	}
-	userB, _, err := testutil.InsertProfile(ctx, db, "userb")
-	if err != nil {
-		t.Fatalf("Failed to insert profile: %v", err)
-	}
-
-	nameA := "A"
-	nameB := "B"
-	wA, err := repo.Create(ctx, userA, &nameA, nil, time.Now(), time.Now(), 0)
-	if err != nil {
-		t.Fatalf("Failed to create workout: %v", err)
-	}
-	wB, err := repo.Create(ctx, userB, &nameB, nil, time.Now(), time.Now(), 0)
-	if err != nil {
-		t.Fatalf("Failed to create workout: %v", err)
-	}
-	// A has higher engagement
-	_, err = db.Exec(ctx, "UPDATE public.workouts SET likes_count = 10, comments_count = 2 WHERE id = $1", wA.ID)
-	if err != nil {
-		t.Fatalf("Failed to update: %v", err)
-	}
-	_, err = db.Exec(ctx, "UPDATE public.workouts SET likes_count = 1, comments_count = 0 WHERE id = $1", wB.ID)
-	if err != nil {
-		t.Fatalf("Failed to update: %v", err)
-	}
-
-	workouts, err := repo.GetForYouTimelineWorkouts(ctx, viewerID, 20, 0)
-	if err != nil {
-		t.Fatalf("Failed to get for-you timeline workouts: %v", err)
+		for _, w := range workouts {
+			if seen[w.ID] {
+				t.Errorf("Workout %v returned twice", w.ID)
+			}
+			seen[w.ID] = true
+		}
+		last := workouts[len(workouts)-1]
+		cursor = &pagination.Cursor{Time: &last.StartedAt, ID: last.ID}
 	}
 
-	if len(workouts) != 2 {
-		t.Fatalf("Expected 2 workouts, got %d", len(workouts))
-	}
-	// First should be higher engagement (wA: 10+2=12, wB: 1+0=1)
-	if workouts[0].ID != wA.ID {
-		t.Errorf("Expected first workout to be higher engagement (wA), got %v", workouts[0].ID)
+	if len(seen) != 5 {
+		t.Errorf("Expected to page through 5 workouts, got %d", len(seen))
 	}
 }
@@ -1,16 +1,24 @@
 package repository
 
 const getWorkoutSetByIDQuery = `
-	SELECT id, workout_exercise_id, weight, reps, order_index, created_at, updated_at
-	FROM public.workout_sets
-	WHERE id = $1
+	SELECT ws.id, ws.workout_exercise_id, ws.weight, ws.reps, ws.order_index, ws.created_at, ws.updated_at
+	FROM public.workout_sets ws
+	JOIN public.workout_exercises we ON ws.workout_exercise_id = we.id
+	JOIN public.workouts w ON we.workout_id = w.id
+	WHERE ws.id = $1
+	-- Visibility Policy: viewer may see the owner's content (see can_view_content)
+	AND public.can_view_content($2, w.user_id, w.hidden_at)
 `
 
 const getWorkoutSetsByWorkoutExerciseIDQuery = `
-	SELECT id, workout_exercise_id, weight, reps, order_index, created_at, updated_at
-	FROM public.workout_sets
-	WHERE workout_exercise_id = $1
-	ORDER BY order_index ASC NULLS LAST, created_at ASC
+	SELECT ws.id, ws.workout_exercise_id, ws.weight, ws.reps, ws.order_index, ws.created_at, ws.updated_at
+	FROM public.workout_sets ws
+	JOIN public.workout_exercises we ON ws.workout_exercise_id = we.id
+	JOIN public.workouts w ON we.workout_id = w.id
+	WHERE ws.workout_exercise_id = $1
+	-- Visibility Policy: viewer may see the owner's content (see can_view_content)
+	AND public.can_view_content($2, w.user_id, w.hidden_at)
+	ORDER BY ws.order_index ASC NULLS LAST, ws.created_at ASC
 `
 
 const insertWorkoutSetQuery = `
@@ -35,4 +43,5 @@ const deleteWorkoutSetByIDQuery = `
       JOIN public.workouts w ON we.workout_id = w.id
       WHERE (w.user_id = $2 OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $2))
   )
+  RETURNING workout_exercise_id
 `
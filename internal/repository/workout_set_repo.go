@@ -33,7 +33,14 @@ func (r *WorkoutSetRepository) CreateWorkoutSet(
 ) (*models.WorkoutSet, error) {
 	var ws models.WorkoutSet
 
-	err := r.DB.QueryRow(
+	// Start a transaction so personal records stay in step with the set
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(
 		ctx, insertWorkoutSetQuery,
 		workoutExerciseID,
 		weight,
@@ -70,16 +77,25 @@ func (r *WorkoutSetRepository) CreateWorkoutSet(
 		return nil, fmt.Errorf("failed to create workout set: %w", err)
 	}
 
+	if err := recomputePersonalRecords(ctx, tx, ws.WorkoutExerciseID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return &ws, nil
 }
 
 func (r *WorkoutSetRepository) GetWorkoutSetByID(
 	ctx context.Context,
 	id uuid.UUID,
+	viewerID uuid.UUID,
 ) (*models.WorkoutSet, error) {
 	var ws models.WorkoutSet
 
-	err := r.DB.QueryRow(ctx, getWorkoutSetByIDQuery, id).Scan(
+	err := r.DB.QueryRow(ctx, getWorkoutSetByIDQuery, id, viewerID).Scan(
 		&ws.ID,
 		&ws.WorkoutExerciseID,
 		&ws.Weight,
@@ -101,8 +117,9 @@ func (r *WorkoutSetRepository) GetWorkoutSetByID(
 func (r *WorkoutSetRepository) GetWorkoutSetsByWorkoutExerciseID(
 	ctx context.Context,
 	workoutExerciseID uuid.UUID,
+	viewerID uuid.UUID,
 ) ([]*models.WorkoutSet, error) {
-	rows, err := r.DB.Query(ctx, getWorkoutSetsByWorkoutExerciseIDQuery, workoutExerciseID)
+	rows, err := r.DB.Query(ctx, getWorkoutSetsByWorkoutExerciseIDQuery, workoutExerciseID, viewerID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get workout sets: %w", err)
 	}
@@ -181,7 +198,8 @@ func (r *WorkoutSetRepository) UpdateWorkoutSet(
         FROM public.workout_exercises we
         JOIN public.workouts w ON we.workout_id = w.id
         WHERE (w.user_id = $%d OR EXISTS (SELECT 1 FROM public.sys_admins WHERE user_id = $%d))
-    )`,
+    )
+    RETURNING workout_exercise_id`,
 		strings.Join(sets, ", "),
 		i,   // workoutSetID
 		i+1, // userID
@@ -190,8 +208,20 @@ func (r *WorkoutSetRepository) UpdateWorkoutSet(
 
 	args = append(args, workoutSetID, userID, userID)
 
-	res, err := r.DB.Exec(ctx, query, args...)
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var workoutExerciseID uuid.UUID
+	err = tx.QueryRow(ctx, query, args...).Scan(&workoutExerciseID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Either it doesn't exist OR the user isn't the owner
+			return ErrWorkoutSetNotFound
+		}
+
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			switch pgErr.Code {
@@ -205,23 +235,32 @@ func (r *WorkoutSetRepository) UpdateWorkoutSet(
 		return fmt.Errorf("failed to update workout set: %w", err)
 	}
 
-	if res.RowsAffected() == 0 {
-		// If 0 rows are affected, it either doesn't exist OR the user isn't the owner
-		return ErrWorkoutSetNotFound
+	if err := recomputePersonalRecords(ctx, tx, workoutExerciseID); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
 func (r *WorkoutSetRepository) DeleteWorkoutSet(ctx context.Context, workoutSetID uuid.UUID, userID uuid.UUID) error {
-	commandTag, err := r.DB.Exec(ctx, deleteWorkoutSetByIDQuery, workoutSetID, userID)
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var workoutExerciseID uuid.UUID
+	err = tx.QueryRow(ctx, deleteWorkoutSetByIDQuery, workoutSetID, userID).Scan(&workoutExerciseID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWorkoutSetNotFound
+		}
 		return fmt.Errorf("failed to delete workout set: %w", err)
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return ErrWorkoutSetNotFound
+	if err := recomputePersonalRecords(ctx, tx, workoutExerciseID); err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
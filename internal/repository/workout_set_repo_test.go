@@ -110,7 +110,7 @@ func TestGetWorkoutSetByID(t *testing.T) {
 
 	created, _ := wsRepo.CreateWorkoutSet(ctx, we.ID, nil, nil, 0, userID)
 
-	ws, err := wsRepo.GetWorkoutSetByID(ctx, created.ID)
+	ws, err := wsRepo.GetWorkoutSetByID(ctx, created.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to get workout set: %v", err)
 	}
@@ -126,7 +126,7 @@ func TestGetWorkoutSetByIDNotFound(t *testing.T) {
 	wsRepo := NewWorkoutSetRepository(db)
 	ctx := context.Background()
 
-	_, err := wsRepo.GetWorkoutSetByID(ctx, uuid.New())
+	_, err := wsRepo.GetWorkoutSetByID(ctx, uuid.New(), uuid.New())
 	if !errors.Is(err, ErrWorkoutSetNotFound) {
 		t.Errorf("Expected ErrWorkoutSetNotFound, but got %v", err)
 	}
@@ -151,7 +151,7 @@ func TestGetWorkoutSetsByWorkoutExerciseID(t *testing.T) {
 	wsRepo.CreateWorkoutSet(ctx, we.ID, nil, nil, order1, userID)
 	wsRepo.CreateWorkoutSet(ctx, we.ID, nil, nil, order2, userID)
 
-	sets, err := wsRepo.GetWorkoutSetsByWorkoutExerciseID(ctx, we.ID)
+	sets, err := wsRepo.GetWorkoutSetsByWorkoutExerciseID(ctx, we.ID, userID)
 	if err != nil {
 		t.Fatalf("Failed to get workout sets: %v", err)
 	}
@@ -192,7 +192,7 @@ func TestUpdateWorkoutSet(t *testing.T) {
 		t.Fatalf("Failed to update workout set: %v", err)
 	}
 
-	updated, _ := wsRepo.GetWorkoutSetByID(ctx, ws.ID)
+	updated, _ := wsRepo.GetWorkoutSetByID(ctx, ws.ID, userID)
 	if *updated.Weight != newWeight {
 		t.Errorf("Weight was not updated: got %v, want %v", *updated.Weight, newWeight)
 	}
@@ -236,7 +236,7 @@ func TestDeleteWorkoutSet(t *testing.T) {
 		t.Fatalf("Failed to delete workout set: %v", err)
 	}
 
-	_, err = wsRepo.GetWorkoutSetByID(ctx, wsID)
+	_, err = wsRepo.GetWorkoutSetByID(ctx, wsID, userID)
 	if !errors.Is(err, ErrWorkoutSetNotFound) {
 		t.Errorf("Expected ErrWorkoutSetNotFound, but got %v", err)
 	}
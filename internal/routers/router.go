@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
 	"github.com/rotsu1/jimu-backend/internal/handlers"
 	"github.com/rotsu1/jimu-backend/internal/middleware"
+	"github.com/rotsu1/jimu-backend/internal/storage"
 )
 
 type JimuRouter struct {
@@ -28,7 +30,23 @@ type JimuRouter struct {
 	RoutineHandler              *handlers.RoutineHandler
 	RoutineExerciseHandler      *handlers.RoutineExerciseHandler
 	RoutineSetHandler           *handlers.RoutineSetHandler
+	PersonalRecordHandler       *handlers.PersonalRecordHandler
+	StatsHandler                *handlers.StatsHandler
+	MentionHandler              *handlers.MentionHandler
+	HashtagHandler              *handlers.HashtagHandler
+	ForYouHandler               *handlers.ForYouHandler
+	NotificationHandler         *handlers.NotificationHandler
+	AppStoreWebhookHandler      *handlers.AppStoreWebhookHandler
+	GooglePlayWebhookHandler    *handlers.GooglePlayWebhookHandler
 	HealthHandler               *handlers.HealthHandler
+	UploadHandler               *handlers.UploadHandler
+	AvatarHandler               *handlers.AvatarHandler
+	AdminHandler                *handlers.AdminHandler
+	ReportHandler               *handlers.ReportHandler
+	Blobs                       http.Handler // serves a local blob store's presigned URLs; nil with a bucket
+	Entitlements                middleware.FeatureChecker
+	Admins                      middleware.AdminChecker
+	Accounts                    middleware.AccountChecker
 	JWTSecret                   string
 }
 
@@ -53,15 +71,57 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			jr.HealthHandler.HealthCheck(w, r)
 			return
 		}
+	// Authenticated by the payload signature rather than a token
+	case "/webhooks/app-store":
+		if method == "POST" {
+			jr.AppStoreWebhookHandler.HandleNotification(w, r)
+			return
+		}
+	case "/webhooks/google-play":
+		if method == "POST" {
+			jr.GooglePlayWebhookHandler.HandleNotification(w, r)
+			return
+		}
+	}
+
+	// GET /avatars/{userId}/{avatarId} -> GetAvatar
+	// Avatars are as public as the profiles that link them
+	if strings.HasPrefix(path, "/avatars/") {
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		if len(parts) == 3 && method == "GET" {
+			jr.AvatarHandler.GetAvatar(w, r)
+			return
+		}
+	}
+
+	// Authenticated by the presigned URL's signature
+	if jr.Blobs != nil && strings.HasPrefix(path, storage.LocalPrefix) {
+		jr.Blobs.ServeHTTP(w, r)
+		return
 	}
 
 	// --- 2. Private Routes ---
-	authMW := middleware.AuthMiddleware(jr.JWTSecret)
+	authMW := middleware.AuthMiddleware(jr.JWTSecret, jr.Accounts)
 
 	// --- Auth Routes ---
+	// Logout skips the account check so suspended and banned users can still
+	// revoke their sessions.
 	if strings.HasPrefix(path, "/logout") {
 		if method == "POST" {
-			authMW(http.HandlerFunc(jr.AuthHandler.Logout)).ServeHTTP(w, r)
+			middleware.AuthMiddleware(jr.JWTSecret, nil)(http.HandlerFunc(jr.AuthHandler.Logout)).ServeHTTP(w, r)
+			return
+		}
+	}
+
+	// PUT /auth/profile/avatar -> UpdateMyAvatar
+	// DELETE /auth/profile/avatar -> DeleteMyAvatar
+	if path == "/auth/profile/avatar" {
+		if method == "PUT" {
+			authMW(http.HandlerFunc(jr.AvatarHandler.UpdateMyAvatar)).ServeHTTP(w, r)
+			return
+		}
+		if method == "DELETE" {
+			authMW(http.HandlerFunc(jr.AvatarHandler.DeleteMyAvatar)).ServeHTTP(w, r)
 			return
 		}
 	}
@@ -155,15 +215,32 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// --- Upload Routes ---
+	// POST /uploads -> CreateUpload
+	if path == "/uploads" {
+		if method == "POST" {
+			authMW(http.HandlerFunc(jr.UploadHandler.CreateUpload)).ServeHTTP(w, r)
+			return
+		}
+	}
+
 	// --- Follow Routes ---
 	// POST /users/{id}/follow -> FollowUser
 	// DELETE /users/{id}/follow -> UnfollowUser
 	// GET /users/{id}/followers -> GetFollowers
 	// GET /users/{id}/following -> GetFollowing
+	// GET /users/{id}/records -> GetPersonalRecords (query: exercise_id)
+	// GET /users/{id}/records/history -> GetPersonalRecordHistory (query: exercise_id, cursor, limit)
 	if strings.HasPrefix(path, "/users/") {
 		parts := strings.Split(strings.Trim(path, "/"), "/")
-		// parts: ["users", "{id}", "follow|followers|following"]
-		if len(parts) >= 3 {
+		// parts: ["users", "{id}", "follow|followers|following|records"]
+		if len(parts) == 4 && parts[2] == "records" && parts[3] == "history" {
+			if method == "GET" {
+				authMW(http.HandlerFunc(jr.PersonalRecordHandler.GetPersonalRecordHistory)).ServeHTTP(w, r)
+				return
+			}
+		}
+		if len(parts) == 3 {
 			switch parts[2] {
 			case "follow":
 				if method == "POST" {
@@ -184,6 +261,272 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					authMW(http.HandlerFunc(jr.FollowHandler.GetFollowing)).ServeHTTP(w, r)
 					return
 				}
+			case "records":
+				if method == "GET" {
+					authMW(http.HandlerFunc(jr.PersonalRecordHandler.GetPersonalRecords)).ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+	}
+
+	// --- Follow Request Routes ---
+	// GET /follow-requests -> GetFollowRequests (query: direction, cursor, limit)
+	// POST /follow-requests/accept-all -> AcceptAllFollowRequests
+	// POST /follow-requests/{userId}/accept -> AcceptFollowRequest
+	// POST /follow-requests/{userId}/reject -> RejectFollowRequest
+	if path == "/follow-requests" {
+		if method == "GET" {
+			authMW(http.HandlerFunc(jr.FollowHandler.GetFollowRequests)).ServeHTTP(w, r)
+			return
+		}
+	}
+	if strings.HasPrefix(path, "/follow-requests/") {
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		if len(parts) == 2 && parts[1] == "accept-all" {
+			if method == "POST" {
+				authMW(http.HandlerFunc(jr.FollowHandler.AcceptAllFollowRequests)).ServeHTTP(w, r)
+				return
+			}
+		}
+		if len(parts) == 3 {
+			switch parts[2] {
+			case "accept":
+				if method == "POST" {
+					authMW(http.HandlerFunc(jr.FollowHandler.AcceptFollowRequest)).ServeHTTP(w, r)
+					return
+				}
+			case "reject":
+				if method == "POST" {
+					authMW(http.HandlerFunc(jr.FollowHandler.RejectFollowRequest)).ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+	}
+
+	// --- Stats Routes ---
+	// GET /stats -> GetStats (query: from, to, bucket, top), premium only
+	if path == "/stats" {
+		if method == "GET" {
+			requirePremium := middleware.RequireFeature(jr.Entitlements, entitlements.FeatureAdvancedStats)
+			authMW(requirePremium(http.HandlerFunc(jr.StatsHandler.GetStats))).ServeHTTP(w, r)
+			return
+		}
+	}
+
+	// --- Admin Routes (sys admins only) ---
+	// GET /admin/users -> SearchUsers (query: q, cursor, limit)
+	// GET /admin/users/{id} -> GetUser
+	// PUT /admin/users/{id}/suspension -> SuspendUser
+	// DELETE /admin/users/{id}/suspension -> UnsuspendUser
+	// PUT /admin/users/{id}/ban -> BanUser
+	// DELETE /admin/users/{id}/ban -> UnbanUser
+	// DELETE /admin/users/{id}/sessions -> RevokeUserSessions
+	// DELETE /admin/comments/{id} -> RemoveComment (query: reason)
+	// DELETE /admin/workouts/{id} -> RemoveWorkout (query: reason)
+	// DELETE /admin/workout-images/{id} -> RemoveWorkoutImage (query: reason)
+	// DELETE /admin/comments/{id}/hidden -> UnhideComment
+	// DELETE /admin/workouts/{id}/hidden -> UnhideWorkout
+	// DELETE /admin/workout-images/{id}/hidden -> UnhideWorkoutImage
+	// GET /admin/reports -> ListReports (query: status, target_type, cursor, limit)
+	// POST /admin/reports/{id}/resolve -> ResolveReport
+	// GET /admin/exercises -> ListSystemExercises
+	// POST /admin/exercises -> CreateSystemExercise
+	// PUT /admin/exercises/{id} -> UpdateSystemExercise
+	// DELETE /admin/exercises/{id} -> DeleteSystemExercise
+	// POST /admin/muscles -> CreateMuscle
+	// DELETE /admin/muscles/{id} -> DeleteMuscle
+	// GET /admin/audit-log -> GetAuditLog (query: admin_id, target_type, target_id, cursor, limit)
+	if strings.HasPrefix(path, "/admin/") {
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		adminMW := middleware.RequireAdmin(jr.Admins)
+
+		resource := ""
+		if len(parts) > 1 {
+			resource = parts[1]
+		}
+		switch resource {
+		case "users":
+			if len(parts) == 2 {
+				if method == "GET" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.SearchUsers))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 3 {
+				if method == "GET" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.GetUser))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "suspension" {
+				if method == "PUT" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.SuspendUser))).ServeHTTP(w, r)
+					return
+				}
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.UnsuspendUser))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "ban" {
+				if method == "PUT" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.BanUser))).ServeHTTP(w, r)
+					return
+				}
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.UnbanUser))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "sessions" {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.RevokeUserSessions))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "comments":
+			if len(parts) == 3 {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.RemoveComment))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "hidden" {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.UnhideComment))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "workouts":
+			if len(parts) == 3 {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.RemoveWorkout))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "hidden" {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.UnhideWorkout))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "workout-images":
+			if len(parts) == 3 {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.RemoveWorkoutImage))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "hidden" {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.UnhideWorkoutImage))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "exercises":
+			if len(parts) == 2 {
+				if method == "GET" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.ListSystemExercises))).ServeHTTP(w, r)
+					return
+				}
+				if method == "POST" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.CreateSystemExercise))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 3 {
+				if method == "PUT" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.UpdateSystemExercise))).ServeHTTP(w, r)
+					return
+				}
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.DeleteSystemExercise))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "muscles":
+			if len(parts) == 2 {
+				if method == "POST" {
+					authMW(adminMW(http.HandlerFunc(jr.MuscleHandler.CreateMuscle))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 3 {
+				if method == "DELETE" {
+					authMW(adminMW(http.HandlerFunc(jr.MuscleHandler.DeleteMuscle))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "reports":
+			if len(parts) == 2 {
+				if method == "GET" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.ListReports))).ServeHTTP(w, r)
+					return
+				}
+			}
+			if len(parts) == 4 && parts[3] == "resolve" {
+				if method == "POST" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.ResolveReport))).ServeHTTP(w, r)
+					return
+				}
+			}
+		case "audit-log":
+			if len(parts) == 2 {
+				if method == "GET" {
+					authMW(adminMW(http.HandlerFunc(jr.AdminHandler.GetAuditLog))).ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+	}
+
+	// --- Mention Routes ---
+	// GET /mentions -> GetMentions (query: cursor, limit)
+	if path == "/mentions" {
+		if method == "GET" {
+			authMW(http.HandlerFunc(jr.MentionHandler.GetMentions)).ServeHTTP(w, r)
+			return
+		}
+	}
+
+	// --- Notification Routes ---
+	// GET /notifications -> GetNotifications (query: cursor, limit)
+	// GET /notifications/unread-count -> GetUnreadCount
+	// POST /notifications/read -> MarkRead
+	// POST /notifications/read-all -> MarkAllRead
+	switch path {
+	case "/notifications":
+		if method == "GET" {
+			authMW(http.HandlerFunc(jr.NotificationHandler.GetNotifications)).ServeHTTP(w, r)
+			return
+		}
+	case "/notifications/unread-count":
+		if method == "GET" {
+			authMW(http.HandlerFunc(jr.NotificationHandler.GetUnreadCount)).ServeHTTP(w, r)
+			return
+		}
+	case "/notifications/read":
+		if method == "POST" {
+			authMW(http.HandlerFunc(jr.NotificationHandler.MarkRead)).ServeHTTP(w, r)
+			return
+		}
+	case "/notifications/read-all":
+		if method == "POST" {
+			authMW(http.HandlerFunc(jr.NotificationHandler.MarkAllRead)).ServeHTTP(w, r)
+			return
+		}
+	}
+
+	// --- Hashtag Routes ---
+	// GET /hashtags/{tag}/workouts -> GetHashtagWorkouts (query: cursor, limit)
+	if strings.HasPrefix(path, "/hashtags/") {
+		parts := strings.Split(strings.Trim(path, "/"), "/")
+		if len(parts) == 3 && parts[2] == "workouts" {
+			if method == "GET" {
+				authMW(http.HandlerFunc(jr.HashtagHandler.GetHashtagWorkouts)).ServeHTTP(w, r)
+				return
 			}
 		}
 	}
@@ -214,6 +557,15 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// --- Report Routes ---
+	// POST /reports -> CreateReport
+	if path == "/reports" {
+		if method == "POST" {
+			authMW(http.HandlerFunc(jr.ReportHandler.CreateReport)).ServeHTTP(w, r)
+			return
+		}
+	}
+
 	// --- Workout Routes (with sub-resources) ---
 	// GET /workouts -> ListWorkouts
 	// POST /workouts -> CreateWorkout
@@ -225,6 +577,7 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// GET /workouts/{id}/likes -> ListLikes
 	// POST /workouts/{id}/images -> AddImage
 	// GET /workouts/{id}/images -> ListImages
+	// PUT /workouts/{id}/images/order -> ReorderImages
 	// DELETE /workouts/{id}/images/{imageId} -> RemoveImage
 	// POST /workouts/{id}/exercises -> AddExercise
 	// PUT /workouts/{id}/exercises/{exerciseId} -> UpdateExercise
@@ -247,8 +600,8 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		// GET /workouts/timeline/following -> GetFollowingTimelineWorkouts (query: limit, offset)
-		// GET /workouts/timeline/for-you -> GetForYouTimelineWorkouts (query: limit, offset)
+		// GET /workouts/timeline/following -> GetFollowingTimelineWorkouts (query: cursor, limit, include)
+		// GET /workouts/timeline/for-you -> GetForYouTimelineWorkouts (query: cursor, limit, include)
 		if len(parts) == 3 && parts[1] == "timeline" {
 			if method == "GET" {
 				switch parts[2] {
@@ -256,13 +609,21 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					authMW(http.HandlerFunc(jr.WorkoutHandler.GetFollowingTimelineWorkouts)).ServeHTTP(w, r)
 					return
 				case "for-you":
-					authMW(http.HandlerFunc(jr.WorkoutHandler.GetForYouTimelineWorkouts)).ServeHTTP(w, r)
+					authMW(http.HandlerFunc(jr.ForYouHandler.GetForYouTimelineWorkouts)).ServeHTTP(w, r)
 					return
 				}
 			}
 		}
 
-		// GET /workouts/timeline -> GetTimelineWorkouts (query: user_id, limit, offset)
+		// POST /workouts/timeline/for-you/seen -> MarkSeen
+		if len(parts) == 4 && parts[1] == "timeline" && parts[2] == "for-you" && parts[3] == "seen" {
+			if method == "POST" {
+				authMW(http.HandlerFunc(jr.ForYouHandler.MarkSeen)).ServeHTTP(w, r)
+				return
+			}
+		}
+
+		// GET /workouts/timeline -> GetTimelineWorkouts (query: user_id, cursor, limit, include)
 		if len(parts) == 2 && parts[1] == "timeline" {
 			if method == "GET" {
 				authMW(http.HandlerFunc(jr.WorkoutHandler.GetTimelineWorkouts)).ServeHTTP(w, r)
@@ -321,6 +682,13 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						return
 					}
 				}
+				// PUT /workouts/{id}/images/order -> ReorderImages
+				if len(parts) == 4 && parts[3] == "order" {
+					if method == "PUT" {
+						authMW(http.HandlerFunc(jr.WorkoutImageHandler.ReorderImages)).ServeHTTP(w, r)
+						return
+					}
+				}
 				// DELETE /workouts/{id}/images/{imageId} -> RemoveImage
 				if len(parts) == 4 {
 					if method == "DELETE" {
@@ -388,6 +756,8 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// GET /exercises/{id} -> GetExercise
 	// PUT /exercises/{id} -> UpdateExercise
 	// DELETE /exercises/{id} -> DeleteExercise
+	// GET /exercises/{id}/history -> GetExerciseHistory (query: cursor, limit)
+	// GET /exercises/{id}/last -> GetLastExerciseSession
 	// POST /exercises/{id}/muscles -> AddTargetMuscle
 	// DELETE /exercises/{id}/muscles/{muscleId} -> RemoveTargetMuscle
 	if strings.HasPrefix(path, "/exercises") {
@@ -421,6 +791,18 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		// GET /exercises/{id}/history or /exercises/{id}/last
+		if len(parts) == 3 && method == "GET" {
+			switch parts[2] {
+			case "history":
+				authMW(http.HandlerFunc(jr.ExerciseHandler.GetExerciseHistory)).ServeHTTP(w, r)
+				return
+			case "last":
+				authMW(http.HandlerFunc(jr.ExerciseHandler.GetLastExerciseSession)).ServeHTTP(w, r)
+				return
+			}
+		}
+
 		// /exercises/{id}/muscles
 		if len(parts) >= 3 && parts[2] == "muscles" {
 			if len(parts) == 3 {
@@ -475,6 +857,7 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// GET /comments -> ListComments (with query params: workout_id, parent_id)
 	// POST /comments -> CreateComment
 	// GET /comments/{id} -> GetComment
+	// PUT /comments/{id} -> UpdateComment
 	// DELETE /comments/{id} -> DeleteComment
 	// POST /comments/{id}/likes -> LikeComment
 	// DELETE /comments/{id}/likes -> UnlikeComment
@@ -495,11 +878,15 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if len(parts) == 2 {
-			// GET/DELETE /comments/{id}
+			// GET/PUT/DELETE /comments/{id}
 			if method == "GET" {
 				authMW(http.HandlerFunc(jr.CommentHandler.GetComment)).ServeHTTP(w, r)
 				return
 			}
+			if method == "PUT" {
+				authMW(http.HandlerFunc(jr.CommentHandler.UpdateComment)).ServeHTTP(w, r)
+				return
+			}
 			if method == "DELETE" {
 				authMW(http.HandlerFunc(jr.CommentHandler.DeleteComment)).ServeHTTP(w, r)
 				return
@@ -529,6 +916,7 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// GET /routines/{id} -> GetRoutine
 	// PUT /routines/{id} -> UpdateRoutine
 	// DELETE /routines/{id} -> DeleteRoutine
+	// POST /routines/{id}/start -> StartRoutine
 	// POST /routines/{id}/exercises -> AddExercise (to routine)
 	// DELETE /routines/{id}/exercises/{exerciseId} -> RemoveExercise (from routine)
 	if strings.HasPrefix(path, "/routines") {
@@ -562,6 +950,14 @@ func (jr *JimuRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		// POST /routines/{id}/start -> StartRoutine
+		if len(parts) == 3 && parts[2] == "start" {
+			if method == "POST" {
+				authMW(http.HandlerFunc(jr.RoutineHandler.StartRoutine)).ServeHTTP(w, r)
+				return
+			}
+		}
+
 		// /routines/{id}/exercises
 		if len(parts) >= 3 && parts[2] == "exercises" {
 			if len(parts) == 3 {
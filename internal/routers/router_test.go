@@ -44,6 +44,9 @@ func TestJimuRouter_Routing(t *testing.T) {
 		RoutineHandler:              &handlers.RoutineHandler{},
 		RoutineExerciseHandler:      &handlers.RoutineExerciseHandler{},
 		RoutineSetHandler:           &handlers.RoutineSetHandler{},
+		AvatarHandler:               &handlers.AvatarHandler{},
+		AdminHandler:                &handlers.AdminHandler{},
+		ReportHandler:               &handlers.ReportHandler{},
 		HealthHandler:               healthHandler,
 		JWTSecret:                   "test-secret",
 	}
@@ -70,6 +73,8 @@ func TestJimuRouter_Routing(t *testing.T) {
 		// Health (Public)
 		{"Health Check - GET", "GET", "/health", http.StatusOK},
 		{"Health Check - Wrong Method POST", "POST", "/health", http.StatusNotFound},
+		{"App Store Webhook - Wrong Method GET", "GET", "/webhooks/app-store", http.StatusNotFound},
+		{"Google Play Webhook - Wrong Method GET", "GET", "/webhooks/google-play", http.StatusNotFound},
 
 		// =====================================================================
 		// PRIVATE ROUTES - AUTH DOMAIN
@@ -117,6 +122,19 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Subscriptions - Wrong Method DELETE", "DELETE", "/subscriptions", http.StatusNotFound},
 		{"Subscriptions - Wrong Method PUT", "PUT", "/subscriptions", http.StatusNotFound},
 
+		// Uploads
+		{"Create Upload - No Token", "POST", "/uploads", http.StatusUnauthorized},
+		{"Uploads - Wrong Method GET", "GET", "/uploads", http.StatusNotFound},
+		{"Blobs - No Local Store", "GET", "/blobs/uploads/a", http.StatusNotFound},
+
+		// Avatars
+		{"Update My Avatar - No Token", "PUT", "/auth/profile/avatar", http.StatusUnauthorized},
+		{"Delete My Avatar - No Token", "DELETE", "/auth/profile/avatar", http.StatusUnauthorized},
+		{"Avatar - Wrong Method POST", "POST", "/auth/profile/avatar", http.StatusNotFound},
+		{"Get Avatar - Public, Invalid IDs", "GET", "/avatars/a/b", http.StatusBadRequest},
+		{"Get Avatar - Missing Avatar ID", "GET", "/avatars/" + testUUID, http.StatusNotFound},
+		{"Avatars - Wrong Method PUT", "PUT", "/avatars/" + testUUID + "/" + testUUID, http.StatusNotFound},
+
 		// =====================================================================
 		// PRIVATE ROUTES - FOLLOWS DOMAIN (Social)
 		// =====================================================================
@@ -131,6 +149,38 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Followers - Wrong Method POST", "POST", "/users/" + testUUID + "/followers", http.StatusNotFound},
 		{"Following - Wrong Method DELETE", "DELETE", "/users/" + testUUID + "/following", http.StatusNotFound},
 
+		// Follow Requests
+		{"Get Follow Requests - No Token", "GET", "/follow-requests", http.StatusUnauthorized},
+		{"Follow Requests - Wrong Method POST", "POST", "/follow-requests", http.StatusNotFound},
+		{"Accept All Follow Requests - No Token", "POST", "/follow-requests/accept-all", http.StatusUnauthorized},
+		{"Accept All Follow Requests - Wrong Method GET", "GET", "/follow-requests/accept-all", http.StatusNotFound},
+		{"Accept Follow Request - No Token", "POST", "/follow-requests/" + testUUID + "/accept", http.StatusUnauthorized},
+		{"Reject Follow Request - No Token", "POST", "/follow-requests/" + testUUID + "/reject", http.StatusUnauthorized},
+		{"Accept Follow Request - Wrong Method DELETE", "DELETE", "/follow-requests/" + testUUID + "/accept", http.StatusNotFound},
+
+		// Stats
+		{"Get Stats - No Token", "GET", "/stats", http.StatusUnauthorized},
+		{"Stats - Wrong Method POST", "POST", "/stats", http.StatusNotFound},
+
+		// Mentions & Hashtags
+		{"Get Mentions - No Token", "GET", "/mentions", http.StatusUnauthorized},
+		{"Mentions - Wrong Method POST", "POST", "/mentions", http.StatusNotFound},
+
+		// Notifications
+		{"Get Notifications - No Token", "GET", "/notifications", http.StatusUnauthorized},
+		{"Notifications - Wrong Method POST", "POST", "/notifications", http.StatusNotFound},
+		{"Get Unread Count - No Token", "GET", "/notifications/unread-count", http.StatusUnauthorized},
+		{"Mark Notifications Read - No Token", "POST", "/notifications/read", http.StatusUnauthorized},
+		{"Notifications Read - Wrong Method GET", "GET", "/notifications/read", http.StatusNotFound},
+		{"Mark All Notifications Read - No Token", "POST", "/notifications/read-all", http.StatusUnauthorized},
+		{"Get Hashtag Workouts - No Token", "GET", "/hashtags/legday/workouts", http.StatusUnauthorized},
+		{"Hashtag - Missing Sub-resource", "GET", "/hashtags/legday", http.StatusNotFound},
+
+		// Personal records
+		{"Get Personal Records - No Token", "GET", "/users/" + testUUID + "/records", http.StatusUnauthorized},
+		{"Personal Records - Wrong Method POST", "POST", "/users/" + testUUID + "/records", http.StatusNotFound},
+		{"Get Personal Record History - No Token", "GET", "/users/" + testUUID + "/records/history", http.StatusUnauthorized},
+
 		// =====================================================================
 		// PRIVATE ROUTES - BLOCKED USERS DOMAIN (Social)
 		// =====================================================================
@@ -138,6 +188,12 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Get Blocked Users - No Token", "GET", "/blocked-users", http.StatusUnauthorized},
 		{"Blocked Users - Wrong Method DELETE on collection", "DELETE", "/blocked-users", http.StatusNotFound},
 
+		// =====================================================================
+		// PRIVATE ROUTES - REPORTS DOMAIN
+		// =====================================================================
+		{"Create Report - No Token", "POST", "/reports", http.StatusUnauthorized},
+		{"Reports - Wrong Method GET", "GET", "/reports", http.StatusNotFound},
+
 		// Unblock User (with ID)
 		{"Unblock User - No Token", "DELETE", "/blocked-users/" + testUUID, http.StatusUnauthorized},
 		{"Unblock User - Wrong Method GET", "GET", "/blocked-users/" + testUUID, http.StatusNotFound},
@@ -164,6 +220,8 @@ func TestJimuRouter_Routing(t *testing.T) {
 		// Timeline For You (GET /workouts/timeline/for-you)
 		{"Get For You Timeline Workouts - No Token", "GET", "/workouts/timeline/for-you", http.StatusUnauthorized},
 		{"Timeline For You - Wrong Method PUT", "PUT", "/workouts/timeline/for-you", http.StatusNotFound},
+		{"Mark For You Seen - No Token", "POST", "/workouts/timeline/for-you/seen", http.StatusUnauthorized},
+		{"For You Seen - Wrong Method GET", "GET", "/workouts/timeline/for-you/seen", http.StatusNotFound},
 
 		// Single workout routes
 		{"Get Workout - No Token", "GET", "/workouts/" + testUUID, http.StatusUnauthorized},
@@ -183,6 +241,8 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Workout Images - Wrong Method DELETE on collection", "DELETE", "/workouts/" + testUUID + "/images", http.StatusNotFound},
 		{"Remove Workout Image - No Token", "DELETE", "/workouts/" + testUUID + "/images/" + testUUID, http.StatusUnauthorized},
 		{"Remove Workout Image - Wrong Method GET", "GET", "/workouts/" + testUUID + "/images/" + testUUID, http.StatusNotFound},
+		{"Reorder Workout Images - No Token", "PUT", "/workouts/" + testUUID + "/images/order", http.StatusUnauthorized},
+		{"Reorder Workout Images - Wrong Method POST", "POST", "/workouts/" + testUUID + "/images/order", http.StatusNotFound},
 
 		// Workout Exercises sub-resource
 		{"Add Workout Exercise - No Token", "POST", "/workouts/" + testUUID + "/exercises", http.StatusUnauthorized},
@@ -221,6 +281,11 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Delete Exercise - No Token", "DELETE", "/exercises/" + testUUID, http.StatusUnauthorized},
 		{"Exercise Detail - Wrong Method POST", "POST", "/exercises/" + testUUID, http.StatusNotFound},
 
+		// Exercise history
+		{"Exercise History - No Token", "GET", "/exercises/" + testUUID + "/history", http.StatusUnauthorized},
+		{"Exercise History - Wrong Method POST", "POST", "/exercises/" + testUUID + "/history", http.StatusNotFound},
+		{"Last Exercise Session - No Token", "GET", "/exercises/" + testUUID + "/last", http.StatusUnauthorized},
+
 		// Exercise Target Muscles sub-resource
 		{"Add Target Muscle - No Token", "POST", "/exercises/" + testUUID + "/muscles", http.StatusUnauthorized},
 		{"Exercise Muscles - Wrong Method GET", "GET", "/exercises/" + testUUID + "/muscles", http.StatusNotFound},
@@ -242,6 +307,52 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Muscle Detail - Wrong Method POST", "POST", "/muscles/" + testUUID, http.StatusNotFound},
 		{"Muscle Detail - Wrong Method PUT", "PUT", "/muscles/" + testUUID, http.StatusNotFound},
 
+		// =====================================================================
+		// PRIVATE ROUTES - ADMIN DOMAIN
+		// =====================================================================
+		// Users
+		{"Admin Search Users - No Token", "GET", "/admin/users", http.StatusUnauthorized},
+		{"Admin Get User - No Token", "GET", "/admin/users/" + testUUID, http.StatusUnauthorized},
+		{"Admin Suspend User - No Token", "PUT", "/admin/users/" + testUUID + "/suspension", http.StatusUnauthorized},
+		{"Admin Unsuspend User - No Token", "DELETE", "/admin/users/" + testUUID + "/suspension", http.StatusUnauthorized},
+		{"Admin Revoke Sessions - No Token", "DELETE", "/admin/users/" + testUUID + "/sessions", http.StatusUnauthorized},
+		{"Admin Users - Wrong Method POST", "POST", "/admin/users", http.StatusNotFound},
+		{"Admin User - Wrong Method DELETE", "DELETE", "/admin/users/" + testUUID, http.StatusNotFound},
+		{"Admin Suspension - Wrong Method GET", "GET", "/admin/users/" + testUUID + "/suspension", http.StatusNotFound},
+		{"Admin Ban User - No Token", "PUT", "/admin/users/" + testUUID + "/ban", http.StatusUnauthorized},
+		{"Admin Unban User - No Token", "DELETE", "/admin/users/" + testUUID + "/ban", http.StatusUnauthorized},
+		{"Admin Ban - Wrong Method GET", "GET", "/admin/users/" + testUUID + "/ban", http.StatusNotFound},
+
+		// Takedowns
+		{"Admin Remove Comment - No Token", "DELETE", "/admin/comments/" + testUUID, http.StatusUnauthorized},
+		{"Admin Remove Workout - No Token", "DELETE", "/admin/workouts/" + testUUID, http.StatusUnauthorized},
+		{"Admin Remove Workout Image - No Token", "DELETE", "/admin/workout-images/" + testUUID, http.StatusUnauthorized},
+		{"Admin Comment - Wrong Method GET", "GET", "/admin/comments/" + testUUID, http.StatusNotFound},
+		{"Admin Unhide Comment - No Token", "DELETE", "/admin/comments/" + testUUID + "/hidden", http.StatusUnauthorized},
+		{"Admin Unhide Workout - No Token", "DELETE", "/admin/workouts/" + testUUID + "/hidden", http.StatusUnauthorized},
+		{"Admin Unhide Workout Image - No Token", "DELETE", "/admin/workout-images/" + testUUID + "/hidden", http.StatusUnauthorized},
+		{"Admin Hidden - Wrong Method PUT", "PUT", "/admin/workouts/" + testUUID + "/hidden", http.StatusNotFound},
+
+		// Moderation queue
+		{"Admin List Reports - No Token", "GET", "/admin/reports", http.StatusUnauthorized},
+		{"Admin Resolve Report - No Token", "POST", "/admin/reports/" + testUUID + "/resolve", http.StatusUnauthorized},
+		{"Admin Reports - Wrong Method POST", "POST", "/admin/reports", http.StatusNotFound},
+		{"Admin Resolve Report - Wrong Method GET", "GET", "/admin/reports/" + testUUID + "/resolve", http.StatusNotFound},
+
+		// System exercises and muscles
+		{"Admin List Exercises - No Token", "GET", "/admin/exercises", http.StatusUnauthorized},
+		{"Admin Create Exercise - No Token", "POST", "/admin/exercises", http.StatusUnauthorized},
+		{"Admin Update Exercise - No Token", "PUT", "/admin/exercises/" + testUUID, http.StatusUnauthorized},
+		{"Admin Delete Exercise - No Token", "DELETE", "/admin/exercises/" + testUUID, http.StatusUnauthorized},
+		{"Admin Exercise - Wrong Method GET", "GET", "/admin/exercises/" + testUUID, http.StatusNotFound},
+		{"Admin Create Muscle - No Token", "POST", "/admin/muscles", http.StatusUnauthorized},
+		{"Admin Delete Muscle - No Token", "DELETE", "/admin/muscles/" + testUUID, http.StatusUnauthorized},
+
+		// Audit log
+		{"Admin Audit Log - No Token", "GET", "/admin/audit-log", http.StatusUnauthorized},
+		{"Admin Audit Log - Wrong Method POST", "POST", "/admin/audit-log", http.StatusNotFound},
+		{"Admin Unknown Resource", "GET", "/admin/", http.StatusNotFound},
+
 		// =====================================================================
 		// PRIVATE ROUTES - COMMENTS DOMAIN
 		// =====================================================================
@@ -253,9 +364,9 @@ func TestJimuRouter_Routing(t *testing.T) {
 
 		// Single comment routes
 		{"Get Comment - No Token", "GET", "/comments/" + testUUID, http.StatusUnauthorized},
+		{"Update Comment - No Token", "PUT", "/comments/" + testUUID, http.StatusUnauthorized},
 		{"Delete Comment - No Token", "DELETE", "/comments/" + testUUID, http.StatusUnauthorized},
 		{"Comment Detail - Wrong Method POST", "POST", "/comments/" + testUUID, http.StatusNotFound},
-		{"Comment Detail - Wrong Method PUT", "PUT", "/comments/" + testUUID, http.StatusNotFound},
 
 		// Comment Likes sub-resource
 		{"Like Comment - No Token", "POST", "/comments/" + testUUID + "/likes", http.StatusUnauthorized},
@@ -277,6 +388,8 @@ func TestJimuRouter_Routing(t *testing.T) {
 		{"Update Routine - No Token", "PUT", "/routines/" + testUUID, http.StatusUnauthorized},
 		{"Delete Routine - No Token", "DELETE", "/routines/" + testUUID, http.StatusUnauthorized},
 		{"Routine Detail - Wrong Method POST", "POST", "/routines/" + testUUID, http.StatusNotFound},
+		{"Start Routine - No Token", "POST", "/routines/" + testUUID + "/start", http.StatusUnauthorized},
+		{"Start Routine - Wrong Method GET", "GET", "/routines/" + testUUID + "/start", http.StatusNotFound},
 
 		// Routine Exercises sub-resource
 		{"Add Routine Exercise - No Token", "POST", "/routines/" + testUUID + "/exercises", http.StatusUnauthorized},
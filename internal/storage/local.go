@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPrefix is the path the LocalStore's Handler is mounted at.
+const LocalPrefix = "/blobs/"
+
+// ErrInvalidKey is returned for keys that are empty or escape the store.
+var ErrInvalidKey = errors.New("invalid object key")
+
+// LocalStore keeps objects on the local filesystem, for development and
+// tests. Its presigned URLs point at Handler, which checks an HMAC of the
+// request the URL was issued for, the way a bucket checks a presigned URL.
+type LocalStore struct {
+	Root string
+	// BaseURL is the externally reachable origin of the API server.
+	BaseURL string
+	Secret  []byte
+	Now     func() time.Time
+}
+
+func NewLocalStore(root, baseURL string, secret []byte) *LocalStore {
+	return &LocalStore{Root: root, BaseURL: strings.TrimSuffix(baseURL, "/"), Secret: secret, Now: time.Now}
+}
+
+func (s *LocalStore) PresignPut(ctx context.Context, key string, contentType string, size int64, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, contentType, size, ttl)
+}
+
+func (s *LocalStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, "", 0, ttl)
+}
+
+func (s *LocalStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to stat object: %w", err)
+	}
+	return ObjectInfo{Size: info.Size()}, nil
+}
+
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open object: %w", err)
+	}
+	return f, nil
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	return s.write(key, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// path maps a key onto the filesystem, rejecting keys that would leave Root.
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
+
+// write replaces an object atomically, so readers never see a partial file.
+func (s *LocalStore) write(key string, fill func(*os.File) error) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("failed to create object directory: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create object: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := fill(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) presign(method, key, contentType string, size int64, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := s.Now().Add(ttl).Unix()
+
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("sig", s.sign(method, key, contentType, size, expires))
+	return s.BaseURL + LocalPrefix + key + "?" + q.Encode(), nil
+}
+
+func (s *LocalStore) sign(method, key, contentType string, size int64, expires int64) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%d", method, key, contentType, size, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Handler serves the LocalStore's presigned URLs. PUTs must carry the
+// Content-Type and Content-Length the URL was signed for.
+func (s *LocalStore) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, LocalPrefix)
+		if _, err := s.path(key); err != nil {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+
+		expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+		if err != nil || s.Now().Unix() > expires {
+			http.Error(w, "Request has expired", http.StatusForbidden)
+			return
+		}
+
+		var contentType string
+		var size int64
+		if r.Method == http.MethodPut {
+			contentType, _, _ = mime.ParseMediaType(r.Header.Get("Content-Type"))
+			size = r.ContentLength
+		}
+		want := s.sign(r.Method, key, contentType, size, expires)
+		if !hmac.Equal([]byte(want), []byte(r.URL.Query().Get("sig"))) {
+			http.Error(w, "Signature does not match", http.StatusForbidden)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			f, err := s.Get(r.Context(), key)
+			if errors.Is(err, ErrNotFound) {
+				http.Error(w, "Not Found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				log.Printf("Read blob error: %v", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			defer f.Close()
+			http.ServeContent(w, r, path.Base(key), time.Time{}, f.(io.ReadSeeker))
+		case http.MethodPut:
+			err := s.write(key, func(f *os.File) error {
+				n, err := io.Copy(f, http.MaxBytesReader(w, r.Body, size))
+				if err == nil && n != size {
+					err = io.ErrUnexpectedEOF
+				}
+				return err
+			})
+			if err != nil {
+				log.Printf("Write blob error: %v", err)
+				http.Error(w, "Failed to store object", http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestLocalStore(t *testing.T) (*LocalStore, *httptest.Server) {
+	t.Helper()
+	s := NewLocalStore(t.TempDir(), "", []byte("secret"))
+	mux := http.NewServeMux()
+	mux.Handle(LocalPrefix, s.Handler())
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	s.BaseURL = srv.URL
+	return s, srv
+}
+
+func put(t *testing.T, url, contentType string, body []byte) int {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PUT failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestLocalStore_PresignedRoundTrip(t *testing.T) {
+	s, _ := newTestLocalStore(t)
+	ctx := context.Background()
+	body := []byte("image bytes")
+
+	putURL, err := s.PresignPut(ctx, "uploads/u1/a", "image/jpeg", int64(len(body)), time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to presign: %v", err)
+	}
+	if code := put(t, putURL, "image/png", body); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another Content-Type, got %d", code)
+	}
+	if code := put(t, putURL, "image/jpeg", body[:4]); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another size, got %d", code)
+	}
+	if code := put(t, putURL, "image/jpeg", body); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	info, err := s.Stat(ctx, "uploads/u1/a")
+	if err != nil || info.Size != int64(len(body)) {
+		t.Fatalf("Expected a %d byte object, got %+v, %v", len(body), info, err)
+	}
+
+	getURL, _ := s.PresignGet(ctx, "uploads/u1/a", time.Minute)
+	resp, err := http.Get(getURL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(got, body) {
+		t.Errorf("Expected the object back, got %d %q", resp.StatusCode, got)
+	}
+
+	// A GET URL does not authorize a PUT
+	if code := put(t, getURL, "image/jpeg", body); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a PUT to a GET URL, got %d", code)
+	}
+}
+
+func TestLocalStore_ExpiredURL(t *testing.T) {
+	s, _ := newTestLocalStore(t)
+	s.Now = func() time.Time { return time.Now().Add(-time.Hour) }
+	getURL, _ := s.PresignGet(context.Background(), "a", time.Minute)
+	s.Now = time.Now
+
+	resp, err := http.Get(getURL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestLocalStore_Objects(t *testing.T) {
+	s, _ := newTestLocalStore(t)
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "images/a.jpg", "image/jpeg", []byte("x")); err != nil {
+		t.Fatalf("Failed to put: %v", err)
+	}
+	if err := s.Delete(ctx, "images/a.jpg"); err != nil {
+		t.Fatalf("Failed to delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "images/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if err := s.Delete(ctx, "images/a.jpg"); err != nil {
+		t.Errorf("Expected deleting a missing object to succeed, got %v", err)
+	}
+
+	for _, key := range []string{"", "../escape", "/abs", "a/../../b"} {
+		if err := s.Put(ctx, key, "image/jpeg", []byte("x")); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Expected ErrInvalidKey for %q, got %v", key, err)
+		}
+	}
+}
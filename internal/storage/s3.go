@@ -0,0 +1,286 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// maxPresignTTL is the longest expiry SigV4 allows.
+	maxPresignTTL = 7 * 24 * time.Hour
+	// requestTimeout bounds a storage call, including reading the body of
+	// an object as large as the biggest upload.
+	requestTimeout = time.Minute
+)
+
+// S3Store keeps objects in an S3-compatible bucket, authenticated with AWS
+// Signature Version 4. Google Cloud Storage speaks the same protocol with
+// HMAC keys; see NewGCSStore.
+type S3Store struct {
+	Endpoint string
+	Region   string
+	// Bucket is addressed path-style under Endpoint. When empty, Endpoint is
+	// the bucket's own virtual-hosted origin.
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Client          *http.Client
+	Now             func() time.Time
+}
+
+func NewS3Store(region, bucket, accessKeyID, secretAccessKey string) *S3Store {
+	return &S3Store{
+		Endpoint:        fmt.Sprintf("https://s3.%s.amazonaws.com", region),
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Client:          &http.Client{Timeout: requestTimeout},
+		Now:             time.Now,
+	}
+}
+
+// NewGCSStore uses a Cloud Storage bucket through its XML API, with an HMAC
+// key of a service account that can read and write the bucket.
+func NewGCSStore(bucket, accessID, secret string) *S3Store {
+	return &S3Store{
+		Endpoint:        "https://storage.googleapis.com",
+		Region:          "auto",
+		Bucket:          bucket,
+		AccessKeyID:     accessID,
+		SecretAccessKey: secret,
+		Client:          &http.Client{Timeout: requestTimeout},
+		Now:             time.Now,
+	}
+}
+
+func (s *S3Store) PresignPut(ctx context.Context, key string, contentType string, size int64, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodPut, key, ttl, map[string]string{
+		"content-type":   contentType,
+		"content-length": strconv.FormatInt(size, 10),
+	})
+}
+
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, ttl, nil)
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, "", nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+	return ObjectInfo{Size: resp.ContentLength}, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	u, _ := url.Parse(s.Endpoint)
+	u.Path = "/" + key
+	u.RawPath = "/" + uriEncode(key, false)
+	if s.Bucket != "" {
+		u.Path = "/" + s.Bucket + u.Path
+		u.RawPath = "/" + uriEncode(s.Bucket, false) + u.RawPath
+	}
+	return u
+}
+
+// presign builds a query-authenticated URL. Extra headers are signed, so the
+// client must send them with exactly these values.
+func (s *S3Store) presign(method, key string, ttl time.Duration, headers map[string]string) (string, error) {
+	if ttl <= 0 || ttl > maxPresignTTL {
+		return "", fmt.Errorf("presigned URL expiry %v is out of range", ttl)
+	}
+	now := s.Now().UTC()
+	u := s.objectURL(key)
+
+	signed := map[string]string{"host": u.Host}
+	for k, v := range headers {
+		signed[k] = v
+	}
+	names := sortedKeys(signed)
+
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", sigV4Algorithm)
+	q.Set("X-Amz-Credential", s.AccessKeyID+"/"+s.scope(now))
+	q.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	q.Set("X-Amz-SignedHeaders", strings.Join(names, ";"))
+
+	canonical := canonicalRequest(method, u.RawPath, canonicalQuery(q), signed, names, unsignedPayload)
+	q.Set("X-Amz-Signature", s.signature(now, canonical))
+	u.RawQuery = canonicalQuery(q)
+	return u.String(), nil
+}
+
+// do sends a header-authenticated request for an object.
+func (s *S3Store) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	now := s.Now().UTC()
+	u := s.objectURL(key)
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	signed := map[string]string{
+		"host":                 u.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           now.Format("20060102T150405Z"),
+	}
+	if contentType != "" {
+		signed["content-type"] = contentType
+	}
+	names := sortedKeys(signed)
+
+	canonical := canonicalRequest(method, u.RawPath, "", signed, names, payloadHash)
+	auth := fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.AccessKeyID, s.scope(now), strings.Join(names, ";"), s.signature(now, canonical))
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build storage request: %w", err)
+	}
+	for _, name := range names {
+		if name != "host" {
+			req.Header.Set(name, signed[name])
+		}
+	}
+	req.Header.Set("Authorization", auth)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call storage: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		resp.Body.Close()
+		return nil, fmt.Errorf("storage returned %d: %s", resp.StatusCode, msg)
+	}
+	return resp, nil
+}
+
+func (s *S3Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.Region + "/s3/aws4_request"
+}
+
+func (s *S3Store) signature(now time.Time, canonical string) string {
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format("20060102T150405Z"),
+		s.scope(now),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalRequest(method, path, query string, headers map[string]string, names []string, payloadHash string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return strings.Join([]string{
+		method,
+		path,
+		query,
+		b.String(),
+		strings.Join(names, ";"),
+		payloadHash,
+	}, "\n")
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := sortedKeys(q)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, uriEncode(k, true)+"="+uriEncode(q.Get(k), true))
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but unreserved characters, as SigV4
+// requires; slashes are kept unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Size int64
+}
+
+// BlobStore keeps uploaded files. Clients transfer object bodies directly
+// through presigned URLs; the server only reads and writes them to process
+// uploads.
+type BlobStore interface {
+	// PresignPut returns a URL that accepts a PUT of exactly size bytes with
+	// the given Content-Type until ttl has passed.
+	PresignPut(ctx context.Context, key string, contentType string, size int64, ttl time.Duration) (string, error)
+	// PresignGet returns a URL from which the object can be read until ttl
+	// has passed.
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	// Delete removes an object; deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
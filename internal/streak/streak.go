@@ -0,0 +1,147 @@
+package streak
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	RuleDaily  = "daily"  // one workout per calendar day
+	RuleWeekly = "weekly" // at least PerWeek workouts per Monday-based week
+)
+
+// Rule describes what counts as keeping a streak alive.
+type Rule struct {
+	Kind    string
+	PerWeek int
+}
+
+// Result is a computed streak. Current and Longest are counted in days for
+// RuleDaily and in weeks for RuleWeekly. ExpiresAt is the instant at which
+// Current drops to zero unless another workout is logged; it is nil when
+// there is no current streak.
+type Result struct {
+	Current   int
+	Longest   int
+	ExpiresAt *time.Time
+}
+
+// Compute derives streaks from workout start times, evaluated in loc as of now.
+// Workouts that start after now are ignored.
+func Compute(startedAt []time.Time, now time.Time, loc *time.Location, rule Rule) Result {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if rule.Kind == RuleWeekly {
+		return computeWeekly(startedAt, now, loc, rule.PerWeek)
+	}
+	return computeDaily(startedAt, now, loc)
+}
+
+// civilDay maps an instant to its local calendar date, represented at UTC
+// midnight so day arithmetic is not affected by DST.
+func civilDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// localMidnight converts a civil day back to the start of that day in loc.
+func localMidnight(day time.Time, loc *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+}
+
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+	return day.AddDate(0, 0, -offset)
+}
+
+// runs walks sorted, de-duplicated periods and returns the longest run of
+// consecutive periods (step apart) and the length of the run ending at end.
+func runs(periods []time.Time, step int, end time.Time) (longest int, endingAt int) {
+	run := 0
+	var prev time.Time
+	for i, p := range periods {
+		if i > 0 && prev.AddDate(0, 0, step).Equal(p) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		if p.Equal(end) {
+			endingAt = run
+		}
+		prev = p
+	}
+	return longest, endingAt
+}
+
+func sortedKeys(set map[time.Time]int) []time.Time {
+	keys := make([]time.Time, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
+	return keys
+}
+
+func computeDaily(startedAt []time.Time, now time.Time, loc *time.Location) Result {
+	today := civilDay(now, loc)
+	days := map[time.Time]int{}
+	for _, t := range startedAt {
+		if t.After(now) {
+			continue
+		}
+		days[civilDay(t, loc)]++
+	}
+	if len(days) == 0 {
+		return Result{}
+	}
+
+	sorted := sortedKeys(days)
+	last := sorted[len(sorted)-1]
+	longest, current := runs(sorted, 1, last)
+
+	// The streak survives until the end of the day after the last workout
+	if today.Sub(last) > 24*time.Hour {
+		return Result{Longest: longest}
+	}
+	expiresAt := localMidnight(last.AddDate(0, 0, 2), loc)
+	return Result{Current: current, Longest: longest, ExpiresAt: &expiresAt}
+}
+
+func computeWeekly(startedAt []time.Time, now time.Time, loc *time.Location, perWeek int) Result {
+	if perWeek < 1 {
+		perWeek = 1
+	}
+	thisWeek := weekStart(civilDay(now, loc))
+	counts := map[time.Time]int{}
+	for _, t := range startedAt {
+		if t.After(now) {
+			continue
+		}
+		counts[weekStart(civilDay(t, loc))]++
+	}
+
+	qualifying := map[time.Time]int{}
+	for week, n := range counts {
+		if n >= perWeek {
+			qualifying[week] = n
+		}
+	}
+	if len(qualifying) == 0 {
+		return Result{}
+	}
+
+	sorted := sortedKeys(qualifying)
+	last := sorted[len(sorted)-1]
+	longest, current := runs(sorted, 7, last)
+
+	// The week in progress cannot break a streak; anything older than last week does
+	if thisWeek.Sub(last) > 7*24*time.Hour {
+		return Result{Longest: longest}
+	}
+	expiresAt := localMidnight(last.AddDate(0, 0, 14), loc)
+	return Result{Current: current, Longest: longest, ExpiresAt: &expiresAt}
+}
@@ -0,0 +1,97 @@
+package streak
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeDaily(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, loc)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 9, 0, 0, 0, loc) }
+
+	tests := []struct {
+		name    string
+		workout []time.Time
+		current int
+		longest int
+	}{
+		{"no workouts", nil, 0, 0},
+		{"today only", []time.Time{day(10)}, 1, 1},
+		{"yesterday keeps streak", []time.Time{day(8), day(9)}, 2, 2},
+		{"gap breaks streak", []time.Time{day(5), day(6), day(7)}, 0, 3},
+		{"out of order and duplicates", []time.Time{day(10), day(8), day(9), day(9), day(2)}, 3, 3},
+		{"future workouts ignored", []time.Time{day(10), day(11)}, 1, 1},
+	}
+	for _, tt := range tests {
+		res := Compute(tt.workout, now, loc, Rule{Kind: RuleDaily})
+		if res.Current != tt.current || res.Longest != tt.longest {
+			t.Errorf("%s: got current=%d longest=%d, want current=%d longest=%d",
+				tt.name, res.Current, res.Longest, tt.current, tt.longest)
+		}
+		if (res.Current > 0) != (res.ExpiresAt != nil) {
+			t.Errorf("%s: ExpiresAt should be set only when there is a current streak", tt.name)
+		}
+	}
+}
+
+func TestComputeDailyTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// 23:30 UTC on the 8th is the morning of the 9th in Tokyo
+	workouts := []time.Time{
+		time.Date(2026, 3, 8, 1, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 8, 23, 30, 0, 0, time.UTC),
+	}
+	now := time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC)
+
+	if res := Compute(workouts, now, time.UTC, Rule{Kind: RuleDaily}); res.Current != 1 {
+		t.Errorf("UTC: expected streak 1, got %d", res.Current)
+	}
+	res := Compute(workouts, now, tokyo, Rule{Kind: RuleDaily})
+	if res.Current != 2 {
+		t.Errorf("Tokyo: expected streak 2, got %d", res.Current)
+	}
+	want := time.Date(2026, 3, 11, 0, 0, 0, 0, tokyo)
+	if res.ExpiresAt == nil || !res.ExpiresAt.Equal(want) {
+		t.Errorf("Tokyo: expected expiry %v, got %v", want, res.ExpiresAt)
+	}
+}
+
+func TestComputeWeekly(t *testing.T) {
+	loc := time.UTC
+	// Wednesday
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, loc)
+	rule := Rule{Kind: RuleWeekly, PerWeek: 2}
+
+	workouts := []time.Time{
+		// Week of Feb 23: 2 workouts (qualifies)
+		time.Date(2026, 2, 23, 9, 0, 0, 0, loc),
+		time.Date(2026, 2, 25, 9, 0, 0, 0, loc),
+		// Week of Mar 2: 1 workout (does not qualify)
+		time.Date(2026, 3, 3, 9, 0, 0, 0, loc),
+		// Week of Mar 9: 3 workouts (qualifies)
+		time.Date(2026, 3, 9, 9, 0, 0, 0, loc),
+		time.Date(2026, 3, 11, 9, 0, 0, 0, loc),
+		time.Date(2026, 3, 13, 9, 0, 0, 0, loc),
+		// Current week: 1 workout so far, does not break the streak
+		time.Date(2026, 3, 16, 9, 0, 0, 0, loc),
+	}
+
+	res := Compute(workouts, now, loc, rule)
+	if res.Current != 1 || res.Longest != 1 {
+		t.Errorf("got current=%d longest=%d, want 1/1", res.Current, res.Longest)
+	}
+	want := time.Date(2026, 3, 23, 0, 0, 0, 0, loc)
+	if res.ExpiresAt == nil || !res.ExpiresAt.Equal(want) {
+		t.Errorf("expected expiry %v, got %v", want, res.ExpiresAt)
+	}
+
+	// Completing the current week extends the streak
+	workouts = append(workouts, time.Date(2026, 3, 17, 9, 0, 0, 0, loc))
+	if res := Compute(workouts, now, loc, rule); res.Current != 2 {
+		t.Errorf("expected current 2 after completing the week, got %d", res.Current)
+	}
+}
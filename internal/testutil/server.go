@@ -10,19 +10,39 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 	migrate "github.com/rubenv/sql-migrate"
 
+	"github.com/rotsu1/jimu-backend/internal/appstore"
+	"github.com/rotsu1/jimu-backend/internal/appstore/appstoretest"
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
 	"github.com/rotsu1/jimu-backend/internal/handlers"
 	"github.com/rotsu1/jimu-backend/internal/repository"
 	router "github.com/rotsu1/jimu-backend/internal/routers"
+	"github.com/rotsu1/jimu-backend/internal/storage"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
 )
 
 // TestJWTSecret is the static secret used for signing JWTs in tests.
 // Must match the value used by CreateTestToken.
 const TestJWTSecret = "test-secret-key-123"
 
+// TestGooglePlayRTDNToken authenticates Google Play notifications in tests.
+const TestGooglePlayRTDNToken = "test-rtdn-token"
+
+// TestAppStoreBundleID is the bundle ID the test server's App Store verifier
+// accepts; signed test transactions must carry it.
+const TestAppStoreBundleID = "com.jimu.test"
+
 // TestServer holds the wired-up router and database pool for integration tests.
 type TestServer struct {
 	Router *router.JimuRouter
 	DB     *pgxpool.Pool
+	// AppStore signs transactions the server accepts as coming from Apple
+	AppStore *appstoretest.Signer
+	// GooglePlay serves the purchases the server fetches from Google
+	GooglePlay *googleplay.FakeClient
+	// Blobs keeps uploads in a temporary directory; its presigned URLs are
+	// served by Router
+	Blobs *storage.LocalStore
 }
 
 // NewTestServer creates a fully-wired TestServer connected to the test database.
@@ -83,27 +103,60 @@ func NewTestServer(t *testing.T) *TestServer {
 	routineRepo := repository.NewRoutineRepository(pool)
 	routineExerciseRepo := repository.NewRoutineExerciseRepository(pool)
 	routineSetRepo := repository.NewRoutineSetRepository(pool)
+	personalRecordRepo := repository.NewPersonalRecordRepository(pool)
+	statsRepo := repository.NewStatsRepository(pool)
+	mentionRepo := repository.NewMentionRepository(pool)
+	hashtagRepo := repository.NewHashtagRepository(pool)
+	forYouRepo := repository.NewForYouRepository(pool)
+	notificationRepo := repository.NewNotificationRepository(pool)
+	uploadRepo := repository.NewUploadRepository(pool)
+	adminRepo := repository.NewAdminRepository(pool)
+	reportRepo := repository.NewReportRepository(pool)
 
 	// 6. Initialize all Handlers (mirroring cmd/api/main.go)
 	authHandler := handlers.NewAuthHandler(userRepo, userSessionRepo, &handlers.GoogleValidator{})
 	userSettingsHandler := handlers.NewUserSettingsHandler(userRepo)
 	userDeviceHandler := handlers.NewUserDeviceHandler(userDeviceRepo)
-	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionRepo)
+	appStoreSigner, err := appstoretest.NewSigner()
+	if err != nil {
+		t.Fatalf("Failed to create App Store signer: %v", err)
+	}
+	appStoreVerifier, err := appstore.NewVerifier(appStoreSigner.Roots(), TestAppStoreBundleID)
+	if err != nil {
+		t.Fatalf("Failed to create App Store verifier: %v", err)
+	}
+	entitlementService := entitlements.NewService(subscriptionRepo)
+	googlePlay := googleplay.NewFakeClient()
+	blobs := storage.NewLocalStore(t.TempDir(), "http://jimu.test", []byte("test-blob-secret"))
+	uploadService := uploads.NewService(blobs, "http://jimu.test")
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionRepo, appStoreVerifier, googlePlay, entitlementService)
 	followHandler := handlers.NewFollowHandler(followRepo)
 	blockedUserHandler := handlers.NewBlockedUserHandler(blockedUserRepo)
-	workoutHandler := handlers.NewWorkoutHandler(workoutRepo)
+	workoutHandler := handlers.NewWorkoutHandler(workoutRepo, uploadService)
 	workoutExerciseHandler := handlers.NewWorkoutExerciseHandler(workoutExerciseRepo)
 	workoutSetHandler := handlers.NewWorkoutSetHandler(workoutSetRepo)
-	workoutImageHandler := handlers.NewWorkoutImageHandler(workoutImageRepo)
+	workoutImageHandler := handlers.NewWorkoutImageHandler(workoutImageRepo, uploadRepo, uploadService)
 	workoutLikeHandler := handlers.NewWorkoutLikeHandler(workoutLikeRepo)
 	exerciseHandler := handlers.NewExerciseHandler(exerciseRepo)
 	muscleHandler := handlers.NewMuscleHandler(muscleRepo)
 	exerciseTargetMuscleHandler := handlers.NewExerciseTargetMuscleHandler(exerciseTargetMuscleRepo)
 	commentHandler := handlers.NewCommentHandler(commentRepo)
 	commentLikeHandler := handlers.NewCommentLikeHandler(commentLikeRepo)
-	routineHandler := handlers.NewRoutineHandler(routineRepo)
+	routineHandler := handlers.NewRoutineHandler(routineRepo, entitlementService)
 	routineExerciseHandler := handlers.NewRoutineExerciseHandler(routineExerciseRepo)
 	routineSetHandler := handlers.NewRoutineSetHandler(routineSetRepo)
+	personalRecordHandler := handlers.NewPersonalRecordHandler(personalRecordRepo)
+	statsHandler := handlers.NewStatsHandler(statsRepo)
+	mentionHandler := handlers.NewMentionHandler(mentionRepo)
+	hashtagHandler := handlers.NewHashtagHandler(hashtagRepo)
+	forYouHandler := handlers.NewForYouHandler(forYouRepo, uploadService)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+	appStoreWebhookHandler := handlers.NewAppStoreWebhookHandler(subscriptionRepo, appStoreVerifier, entitlementService)
+	googlePlayWebhookHandler := handlers.NewGooglePlayWebhookHandler(subscriptionRepo, googlePlay, entitlementService, TestGooglePlayRTDNToken)
+	uploadHandler := handlers.NewUploadHandler(uploadRepo, uploadService)
+	avatarHandler := handlers.NewAvatarHandler(userRepo, uploadRepo, uploadService)
+	adminHandler := handlers.NewAdminHandler(adminRepo)
+	reportHandler := handlers.NewReportHandler(reportRepo)
 
 	// 7. Create Router (mirroring cmd/api/main.go)
 	jimuRouter := &router.JimuRouter{
@@ -126,12 +179,31 @@ func NewTestServer(t *testing.T) *TestServer {
 		RoutineHandler:              routineHandler,
 		RoutineExerciseHandler:      routineExerciseHandler,
 		RoutineSetHandler:           routineSetHandler,
+		PersonalRecordHandler:       personalRecordHandler,
+		StatsHandler:                statsHandler,
+		MentionHandler:              mentionHandler,
+		HashtagHandler:              hashtagHandler,
+		ForYouHandler:               forYouHandler,
+		NotificationHandler:         notificationHandler,
+		AppStoreWebhookHandler:      appStoreWebhookHandler,
+		GooglePlayWebhookHandler:    googlePlayWebhookHandler,
+		UploadHandler:               uploadHandler,
+		AvatarHandler:               avatarHandler,
+		AdminHandler:                adminHandler,
+		ReportHandler:               reportHandler,
+		Blobs:                       blobs.Handler(),
+		Entitlements:                entitlementService,
+		Admins:                      adminRepo,
+		Accounts:                    userRepo,
 		JWTSecret:                   TestJWTSecret,
 	}
 
 	return &TestServer{
-		Router: jimuRouter,
-		DB:     pool,
+		Router:     jimuRouter,
+		DB:         pool,
+		AppStore:   appStoreSigner,
+		GooglePlay: googlePlay,
+		Blobs:      blobs,
 	}
 }
 
@@ -144,11 +216,26 @@ func cleanupTables(t *testing.T, db *pgxpool.Pool) {
 		public.routine_sets,
 		public.routine_exercises,
 		public.routines,
+		public.push_outbox,
+		public.uploads,
+		public.blob_deletions,
+		public.admin_audit_log,
+		public.reports,
+		public.subscription_events,
+		public.notifications,
+		public.feed_items,
+		public.for_you_impressions,
+		public.for_you_candidates,
+		public.comment_mentions,
+		public.comment_hashtags,
 		public.comment_likes,
 		public.comments,
 		public.exercise_target_muscles,
+		public.workout_mentions,
+		public.workout_hashtags,
 		public.workout_likes,
 		public.workout_images,
+		public.personal_records,
 		public.workout_sets,
 		public.workout_exercises,
 		public.workouts,
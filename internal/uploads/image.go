@@ -0,0 +1,226 @@
+package uploads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // registers the PNG decoder
+	"net/http"
+)
+
+var (
+	// ErrUnsupportedType means the content is not one of AllowedContentTypes,
+	// or is not what its declared type claims.
+	ErrUnsupportedType = errors.New("unsupported image type")
+	// ErrInvalidImage means the content could not be decoded or exceeds the
+	// dimension limits.
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+const (
+	// MaxDimension and MaxPixels bound decoded images, which take far more
+	// memory than their compressed size suggests.
+	MaxDimension = 8000
+	MaxPixels    = 25_000_000
+
+	jpegQuality = 85
+)
+
+// AllowedContentTypes are the image types that may be uploaded.
+var AllowedContentTypes = []string{"image/jpeg", "image/png"}
+
+// Decode checks that data is an image of the declared content type within
+// the dimension limits, and decodes it upright: a JPEG's EXIF orientation is
+// applied, since re-encoding drops the EXIF block that carried it.
+func Decode(data []byte, contentType string) (image.Image, error) {
+	if http.DetectContentType(data) != contentType {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 ||
+		cfg.Width > MaxDimension || cfg.Height > MaxDimension ||
+		cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds the size limits", ErrInvalidImage, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if contentType == "image/jpeg" {
+		img = orient(img, exifOrientation(data))
+	}
+	return img, nil
+}
+
+// EncodeJPEG encodes img as a JPEG. The output carries no metadata at all,
+// so location and camera details in the original never reach storage.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Fit scales img down so neither side exceeds maxSide, keeping its aspect
+// ratio. Images already small enough are only flattened.
+func Fit(img image.Image, maxSide int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			w, h = maxSide, max(1, h*maxSide/w)
+		} else {
+			w, h = max(1, w*maxSide/h), maxSide
+		}
+	}
+	return resize(img, b, w, h)
+}
+
+// Square crops the centered square out of img and scales it to side x side.
+func Square(img image.Image, side int) *image.RGBA {
+	b := img.Bounds()
+	s := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-s)/2
+	y0 := b.Min.Y + (b.Dy()-s)/2
+	return resize(img, image.Rect(x0, y0, x0+s, y0+s), side, side)
+}
+
+// resize scales the src rectangle of img to w x h by averaging the source
+// pixels each output pixel covers, and flattens transparency onto white
+// since JPEG has no alpha channel.
+func resize(img image.Image, src image.Rectangle, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	sw, sh := src.Dx(), src.Dy()
+	for dy := 0; dy < h; dy++ {
+		y0 := src.Min.Y + dy*sh/h
+		y1 := max(y0+1, src.Min.Y+(dy+1)*sh/h)
+		for dx := 0; dx < w; dx++ {
+			x0 := src.Min.X + dx*sw/w
+			x1 := max(x0+1, src.Min.X+(dx+1)*sw/w)
+
+			var r, g, bl, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := img.At(x, y).RGBA()
+					// Colors are alpha-premultiplied; add white for the
+					// transparent part
+					white := uint64(0xffff - pa)
+					r += uint64(pr) + white
+					g += uint64(pg) + white
+					bl += uint64(pb) + white
+					n++
+				}
+			}
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// orient turns img upright according to an EXIF orientation (1-8).
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-dx, dy
+			case 3: // rotated 180°
+				sx, sy = w-1-dx, h-1-dy
+			case 4: // mirrored vertically
+				sx, sy = dx, h-1-dy
+			case 5: // transposed
+				sx, sy = dy, dx
+			case 6: // rotated 90° clockwise to display
+				sx, sy = dy, h-1-dx
+			case 7: // transversed
+				sx, sy = w-1-dy, h-1-dx
+			case 8: // rotated 90° counter-clockwise to display
+				sx, sy = w-1-dy, dx
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// exifOrientation reads the Orientation tag from a JPEG's EXIF block,
+// returning 1 (upright) when there is none.
+func exifOrientation(data []byte) int {
+	const orientationTag = 0x0112
+
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // image data starts
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2:]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		i += 2 + size
+
+		if marker != 0xE1 || len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+			continue
+		}
+		tiff := segment[6:]
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 1
+		}
+		ifd := int(order.Uint32(tiff[4:]))
+		if ifd+2 > len(tiff) {
+			return 1
+		}
+		entries := int(order.Uint16(tiff[ifd:]))
+		for e := 0; e < entries; e++ {
+			off := ifd + 2 + e*12
+			if off+12 > len(tiff) {
+				return 1
+			}
+			if order.Uint16(tiff[off:]) == orientationTag {
+				return int(order.Uint16(tiff[off+8:]))
+			}
+		}
+		return 1
+	}
+	return 1
+}
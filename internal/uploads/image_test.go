@@ -0,0 +1,161 @@
+package uploads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testJPEG encodes a w x h image whose left half is red and right half blue.
+func testJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withEXIF inserts an APP1 block holding an orientation tag and a GPS IFD
+// pointer, as phone cameras write them.
+func withEXIF(data []byte, orientation uint16) []byte {
+	tiff := new(bytes.Buffer)
+	tiff.WriteString("II")
+	binary.Write(tiff, binary.LittleEndian, uint16(42))
+	binary.Write(tiff, binary.LittleEndian, uint32(8))
+	binary.Write(tiff, binary.LittleEndian, uint16(2))
+	// Orientation, SHORT, count 1
+	binary.Write(tiff, binary.LittleEndian, []uint16{0x0112, 3})
+	binary.Write(tiff, binary.LittleEndian, uint32(1))
+	binary.Write(tiff, binary.LittleEndian, []uint16{orientation, 0})
+	// GPSInfo IFD pointer, LONG, count 1
+	binary.Write(tiff, binary.LittleEndian, []uint16{0x8825, 4})
+	binary.Write(tiff, binary.LittleEndian, uint32(1))
+	binary.Write(tiff, binary.LittleEndian, uint32(38))
+	binary.Write(tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString("GPS 35.6812N 139.7671E")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestDecode_AppliesOrientationAndStripsEXIF(t *testing.T) {
+	data := withEXIF(testJPEG(t, 40, 20), 6)
+	if !bytes.Contains(data, []byte("35.6812N")) {
+		t.Fatal("test image is missing its location data")
+	}
+
+	img, err := Decode(data, "image/jpeg")
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+		t.Fatalf("Expected the image rotated to 20x40, got %dx%d", b.Dx(), b.Dy())
+	}
+	// Rotated clockwise, the red left half ends up on top
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < b {
+		t.Errorf("Expected red at the top, got r=%d b=%d", r, b)
+	}
+
+	out, err := EncodeJPEG(Fit(img, 320))
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if bytes.Contains(out, []byte("Exif")) || bytes.Contains(out, []byte("35.6812N")) {
+		t.Error("Expected the re-encoded image to carry no EXIF data")
+	}
+}
+
+func TestExifOrientation(t *testing.T) {
+	plain := testJPEG(t, 4, 4)
+	if got := exifOrientation(plain); got != 1 {
+		t.Errorf("Expected 1 without EXIF, got %d", got)
+	}
+	for _, o := range []uint16{3, 6, 8} {
+		if got := exifOrientation(withEXIF(plain, o)); got != int(o) {
+			t.Errorf("Expected %d, got %d", o, got)
+		}
+	}
+	if got := exifOrientation(withEXIF(plain, 6)[:30]); got != 1 {
+		t.Errorf("Expected 1 for a truncated file, got %d", got)
+	}
+}
+
+func TestDecode_Rejects(t *testing.T) {
+	jpg := testJPEG(t, 8, 8)
+
+	if _, err := Decode(jpg, "image/png"); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Expected ErrUnsupportedType for a mislabeled file, got %v", err)
+	}
+	if _, err := Decode([]byte("GIF89a..."), "image/jpeg"); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("Expected ErrUnsupportedType for a GIF, got %v", err)
+	}
+	if _, err := Decode(jpg[:len(jpg)/2], "image/jpeg"); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("Expected ErrInvalidImage for a truncated file, got %v", err)
+	}
+
+	// Only the header is read to reject oversized images
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, MaxDimension+1, 1)))
+	if _, err := Decode(buf.Bytes(), "image/png"); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("Expected ErrInvalidImage for an oversized image, got %v", err)
+	}
+}
+
+func TestFit(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1000, 500))
+	got := Fit(img, 320)
+	if b := got.Bounds(); b.Dx() != 320 || b.Dy() != 160 {
+		t.Errorf("Expected 320x160, got %dx%d", b.Dx(), b.Dy())
+	}
+	// Transparent pixels are flattened onto white
+	if c := got.RGBAAt(0, 0); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("Expected white, got %v", c)
+	}
+
+	small := Fit(image.NewRGBA(image.Rect(0, 0, 10, 30)), 320)
+	if b := small.Bounds(); b.Dx() != 10 || b.Dy() != 30 {
+		t.Errorf("Expected a small image to keep its size, got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestSquare(t *testing.T) {
+	// A wide image whose left and right quarters are black
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 100; x < 300; x++ {
+			img.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+
+	got := Square(img, 128)
+	if b := got.Bounds(); b.Dx() != 128 || b.Dy() != 128 {
+		t.Fatalf("Expected 128x128, got %dx%d", b.Dx(), b.Dy())
+	}
+	for _, x := range []int{0, 127} {
+		if c := got.RGBAAt(x, 64); c != (color.RGBA{255, 255, 255, 255}) {
+			t.Errorf("Expected only the white center to remain, got %v at x=%d", c, x)
+		}
+	}
+}
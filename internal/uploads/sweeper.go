@@ -0,0 +1,63 @@
+package uploads
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/storage"
+)
+
+// DeletionQueue holds stored objects that nothing references anymore.
+type DeletionQueue interface {
+	ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error)
+	DeleteBlobDeletions(ctx context.Context, ids []uuid.UUID) error
+}
+
+// Sweeper deletes queued objects from a BlobStore. An object that fails to
+// delete stays queued and is retried on the next run.
+type Sweeper struct {
+	Queue     DeletionQueue
+	Store     storage.BlobStore
+	BatchSize int
+}
+
+func NewSweeper(queue DeletionQueue, store storage.BlobStore) *Sweeper {
+	return &Sweeper{Queue: queue, Store: store, BatchSize: 100}
+}
+
+// Run deletes everything that is currently queued.
+func (s *Sweeper) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deletions, err := s.Queue.ListBlobDeletions(ctx, s.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		var deleted []uuid.UUID
+		var failed error
+		for _, d := range deletions {
+			if err := s.Store.Delete(ctx, d.ObjectKey); err != nil {
+				failed = fmt.Errorf("failed to delete blob %s: %w", d.ObjectKey, err)
+				continue
+			}
+			deleted = append(deleted, d.ID)
+		}
+		if len(deleted) > 0 {
+			if err := s.Queue.DeleteBlobDeletions(ctx, deleted); err != nil {
+				return err
+			}
+		}
+
+		// The failed objects are still at the head of the queue, so another
+		// pass now would only list them again
+		if failed != nil {
+			return failed
+		}
+		if len(deletions) < s.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
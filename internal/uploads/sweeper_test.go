@@ -0,0 +1,82 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/storage"
+)
+
+type mockDeletionQueue struct {
+	deletions []*models.BlobDeletion
+}
+
+func (m *mockDeletionQueue) ListBlobDeletions(ctx context.Context, limit int) ([]*models.BlobDeletion, error) {
+	return m.deletions[:min(limit, len(m.deletions))], nil
+}
+
+func (m *mockDeletionQueue) DeleteBlobDeletions(ctx context.Context, ids []uuid.UUID) error {
+	var kept []*models.BlobDeletion
+	for _, d := range m.deletions {
+		if !slices.Contains(ids, d.ID) {
+			kept = append(kept, d)
+		}
+	}
+	m.deletions = kept
+	return nil
+}
+
+// failingStore fails to delete one key
+type failingStore struct {
+	*storage.LocalStore
+	failKey string
+}
+
+func (s *failingStore) Delete(ctx context.Context, key string) error {
+	if key == s.failKey {
+		return errors.New("unavailable")
+	}
+	return s.LocalStore.Delete(ctx, key)
+}
+
+func TestSweeper_Run(t *testing.T) {
+	local := storage.NewLocalStore(t.TempDir(), "http://jimu.test", []byte("secret"))
+	ctx := context.Background()
+
+	queue := &mockDeletionQueue{}
+	for _, key := range []string{"avatars/a/1.jpg", "avatars/a/2.jpg", "avatars/a/3.jpg"} {
+		local.Put(ctx, key, "image/jpeg", []byte("x"))
+		queue.deletions = append(queue.deletions, &models.BlobDeletion{ID: uuid.New(), ObjectKey: key})
+	}
+	// Already gone objects are dequeued too
+	queue.deletions = append(queue.deletions, &models.BlobDeletion{ID: uuid.New(), ObjectKey: "avatars/a/missing.jpg"})
+
+	s := NewSweeper(queue, &failingStore{LocalStore: local, failKey: "avatars/a/2.jpg"})
+	s.BatchSize = 2
+
+	if err := s.Run(ctx); err == nil {
+		t.Fatal("Expected the failed deletion to be reported")
+	}
+	if len(queue.deletions) != 3 || queue.deletions[0].ObjectKey != "avatars/a/2.jpg" {
+		t.Fatalf("Expected only the first object to be dequeued, got %d left", len(queue.deletions))
+	}
+	if _, err := local.Stat(ctx, "avatars/a/1.jpg"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected avatars/a/1.jpg to be deleted, got %v", err)
+	}
+
+	// Once the store recovers, the rest of the queue drains
+	s.Store = local
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Failed to sweep: %v", err)
+	}
+	if len(queue.deletions) != 0 {
+		t.Errorf("Expected an empty queue, got %d left", len(queue.deletions))
+	}
+	if _, err := local.Stat(ctx, "avatars/a/3.jpg"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected avatars/a/3.jpg to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/storage"
+)
+
+var (
+	// ErrTooLarge means an upload exceeds MaxUploadBytes.
+	ErrTooLarge = errors.New("upload is too large")
+	// ErrNotUploaded means nothing was uploaded for an upload, or not what
+	// was declared when it was created.
+	ErrNotUploaded = errors.New("upload has not been completed")
+)
+
+const (
+	MaxUploadBytes = 10 << 20
+
+	// UploadURLTTL is how long a presigned upload URL accepts the file.
+	UploadURLTTL = 15 * time.Minute
+	// CommitWindow is how long an upload can be committed after it was
+	// created, which leaves time to finish a transfer started just before
+	// the URL expired.
+	CommitWindow = time.Hour
+	// ReadURLTTL is how long the signed image URLs in responses stay valid.
+	ReadURLTTL = time.Hour
+
+	// Processed images are stored as JPEGs no larger than these sides.
+	FullSize      = 2048
+	ThumbnailSize = 320
+)
+
+// AvatarSizes are the square sizes every avatar is stored in, largest first.
+var AvatarSizes = []int{512, 128}
+
+// CheckUpload validates the type and size a client declares for an upload.
+func CheckUpload(contentType string, size int64) error {
+	if !slices.Contains(AllowedContentTypes, contentType) {
+		return ErrUnsupportedType
+	}
+	if size > MaxUploadBytes {
+		return ErrTooLarge
+	}
+	if size <= 0 {
+		return fmt.Errorf("%w: size must be positive", ErrInvalidImage)
+	}
+	return nil
+}
+
+// NewUploadKey returns a fresh key for a user's raw upload. Raw uploads are
+// only read back for processing and never served.
+func NewUploadKey(userID uuid.UUID) string {
+	return fmt.Sprintf("uploads/%s/%s", userID, uuid.New())
+}
+
+// AvatarKey is where one size of an avatar is stored.
+func AvatarKey(userID, avatarID uuid.UUID, size int) string {
+	return fmt.Sprintf("avatars/%s/%s/%d.jpg", userID, avatarID, size)
+}
+
+// WorkoutImagePrefix is where a workout's processed images are stored.
+func WorkoutImagePrefix(workoutID uuid.UUID) string {
+	return fmt.Sprintf("images/workouts/%s/", workoutID)
+}
+
+// IsWorkoutImageKey reports whether key was stored for the workout. Paths
+// clients set before uploads were processed may name any object.
+func IsWorkoutImageKey(workoutID uuid.UUID, key string) bool {
+	return strings.HasPrefix(key, WorkoutImagePrefix(workoutID)) && path.Clean(key) == key
+}
+
+// ProcessedImage is an upload turned into servable images.
+type ProcessedImage struct {
+	Path          string
+	ThumbnailPath string
+}
+
+// Avatar is an upload turned into an avatar: the stable URL profiles point
+// at, and the objects stored for it, one per size.
+type Avatar struct {
+	URL  string
+	Keys []string
+}
+
+// Service moves uploaded images through a BlobStore: clients upload to
+// presigned URLs, and committed uploads are validated and re-encoded before
+// anything is served from them.
+type Service struct {
+	Store storage.BlobStore
+	// PublicURL is the API's own base URL, which avatar URLs point at
+	PublicURL string
+}
+
+func NewService(store storage.BlobStore, publicURL string) *Service {
+	return &Service{Store: store, PublicURL: strings.TrimSuffix(publicURL, "/")}
+}
+
+// PresignUpload returns the URL the client uploads the file to. It only
+// accepts the declared content type and size.
+func (s *Service) PresignUpload(ctx context.Context, upload *models.Upload) (string, error) {
+	return s.Store.PresignPut(ctx, upload.ObjectKey, upload.ContentType, upload.SizeBytes, UploadURLTTL)
+}
+
+// SignURL returns a signed URL for reading a stored image.
+func (s *Service) SignURL(ctx context.Context, key string) (string, error) {
+	return s.Store.PresignGet(ctx, key, ReadURLTTL)
+}
+
+// ProcessWorkoutImage verifies an upload and stores a full-size image and a
+// thumbnail for the workout. Both are re-encoded, which strips EXIF data
+// such as GPS coordinates. Paths are unique per call, so a failed commit can
+// discard its own images without touching a concurrent one's.
+func (s *Service) ProcessWorkoutImage(ctx context.Context, upload *models.Upload, workoutID uuid.UUID) (*ProcessedImage, error) {
+	img, err := s.load(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	base := WorkoutImagePrefix(workoutID) + uuid.New().String()
+	processed := &ProcessedImage{Path: base + ".jpg", ThumbnailPath: base + "_thumb.jpg"}
+
+	full, err := EncodeJPEG(Fit(img, FullSize))
+	if err != nil {
+		return nil, err
+	}
+	thumb, err := EncodeJPEG(Fit(img, ThumbnailSize))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Store.Put(ctx, processed.Path, "image/jpeg", full); err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	if err := s.Store.Put(ctx, processed.ThumbnailPath, "image/jpeg", thumb); err != nil {
+		s.Delete(ctx, processed.Path)
+		return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+	return processed, nil
+}
+
+// ProcessAvatar verifies an upload and stores its centered square in each of
+// AvatarSizes. The avatar URL is served by the API and redirects to a signed
+// URL, so it stays valid for as long as the avatar is set.
+func (s *Service) ProcessAvatar(ctx context.Context, upload *models.Upload) (*Avatar, error) {
+	img, err := s.load(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	avatarID := uuid.New()
+	avatar := &Avatar{URL: fmt.Sprintf("%s/avatars/%s/%s", s.PublicURL, upload.UserID, avatarID)}
+	for _, size := range AvatarSizes {
+		data, err := EncodeJPEG(Square(img, size))
+		if err != nil {
+			s.Delete(ctx, avatar.Keys...)
+			return nil, err
+		}
+		key := AvatarKey(upload.UserID, avatarID, size)
+		if err := s.Store.Put(ctx, key, "image/jpeg", data); err != nil {
+			s.Delete(ctx, avatar.Keys...)
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+		avatar.Keys = append(avatar.Keys, key)
+	}
+	return avatar, nil
+}
+
+// load reads and decodes an upload, checking it is what the client declared.
+func (s *Service) load(ctx context.Context, upload *models.Upload) (image.Image, error) {
+	info, err := s.Store.Stat(ctx, upload.ObjectKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotUploaded
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat upload: %w", err)
+	}
+	if info.Size != upload.SizeBytes {
+		return nil, ErrNotUploaded
+	}
+
+	rc, err := s.Store.Get(ctx, upload.ObjectKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrNotUploaded
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MaxUploadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if len(data) > MaxUploadBytes {
+		return nil, ErrTooLarge
+	}
+	return Decode(data, upload.ContentType)
+}
+
+// Delete removes stored objects, logging failures; leftovers are only
+// wasted space.
+func (s *Service) Delete(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.Store.Delete(ctx, key); err != nil {
+			log.Printf("Delete blob %s error: %v", key, err)
+		}
+	}
+}
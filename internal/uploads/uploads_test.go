@@ -0,0 +1,122 @@
+package uploads
+
+import (
+	"context"
+	"errors"
+	"image/jpeg"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/storage"
+)
+
+func TestCheckUpload(t *testing.T) {
+	tests := []struct {
+		contentType string
+		size        int64
+		want        error
+	}{
+		{"image/jpeg", 1024, nil},
+		{"image/png", MaxUploadBytes, nil},
+		{"image/gif", 1024, ErrUnsupportedType},
+		{"image/jpeg", MaxUploadBytes + 1, ErrTooLarge},
+		{"image/jpeg", 0, ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		if err := CheckUpload(tt.contentType, tt.size); !errors.Is(err, tt.want) {
+			t.Errorf("CheckUpload(%q, %d) = %v, want %v", tt.contentType, tt.size, err, tt.want)
+		}
+	}
+}
+
+func TestProcessWorkoutImage(t *testing.T) {
+	store := storage.NewLocalStore(t.TempDir(), "http://jimu.test", []byte("secret"))
+	s := NewService(store, "http://jimu.test")
+	ctx := context.Background()
+
+	data := withEXIF(testJPEG(t, 3000, 1500), 1)
+	upload := &models.Upload{ObjectKey: NewUploadKey(uuid.New()), ContentType: "image/jpeg", SizeBytes: int64(len(data))}
+
+	workoutID := uuid.New()
+	if _, err := s.ProcessWorkoutImage(ctx, upload, workoutID); !errors.Is(err, ErrNotUploaded) {
+		t.Fatalf("Expected ErrNotUploaded before the upload, got %v", err)
+	}
+
+	store.Put(ctx, upload.ObjectKey, "image/jpeg", data)
+	processed, err := s.ProcessWorkoutImage(ctx, upload, workoutID)
+	if err != nil {
+		t.Fatalf("Failed to process: %v", err)
+	}
+	if !strings.HasPrefix(processed.Path, "images/workouts/"+workoutID.String()+"/") {
+		t.Errorf("Unexpected path %q", processed.Path)
+	}
+
+	for key, side := range map[string]int{processed.Path: FullSize, processed.ThumbnailPath: ThumbnailSize} {
+		rc, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Expected %s to be stored: %v", key, err)
+		}
+		cfg, err := jpeg.DecodeConfig(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Expected %s to be a JPEG: %v", key, err)
+		}
+		if cfg.Width != side || cfg.Height != side/2 {
+			t.Errorf("Expected %s to be %dx%d, got %dx%d", key, side, side/2, cfg.Width, cfg.Height)
+		}
+	}
+
+	// A file other than the declared one is not accepted
+	upload.SizeBytes++
+	if _, err := s.ProcessWorkoutImage(ctx, upload, workoutID); !errors.Is(err, ErrNotUploaded) {
+		t.Errorf("Expected ErrNotUploaded for a size mismatch, got %v", err)
+	}
+}
+
+func TestProcessAvatar(t *testing.T) {
+	store := storage.NewLocalStore(t.TempDir(), "http://jimu.test", []byte("secret"))
+	s := NewService(store, "https://api.jimu.test/")
+	ctx := context.Background()
+
+	userID := uuid.New()
+	data := testJPEG(t, 900, 600)
+	upload := &models.Upload{UserID: userID, ObjectKey: NewUploadKey(userID), ContentType: "image/jpeg", SizeBytes: int64(len(data))}
+	store.Put(ctx, upload.ObjectKey, "image/jpeg", data)
+
+	avatar, err := s.ProcessAvatar(ctx, upload)
+	if err != nil {
+		t.Fatalf("Failed to process: %v", err)
+	}
+	prefix := "https://api.jimu.test/avatars/" + userID.String() + "/"
+	if !strings.HasPrefix(avatar.URL, prefix) {
+		t.Fatalf("Expected a URL under %s, got %q", prefix, avatar.URL)
+	}
+	avatarID, err := uuid.Parse(strings.TrimPrefix(avatar.URL, prefix))
+	if err != nil {
+		t.Fatalf("Expected the URL to end in the avatar ID: %v", err)
+	}
+	if len(avatar.Keys) != len(AvatarSizes) {
+		t.Fatalf("Expected %d keys, got %v", len(AvatarSizes), avatar.Keys)
+	}
+
+	for i, size := range AvatarSizes {
+		key := AvatarKey(userID, avatarID, size)
+		if avatar.Keys[i] != key {
+			t.Errorf("Expected key %s, got %s", key, avatar.Keys[i])
+		}
+		rc, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Expected %s to be stored: %v", key, err)
+		}
+		cfg, err := jpeg.DecodeConfig(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Expected %s to be a JPEG: %v", key, err)
+		}
+		if cfg.Width != size || cfg.Height != size {
+			t.Errorf("Expected %s to be %dx%d, got %dx%d", key, size, size, cfg.Width, cfg.Height)
+		}
+	}
+}
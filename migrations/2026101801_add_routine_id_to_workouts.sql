@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE public.workouts
+    ADD COLUMN IF NOT EXISTS routine_id uuid REFERENCES public.routines(id) ON DELETE SET NULL;
+
+-- Index for "which workouts were started from this routine" lookups
+CREATE INDEX IF NOT EXISTS idx_workouts_routine_id ON public.workouts(routine_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS public.idx_workouts_routine_id;
+ALTER TABLE public.workouts DROP COLUMN IF EXISTS routine_id;
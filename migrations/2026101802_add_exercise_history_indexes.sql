@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Supports "my recent sessions of exercise X": filter by exercise, join to the parent workout
+CREATE INDEX IF NOT EXISTS idx_workout_exercises_exercise_id_workout_id ON public.workout_exercises(exercise_id, workout_id);
+
+-- Supports ordering a user's workouts by recency
+CREATE INDEX IF NOT EXISTS idx_workouts_user_id_started_at ON public.workouts(user_id, started_at DESC);
+
+-- Supports reading the sets of a workout exercise in order
+CREATE INDEX IF NOT EXISTS idx_workout_sets_workout_exercise_id_order_index ON public.workout_sets(workout_exercise_id, order_index);
+
+-- +migrate Down
+DROP INDEX IF EXISTS public.idx_workout_sets_workout_exercise_id_order_index;
+DROP INDEX IF EXISTS public.idx_workouts_user_id_started_at;
+DROP INDEX IF EXISTS public.idx_workout_exercises_exercise_id_workout_id;
@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Each row is a PR event: a workout session in which the user beat their
+-- previous best for an exercise. The current best for a record type is the
+-- highest-valued event. Rows are rebuilt per (user, exercise) by the
+-- application whenever that exercise's sets change.
+CREATE TABLE IF NOT EXISTS public.personal_records (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    exercise_id uuid NOT NULL REFERENCES public.exercises(id) ON DELETE CASCADE,
+    workout_id uuid NOT NULL REFERENCES public.workouts(id) ON DELETE CASCADE,
+    workout_set_id uuid REFERENCES public.workout_sets(id) ON DELETE CASCADE, -- NULL for session volume
+    record_type text NOT NULL CHECK (record_type IN ('max_weight', 'estimated_1rm', 'max_reps', 'max_volume')),
+    weight numeric,
+    reps integer,
+    value numeric NOT NULL,
+    achieved_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_id_exercise_id ON public.personal_records(user_id, exercise_id, record_type);
+CREATE INDEX IF NOT EXISTS idx_personal_records_workout_set_id ON public.personal_records(workout_set_id);
+CREATE INDEX IF NOT EXISTS idx_personal_records_workout_id ON public.personal_records(workout_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS public.personal_records;
@@ -0,0 +1,98 @@
+-- +migrate Up
+-- Streaks are now computed by the application in the user's timezone
+ALTER TABLE public.user_settings
+    ADD COLUMN IF NOT EXISTS timezone text NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS streak_rule text NOT NULL DEFAULT 'daily' CHECK (streak_rule IN ('daily', 'weekly')),
+    ADD COLUMN IF NOT EXISTS streak_weekly_target integer NOT NULL DEFAULT 1 CHECK (streak_weekly_target BETWEEN 1 AND 7);
+
+ALTER TABLE public.profiles
+    ADD COLUMN IF NOT EXISTS longest_streak integer DEFAULT 0,
+    -- current_streak reads as 0 once this instant has passed
+    ADD COLUMN IF NOT EXISTS streak_expires_at TIMESTAMPTZ;
+
+UPDATE public.profiles SET longest_streak = current_streak WHERE current_streak > 0;
+
+-- +migrate StatementBegin
+-- Totals stay in the trigger; streaks and last_worked_out_at are maintained by the application
+CREATE OR REPLACE FUNCTION handle_profile_stats_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE public.profiles
+        SET 
+            total_workouts = total_workouts + 1,
+            total_weight = total_weight + COALESCE(NEW.total_weight, 0)
+        WHERE id = NEW.user_id;
+
+    ELSIF (TG_OP = 'UPDATE') THEN
+        -- Only update profile if the weight actually changed
+        IF NEW.total_weight <> OLD.total_weight THEN
+            UPDATE public.profiles
+            SET total_weight = total_weight - OLD.total_weight + NEW.total_weight
+            WHERE id = NEW.user_id;
+        END IF;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        UPDATE public.profiles
+        SET 
+            total_workouts = total_workouts - 1,
+            total_weight = total_weight - COALESCE(OLD.total_weight, 0)
+        WHERE id = OLD.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION handle_profile_stats_sync()
+RETURNS TRIGGER AS $$
+DECLARE
+    last_workout TIMESTAMPTZ;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        SELECT last_worked_out_at INTO last_workout 
+        FROM public.profiles 
+        WHERE id = NEW.user_id;
+
+        UPDATE public.profiles
+        SET 
+            total_workouts = total_workouts + 1,
+            total_weight = total_weight + COALESCE(NEW.total_weight, 0),
+            current_streak = CASE
+                WHEN last_workout IS NULL THEN 1
+                WHEN (NEW.started_at::date - last_workout::date) = 1 THEN current_streak + 1
+                WHEN (NEW.started_at::date - last_workout::date) = 0 THEN current_streak
+                ELSE 1
+            END,
+            last_worked_out_at = NEW.started_at
+        WHERE id = NEW.user_id;
+
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF NEW.total_weight <> OLD.total_weight THEN
+            UPDATE public.profiles
+            SET total_weight = total_weight - OLD.total_weight + NEW.total_weight
+            WHERE id = NEW.user_id;
+        END IF;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        UPDATE public.profiles
+        SET 
+            total_workouts = total_workouts - 1,
+            total_weight = total_weight - COALESCE(OLD.total_weight, 0)
+        WHERE id = OLD.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+ALTER TABLE public.profiles
+    DROP COLUMN IF EXISTS streak_expires_at,
+    DROP COLUMN IF EXISTS longest_streak;
+
+ALTER TABLE public.user_settings
+    DROP COLUMN IF EXISTS streak_weekly_target,
+    DROP COLUMN IF EXISTS streak_rule,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,42 @@
+-- +migrate Up
+-- The single visibility policy every read path goes through.
+
+-- +migrate StatementBegin
+-- is_blocked_between reports whether either user has blocked the other
+CREATE OR REPLACE FUNCTION public.is_blocked_between(a uuid, b uuid)
+RETURNS boolean AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM public.blocked_users bu
+        WHERE (bu.blocker_id = a AND bu.blocked_id = b)
+           OR (bu.blocker_id = b AND bu.blocked_id = a)
+    )
+$$ LANGUAGE sql STABLE;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+-- can_view_user reports whether viewer may see content owned by owner:
+-- their own content, or no block in either direction and either a public
+-- account or an accepted follow. Pending follows do not grant access.
+CREATE OR REPLACE FUNCTION public.can_view_user(viewer uuid, owner uuid)
+RETURNS boolean AS $$
+    SELECT viewer = owner OR (
+        NOT public.is_blocked_between(viewer, owner)
+        AND (
+            EXISTS (
+                SELECT 1 FROM public.profiles p
+                WHERE p.id = owner AND p.is_private_account = false
+            )
+            OR EXISTS (
+                SELECT 1 FROM public.follows f
+                WHERE f.follower_id = viewer
+                  AND f.following_id = owner
+                  AND f.status = 'accepted'
+            )
+        )
+    )
+$$ LANGUAGE sql STABLE;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP FUNCTION IF EXISTS public.can_view_user(uuid, uuid);
+DROP FUNCTION IF EXISTS public.is_blocked_between(uuid, uuid);
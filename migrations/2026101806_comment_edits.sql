@@ -0,0 +1,92 @@
+-- +migrate Up
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT now(),
+    -- Set when the author edits the content; NULL for unedited comments
+    ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ,
+    -- Soft-deleted comments keep their row so replies survive
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Previous versions of a comment, one row per edit
+CREATE TABLE IF NOT EXISTS public.comment_revisions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id uuid NOT NULL REFERENCES public.comments(id) ON DELETE CASCADE,
+    content text NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON public.comment_revisions(comment_id, created_at);
+
+CREATE TRIGGER update_comments_updated_at
+    BEFORE UPDATE ON public.comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate StatementBegin
+-- Soft-deleting a top-level comment removes it from the count; hard-deleting
+-- a comment that was already soft-deleted must not count it twice.
+CREATE OR REPLACE FUNCTION handle_workout_comment_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE public.workouts 
+        SET comments_count = comments_count + 1 
+        WHERE id = NEW.workout_id
+        AND NEW.parent_id IS NULL;
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF (OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL) THEN
+            UPDATE public.workouts 
+            SET comments_count = comments_count - 1 
+            WHERE id = NEW.workout_id
+            AND NEW.parent_id IS NULL;
+        END IF;
+    ELSIF (TG_OP = 'DELETE') THEN
+        UPDATE public.workouts 
+        SET comments_count = comments_count - 1 
+        WHERE id = OLD.workout_id
+        AND OLD.parent_id IS NULL
+        AND OLD.deleted_at IS NULL;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS tr_sync_workout_comments ON public.comments;
+CREATE TRIGGER tr_sync_workout_comments
+AFTER INSERT OR UPDATE OR DELETE ON public.comments
+FOR EACH ROW EXECUTE FUNCTION handle_workout_comment_sync();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_sync_workout_comments ON public.comments;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION handle_workout_comment_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        UPDATE public.workouts 
+        SET comments_count = comments_count + 1 
+        WHERE id = NEW.workout_id
+        AND NEW.parent_id IS NULL;
+    ELSIF (TG_OP = 'DELETE') THEN
+        UPDATE public.workouts 
+        SET comments_count = comments_count - 1 
+        WHERE id = OLD.workout_id
+        AND OLD.parent_id IS NULL;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_sync_workout_comments
+AFTER INSERT OR DELETE ON public.comments
+FOR EACH ROW EXECUTE FUNCTION handle_workout_comment_sync();
+
+DROP TRIGGER IF EXISTS update_comments_updated_at ON public.comments;
+DROP TABLE IF EXISTS public.comment_revisions;
+
+ALTER TABLE public.comments
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS edited_at,
+    DROP COLUMN IF EXISTS updated_at;
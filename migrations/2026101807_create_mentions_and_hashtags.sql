@@ -0,0 +1,47 @@
+-- +migrate Up
+-- @mentions and #hashtags extracted from workout captions and comment content.
+-- Rows are kept in sync by the application whenever the text is created or
+-- edited; a mention row doubles as the mentioned user's in-app notification.
+CREATE TABLE IF NOT EXISTS public.workout_mentions (
+    workout_id uuid REFERENCES public.workouts(id) ON DELETE CASCADE,
+    user_id uuid REFERENCES public.profiles(id) ON DELETE CASCADE, -- the mentioned user
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (workout_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.comment_mentions (
+    comment_id uuid REFERENCES public.comments(id) ON DELETE CASCADE,
+    user_id uuid REFERENCES public.profiles(id) ON DELETE CASCADE, -- the mentioned user
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- Tags are stored lowercased and without the leading '#'
+CREATE TABLE IF NOT EXISTS public.workout_hashtags (
+    workout_id uuid REFERENCES public.workouts(id) ON DELETE CASCADE,
+    tag text NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (workout_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS public.comment_hashtags (
+    comment_id uuid REFERENCES public.comments(id) ON DELETE CASCADE,
+    tag text NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (comment_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_mentions_user_id ON public.workout_mentions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_user_id ON public.comment_mentions(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_workout_hashtags_tag ON public.workout_hashtags(tag);
+CREATE INDEX IF NOT EXISTS idx_comment_hashtags_tag ON public.comment_hashtags(tag);
+
+-- Mentions are resolved case-insensitively
+CREATE INDEX IF NOT EXISTS idx_profiles_username_lower ON public.profiles(lower(username));
+
+-- +migrate Down
+DROP INDEX IF EXISTS public.idx_profiles_username_lower;
+DROP TABLE IF EXISTS public.comment_hashtags;
+DROP TABLE IF EXISTS public.workout_hashtags;
+DROP TABLE IF EXISTS public.comment_mentions;
+DROP TABLE IF EXISTS public.workout_mentions;
@@ -0,0 +1,31 @@
+-- +migrate Up
+-- Precomputed For-You candidate pool, rebuilt periodically by a background job
+-- from recent workouts. Visibility is not baked in; it is checked per viewer at
+-- read time so privacy and block changes apply immediately.
+CREATE TABLE IF NOT EXISTS public.for_you_candidates (
+    workout_id uuid PRIMARY KEY REFERENCES public.workouts(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    likes_count integer NOT NULL DEFAULT 0,
+    comments_count integer NOT NULL DEFAULT 0,
+    exercise_ids uuid[] NOT NULL DEFAULT '{}',
+    refreshed_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_for_you_candidates_user_id ON public.for_you_candidates(user_id);
+
+-- Workouts a user has already been shown in For-You, reported by the client.
+-- variant records the ranking experiment arm that served them.
+CREATE TABLE IF NOT EXISTS public.for_you_impressions (
+    user_id uuid REFERENCES public.profiles(id) ON DELETE CASCADE,
+    workout_id uuid REFERENCES public.workouts(id) ON DELETE CASCADE,
+    variant text,
+    seen_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (user_id, workout_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_for_you_impressions_seen_at ON public.for_you_impressions(seen_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS public.for_you_impressions;
+DROP TABLE IF EXISTS public.for_you_candidates;
@@ -0,0 +1,125 @@
+-- +migrate Up
+-- Materialized home feed: one row per workout per follower who should see it,
+-- written when the workout is created (fan-out on write). The author gets a
+-- row too, since the home feed includes your own workouts.
+CREATE TABLE IF NOT EXISTS public.feed_items (
+    user_id uuid REFERENCES public.profiles(id) ON DELETE CASCADE,
+    workout_id uuid REFERENCES public.workouts(id) ON DELETE CASCADE,
+    author_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    PRIMARY KEY (user_id, workout_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_items_user_started_at ON public.feed_items(user_id, started_at DESC, workout_id DESC);
+CREATE INDEX IF NOT EXISTS idx_feed_items_user_author ON public.feed_items(user_id, author_id);
+
+-- +migrate StatementBegin
+-- feed_fanout_threshold is the follower count at which an author's workouts
+-- stop being fanned out. Followers pull them at read time instead, so a post
+-- by a popular user does not write one row per follower.
+CREATE OR REPLACE FUNCTION public.feed_fanout_threshold()
+RETURNS integer AS $$
+    SELECT 5000
+$$ LANGUAGE sql IMMUTABLE;
+-- +migrate StatementEnd
+
+-- How many of an author's recent workouts land in a new follower's feed
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.feed_backfill_limit()
+RETURNS integer AS $$
+    SELECT 50
+$$ LANGUAGE sql IMMUTABLE;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_workout_feed_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+        VALUES (NEW.user_id, NEW.id, NEW.user_id, NEW.started_at)
+        ON CONFLICT DO NOTHING;
+
+        INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+        SELECT f.follower_id, NEW.id, NEW.user_id, NEW.started_at
+        FROM public.follows f
+        JOIN public.profiles p ON p.id = NEW.user_id
+        WHERE f.following_id = NEW.user_id
+          AND f.status = 'accepted'
+          AND p.followers_count < public.feed_fanout_threshold()
+        ON CONFLICT DO NOTHING;
+
+    -- Keep feed order in step with edits to the workout's start time
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF (NEW.started_at IS DISTINCT FROM OLD.started_at) THEN
+            UPDATE public.feed_items SET started_at = NEW.started_at WHERE workout_id = NEW.id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_sync_workout_feed
+    AFTER INSERT OR UPDATE OF started_at ON public.workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_workout_feed_sync();
+
+-- +migrate StatementBegin
+-- Backfill a new follower's feed when a follow becomes accepted, and clear it
+-- on unfollow. Blocks delete follows in both directions, so they land here too.
+CREATE OR REPLACE FUNCTION public.fn_on_follow_feed_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        IF (NEW.status = 'accepted' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM 'accepted')) THEN
+            INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+            SELECT NEW.follower_id, w.id, w.user_id, w.started_at
+            FROM public.workouts w
+            WHERE w.user_id = NEW.following_id
+              AND (SELECT p.followers_count FROM public.profiles p WHERE p.id = NEW.following_id) < public.feed_fanout_threshold()
+            ORDER BY w.started_at DESC
+            LIMIT public.feed_backfill_limit()
+            ON CONFLICT DO NOTHING;
+        END IF;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.feed_items
+        WHERE user_id = OLD.follower_id AND author_id = OLD.following_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_sync_follow_feed
+    AFTER INSERT OR UPDATE OR DELETE ON public.follows
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_follow_feed_sync();
+
+-- Materialize feeds for existing workouts and follows
+INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+SELECT w.user_id, w.id, w.user_id, w.started_at
+FROM public.workouts w
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+SELECT f.follower_id, w.id, w.user_id, w.started_at
+FROM public.follows f
+JOIN public.profiles p ON p.id = f.following_id
+JOIN public.workouts w ON w.user_id = f.following_id
+WHERE f.status = 'accepted'
+  AND p.followers_count < public.feed_fanout_threshold()
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_sync_follow_feed ON public.follows;
+DROP TRIGGER IF EXISTS tr_sync_workout_feed ON public.workouts;
+DROP FUNCTION IF EXISTS public.fn_on_follow_feed_sync;
+DROP FUNCTION IF EXISTS public.fn_on_workout_feed_sync;
+DROP FUNCTION IF EXISTS public.feed_backfill_limit;
+DROP FUNCTION IF EXISTS public.feed_fanout_threshold;
+DROP TABLE IF EXISTS public.feed_items;
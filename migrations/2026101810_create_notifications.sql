@@ -0,0 +1,239 @@
+-- +migrate Up
+-- In-app notifications, one row per event. Rows are written by triggers on the
+-- tables that generate them, so every write path notifies consistently, and
+-- are removed again when the event is undone (unlike, unfollow, deletion).
+--
+-- group_key ties together rows that are shown as one inbox entry, such as all
+-- likes on a workout ("Alex and 12 others liked your workout").
+CREATE TABLE IF NOT EXISTS public.notifications (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE, -- the recipient
+    actor_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    type text NOT NULL, -- 'follow', 'follow_request', 'workout_like', 'comment_like', 'comment', 'reply', 'mention'
+    workout_id uuid REFERENCES public.workouts(id) ON DELETE CASCADE,
+    comment_id uuid REFERENCES public.comments(id) ON DELETE CASCADE,
+    group_key text NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    read_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON public.notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_group ON public.notifications(user_id, group_key);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON public.notifications(user_id) WHERE read_at IS NULL;
+
+-- +migrate StatementBegin
+-- notify_user records a notification unless the actor is the recipient, a
+-- block exists between them, or the recipient turned that kind off in their
+-- settings. Mentions follow the comments setting.
+CREATE OR REPLACE FUNCTION public.notify_user(
+    recipient uuid, actor uuid, kind text, workout uuid, comment uuid, grp text
+)
+RETURNS void AS $$
+BEGIN
+    IF recipient IS NULL OR recipient = actor OR public.is_blocked_between(recipient, actor) THEN
+        RETURN;
+    END IF;
+
+    IF NOT COALESCE((
+        SELECT CASE
+            WHEN kind IN ('follow', 'follow_request') THEN s.notify_new_follower
+            WHEN kind IN ('workout_like', 'comment_like') THEN s.notify_likes
+            ELSE s.notify_comments
+        END
+        FROM public.user_settings s
+        WHERE s.user_id = recipient
+    ), true) THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO public.notifications (user_id, actor_id, type, workout_id, comment_id, group_key)
+    VALUES (recipient, actor, kind, workout, comment, grp);
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+-- New followers of the same day are grouped; requests stay separate since
+-- each one is acted on individually.
+CREATE OR REPLACE FUNCTION public.fn_on_follow_notify()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        IF (NEW.status = 'accepted') THEN
+            PERFORM public.notify_user(NEW.following_id, NEW.follower_id, 'follow', NULL, NULL,
+                'follow:' || to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD'));
+        ELSE
+            PERFORM public.notify_user(NEW.following_id, NEW.follower_id, 'follow_request', NULL, NULL,
+                'follow_request:' || NEW.follower_id);
+        END IF;
+
+    -- An accepted request has been dealt with
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF (OLD.status = 'pending' AND NEW.status = 'accepted') THEN
+            DELETE FROM public.notifications
+            WHERE user_id = NEW.following_id AND actor_id = NEW.follower_id AND type = 'follow_request';
+        END IF;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.notifications
+        WHERE user_id = OLD.following_id AND actor_id = OLD.follower_id
+          AND type IN ('follow', 'follow_request');
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_notify_follow
+    AFTER INSERT OR UPDATE OF status OR DELETE ON public.follows
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_follow_notify();
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_workout_like_notify()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        PERFORM public.notify_user(w.user_id, NEW.user_id, 'workout_like', NEW.workout_id, NULL,
+            'workout_like:' || NEW.workout_id)
+        FROM public.workouts w
+        WHERE w.id = NEW.workout_id;
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.notifications
+        WHERE actor_id = OLD.user_id AND workout_id = OLD.workout_id AND type = 'workout_like';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_notify_workout_like
+    AFTER INSERT OR DELETE ON public.workout_likes
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_workout_like_notify();
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_comment_like_notify()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        PERFORM public.notify_user(c.user_id, NEW.user_id, 'comment_like', c.workout_id, NEW.comment_id,
+            'comment_like:' || NEW.comment_id)
+        FROM public.comments c
+        WHERE c.id = NEW.comment_id;
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.notifications
+        WHERE actor_id = OLD.user_id AND comment_id = OLD.comment_id AND type = 'comment_like';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_notify_comment_like
+    AFTER INSERT OR DELETE ON public.comment_likes
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_comment_like_notify();
+
+-- +migrate StatementBegin
+-- Comments notify the workout owner, grouped per workout. Replies also notify
+-- the parent comment's author, grouped per parent. Soft-deleting a comment
+-- withdraws everything it caused.
+CREATE OR REPLACE FUNCTION public.fn_on_comment_notify()
+RETURNS TRIGGER AS $$
+DECLARE
+    parent_author uuid;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        IF (NEW.parent_id IS NOT NULL) THEN
+            SELECT c.user_id INTO parent_author FROM public.comments c WHERE c.id = NEW.parent_id;
+            PERFORM public.notify_user(parent_author, NEW.user_id, 'reply', NEW.workout_id, NEW.id,
+                'reply:' || NEW.parent_id);
+        END IF;
+
+        PERFORM public.notify_user(w.user_id, NEW.user_id, 'comment', NEW.workout_id, NEW.id,
+            'comment:' || NEW.workout_id)
+        FROM public.workouts w
+        WHERE w.id = NEW.workout_id
+          AND w.user_id IS DISTINCT FROM parent_author;
+
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF (OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL) THEN
+            DELETE FROM public.notifications WHERE comment_id = NEW.id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_notify_comment
+    AFTER INSERT OR UPDATE OF deleted_at ON public.comments
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_comment_notify();
+
+-- +migrate StatementBegin
+-- Mention rows are only inserted for newly mentioned users, so edits do not
+-- re-notify anyone.
+CREATE OR REPLACE FUNCTION public.fn_on_workout_mention_notify()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        PERFORM public.notify_user(NEW.user_id, w.user_id, 'mention', NEW.workout_id, NULL,
+            'mention:' || NEW.workout_id)
+        FROM public.workouts w
+        WHERE w.id = NEW.workout_id;
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.notifications
+        WHERE user_id = OLD.user_id AND workout_id = OLD.workout_id
+          AND comment_id IS NULL AND type = 'mention';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_notify_workout_mention
+    AFTER INSERT OR DELETE ON public.workout_mentions
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_workout_mention_notify();
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_comment_mention_notify()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        PERFORM public.notify_user(NEW.user_id, c.user_id, 'mention', c.workout_id, NEW.comment_id,
+            'mention:' || NEW.comment_id)
+        FROM public.comments c
+        WHERE c.id = NEW.comment_id;
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.notifications
+        WHERE user_id = OLD.user_id AND comment_id = OLD.comment_id AND type = 'mention';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_notify_comment_mention
+    AFTER INSERT OR DELETE ON public.comment_mentions
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_on_comment_mention_notify();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_notify_comment_mention ON public.comment_mentions;
+DROP TRIGGER IF EXISTS tr_notify_workout_mention ON public.workout_mentions;
+DROP TRIGGER IF EXISTS tr_notify_comment ON public.comments;
+DROP TRIGGER IF EXISTS tr_notify_comment_like ON public.comment_likes;
+DROP TRIGGER IF EXISTS tr_notify_workout_like ON public.workout_likes;
+DROP TRIGGER IF EXISTS tr_notify_follow ON public.follows;
+DROP FUNCTION IF EXISTS public.fn_on_comment_mention_notify;
+DROP FUNCTION IF EXISTS public.fn_on_workout_mention_notify;
+DROP FUNCTION IF EXISTS public.fn_on_comment_notify;
+DROP FUNCTION IF EXISTS public.fn_on_comment_like_notify;
+DROP FUNCTION IF EXISTS public.fn_on_workout_like_notify;
+DROP FUNCTION IF EXISTS public.fn_on_follow_notify;
+DROP FUNCTION IF EXISTS public.notify_user;
+DROP TABLE IF EXISTS public.notifications;
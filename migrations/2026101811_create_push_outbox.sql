@@ -0,0 +1,51 @@
+-- +migrate Up
+-- Quiet hours are local times in the user's timezone, such as '22:00' to
+-- '07:00'. Pushes due inside the window are held until it ends.
+ALTER TABLE public.user_settings
+    ADD COLUMN IF NOT EXISTS quiet_hours_start text CHECK (quiet_hours_start ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    ADD COLUMN IF NOT EXISTS quiet_hours_end text CHECK (quiet_hours_end ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$');
+
+-- Pending push deliveries, one per notification. The delivery worker claims
+-- due rows, sends them to every device of the recipient and either marks them
+-- sent or schedules a retry. Withdrawn notifications take their push with them.
+CREATE TABLE IF NOT EXISTS public.push_outbox (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    notification_id uuid NOT NULL UNIQUE REFERENCES public.notifications(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error text,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_outbox_due ON public.push_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_push_outbox_finished ON public.push_outbox(created_at) WHERE status <> 'pending';
+
+-- +migrate StatementBegin
+-- Only users with a registered device get a push queued
+CREATE OR REPLACE FUNCTION public.fn_enqueue_push()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO public.push_outbox (notification_id, user_id)
+    SELECT NEW.id, NEW.user_id
+    WHERE EXISTS (SELECT 1 FROM public.user_devices d WHERE d.user_id = NEW.user_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_enqueue_push
+    AFTER INSERT ON public.notifications
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_enqueue_push();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_enqueue_push ON public.notifications;
+DROP FUNCTION IF EXISTS public.fn_enqueue_push;
+DROP TABLE IF EXISTS public.push_outbox;
+
+ALTER TABLE public.user_settings
+    DROP COLUMN IF EXISTS quiet_hours_end,
+    DROP COLUMN IF EXISTS quiet_hours_start;
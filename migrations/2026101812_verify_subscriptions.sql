@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Subscriptions are derived from store transactions verified by the server.
+-- signed_at orders transactions so a replayed older one cannot roll a
+-- subscription back; verified_payload keeps the transaction as it was signed.
+ALTER TABLE public.subscriptions
+    ADD COLUMN IF NOT EXISTS latest_transaction_id text,
+    ADD COLUMN IF NOT EXISTS signed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS verified_payload jsonb;
+
+-- +migrate Down
+ALTER TABLE public.subscriptions
+    DROP COLUMN IF EXISTS verified_payload,
+    DROP COLUMN IF EXISTS signed_at,
+    DROP COLUMN IF EXISTS latest_transaction_id;
@@ -0,0 +1,39 @@
+-- +migrate Up
+-- History of store notifications about subscriptions, one row per
+-- notification. The unique notification_id makes redelivered notifications
+-- no-ops. user_id is the subscriber at the time and deliberately has no
+-- foreign key, so billing history survives account deletion.
+CREATE TABLE IF NOT EXISTS public.subscription_events (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    notification_id text NOT NULL UNIQUE,
+    notification_type text NOT NULL,
+    subtype text,
+    original_transaction_id text,
+    user_id uuid,
+    status text,
+    signed_at TIMESTAMPTZ NOT NULL,
+    payload jsonb NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_events_original_transaction_id ON public.subscription_events(original_transaction_id, signed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_subscription_events_user_id ON public.subscription_events(user_id, signed_at DESC);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_prevent_subscription_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'subscription_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_subscription_events_append_only
+    BEFORE UPDATE OR DELETE ON public.subscription_events
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_prevent_subscription_event_changes();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_subscription_events_append_only ON public.subscription_events;
+DROP FUNCTION IF EXISTS public.fn_prevent_subscription_event_changes;
+DROP TABLE IF EXISTS public.subscription_events;
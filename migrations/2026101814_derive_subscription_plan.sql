@@ -0,0 +1,28 @@
+-- +migrate Up
+-- A user's plan is derived from their verified subscription rather than
+-- stored on the profile, where users could write it themselves. The rules
+-- mirror entitlements.PlanFor: an active subscription entitles until an hour
+-- past expires_at (renewals can arrive late), a grace period for up to 16 days.
+ALTER TABLE public.profiles DROP COLUMN IF EXISTS subscription_plan;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.subscription_plan(p_user_id uuid)
+RETURNS text
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE WHEN EXISTS (
+        SELECT 1
+        FROM public.subscriptions s
+        WHERE s.user_id = p_user_id
+          AND (
+              (s.status = 'active' AND s.expires_at + interval '1 hour' > now())
+              OR (s.status = 'grace_period' AND s.expires_at + interval '16 days' > now())
+          )
+    ) THEN 'premium' ELSE 'free' END;
+$$;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP FUNCTION IF EXISTS public.subscription_plan(uuid);
+ALTER TABLE public.profiles ADD COLUMN IF NOT EXISTS subscription_plan text;
@@ -0,0 +1,29 @@
+-- +migrate Up
+-- Subscriptions can come from the App Store or Google Play. A purchase is
+-- identified by its store and original_transaction_id, which for Google Play
+-- holds the purchase token; latest_transaction_id holds the latest order ID.
+ALTER TABLE public.subscriptions
+    ADD COLUMN IF NOT EXISTS store text NOT NULL DEFAULT 'app_store'
+        CHECK (store IN ('app_store', 'google_play'));
+
+ALTER TABLE public.subscriptions DROP CONSTRAINT IF EXISTS subscriptions_original_transaction_id_key;
+DROP INDEX IF EXISTS public.idx_subscriptions_original_transaction_id;
+ALTER TABLE public.subscriptions
+    ADD CONSTRAINT subscriptions_store_original_transaction_id_key UNIQUE (store, original_transaction_id);
+
+-- Notification IDs are only unique within a store
+ALTER TABLE public.subscription_events
+    ADD COLUMN IF NOT EXISTS store text NOT NULL DEFAULT 'app_store';
+ALTER TABLE public.subscription_events DROP CONSTRAINT IF EXISTS subscription_events_notification_id_key;
+ALTER TABLE public.subscription_events
+    ADD CONSTRAINT subscription_events_store_notification_id_key UNIQUE (store, notification_id);
+
+-- +migrate Down
+ALTER TABLE public.subscription_events DROP CONSTRAINT IF EXISTS subscription_events_store_notification_id_key;
+ALTER TABLE public.subscription_events ADD CONSTRAINT subscription_events_notification_id_key UNIQUE (notification_id);
+ALTER TABLE public.subscription_events DROP COLUMN IF EXISTS store;
+
+ALTER TABLE public.subscriptions DROP CONSTRAINT IF EXISTS subscriptions_store_original_transaction_id_key;
+ALTER TABLE public.subscriptions ADD CONSTRAINT subscriptions_original_transaction_id_key UNIQUE (original_transaction_id);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_original_transaction_id ON public.subscriptions(original_transaction_id);
+ALTER TABLE public.subscriptions DROP COLUMN IF EXISTS store;
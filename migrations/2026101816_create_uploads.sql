@@ -0,0 +1,23 @@
+-- +migrate Up
+-- Files clients were allowed to put into blob storage through presigned
+-- URLs. An upload is committed once, when its file is turned into something
+-- servable; pending uploads past expires_at can no longer be committed.
+CREATE TABLE IF NOT EXISTS public.uploads (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    object_key text NOT NULL UNIQUE,
+    content_type text NOT NULL,
+    size_bytes bigint NOT NULL CHECK (size_bytes > 0),
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'committed')),
+    created_at TIMESTAMPTZ DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_uploads_user_id ON public.uploads(user_id);
+CREATE INDEX IF NOT EXISTS idx_uploads_pending_expires_at ON public.uploads(expires_at) WHERE status = 'pending';
+
+ALTER TABLE public.workout_images ADD COLUMN IF NOT EXISTS thumbnail_path text;
+
+-- +migrate Down
+ALTER TABLE public.workout_images DROP COLUMN IF EXISTS thumbnail_path;
+DROP TABLE IF EXISTS public.uploads;
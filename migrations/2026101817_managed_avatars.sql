@@ -0,0 +1,48 @@
+-- +migrate Up
+-- Avatars are now uploaded images served by the API. avatar_url points at
+-- the API's avatar route and avatar_keys lists the stored sizes. URLs
+-- clients set themselves could point anywhere, so they are dropped.
+ALTER TABLE public.profiles ADD COLUMN IF NOT EXISTS avatar_keys text[];
+UPDATE public.profiles SET avatar_url = NULL WHERE avatar_url IS NOT NULL;
+
+-- Stored objects nothing references anymore. Rows are queued in the same
+-- transaction that drops the reference, and the blob sweeper deletes the
+-- objects and then the rows.
+CREATE TABLE IF NOT EXISTS public.blob_deletions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    object_key text NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_blob_deletions_created_at ON public.blob_deletions(created_at);
+
+-- +migrate StatementBegin
+-- Queues a profile's previous avatar when it is replaced, removed, or the
+-- profile is deleted
+CREATE OR REPLACE FUNCTION public.fn_enqueue_avatar_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO public.blob_deletions (object_key)
+        SELECT unnest(OLD.avatar_keys);
+    ELSE
+        INSERT INTO public.blob_deletions (object_key)
+        SELECT k FROM unnest(OLD.avatar_keys) AS k
+        WHERE NOT k = ANY(COALESCE(NEW.avatar_keys, '{}'));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_enqueue_avatar_deletion
+    AFTER UPDATE OF avatar_keys OR DELETE ON public.profiles
+    FOR EACH ROW
+    WHEN (OLD.avatar_keys IS NOT NULL)
+    EXECUTE FUNCTION public.fn_enqueue_avatar_deletion();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_enqueue_avatar_deletion ON public.profiles;
+DROP FUNCTION IF EXISTS public.fn_enqueue_avatar_deletion;
+DROP TABLE IF EXISTS public.blob_deletions;
+ALTER TABLE public.profiles DROP COLUMN IF EXISTS avatar_keys;
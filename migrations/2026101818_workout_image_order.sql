@@ -0,0 +1,43 @@
+-- +migrate Up
+-- Images of a workout are shown in display_order, which is now unique per
+-- workout. Existing duplicates are renumbered by their current position.
+-- The constraint is checked per statement, so a reorder can swap positions
+-- in a single UPDATE.
+UPDATE public.workout_images wi
+SET display_order = ranked.position
+FROM (
+    SELECT id, (row_number() OVER (PARTITION BY workout_id ORDER BY display_order, created_at, id) - 1)::integer AS position
+    FROM public.workout_images
+) ranked
+WHERE wi.id = ranked.id
+AND wi.display_order <> ranked.position;
+
+ALTER TABLE public.workout_images
+    ADD CONSTRAINT workout_images_workout_id_display_order_key
+    UNIQUE (workout_id, display_order) DEFERRABLE INITIALLY IMMEDIATE;
+
+-- +migrate StatementBegin
+-- Queues a deleted image's files, whether the image or its whole workout was
+-- deleted. Paths outside the workout's own prefix were set by clients before
+-- uploads were processed and may name objects the image never owned, so they
+-- are left alone.
+CREATE OR REPLACE FUNCTION public.fn_enqueue_workout_image_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO public.blob_deletions (object_key)
+    SELECT k FROM unnest(ARRAY[OLD.storage_path, OLD.thumbnail_path]) AS k
+    WHERE k LIKE 'images/workouts/' || OLD.workout_id || '/%';
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_enqueue_workout_image_deletion
+    AFTER DELETE ON public.workout_images
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_enqueue_workout_image_deletion();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_enqueue_workout_image_deletion ON public.workout_images;
+DROP FUNCTION IF EXISTS public.fn_enqueue_workout_image_deletion;
+ALTER TABLE public.workout_images DROP CONSTRAINT IF EXISTS workout_images_workout_id_display_order_key;
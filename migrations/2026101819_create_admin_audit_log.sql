@@ -0,0 +1,43 @@
+-- +migrate Up
+-- A suspended user cannot sign in until suspended_until has passed.
+ALTER TABLE public.profiles ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMPTZ;
+ALTER TABLE public.profiles ADD COLUMN IF NOT EXISTS suspension_reason text;
+
+-- One row per action taken through the admin API, written in the same
+-- transaction as the action. admin_id and target_id are deliberately not
+-- foreign keys: the record must outlive the accounts and content it names.
+CREATE TABLE IF NOT EXISTS public.admin_audit_log (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    admin_id uuid NOT NULL,
+    action text NOT NULL,
+    target_type text NOT NULL,
+    target_id uuid NOT NULL,
+    details jsonb NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON public.admin_audit_log(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_admin_id ON public.admin_audit_log(admin_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON public.admin_audit_log(target_type, target_id, created_at DESC);
+
+-- +migrate StatementBegin
+-- The audit log is append-only
+CREATE OR REPLACE FUNCTION public.fn_reject_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'admin_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_reject_audit_log_change
+    BEFORE UPDATE OR DELETE ON public.admin_audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_reject_audit_log_change();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_reject_audit_log_change ON public.admin_audit_log;
+DROP FUNCTION IF EXISTS public.fn_reject_audit_log_change;
+DROP TABLE IF EXISTS public.admin_audit_log;
+ALTER TABLE public.profiles DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE public.profiles DROP COLUMN IF EXISTS suspended_until;
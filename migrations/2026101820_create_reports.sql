@@ -0,0 +1,59 @@
+-- +migrate Up
+-- Content hidden by a moderator stays in place, so it can be restored, but
+-- only its author still sees it.
+ALTER TABLE public.workouts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+ALTER TABLE public.comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+ALTER TABLE public.workout_images ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
+-- +migrate StatementBegin
+-- is_hidden_from reports whether content owned by owner and hidden at
+-- hidden_at is kept from viewer. Authors always see their own content.
+CREATE OR REPLACE FUNCTION public.is_hidden_from(viewer uuid, owner uuid, hidden_at timestamptz)
+RETURNS boolean AS $$
+    SELECT hidden_at IS NOT NULL AND viewer IS DISTINCT FROM owner
+$$ LANGUAGE sql IMMUTABLE;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+-- can_view_content is can_view_user for a single piece of content, which
+-- moderators may also have hidden.
+CREATE OR REPLACE FUNCTION public.can_view_content(viewer uuid, owner uuid, hidden_at timestamptz)
+RETURNS boolean AS $$
+    SELECT NOT public.is_hidden_from(viewer, owner, hidden_at)
+        AND public.can_view_user(viewer, owner)
+$$ LANGUAGE sql STABLE;
+-- +migrate StatementEnd
+
+-- A user's report of a comment, workout, image or profile. target_id is not
+-- a foreign key so the report outlives content removed in response to it;
+-- target_user_id is the reported content's author, or the reported user.
+CREATE TABLE IF NOT EXISTS public.reports (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    reporter_id uuid NOT NULL REFERENCES public.profiles(id) ON DELETE CASCADE,
+    target_type text NOT NULL CHECK (target_type IN ('comment', 'workout', 'workout_image', 'user')),
+    target_id uuid NOT NULL,
+    target_user_id uuid NOT NULL,
+    reason text NOT NULL CHECK (reason IN ('spam', 'harassment', 'hate_speech', 'nudity', 'violence', 'self_harm', 'other')),
+    note text,
+    status text NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+    resolution text,
+    resolved_by uuid,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((status = 'open') = (resolved_at IS NULL))
+);
+
+-- A user has at most one open report per target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_open_unique
+    ON public.reports(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_status_created_at ON public.reports(status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_reports_target ON public.reports(target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_reporter_created_at ON public.reports(reporter_id, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS public.reports;
+DROP FUNCTION IF EXISTS public.can_view_content(uuid, uuid, timestamptz);
+DROP FUNCTION IF EXISTS public.is_hidden_from(uuid, uuid, timestamptz);
+ALTER TABLE public.workout_images DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE public.comments DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE public.workouts DROP COLUMN IF EXISTS hidden_at;
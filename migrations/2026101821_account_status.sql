@@ -0,0 +1,84 @@
+-- +migrate Up
+-- A suspended account is disabled until suspended_until; a banned one until
+-- an admin lifts the ban. Neither can sign in, and their content is hidden.
+ALTER TABLE public.profiles ADD COLUMN IF NOT EXISTS account_status text NOT NULL DEFAULT 'active'
+    CHECK (account_status IN ('active', 'suspended', 'banned'));
+
+UPDATE public.profiles SET account_status = 'suspended' WHERE suspended_until > now();
+UPDATE public.profiles SET suspended_until = NULL, suspension_reason = NULL
+    WHERE account_status = 'active';
+
+-- A suspension always ends; a ban has no end date
+ALTER TABLE public.profiles ADD CONSTRAINT chk_profiles_suspension
+    CHECK ((account_status = 'suspended') = (suspended_until IS NOT NULL));
+
+CREATE INDEX IF NOT EXISTS idx_profiles_suspended_until
+    ON public.profiles(suspended_until) WHERE account_status = 'suspended';
+
+-- +migrate StatementBegin
+-- is_account_active reports whether uid may sign in and be seen. A
+-- suspension that has run out counts as active before it is swept.
+CREATE OR REPLACE FUNCTION public.is_account_active(uid uuid)
+RETURNS boolean AS $$
+    SELECT NOT EXISTS (
+        SELECT 1 FROM public.profiles p
+        WHERE p.id = uid
+          AND (
+              p.account_status = 'banned'
+              OR (p.account_status = 'suspended' AND p.suspended_until > now())
+          )
+    )
+$$ LANGUAGE sql STABLE;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+-- can_view_user now also requires the owner's account to be active, so a
+-- disabled user's content drops out of every read path and comes back
+-- when the account is restored.
+CREATE OR REPLACE FUNCTION public.can_view_user(viewer uuid, owner uuid)
+RETURNS boolean AS $$
+    SELECT viewer = owner OR (
+        NOT public.is_blocked_between(viewer, owner)
+        AND public.is_account_active(owner)
+        AND (
+            EXISTS (
+                SELECT 1 FROM public.profiles p
+                WHERE p.id = owner AND p.is_private_account = false
+            )
+            OR EXISTS (
+                SELECT 1 FROM public.follows f
+                WHERE f.follower_id = viewer
+                  AND f.following_id = owner
+                  AND f.status = 'accepted'
+            )
+        )
+    )
+$$ LANGUAGE sql STABLE;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.can_view_user(viewer uuid, owner uuid)
+RETURNS boolean AS $$
+    SELECT viewer = owner OR (
+        NOT public.is_blocked_between(viewer, owner)
+        AND (
+            EXISTS (
+                SELECT 1 FROM public.profiles p
+                WHERE p.id = owner AND p.is_private_account = false
+            )
+            OR EXISTS (
+                SELECT 1 FROM public.follows f
+                WHERE f.follower_id = viewer
+                  AND f.following_id = owner
+                  AND f.status = 'accepted'
+            )
+        )
+    )
+$$ LANGUAGE sql STABLE;
+-- +migrate StatementEnd
+
+DROP FUNCTION IF EXISTS public.is_account_active(uuid);
+DROP INDEX IF EXISTS public.idx_profiles_suspended_until;
+ALTER TABLE public.profiles DROP CONSTRAINT IF EXISTS chk_profiles_suspension;
+ALTER TABLE public.profiles DROP COLUMN IF EXISTS account_status;
@@ -0,0 +1,42 @@
+-- +migrate Up
+-- Only paid purchases of products on sale grant a plan. Sandbox and license
+-- tester purchases are free, and unknown product IDs grant nothing. The
+-- product list mirrors entitlements.ProductPlans.
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.subscription_plan(p_user_id uuid)
+RETURNS text
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE WHEN EXISTS (
+        SELECT 1
+        FROM public.subscriptions s
+        WHERE s.user_id = p_user_id
+          AND s.environment = 'production'
+          AND s.product_id IN ('premium_monthly', 'premium_yearly')
+          AND (
+              (s.status = 'active' AND s.expires_at + interval '1 hour' > now())
+              OR (s.status = 'grace_period' AND s.expires_at + interval '16 days' > now())
+          )
+    ) THEN 'premium' ELSE 'free' END;
+$$;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.subscription_plan(p_user_id uuid)
+RETURNS text
+LANGUAGE sql
+STABLE
+AS $$
+    SELECT CASE WHEN EXISTS (
+        SELECT 1
+        FROM public.subscriptions s
+        WHERE s.user_id = p_user_id
+          AND (
+              (s.status = 'active' AND s.expires_at + interval '1 hour' > now())
+              OR (s.status = 'grace_period' AND s.expires_at + interval '16 days' > now())
+          )
+    ) THEN 'premium' ELSE 'free' END;
+$$;
+-- +migrate StatementEnd
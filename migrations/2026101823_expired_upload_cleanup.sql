@@ -0,0 +1,23 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- Queues an upload's raw object when its row is deleted, whether the upload
+-- expired unused or the profile was deleted. Raw uploads keep the client's
+-- EXIF data, so none may outlive their row. Objects already removed after a
+-- commit are deleted again, which is harmless.
+CREATE OR REPLACE FUNCTION public.fn_enqueue_upload_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO public.blob_deletions (object_key) VALUES (OLD.object_key);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_enqueue_upload_deletion
+    AFTER DELETE ON public.uploads
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_enqueue_upload_deletion();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS tr_enqueue_upload_deletion ON public.uploads;
+DROP FUNCTION IF EXISTS public.fn_enqueue_upload_deletion;
@@ -0,0 +1,165 @@
+-- +migrate Up
+-- Whether a workout was fanned out is now decided once, when it is posted,
+-- and stored on the workout. Followers pull the workouts that were not, so
+-- an author crossing the fan-out threshold in either direction no longer
+-- drops the workouts posted on the other side of it from feeds.
+ALTER TABLE public.workouts ADD COLUMN IF NOT EXISTS fanned_out boolean NOT NULL DEFAULT true;
+
+-- Workouts of authors at the threshold were being pulled
+UPDATE public.workouts w
+SET fanned_out = false
+FROM public.profiles p
+WHERE p.id = w.user_id
+  AND p.followers_count >= public.feed_fanout_threshold();
+
+-- Workouts of authors below it were read from feed_items, which misses any
+-- posted while the author was above it
+INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+SELECT f.follower_id, w.id, w.user_id, w.started_at
+FROM public.follows f
+JOIN public.workouts w ON w.user_id = f.following_id
+WHERE f.status = 'accepted'
+  AND w.fanned_out
+ON CONFLICT DO NOTHING;
+
+-- Supports pulling followed authors' workouts that were not fanned out
+CREATE INDEX IF NOT EXISTS idx_workouts_pulled ON public.workouts(user_id, started_at DESC, id DESC) WHERE NOT fanned_out;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_set_workout_fanned_out()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- A missing author is left to the foreign key to reject
+    NEW.fanned_out := COALESCE((
+        SELECT p.followers_count < public.feed_fanout_threshold()
+        FROM public.profiles p
+        WHERE p.id = NEW.user_id
+    ), true);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER tr_set_workout_fanned_out
+    BEFORE INSERT ON public.workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION public.fn_set_workout_fanned_out();
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_workout_feed_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+        VALUES (NEW.user_id, NEW.id, NEW.user_id, NEW.started_at)
+        ON CONFLICT DO NOTHING;
+
+        IF (NEW.fanned_out) THEN
+            INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+            SELECT f.follower_id, NEW.id, NEW.user_id, NEW.started_at
+            FROM public.follows f
+            WHERE f.following_id = NEW.user_id
+              AND f.status = 'accepted'
+            ON CONFLICT DO NOTHING;
+        END IF;
+
+    -- Keep feed order in step with edits to the workout's start time
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF (NEW.started_at IS DISTINCT FROM OLD.started_at) THEN
+            UPDATE public.feed_items SET started_at = NEW.started_at WHERE workout_id = NEW.id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+-- A new follower gets the author's recent fanned-out workouts; the rest are
+-- pulled at read time.
+CREATE OR REPLACE FUNCTION public.fn_on_follow_feed_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        IF (NEW.status = 'accepted' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM 'accepted')) THEN
+            INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+            SELECT NEW.follower_id, w.id, w.user_id, w.started_at
+            FROM public.workouts w
+            WHERE w.user_id = NEW.following_id
+              AND w.fanned_out
+            ORDER BY w.started_at DESC
+            LIMIT public.feed_backfill_limit()
+            ON CONFLICT DO NOTHING;
+        END IF;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.feed_items
+        WHERE user_id = OLD.follower_id AND author_id = OLD.following_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_follow_feed_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT' OR TG_OP = 'UPDATE') THEN
+        IF (NEW.status = 'accepted' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM 'accepted')) THEN
+            INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+            SELECT NEW.follower_id, w.id, w.user_id, w.started_at
+            FROM public.workouts w
+            WHERE w.user_id = NEW.following_id
+              AND (SELECT p.followers_count FROM public.profiles p WHERE p.id = NEW.following_id) < public.feed_fanout_threshold()
+            ORDER BY w.started_at DESC
+            LIMIT public.feed_backfill_limit()
+            ON CONFLICT DO NOTHING;
+        END IF;
+
+    ELSIF (TG_OP = 'DELETE') THEN
+        DELETE FROM public.feed_items
+        WHERE user_id = OLD.follower_id AND author_id = OLD.following_id;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION public.fn_on_workout_feed_sync()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+        VALUES (NEW.user_id, NEW.id, NEW.user_id, NEW.started_at)
+        ON CONFLICT DO NOTHING;
+
+        INSERT INTO public.feed_items (user_id, workout_id, author_id, started_at)
+        SELECT f.follower_id, NEW.id, NEW.user_id, NEW.started_at
+        FROM public.follows f
+        JOIN public.profiles p ON p.id = NEW.user_id
+        WHERE f.following_id = NEW.user_id
+          AND f.status = 'accepted'
+          AND p.followers_count < public.feed_fanout_threshold()
+        ON CONFLICT DO NOTHING;
+
+    ELSIF (TG_OP = 'UPDATE') THEN
+        IF (NEW.started_at IS DISTINCT FROM OLD.started_at) THEN
+            UPDATE public.feed_items SET started_at = NEW.started_at WHERE workout_id = NEW.id;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS tr_set_workout_fanned_out ON public.workouts;
+DROP FUNCTION IF EXISTS public.fn_set_workout_fanned_out;
+DROP INDEX IF EXISTS public.idx_workouts_pulled;
+ALTER TABLE public.workouts DROP COLUMN IF EXISTS fanned_out;
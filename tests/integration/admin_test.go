@@ -0,0 +1,257 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/testutil"
+)
+
+// =============================================================================
+// Helper: seedAdmin creates a user and makes them a sys admin
+// =============================================================================
+
+func seedAdmin(t *testing.T, srv *testutil.TestServer, username string) (*testutil.TestUser, string) {
+	t.Helper()
+	admin := srv.SeedUser(t, username)
+	_, err := srv.DB.Exec(context.Background(), "INSERT INTO sys_admins (user_id) VALUES ($1)", admin.ID)
+	if err != nil {
+		t.Fatalf("Failed to seed sys admin: %v", err)
+	}
+	return admin, testutil.CreateTestToken(admin.ID)
+}
+
+// =============================================================================
+// AdminHandler Tests
+// =============================================================================
+
+// TestIntegration_Admin_RequiresAdmin verifies regular users cannot reach the admin API.
+func TestIntegration_Admin_RequiresAdmin(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "not-an-admin")
+	token := testutil.CreateTestToken(user.ID)
+
+	req := httptest.NewRequest("GET", "/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("GET /admin/users: expected 403, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+// TestIntegration_Admin_SuspendUser tests suspending a user, which signs them
+// out, and lifting the suspension, with both actions audited.
+func TestIntegration_Admin_SuspendUser(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+	ctx := context.Background()
+
+	admin, adminToken := seedAdmin(t, srv, "suspend-admin")
+	target := srv.SeedUser(t, "suspend-target")
+
+	refreshToken := "test-refresh-token-" + uuid.New().String()
+	_, err := srv.DB.Exec(ctx,
+		`INSERT INTO user_sessions (user_id, refresh_token, expires_at) VALUES ($1, $2, $3)`,
+		target.ID, refreshToken, time.Now().Add(24*time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("Failed to seed user session: %v", err)
+	}
+
+	// 1. Act - PUT /admin/users/{id}/suspension
+	until := time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+	payload := `{"until": "` + until + `", "reason": "spam"}`
+	req := httptest.NewRequest("PUT", "/admin/users/"+target.ID.String()+"/suspension", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr := httptest.NewRecorder()
+
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("PUT suspension: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 2. The user is signed out and cannot refresh
+	req = httptest.NewRequest("POST", "/auth/refresh", strings.NewReader(`{"refresh_token": "`+refreshToken+`"}`))
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("POST /auth/refresh: expected 401 after suspension, got %d", rr.Code)
+	}
+
+	// 3. Admins see the suspension
+	req = httptest.NewRequest("GET", "/admin/users/"+target.ID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	var user models.AdminUser
+	json.NewDecoder(rr.Body).Decode(&user)
+	if user.SuspendedUntil == nil || user.SuspensionReason == nil || *user.SuspensionReason != "spam" {
+		t.Errorf("Expected the suspension on the user, got %+v", user)
+	}
+
+	// 4. Act - DELETE /admin/users/{id}/suspension
+	req = httptest.NewRequest("DELETE", "/admin/users/"+target.ID.String()+"/suspension", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE suspension: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 5. Both actions are in the audit log, newest first
+	req = httptest.NewRequest("GET", "/admin/audit-log?target_id="+target.ID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /admin/audit-log: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var page pagination.Page[models.AuditLogEntry]
+	json.NewDecoder(rr.Body).Decode(&page)
+	if len(page.Items) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(page.Items))
+	}
+	if page.Items[0].Action != models.AuditUnsuspendUser || page.Items[1].Action != models.AuditSuspendUser {
+		t.Errorf("Expected unsuspend then suspend, got %s, %s", page.Items[0].Action, page.Items[1].Action)
+	}
+	if page.Items[1].AdminID != admin.ID {
+		t.Errorf("Expected the acting admin to be recorded, got %s", page.Items[1].AdminID)
+	}
+}
+
+// TestIntegration_Admin_BanUser tests that a ban locks the user out at once,
+// even with a live access token, hides their workouts from others, and that
+// lifting it restores both.
+func TestIntegration_Admin_BanUser(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	_, adminToken := seedAdmin(t, srv, "ban-admin")
+	target := srv.SeedUser(t, "ban-target")
+	viewer := srv.SeedUser(t, "ban-viewer")
+	targetToken := testutil.CreateTestToken(target.ID)
+	viewerToken := testutil.CreateTestToken(viewer.ID)
+	workoutID := seedWorkoutForComment(t, srv, target.ID)
+
+	// 1. Act - PUT /admin/users/{id}/ban
+	req := httptest.NewRequest("PUT", "/admin/users/"+target.ID.String()+"/ban", strings.NewReader(`{"reason": "spam"}`))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr := httptest.NewRecorder()
+
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("PUT ban: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 2. The banned user's access token no longer works
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+targetToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("GET as banned user: expected 403, got %d", rr.Code)
+	}
+
+	// ...but they can still log out
+	t.Setenv("JIMU_SECRET", testutil.TestJWTSecret)
+	req = httptest.NewRequest("POST", "/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+targetToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("POST /logout as banned user: expected 204, got %d", rr.Code)
+	}
+
+	// 3. Their workout is hidden from others
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+viewerToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("GET banned user's workout: expected 404, got %d", rr.Code)
+	}
+
+	// 4. Act - DELETE /admin/users/{id}/ban
+	req = httptest.NewRequest("DELETE", "/admin/users/"+target.ID.String()+"/ban", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE ban: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 5. The user and their workout are back
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+targetToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET as unbanned user: expected 200, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+viewerToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET restored workout: expected 200, got %d", rr.Code)
+	}
+}
+
+// TestIntegration_Admin_RemoveWorkout tests a content takedown and that the
+// audit log cannot be rewritten afterwards.
+func TestIntegration_Admin_RemoveWorkout(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+	ctx := context.Background()
+
+	_, adminToken := seedAdmin(t, srv, "takedown-admin")
+	owner := srv.SeedUser(t, "takedown-owner")
+	workoutID := seedWorkoutForComment(t, srv, owner.ID)
+
+	// 1. Act - DELETE /admin/workouts/{id}
+	req := httptest.NewRequest("DELETE", "/admin/workouts/"+workoutID.String()+"?reason=offensive", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr := httptest.NewRecorder()
+
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE /admin/workouts: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 2. The workout is gone
+	var exists bool
+	srv.DB.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM workouts WHERE id = $1)", workoutID).Scan(&exists)
+	if exists {
+		t.Error("Expected the workout to be removed")
+	}
+
+	// 3. The audit log keeps the reason and cannot be edited or deleted
+	var reason string
+	err := srv.DB.QueryRow(ctx, "SELECT details->>'reason' FROM admin_audit_log WHERE target_id = $1", workoutID).Scan(&reason)
+	if err != nil || reason != "offensive" {
+		t.Errorf("Expected the takedown reason in the audit log, got %q, %v", reason, err)
+	}
+	if _, err := srv.DB.Exec(ctx, "UPDATE admin_audit_log SET action = 'nothing'"); err == nil {
+		t.Error("Expected the audit log to reject updates")
+	}
+	if _, err := srv.DB.Exec(ctx, "DELETE FROM admin_audit_log"); err == nil {
+		t.Error("Expected the audit log to reject deletes")
+	}
+}
@@ -2,6 +2,8 @@ package integration_test
 
 import (
 	"context"
+	"encoding/json"
+	"image/jpeg"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/testutil"
 )
 
@@ -128,6 +131,61 @@ func TestIntegration_Auth_GetOtherProfile_NotFound(t *testing.T) {
 // AuthHandler RefreshToken Tests
 // =============================================================================
 
+// TestIntegration_Auth_Avatar tests setting, replacing and serving an avatar.
+func TestIntegration_Auth_Avatar(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "avatar-user")
+	token := testutil.CreateTestToken(user.ID)
+
+	setAvatar := func() string {
+		t.Helper()
+		uploadID := uploadTestImage(t, srv, token)
+		req := httptest.NewRequest("PUT", "/auth/profile/avatar", strings.NewReader(`{"upload_id": "`+uploadID.String()+`"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("PUT /auth/profile/avatar: expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp models.AvatarResponse
+		json.NewDecoder(rr.Body).Decode(&resp)
+		return resp.AvatarURL
+	}
+
+	// 1. The avatar URL redirects to the stored 128px square
+	first := setAvatar()
+	req := httptest.NewRequest("GET", strings.TrimPrefix(first, "http://jimu.test")+"?size=128", nil)
+	rr := httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusFound {
+		t.Fatalf("GET avatar: expected 302, got %d: %s", rr.Code, rr.Body.String())
+	}
+	req = httptest.NewRequest("GET", strings.TrimPrefix(rr.Header().Get("Location"), "http://jimu.test"), nil)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	cfg, err := jpeg.DecodeConfig(rr.Body)
+	if err != nil || cfg.Width != 128 || cfg.Height != 128 {
+		t.Errorf("Expected a 128x128 JPEG, got %+v, %v", cfg, err)
+	}
+
+	// 2. The profile points at the avatar
+	var avatarURL *string
+	srv.DB.QueryRow(context.Background(), "SELECT avatar_url FROM profiles WHERE id = $1", user.ID).Scan(&avatarURL)
+	if avatarURL == nil || *avatarURL != first {
+		t.Errorf("Expected avatar_url %q, got %v", first, avatarURL)
+	}
+
+	// 3. Replacing it queues the old files for deletion
+	setAvatar()
+	var queued int
+	srv.DB.QueryRow(context.Background(), "SELECT count(*) FROM blob_deletions WHERE object_key LIKE 'avatars/%'").Scan(&queued)
+	if queued != 2 {
+		t.Errorf("Expected the 2 old avatar files to be queued, got %d", queued)
+	}
+}
+
 // TestIntegration_Auth_RefreshToken tests the token refresh flow.
 func TestIntegration_Auth_RefreshToken(t *testing.T) {
 	srv := testutil.NewTestServer(t)
@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/pagination"
+	"github.com/rotsu1/jimu-backend/internal/testutil"
+)
+
+// =============================================================================
+// ReportHandler Tests
+// =============================================================================
+
+// TestIntegration_Report_HideWorkout tests the moderation flow: a user reports
+// a workout, an admin hides it from the queue, and the workout is no longer
+// shown to anyone but its author.
+func TestIntegration_Report_HideWorkout(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	_, adminToken := seedAdmin(t, srv, "report-admin")
+	owner := srv.SeedUser(t, "report-owner")
+	reporter := srv.SeedUser(t, "report-reporter")
+	ownerToken := testutil.CreateTestToken(owner.ID)
+	reporterToken := testutil.CreateTestToken(reporter.ID)
+	workoutID := seedWorkoutForComment(t, srv, owner.ID)
+
+	// 1. Act - POST /reports
+	payload := `{"target_type": "workout", "target_id": "` + workoutID.String() + `", "reason": "violence"}`
+	req := httptest.NewRequest("POST", "/reports", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+reporterToken)
+	rr := httptest.NewRecorder()
+
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /reports: expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 2. The same report again is a duplicate
+	req = httptest.NewRequest("POST", "/reports", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+reporterToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("POST /reports again: expected 409, got %d", rr.Code)
+	}
+
+	// 3. The report is in the admin queue
+	req = httptest.NewRequest("GET", "/admin/reports", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("GET /admin/reports: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var page pagination.Page[models.QueuedReport]
+	json.NewDecoder(rr.Body).Decode(&page)
+	if len(page.Items) != 1 || page.Items[0].TargetID != workoutID {
+		t.Fatalf("Expected the report in the queue, got %+v", page.Items)
+	}
+
+	// 4. Act - POST /admin/reports/{id}/resolve
+	req = httptest.NewRequest("POST", "/admin/reports/"+page.Items[0].ID.String()+"/resolve",
+		strings.NewReader(`{"action": "hide_content"}`))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST resolve: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 5. The workout is hidden from the reporter but not from its owner
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+reporterToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("GET hidden workout as reporter: expected 404, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+ownerToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET hidden workout as owner: expected 200, got %d", rr.Code)
+	}
+
+	// 6. Restoring the workout shows it again
+	req = httptest.NewRequest("DELETE", "/admin/workouts/"+workoutID.String()+"/hidden", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("DELETE hidden: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String(), nil)
+	req.Header.Set("Authorization", "Bearer "+reporterToken)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET restored workout as reporter: expected 200, got %d", rr.Code)
+	}
+}
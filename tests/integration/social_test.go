@@ -2,12 +2,17 @@ package integration_test
 
 import (
 	"context"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/rotsu1/jimu-backend/internal/entitlements"
+	"github.com/rotsu1/jimu-backend/internal/googleplay"
+	"github.com/rotsu1/jimu-backend/internal/models"
 	"github.com/rotsu1/jimu-backend/internal/testutil"
 )
 
@@ -205,16 +210,22 @@ func TestIntegration_Subscription_UpsertAndGet(t *testing.T) {
 	user := srv.SeedUser(t, "subscription-user")
 	token := testutil.CreateTestToken(user.ID)
 
-	expiresAt := time.Now().Add(30 * 24 * time.Hour).Format(time.RFC3339)
+	signed, err := srv.AppStore.Sign(map[string]any{
+		"transactionId":         "txn_12346",
+		"originalTransactionId": "txn_12345",
+		"bundleId":              testutil.TestAppStoreBundleID,
+		"productId":             "premium_monthly",
+		"expiresDate":           time.Now().Add(30 * 24 * time.Hour).UnixMilli(),
+		"signedDate":            time.Now().UnixMilli(),
+		"environment":           "Production",
+		"appAccountToken":       user.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
 
 	// 1. Act - POST /subscriptions
-	payload := `{
-		"original_transaction_id": "txn_12345",
-		"product_id": "premium_monthly",
-		"status": "active",
-		"expires_at": "` + expiresAt + `",
-		"environment": "sandbox"
-	}`
+	payload := `{"signed_transaction": "` + signed + `"}`
 	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -228,7 +239,7 @@ func TestIntegration_Subscription_UpsertAndGet(t *testing.T) {
 
 	// 2. Verify database
 	var count int
-	err := srv.DB.QueryRow(
+	err = srv.DB.QueryRow(
 		context.Background(),
 		"SELECT COUNT(*) FROM subscriptions WHERE user_id = $1",
 		user.ID,
@@ -256,6 +267,122 @@ func TestIntegration_Subscription_UpsertAndGet(t *testing.T) {
 	}
 }
 
+// TestIntegration_Subscription_RejectsUnsignedClaims tests that a client
+// cannot grant itself a subscription without a signed transaction.
+func TestIntegration_Subscription_RejectsUnsignedClaims(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "forging-user")
+	token := testutil.CreateTestToken(user.ID)
+
+	payload := `{
+		"original_transaction_id": "txn_forged",
+		"product_id": "premium_monthly",
+		"status": "active",
+		"expires_at": "2099-01-01T00:00:00Z"
+	}`
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unsigned claim, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var count int
+	err := srv.DB.QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM subscriptions WHERE user_id = $1",
+		user.ID,
+	).Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to query subscriptions: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected no subscription, got %d", count)
+	}
+}
+
+// TestIntegration_Subscription_AppStoreWebhook tests that a signed refund
+// notification revokes the subscription and that redelivery is harmless.
+func TestIntegration_Subscription_AppStoreWebhook(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "webhook-user")
+	token := testutil.CreateTestToken(user.ID)
+
+	transaction := map[string]any{
+		"transactionId":         "txn_200",
+		"originalTransactionId": "txn_100",
+		"bundleId":              testutil.TestAppStoreBundleID,
+		"productId":             "premium_monthly",
+		"expiresDate":           time.Now().Add(30 * 24 * time.Hour).UnixMilli(),
+		"signedDate":            time.Now().Add(-time.Minute).UnixMilli(),
+		"environment":           "Production",
+	}
+	signedTx, err := srv.AppStore.Sign(transaction)
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(`{"signed_transaction": "`+signedTx+`"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /subscriptions: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	transaction["signedDate"] = time.Now().UnixMilli()
+	transaction["revocationDate"] = time.Now().UnixMilli()
+	signedRefundTx, _ := srv.AppStore.Sign(transaction)
+	signedPayload, err := srv.AppStore.Sign(map[string]any{
+		"notificationType": "REFUND",
+		"notificationUUID": "b3b0c4e2-1111-4000-8000-000000000001",
+		"version":          "2.0",
+		"signedDate":       time.Now().UnixMilli(),
+		"data": map[string]any{
+			"bundleId":              testutil.TestAppStoreBundleID,
+			"environment":           "Production",
+			"signedTransactionInfo": signedRefundTx,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign notification: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		req = httptest.NewRequest("POST", "/webhooks/app-store", strings.NewReader(`{"signedPayload": "`+signedPayload+`"}`))
+		rr = httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("POST /webhooks/app-store: expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+	}
+
+	var status string
+	var events int
+	err = srv.DB.QueryRow(
+		context.Background(),
+		`SELECT s.status, (SELECT count(*) FROM subscription_events e WHERE e.user_id = s.user_id)
+		FROM subscriptions s WHERE s.user_id = $1`,
+		user.ID,
+	).Scan(&status, &events)
+	if err != nil {
+		t.Fatalf("Failed to query subscriptions: %v", err)
+	}
+	if status != "revoked" {
+		t.Errorf("Expected status revoked, got %q", status)
+	}
+	if events != 1 {
+		t.Errorf("Expected 1 recorded event, got %d", events)
+	}
+}
+
 // TestIntegration_Subscription_GetNotFound tests getting subscription when none exists.
 func TestIntegration_Subscription_GetNotFound(t *testing.T) {
 	srv := testutil.NewTestServer(t)
@@ -274,3 +401,186 @@ func TestIntegration_Subscription_GetNotFound(t *testing.T) {
 		t.Errorf("Expected 404 for no subscription, got %d: %s", rr.Code, rr.Body.String())
 	}
 }
+
+// TestIntegration_Subscription_GatesPremiumStats tests that stats require an
+// active subscription and unlock as soon as one is recorded.
+func TestIntegration_Subscription_GatesPremiumStats(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "premium-stats-user")
+	token := testutil.CreateTestToken(user.ID)
+
+	getStats := func() int {
+		req := httptest.NewRequest("GET", "/stats", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// 1. Free users are asked to subscribe
+	if code := getStats(); code != http.StatusPaymentRequired {
+		t.Fatalf("GET /stats on free plan: expected 402, got %d", code)
+	}
+
+	// 2. Subscribe
+	signed, err := srv.AppStore.Sign(map[string]any{
+		"transactionId":         "txn_stats_2",
+		"originalTransactionId": "txn_stats_1",
+		"bundleId":              testutil.TestAppStoreBundleID,
+		"productId":             "premium_monthly",
+		"expiresDate":           time.Now().Add(30 * 24 * time.Hour).UnixMilli(),
+		"signedDate":            time.Now().UnixMilli(),
+		"environment":           "Production",
+		"appAccountToken":       user.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(`{"signed_transaction": "`+signed+`"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /subscriptions: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 3. The cached free plan is dropped and stats unlock
+	if code := getStats(); code != http.StatusOK {
+		t.Errorf("GET /stats on premium plan: expected 200, got %d", code)
+	}
+
+	// 4. The profile reports the derived plan
+	var plan string
+	err = srv.DB.QueryRow(context.Background(), "SELECT public.subscription_plan($1)", user.ID).Scan(&plan)
+	if err != nil {
+		t.Fatalf("Failed to query plan: %v", err)
+	}
+	if plan != "premium" {
+		t.Errorf("Expected premium plan, got %q", plan)
+	}
+}
+
+// TestIntegration_Subscription_PlanMatchesEntitlements tests that the SQL
+// plan function and entitlements.PlanFor agree on which products and
+// environments grant premium.
+func TestIntegration_Subscription_PlanMatchesEntitlements(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+	ctx := context.Background()
+
+	var products []string
+	for productID := range entitlements.ProductPlans {
+		products = append(products, productID)
+	}
+	products = append(products, "unknown_product")
+
+	for _, productID := range products {
+		for _, environment := range []string{models.EnvironmentProduction, "sandbox"} {
+			user := srv.SeedUser(t, "plan-"+productID+"-"+environment)
+			sub := &models.Subscription{
+				ProductID:   productID,
+				Status:      models.SubscriptionActive,
+				ExpiresAt:   time.Now().Add(24 * time.Hour),
+				Environment: environment,
+			}
+			_, err := srv.DB.Exec(ctx,
+				`INSERT INTO subscriptions (user_id, original_transaction_id, product_id, status, expires_at, environment)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				user.ID, "txn-"+user.ID.String(), sub.ProductID, sub.Status, sub.ExpiresAt, sub.Environment,
+			)
+			if err != nil {
+				t.Fatalf("Failed to seed subscription: %v", err)
+			}
+
+			var plan string
+			if err := srv.DB.QueryRow(ctx, "SELECT public.subscription_plan($1)", user.ID).Scan(&plan); err != nil {
+				t.Fatalf("Failed to query plan: %v", err)
+			}
+			if want, _ := entitlements.PlanFor(sub, time.Now()); plan != want {
+				t.Errorf("%s in %s: SQL plan %q, PlanFor %q", productID, environment, plan, want)
+			}
+		}
+	}
+}
+
+// TestIntegration_Subscription_GooglePlay tests a Google Play purchase
+// reported by the app and later revoked through a developer notification.
+func TestIntegration_Subscription_GooglePlay(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "play-user")
+	token := testutil.CreateTestToken(user.ID)
+
+	purchase := &googleplay.SubscriptionPurchase{
+		SubscriptionState:    googleplay.StateActive,
+		LatestOrderID:        "GPA.1234",
+		AcknowledgementState: googleplay.AcknowledgementPending,
+		LineItems: []googleplay.LineItem{
+			{ProductID: "premium_monthly", ExpiryTime: time.Now().Add(30 * 24 * time.Hour)},
+		},
+	}
+	purchase.ExternalAccountIdentifiers.ObfuscatedExternalAccountID = user.ID.String()
+	srv.GooglePlay.SetPurchase("play-token", purchase)
+
+	// 1. Act - POST /subscriptions with a purchase token
+	req := httptest.NewRequest("POST", "/subscriptions", strings.NewReader(`{"store": "google_play", "purchase_token": "play-token"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /subscriptions: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(srv.GooglePlay.Acknowledged) != 1 {
+		t.Errorf("Expected the purchase to be acknowledged, got %v", srv.GooglePlay.Acknowledged)
+	}
+
+	// 2. Premium features unlock as for App Store subscribers
+	req = httptest.NewRequest("GET", "/stats", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("GET /stats: expected 200, got %d", rr.Code)
+	}
+
+	// 3. Act - Google revokes the purchase
+	purchase.SubscriptionState = googleplay.StateExpired
+	data := `{"version":"1.0","packageName":"app.jimu","eventTimeMillis":"` +
+		strconv.FormatInt(time.Now().UnixMilli(), 10) +
+		`","subscriptionNotification":{"version":"1.0","notificationType":12,"purchaseToken":"play-token","subscriptionId":"premium_monthly"}}`
+	body := `{"message":{"data":"` + base64.StdEncoding.EncodeToString([]byte(data)) + `","messageId":"message-1"}}`
+	req = httptest.NewRequest("POST", "/webhooks/google-play?token="+testutil.TestGooglePlayRTDNToken, strings.NewReader(body))
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("POST /webhooks/google-play: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 4. Verify database
+	var store, status string
+	err := srv.DB.QueryRow(
+		context.Background(),
+		"SELECT store, status FROM subscriptions WHERE user_id = $1",
+		user.ID,
+	).Scan(&store, &status)
+	if err != nil {
+		t.Fatalf("Failed to query subscription: %v", err)
+	}
+	if store != "google_play" || status != "revoked" {
+		t.Errorf("Expected a revoked google_play subscription, got %s %s", store, status)
+	}
+
+	// 5. Premium features lock again right away
+	req = httptest.NewRequest("GET", "/stats", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusPaymentRequired {
+		t.Errorf("GET /stats after revocation: expected 402, got %d", rr.Code)
+	}
+}
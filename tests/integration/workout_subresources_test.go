@@ -1,7 +1,13 @@
 package integration_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -9,7 +15,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rotsu1/jimu-backend/internal/models"
+	"github.com/rotsu1/jimu-backend/internal/repository"
+	"github.com/rotsu1/jimu-backend/internal/storage"
 	"github.com/rotsu1/jimu-backend/internal/testutil"
+	"github.com/rotsu1/jimu-backend/internal/uploads"
 )
 
 // =============================================================================
@@ -263,6 +273,36 @@ func TestIntegration_WorkoutSet_AddUpdateRemove(t *testing.T) {
 // WorkoutImageHandler Tests
 // =============================================================================
 
+// uploadTestImage uploads a PNG the way a client does: it creates an upload
+// and PUTs the file to the returned presigned URL.
+func uploadTestImage(t *testing.T, srv *testutil.TestServer, token string) uuid.UUID {
+	t.Helper()
+	var img bytes.Buffer
+	png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 64, 48)))
+
+	payload := fmt.Sprintf(`{"content_type": "image/png", "size_bytes": %d}`, img.Len())
+	req := httptest.NewRequest("POST", "/uploads", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("POST /uploads: expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var upload models.CreateUploadResponse
+	json.NewDecoder(rr.Body).Decode(&upload)
+
+	req = httptest.NewRequest(upload.Method, strings.TrimPrefix(upload.UploadURL, "http://jimu.test"), bytes.NewReader(img.Bytes()))
+	for k, v := range upload.Headers {
+		req.Header.Set(k, v)
+	}
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT upload URL: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	return upload.UploadID
+}
+
 // TestIntegration_WorkoutImage_AddListRemove tests the workout image lifecycle.
 func TestIntegration_WorkoutImage_AddListRemove(t *testing.T) {
 	srv := testutil.NewTestServer(t)
@@ -272,8 +312,9 @@ func TestIntegration_WorkoutImage_AddListRemove(t *testing.T) {
 	token := testutil.CreateTestToken(user.ID)
 	workoutID := seedWorkout(t, srv, user.ID, "Image Test Workout")
 
-	// 1. Act - POST /workouts/{id}/images
-	payload := `{"storage_path": "images/workout/test-image.jpg", "display_order": 1}`
+	// 1. Act - POST /uploads, PUT the file, POST /workouts/{id}/images
+	uploadID := uploadTestImage(t, srv, token)
+	payload := `{"upload_id": "` + uploadID.String() + `", "display_order": 1}`
 	req := httptest.NewRequest("POST", "/workouts/"+workoutID.String()+"/images", strings.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -282,7 +323,32 @@ func TestIntegration_WorkoutImage_AddListRemove(t *testing.T) {
 	srv.Router.ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusCreated {
-		t.Errorf("POST /workouts/{id}/images: expected 201, got %d: %s", rr.Code, rr.Body.String())
+		t.Fatalf("POST /workouts/{id}/images: expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		StoragePath  string `json:"storage_path"`
+		URL          string `json:"url"`
+		ThumbnailURL string `json:"thumbnail_url"`
+	}
+	json.NewDecoder(rr.Body).Decode(&created)
+
+	// The signed URLs serve the processed JPEGs
+	for _, signed := range []string{created.URL, created.ThumbnailURL} {
+		req = httptest.NewRequest("GET", strings.TrimPrefix(signed, "http://jimu.test"), nil)
+		rr = httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK || http.DetectContentType(rr.Body.Bytes()) != "image/jpeg" {
+			t.Errorf("GET %s: expected a JPEG, got %d", signed, rr.Code)
+		}
+	}
+
+	// An upload is committed once
+	req = httptest.NewRequest("POST", "/workouts/"+workoutID.String()+"/images", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Reusing an upload: expected 404, got %d", rr.Code)
 	}
 
 	// 2. Verify database
@@ -307,8 +373,8 @@ func TestIntegration_WorkoutImage_AddListRemove(t *testing.T) {
 		t.Errorf("GET /workouts/{id}/images: expected 200, got %d: %s", rr.Code, rr.Body.String())
 	}
 
-	if !strings.Contains(rr.Body.String(), "test-image.jpg") {
-		t.Errorf("Expected response to contain image path, got: %s", rr.Body.String())
+	if !strings.Contains(rr.Body.String(), `"thumbnail_url"`) {
+		t.Errorf("Expected response to contain signed image URLs, got: %s", rr.Body.String())
 	}
 
 	// 4. Act - DELETE /workouts/{id}/images/{imageId}
@@ -335,6 +401,77 @@ func TestIntegration_WorkoutImage_AddListRemove(t *testing.T) {
 	if count != 0 {
 		t.Errorf("Expected 0 workout images after deletion, got %d", count)
 	}
+
+	// 6. The sweeper deletes the stored files
+	sweeper := uploads.NewSweeper(repository.NewBlobDeletionRepository(srv.DB), srv.Blobs)
+	if err := sweeper.Run(context.Background()); err != nil {
+		t.Fatalf("Failed to sweep blobs: %v", err)
+	}
+	if _, err := srv.Blobs.Stat(context.Background(), created.StoragePath); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected %s to be deleted, got %v", created.StoragePath, err)
+	}
+}
+
+// TestIntegration_WorkoutImage_Reorder tests reordering a workout's images.
+func TestIntegration_WorkoutImage_Reorder(t *testing.T) {
+	srv := testutil.NewTestServer(t)
+	defer srv.DB.Close()
+
+	user := srv.SeedUser(t, "workout-reorder-user")
+	token := testutil.CreateTestToken(user.ID)
+	workoutID := seedWorkout(t, srv, user.ID, "Reorder Test Workout")
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		payload := `{"upload_id": "` + uploadTestImage(t, srv, token).String() + `"}`
+		req := httptest.NewRequest("POST", "/workouts/"+workoutID.String()+"/images", strings.NewReader(payload))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		srv.Router.ServeHTTP(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("POST /workouts/{id}/images: expected 201, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var created models.WorkoutImage
+		json.NewDecoder(rr.Body).Decode(&created)
+		ids = append(ids, created.ID.String())
+	}
+
+	// 1. Act - PUT /workouts/{id}/images/order
+	payload := `{"image_ids": ["` + ids[2] + `", "` + ids[0] + `", "` + ids[1] + `"]}`
+	req := httptest.NewRequest("PUT", "/workouts/"+workoutID.String()+"/images/order", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("PUT /workouts/{id}/images/order: expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 2. Verify the listing follows the new order
+	req = httptest.NewRequest("GET", "/workouts/"+workoutID.String()+"/images", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	var images []models.WorkoutImage
+	json.NewDecoder(rr.Body).Decode(&images)
+	want := []string{ids[2], ids[0], ids[1]}
+	if len(images) != len(want) {
+		t.Fatalf("Expected %d images, got %d", len(want), len(images))
+	}
+	for i, wi := range images {
+		if wi.ID.String() != want[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, want[i], wi.ID)
+		}
+	}
+
+	// 3. An order missing an image is rejected
+	payload = `{"image_ids": ["` + ids[0] + `", "` + ids[1] + `"]}`
+	req = httptest.NewRequest("PUT", "/workouts/"+workoutID.String()+"/images/order", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr = httptest.NewRecorder()
+	srv.Router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Incomplete order: expected 400, got %d", rr.Code)
+	}
 }
 
 // =============================================================================